@@ -92,6 +92,10 @@ type IPPoolAllowedUse string
 const (
 	IPPoolAllowedUseWorkload IPPoolAllowedUse = "Workload"
 	IPPoolAllowedUseTunnel   IPPoolAllowedUse = "Tunnel"
+
+	// IPPoolAllowedUseLoadBalancer reserves the pool for the addresses of LoadBalancer
+	// Services. It can't be combined with other uses.
+	IPPoolAllowedUseLoadBalancer IPPoolAllowedUse = "LoadBalancer"
 )
 
 type VXLANMode string
@@ -148,6 +148,53 @@ type KubeControllersConfigurationStatus struct {
 	// EnvironmentVars contains the environment variables on the kube-controllers that influenced
 	// the RunningConfig.
 	EnvironmentVars map[string]string `json:"environmentVars,omitempty"`
+
+	// DryRunPlans contains, for each controller running in dry-run mode, a summary of the changes
+	// that the controller would have made to the Calico datastore during its most recent reconciliation.
+	DryRunPlans []ReconcilePlan `json:"dryRunPlans,omitempty"`
+
+	// Heartbeat is written periodically by kube-controllers, so that other components can tell
+	// whether it's running, and which instance wrote to the datastore most recently.
+	Heartbeat *KubeControllersHeartbeat `json:"heartbeat,omitempty"`
+}
+
+// KubeControllersHeartbeat records that a kube-controllers instance is running.
+type KubeControllersHeartbeat struct {
+	// Identity is the identity of the kube-controllers instance, normally the name of its pod.
+	Identity string `json:"identity"`
+
+	// Version is the version of the kube-controllers instance.
+	Version string `json:"version,omitempty"`
+
+	// Time is the time of the most recent heartbeat.
+	Time metav1.Time `json:"time"`
+}
+
+// ReconcilePlan summarizes the changes to the Calico datastore planned by a controller.
+type ReconcilePlan struct {
+	// Controller is the name of the controller that produced the plan.
+	Controller string `json:"controller"`
+
+	// Time is the time at which the plan was produced.
+	Time metav1.Time `json:"time"`
+
+	// Creates is the number of resources that would be created.
+	Creates int `json:"creates"`
+
+	// Updates is the number of resources that would be updated.
+	Updates int `json:"updates"`
+
+	// Deletes is the number of resources that would be deleted.
+	Deletes int `json:"deletes"`
+
+	// SampleCreates contains a sample of the keys of the resources that would be created.
+	SampleCreates []string `json:"sampleCreates,omitempty"`
+
+	// SampleUpdates contains a sample of the keys of the resources that would be updated.
+	SampleUpdates []string `json:"sampleUpdates,omitempty"`
+
+	// SampleDeletes contains a sample of the keys of the resources that would be deleted.
+	SampleDeletes []string `json:"sampleDeletes,omitempty"`
 }
 
 // New KubeControllersConfiguration creates a new (zeroed) KubeControllersConfiguration struct with
@@ -2266,6 +2266,18 @@ func (in *KubeControllersConfigurationStatus) DeepCopyInto(out *KubeControllersC
 			(*out)[key] = val
 		}
 	}
+	if in.DryRunPlans != nil {
+		in, out := &in.DryRunPlans, &out.DryRunPlans
+		*out = make([]ReconcilePlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Heartbeat != nil {
+		in, out := &in.Heartbeat, &out.Heartbeat
+		*out = new(KubeControllersHeartbeat)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2279,6 +2291,23 @@ func (in *KubeControllersConfigurationStatus) DeepCopy() *KubeControllersConfigu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeControllersHeartbeat) DeepCopyInto(out *KubeControllersHeartbeat) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeControllersHeartbeat.
+func (in *KubeControllersHeartbeat) DeepCopy() *KubeControllersHeartbeat {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeControllersHeartbeat)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NamespaceControllerConfig) DeepCopyInto(out *NamespaceControllerConfig) {
 	*out = *in
@@ -2672,6 +2701,38 @@ func (in *ProtoPort) DeepCopy() *ProtoPort {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconcilePlan) DeepCopyInto(out *ReconcilePlan) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.SampleCreates != nil {
+		in, out := &in.SampleCreates, &out.SampleCreates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SampleUpdates != nil {
+		in, out := &in.SampleUpdates, &out.SampleUpdates
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SampleDeletes != nil {
+		in, out := &in.SampleDeletes, &out.SampleDeletes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconcilePlan.
+func (in *ReconcilePlan) DeepCopy() *ReconcilePlan {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconcilePlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteTableIDRange) DeepCopyInto(out *RouteTableIDRange) {
 	*out = *in
@@ -23,6 +23,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/debugserver"
@@ -32,28 +33,56 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/srv"
 	"go.etcd.io/etcd/client/pkg/v3/transport"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/storage/etcd3"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/etcdv3"
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/logutils"
 
 	"github.com/projectcalico/calico/crypto/pkg/tls"
+	"github.com/projectcalico/calico/kube-controllers/pkg/admin"
+	"github.com/projectcalico/calico/kube-controllers/pkg/apf"
 	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/consistency"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/dnsnetworkset"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/duplicateip"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/externalhost"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/felixconfig"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/flannelmigration"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/loadbalancer"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/namespace"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/namespacepool"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/networkpolicy"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/networkset"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/node"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/pod"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/poolassignment"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/serviceaccount"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/servicenetworkset"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/systempolicy"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/threatfeed"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/wireguardkeys"
+	"github.com/projectcalico/calico/kube-controllers/pkg/datastore"
+	"github.com/projectcalico/calico/kube-controllers/pkg/heartbeat"
+	"github.com/projectcalico/calico/kube-controllers/pkg/metricsserver"
+	"github.com/projectcalico/calico/kube-controllers/pkg/statsd"
 	"github.com/projectcalico/calico/kube-controllers/pkg/status"
+	"github.com/projectcalico/calico/kube-controllers/pkg/termination"
+	"github.com/projectcalico/calico/kube-controllers/pkg/tracing"
 )
 
+// How often to check that the datastore is reachable, when rebuilding the connection to it if it's
+// stuck.
+const datastoreCheckInterval = 10 * time.Second
+
 // VERSION is filled out during the build process (using git describe output)
 var (
 	VERSION    string
@@ -90,10 +119,13 @@ func main() {
 	// Install a hook that adds file/line no information.
 	log.AddHook(&logutils.ContextHook{})
 
+	// Install a hook that reports the reason for any fatal error in the pod's termination message.
+	log.AddHook(termination.NewHook(termination.DefaultLogPath))
+
 	// Attempt to load configuration.
 	cfg := new(config.Config)
 	if err := cfg.Parse(); err != nil {
-		log.WithError(err).Fatal("Failed to parse config")
+		log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("Failed to parse config")
 	}
 	log.WithField("config", cfg).Info("Loaded configuration from environment")
 
@@ -106,9 +138,9 @@ func main() {
 	log.SetLevel(logLevel)
 
 	// Build clients to be used by the controllers.
-	k8sClientset, calicoClient, err := getClients(cfg.Kubeconfig)
+	k8sClientset, dynamicClient, calicoClient, err := getClients(cfg)
 	if err != nil {
-		log.WithError(err).Fatal("Failed to start")
+		log.WithError(err).WithField(termination.CodeField, termination.CodeKubernetesUnavailable).Fatal("Failed to start")
 	}
 
 	stop := make(chan struct{})
@@ -116,43 +148,65 @@ func main() {
 	// Create the context.
 	ctx, cancel := context.WithCancel(context.Background())
 
+	if cfg.OTLPEndpoint != "" {
+		// Tracing is only a diagnostic aid, so carry on without it if it can't be set up.
+		shutdownTracing, err := tracing.Setup(ctx, cfg.OTLPEndpoint)
+		if err != nil {
+			log.WithError(err).Error("Failed to set up tracing")
+		} else {
+			log.WithField("endpoint", cfg.OTLPEndpoint).Info("Sending traces to OTLP collector")
+			defer func() {
+				if err := shutdownTracing(context.Background()); err != nil {
+					log.WithError(err).Warn("Failed to flush traces")
+				}
+			}()
+		}
+	}
+
 	// Create the status file. We will only update it if we have healthchecks enabled.
 	s := status.New(statusFile)
 
 	log.Info("Ensuring Calico datastore is initialized")
 	s.SetReady("Startup", false, "initialized to false")
-	initCtx, cancelInit := context.WithTimeout(ctx, 60*time.Second)
-	// Loop until context expires or calicoClient is initialized.
-	for {
-		err := calicoClient.EnsureInitialized(initCtx, "", "k8s")
-		if err != nil {
-			log.WithError(err).Info("Failed to initialize datastore")
-			s.SetReady(
-				"Startup",
-				false,
-				fmt.Sprintf("Error initializing datastore: %v", err),
-			)
-		} else {
-			// Exit loop
-			break
-		}
-
-		select {
-		case <-initCtx.Done():
-			log.Fatal("Failed to initialize Calico datastore")
-		case <-time.After(5 * time.Second):
-			// Try to initialize again
-		}
+	err = datastore.WaitForDatastore(ctx, calicoClient, cfg.DatastoreStartupTimeout, func(err error) {
+		s.SetReady(
+			"Startup",
+			false,
+			fmt.Sprintf("Error initializing datastore: %v", err),
+		)
+	})
+	if err != nil {
+		log.WithError(err).WithField(termination.CodeField, termination.CodeDatastoreUnavailable).Fatal("Failed to initialize Calico datastore")
 	}
 	log.Info("Calico datastore is initialized")
 	s.SetReady("Startup", true, "")
-	cancelInit()
+
+	// Rebuild the connection to the datastore if it's stuck, rather than carrying on failing.
+	if cfg.DatastoreReconnectAfter > 0 {
+		go calicoClient.Run(ctx, datastoreCheckInterval, cfg.DatastoreReconnectAfter)
+	}
+
+	// Suspend the controllers' writes to the datastore, and report not ready, while the datastore
+	// is failing, probing it with a single write at increasing intervals until it recovers.
+	controller.DatastoreBreaker = controller.NewCircuitBreaker(cfg.DatastoreFailureThreshold, time.Second, cfg.DatastoreMaxProbeInterval)
+	controller.DatastoreBreaker.OnStateChange(func(open bool, reason string) {
+		s.SetReady("CalicoDatastoreWrites", !open, reason)
+	})
+
+	// Fail the liveness probe if any of the controllers' workers gets stuck, so that we're
+	// restarted.
+	controller.WorkerWatchdog = controller.NewWatchdog(cfg.WorkerStallTimeout)
+	controller.WorkerWatchdog.OnStateChange(func(stalled bool, reason string) {
+		s.SetReady("Workers", !stalled, reason)
+	})
+	go controller.WorkerWatchdog.Run(ctx)
 
 	controllerCtrl := &controllerControl{
-		ctx:         ctx,
-		controllers: make(map[string]controller.Controller),
-		stop:        stop,
-		informers:   make([]cache.SharedIndexInformer, 0),
+		ctx:           ctx,
+		controllers:   make(map[string]controller.Controller),
+		stop:          stop,
+		informers:     make([]cache.SharedIndexInformer, 0),
+		dynamicClient: dynamicClient,
 	}
 
 	var runCfg config.RunConfig
@@ -160,12 +214,12 @@ func main() {
 	v, ok := os.LookupEnv(config.EnvEnabledControllers)
 	if ok && strings.Contains(v, "flannelmigration") {
 		if strings.Trim(v, " ,") != "flannelmigration" {
-			log.WithField(config.EnvEnabledControllers, v).Fatal("flannelmigration must be the only controller running")
+			log.WithFields(log.Fields{config.EnvEnabledControllers: v, termination.CodeField: termination.CodeConfig}).Fatal("flannelmigration must be the only controller running")
 		}
 		// Attempt to load Flannel configuration.
 		flannelConfig := new(flannelmigration.Config)
 		if err := flannelConfig.Parse(); err != nil {
-			log.WithError(err).Fatal("Failed to parse Flannel config")
+			log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("Failed to parse Flannel config")
 		}
 		log.WithField("flannelConfig", flannelConfig).Info("Loaded Flannel configuration from environment")
 
@@ -191,6 +245,15 @@ func main() {
 		controllerCtrl.InitControllers(ctx, runCfg, k8sClientset, calicoClient)
 	}
 
+	if cfg.HeartbeatPeriod > 0 {
+		// Let other components know that we're running. The hostname is the pod name.
+		identity, err := os.Hostname()
+		if err != nil {
+			log.WithError(err).Warn("Failed to get hostname for heartbeat")
+		}
+		go heartbeat.New(identity, VERSION, calicoClient).Run(ctx, cfg.HeartbeatPeriod)
+	}
+
 	if cfg.DatastoreType == "etcdv3" {
 		// If configured to do so, start an etcdv3 compaction.
 		go startCompactor(ctx, runCfg.EtcdV3CompactionPeriod)
@@ -207,17 +270,45 @@ func main() {
 
 	if runCfg.PrometheusPort != 0 {
 		// Serve prometheus metrics.
-		log.Infof("Starting Prometheus metrics server on port %d", runCfg.PrometheusPort)
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		if cfg.AdminTokenFile != "" {
+			admin.NewHandler(cfg.AdminTokenFile, controllerCtrl.controllers).Register(mux)
+		}
+		srv, err := metricsserver.New(metricsserver.Config{
+			Host:         cfg.MetricsBindAddress,
+			Port:         runCfg.PrometheusPort,
+			CertFile:     cfg.MetricsTLSCertFile,
+			KeyFile:      cfg.MetricsTLSKeyFile,
+			ClientCAFile: cfg.MetricsClientCAFile,
+		}, mux)
+		if err != nil {
+			log.WithError(err).WithField(termination.CodeField, termination.CodeMetricsServer).Fatal("Failed to configure prometheus metrics server")
+		}
+		log.WithFields(log.Fields{"address": srv.Addr, "tls": srv.TLSConfig != nil}).Info("Starting Prometheus metrics server")
 		go func() {
-			mux := http.NewServeMux()
-			mux.Handle("/metrics", promhttp.Handler())
-			err := http.ListenAndServe(fmt.Sprintf(":%d", runCfg.PrometheusPort), mux)
+			err := metricsserver.ListenAndServe(srv)
 			if err != nil {
-				log.WithError(err).Fatal("Failed to serve prometheus metrics")
+				log.WithError(err).WithField(termination.CodeField, termination.CodeMetricsServer).Fatal("Failed to serve prometheus metrics")
 			}
 		}()
 	}
 
+	if runCfg.PrometheusPort == 0 && cfg.AdminTokenFile != "" {
+		log.Warn("Admin API is configured but the Prometheus metrics server that hosts it is disabled")
+	}
+
+	if cfg.StatsdAddress != "" {
+		// Push metrics to StatsD, in addition to serving them to Prometheus.
+		pusher, err := statsd.NewPusher(cfg.StatsdAddress, cfg.StatsdPrefix, prometheus.DefaultGatherer)
+		if err != nil {
+			log.WithError(err).Error("Failed to set up StatsD metrics")
+		} else {
+			log.WithField("address", cfg.StatsdAddress).Info("Pushing metrics to StatsD")
+			go pusher.Run(ctx, cfg.StatsdPushInterval)
+		}
+	}
+
 	if runCfg.DebugProfilePort != 0 {
 		debugserver.StartDebugPprofServer("0.0.0.0", int(runCfg.DebugProfilePort))
 	}
@@ -313,47 +404,89 @@ func startCompactor(ctx context.Context, interval time.Duration) {
 
 	// Kick off a periodic compaction of etcd, retry until success.
 	for {
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			// carry on
-		}
 		etcdClient, err := newEtcdV3Client()
 		if err != nil {
 			log.WithError(err).Error("Failed to start etcd compaction routine, retry in 1m")
-			time.Sleep(1 * time.Minute)
-			continue
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(1 * time.Minute):
+				continue
+			}
 		}
 
 		log.WithField("period", interval).Info("Starting periodic etcdv3 compaction")
 		etcd3.StartCompactor(ctx, etcdClient, interval)
-		break
+
+		// The compactor runs in the background until the context is cancelled, at which
+		// point we can release the client.
+		<-ctx.Done()
+		log.Info("Stopping periodic etcdv3 compaction")
+		if err := etcdClient.Close(); err != nil {
+			log.WithError(err).Warn("Failed to close etcd client used for compaction")
+		}
+		return
 	}
 }
 
 // getClients builds and returns Kubernetes and Calico clients.
-func getClients(kubeconfig string) (*kubernetes.Clientset, client.Interface, error) {
+func getClients(cfg *config.Config) (*kubernetes.Clientset, dynamic.Interface, *datastore.ReconnectingClient, error) {
 	// Get Calico client
-	calicoClient, err := client.NewFromEnv()
+	apiConfig, err := apiconfig.LoadClientConfigFromEnvironment()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load Calico client config: %s", err)
+	}
+	if cfg.KubeClientQPS > 0 {
+		apiConfig.Spec.K8sClientQPS = cfg.KubeClientQPS
+	}
+	if cfg.KubeClientBurst > 0 {
+		apiConfig.Spec.K8sClientBurst = cfg.KubeClientBurst
+	}
+	calicoClient, err := datastore.NewReconnectingClient(func() (client.Interface, error) {
+		return client.New(*apiConfig)
+	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to build Calico client: %s", err)
+		return nil, nil, nil, fmt.Errorf("failed to build Calico client: %s", err)
 	}
 
 	// Now build the Kubernetes client, we support in-cluster config and kubeconfig
 	// as means of configuring the client.
-	k8sconfig, err := winutils.BuildConfigFromFlags("", kubeconfig)
+	k8sconfig, err := winutils.BuildConfigFromFlags("", cfg.Kubeconfig)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to build kubernetes client config: %s", err)
+		return nil, nil, nil, fmt.Errorf("failed to build kubernetes client config: %s", err)
+	}
+
+	// Allow the cluster admin to identify our requests, and report when they are throttled by
+	// API Priority and Fairness.
+	apf.Configure(k8sconfig, cfg.KubeClientUserAgent, cfg.KubeClientTimeout)
+
+	// Use protobuf rather than JSON, which is much cheaper to encode and decode for both us and
+	// the API server. The clientset only handles built-in types, which all support protobuf.
+	k8sconfig.AcceptContentTypes = strings.Join([]string{runtime.ContentTypeProtobuf, runtime.ContentTypeJSON}, ",")
+	k8sconfig.ContentType = runtime.ContentTypeProtobuf
+
+	// Allow the client rate limits to be raised for large clusters, or lowered to protect the
+	// API server.
+	if cfg.KubeClientQPS > 0 {
+		k8sconfig.QPS = cfg.KubeClientQPS
+	}
+	if cfg.KubeClientBurst > 0 {
+		k8sconfig.Burst = cfg.KubeClientBurst
 	}
 
 	// Get Kubernetes clientset
 	k8sClientset, err := kubernetes.NewForConfig(k8sconfig)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to build kubernetes client: %s", err)
+		return nil, nil, nil, fmt.Errorf("failed to build kubernetes client: %s", err)
 	}
 
-	return k8sClientset, calicoClient, nil
+	// The dynamic client always uses JSON, which is all that custom resources support.
+	dynamicClient, err := dynamic.NewForConfig(k8sconfig)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to build kubernetes dynamic client: %s", err)
+	}
+
+	return k8sClientset, dynamicClient, calicoClient, nil
 }
 
 // Returns an etcdv3 client based on the environment. The client will be configured to
@@ -372,7 +505,7 @@ func newEtcdV3Client() (*clientv3.Client, error) {
 	// Split the endpoints into a location slice.
 	etcdLocation := []string{}
 	if config.Spec.EtcdEndpoints != "" {
-		etcdLocation = strings.Split(config.Spec.EtcdEndpoints, ",")
+		etcdLocation = etcdv3.ParseEndpoints(config.Spec.EtcdEndpoints)
 	}
 
 	if config.Spec.EtcdDiscoverySrv != "" {
@@ -398,7 +531,6 @@ func newEtcdV3Client() (*clientv3.Client, error) {
 	if err != nil {
 		return nil, err
 	}
-
 	baseTLSConfig := tls.NewTLSConfig()
 	tlsClient.MaxVersion = baseTLSConfig.MaxVersion
 	tlsClient.MinVersion = baseTLSConfig.MinVersion
@@ -407,18 +539,28 @@ func newEtcdV3Client() (*clientv3.Client, error) {
 	tlsClient.Renegotiation = baseTLSConfig.Renegotiation
 
 	cfg := clientv3.Config{
-		Endpoints:   etcdLocation,
-		TLS:         tlsClient,
-		DialTimeout: 10 * time.Second,
+		Endpoints:            etcdLocation,
+		TLS:                  tlsClient,
+		DialTimeout:          10 * time.Second,
+		DialKeepAliveTime:    30 * time.Second,
+		DialKeepAliveTimeout: 10 * time.Second,
+	}
+	if config.Spec.EtcdCACertFile != "" {
+		etcdv3.EnableCAReload(&cfg, config.Spec.EtcdCACertFile)
 	}
 
 	// Plumb through the username and password if both are configured.
-	if config.Spec.EtcdUsername != "" && config.Spec.EtcdPassword != "" {
-		cfg.Username = config.Spec.EtcdUsername
-		cfg.Password = config.Spec.EtcdPassword
+	cfg.Username, cfg.Password, err = etcdv3.Credentials(&config.Spec.EtcdConfig)
+	if err != nil {
+		return nil, err
 	}
 
-	return clientv3.New(cfg)
+	client, err := clientv3.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	etcdv3.MonitorEndpoints(client, etcdLocation)
+	return client, nil
 }
 
 // Object for keeping track of controller states and statuses.
@@ -428,6 +570,9 @@ type controllerControl struct {
 	stop        chan struct{}
 	restart     <-chan config.RunConfig
 	informers   []cache.SharedIndexInformer
+
+	// For the custom resources that the controllers read from the Kubernetes API.
+	dynamicClient dynamic.Interface
 }
 
 func (cc *controllerControl) InitControllers(ctx context.Context, cfg config.RunConfig, k8sClientset *kubernetes.Clientset, calicoClient client.Interface) {
@@ -460,6 +605,58 @@ func (cc *controllerControl) InitControllers(ctx context.Context, cfg config.Run
 		serviceAccountController := serviceaccount.NewServiceAccountController(ctx, k8sClientset, calicoClient, *cfg.Controllers.ServiceAccount)
 		cc.controllers["ServiceAccount"] = serviceAccountController
 	}
+	if cfg.Controllers.SystemPolicy != nil {
+		systemPolicyController := systempolicy.NewSystemPolicyController(ctx, calicoClient, *cfg.Controllers.SystemPolicy)
+		cc.controllers["SystemPolicy"] = systemPolicyController
+	}
+	if cfg.Controllers.NamespaceIPPool != nil {
+		namespacePoolController := namespacepool.NewNamespacePoolController(ctx, k8sClientset, calicoClient, *cfg.Controllers.NamespaceIPPool)
+		cc.controllers["NamespaceIPPool"] = namespacePoolController
+	}
+	if cfg.Controllers.PoolAssignment != nil {
+		poolAssignmentController := poolassignment.NewPoolAssignmentController(ctx, k8sClientset, calicoClient, *cfg.Controllers.PoolAssignment)
+		cc.controllers["PoolAssignment"] = poolAssignmentController
+	}
+	if cfg.Controllers.LoadBalancer != nil {
+		loadBalancerController := loadbalancer.NewLoadBalancerController(ctx, k8sClientset, calicoClient, *cfg.Controllers.LoadBalancer)
+		cc.controllers["LoadBalancer"] = loadBalancerController
+	}
+	if cfg.Controllers.FelixConfig != nil {
+		felixConfigController := felixconfig.NewFelixConfigController(ctx, k8sClientset, calicoClient, *cfg.Controllers.FelixConfig)
+		cc.controllers["FelixConfig"] = felixConfigController
+	}
+	if cfg.Controllers.ThreatFeed != nil {
+		threatFeedController := threatfeed.NewThreatFeedController(ctx, calicoClient, *cfg.Controllers.ThreatFeed)
+		cc.controllers["ThreatFeed"] = threatFeedController
+	}
+	if cfg.Controllers.NetworkSet != nil {
+		networkSetController := networkset.NewNetworkSetController(ctx, k8sClientset, calicoClient, *cfg.Controllers.NetworkSet)
+		cc.controllers["NetworkSet"] = networkSetController
+	}
+	if cfg.Controllers.DNSNetworkSet != nil {
+		dnsNetworkSetController := dnsnetworkset.NewDNSNetworkSetController(ctx, calicoClient, *cfg.Controllers.DNSNetworkSet)
+		cc.controllers["DNSNetworkSet"] = dnsNetworkSetController
+	}
+	if cfg.Controllers.ServiceNetworkSet != nil {
+		serviceNetworkSetController := servicenetworkset.NewServiceNetworkSetController(ctx, k8sClientset, calicoClient, *cfg.Controllers.ServiceNetworkSet)
+		cc.controllers["ServiceNetworkSet"] = serviceNetworkSetController
+	}
+	if cfg.Controllers.ExternalHost != nil {
+		externalHostController := externalhost.NewExternalHostController(ctx, k8sClientset, cc.dynamicClient, calicoClient, *cfg.Controllers.ExternalHost)
+		cc.controllers["ExternalHost"] = externalHostController
+	}
+	if cfg.ConsistencyCheckPeriod > 0 && len(consistency.Invariants(cfg.Controllers)) > 0 {
+		consistencyChecker := consistency.NewConsistencyChecker(ctx, k8sClientset, calicoClient, cfg.ConsistencyCheckPeriod, cfg.Controllers)
+		cc.controllers["ConsistencyChecker"] = consistencyChecker
+	}
+	if cfg.DuplicateIPCheckPeriod > 0 {
+		duplicateIPChecker := duplicateip.NewDuplicateIPChecker(ctx, k8sClientset, calicoClient, cfg.DuplicateIPCheckPeriod, cfg.ReleaseDuplicateIPClaims)
+		cc.controllers["DuplicateIPChecker"] = duplicateIPChecker
+	}
+	if cfg.WireguardKeyCheckPeriod > 0 {
+		wireguardKeyChecker := wireguardkeys.NewWireguardKeyChecker(ctx, k8sClientset, calicoClient, cfg.WireguardKeyCheckPeriod, cfg.ClearStaleWireguardKeys)
+		cc.controllers["WireguardKeyChecker"] = wireguardKeyChecker
+	}
 }
 
 // registerInformers registers the given informers, if not already registered. Registered informers
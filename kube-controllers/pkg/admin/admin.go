@@ -0,0 +1,174 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin provides authenticated HTTP endpoints for operating a running kube-controllers.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/cache"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+)
+
+// ResyncPath is the path of the endpoint that triggers an immediate resync of a controller.
+const ResyncPath = "/admin/resync"
+
+// ConfirmDeletesPath is the path of the endpoint that confirms a mass delete withheld by a
+// controller's reconciler.
+const ConfirmDeletesPath = "/admin/confirm-deletes"
+
+// DumpPath is the path of the endpoint that dumps the caches and queues of the controllers.
+const DumpPath = "/admin/dump"
+
+// Handler serves the admin endpoints.
+type Handler struct {
+	tokenFile   string
+	controllers map[string]controller.Controller
+	mux         *http.ServeMux
+}
+
+// NewHandler returns a handler for the admin endpoints of the given controllers, keyed by name.
+// Requests must carry a bearer token matching the contents of tokenFile. The file is re-read on
+// each request so that the token can be rotated without a restart.
+func NewHandler(tokenFile string, controllers map[string]controller.Controller) *Handler {
+	h := &Handler{
+		tokenFile:   tokenFile,
+		controllers: controllers,
+		mux:         http.NewServeMux(),
+	}
+	h.mux.HandleFunc(ResyncPath, h.resync)
+	h.mux.HandleFunc(ConfirmDeletesPath, h.confirmDeletes)
+	h.mux.HandleFunc(DumpPath, h.dump)
+	return h
+}
+
+// Register adds the admin endpoints to the given mux.
+func (h *Handler) Register(mux *http.ServeMux) {
+	mux.Handle("/admin/", h)
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) authorized(r *http.Request) bool {
+	b, err := os.ReadFile(h.tokenFile)
+	if err != nil {
+		log.WithError(err).Warn("Failed to read admin token file, rejecting request")
+		return false
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		log.Warn("Admin token file is empty, rejecting request")
+		return false
+	}
+	provided, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(token)) == 1
+}
+
+// resync handles POST /admin/resync?controller=<name>.
+func (h *Handler) resync(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := r.URL.Query().Get("controller")
+	c, ok := h.controllers[name]
+	if !ok {
+		http.Error(w, "unknown controller "+name, http.StatusNotFound)
+		return
+	}
+	rs, ok := c.(controller.Resyncer)
+	if !ok {
+		http.Error(w, "controller "+name+" does not support resync", http.StatusBadRequest)
+		return
+	}
+	log.WithField("controller", name).Info("Resync requested through admin API")
+	rs.Resync()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// confirmDeletes handles POST /admin/confirm-deletes?controller=<name>.
+func (h *Handler) confirmDeletes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := r.URL.Query().Get("controller")
+	c, ok := h.controllers[name]
+	if !ok {
+		http.Error(w, "unknown controller "+name, http.StatusNotFound)
+		return
+	}
+	dc, ok := c.(controller.DeleteConfirmer)
+	if !ok {
+		http.Error(w, "controller "+name+" does not support confirming deletes", http.StatusBadRequest)
+		return
+	}
+	log.WithField("controller", name).Warn("Mass delete confirmed through admin API")
+	dc.ConfirmDeletes()
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// dump handles GET /admin/dump, optionally restricted to a single controller with
+// ?controller=<name>. It returns the cache keys, the outstanding updates and their retry counts
+// for each controller, keyed by controller name.
+func (h *Handler) dump(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	dumps := map[string]cache.Dump{}
+	if name := r.URL.Query().Get("controller"); name != "" {
+		c, ok := h.controllers[name]
+		if !ok {
+			http.Error(w, "unknown controller "+name, http.StatusNotFound)
+			return
+		}
+		d, ok := c.(controller.Dumper)
+		if !ok {
+			http.Error(w, "controller "+name+" does not support dumps", http.StatusBadRequest)
+			return
+		}
+		dumps[name] = d.Dump()
+	} else {
+		for name, c := range h.controllers {
+			if d, ok := c.(controller.Dumper); ok {
+				dumps[name] = d.Dump()
+			}
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(dumps); err != nil {
+		log.WithError(err).Warn("Failed to write dump")
+	}
+}
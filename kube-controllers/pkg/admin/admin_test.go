@@ -0,0 +1,155 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/admin"
+	"github.com/projectcalico/calico/kube-controllers/pkg/cache"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+)
+
+type plainController struct{}
+
+func (plainController) Run(stopCh chan struct{}) {}
+
+type resyncingController struct {
+	plainController
+	resyncs  int
+	confirms int
+}
+
+func (c *resyncingController) Resync() { c.resyncs++ }
+
+func (c *resyncingController) ConfirmDeletes() { c.confirms++ }
+
+func (c *resyncingController) Dump() cache.Dump {
+	return cache.Dump{Keys: []string{"ns1"}, Queue: []cache.QueueItem{{Key: "ns1", State: cache.QueueStateQueued, Retries: 2}}}
+}
+
+var _ = Describe("Admin API", func() {
+	var (
+		tokenDir  string
+		tokenFile string
+		secret    string
+		resyncer  *resyncingController
+		mux       *http.ServeMux
+	)
+
+	BeforeEach(func() {
+		// Generate a token for each test rather than relying on a fixed one.
+		b := make([]byte, 16)
+		_, err := rand.Read(b)
+		Expect(err).NotTo(HaveOccurred())
+		secret = hex.EncodeToString(b)
+
+		tokenDir, err = os.MkdirTemp("", "admin-token")
+		Expect(err).NotTo(HaveOccurred())
+		tokenFile = filepath.Join(tokenDir, "token")
+		Expect(os.WriteFile(tokenFile, []byte(secret+"\n"), 0o600)).To(Succeed())
+		resyncer = &resyncingController{}
+		mux = http.NewServeMux()
+		admin.NewHandler(tokenFile, map[string]controller.Controller{
+			"Namespace": resyncer,
+			"Node":      plainController{},
+		}).Register(mux)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tokenDir)).To(Succeed())
+	})
+
+	serve := func(method, target, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	do := func(method, target, token string) int {
+		return serve(method, target, token).Code
+	}
+
+	It("should trigger a resync of the named controller", func() {
+		Expect(do(http.MethodPost, "/admin/resync?controller=Namespace", secret)).To(Equal(http.StatusAccepted))
+		Expect(resyncer.resyncs).To(Equal(1))
+	})
+
+	It("should reject requests without a valid token", func() {
+		Expect(do(http.MethodPost, "/admin/resync?controller=Namespace", "")).To(Equal(http.StatusUnauthorized))
+		Expect(do(http.MethodPost, "/admin/resync?controller=Namespace", "wrong")).To(Equal(http.StatusUnauthorized))
+		Expect(resyncer.resyncs).To(BeZero())
+	})
+
+	It("should pick up a rotated token", func() {
+		Expect(os.WriteFile(tokenFile, []byte("rotated"), 0o600)).To(Succeed())
+		Expect(do(http.MethodPost, "/admin/resync?controller=Namespace", secret)).To(Equal(http.StatusUnauthorized))
+		Expect(do(http.MethodPost, "/admin/resync?controller=Namespace", "rotated")).To(Equal(http.StatusAccepted))
+	})
+
+	It("should reject all requests when the token file is empty or missing", func() {
+		Expect(os.WriteFile(tokenFile, nil, 0o600)).To(Succeed())
+		Expect(do(http.MethodPost, "/admin/resync?controller=Namespace", "")).To(Equal(http.StatusUnauthorized))
+		Expect(os.Remove(tokenFile)).To(Succeed())
+		Expect(do(http.MethodPost, "/admin/resync?controller=Namespace", secret)).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should only accept POST", func() {
+		Expect(do(http.MethodGet, "/admin/resync?controller=Namespace", secret)).To(Equal(http.StatusMethodNotAllowed))
+		Expect(resyncer.resyncs).To(BeZero())
+	})
+
+	It("should reject unknown controllers and controllers that cannot resync", func() {
+		Expect(do(http.MethodPost, "/admin/resync?controller=Bogus", secret)).To(Equal(http.StatusNotFound))
+		Expect(do(http.MethodPost, "/admin/resync?controller=Node", secret)).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should confirm deletes for the named controller", func() {
+		Expect(do(http.MethodPost, "/admin/confirm-deletes?controller=Namespace", secret)).To(Equal(http.StatusAccepted))
+		Expect(resyncer.confirms).To(Equal(1))
+
+		Expect(do(http.MethodGet, "/admin/confirm-deletes?controller=Namespace", secret)).To(Equal(http.StatusMethodNotAllowed))
+		Expect(do(http.MethodPost, "/admin/confirm-deletes?controller=Namespace", "")).To(Equal(http.StatusUnauthorized))
+		Expect(do(http.MethodPost, "/admin/confirm-deletes?controller=Bogus", secret)).To(Equal(http.StatusNotFound))
+		Expect(do(http.MethodPost, "/admin/confirm-deletes?controller=Node", secret)).To(Equal(http.StatusBadRequest))
+		Expect(resyncer.confirms).To(Equal(1))
+	})
+
+	It("should dump the controllers that support it", func() {
+		rec := serve(http.MethodGet, "/admin/dump", secret)
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		var dumps map[string]cache.Dump
+		Expect(json.Unmarshal(rec.Body.Bytes(), &dumps)).To(Succeed())
+		Expect(dumps).To(Equal(map[string]cache.Dump{
+			"Namespace": {Keys: []string{"ns1"}, Queue: []cache.QueueItem{{Key: "ns1", State: cache.QueueStateQueued, Retries: 2}}},
+		}))
+
+		Expect(do(http.MethodGet, "/admin/dump?controller=Namespace", secret)).To(Equal(http.StatusOK))
+		Expect(do(http.MethodGet, "/admin/dump?controller=Node", secret)).To(Equal(http.StatusBadRequest))
+		Expect(do(http.MethodGet, "/admin/dump", "")).To(Equal(http.StatusUnauthorized))
+	})
+})
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apf
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	flowcontrolv1beta3 "k8s.io/api/flowcontrol/v1beta3"
+	"k8s.io/client-go/rest"
+)
+
+// How often to log that requests are being throttled, to avoid flooding the logs while the
+// API server is overloaded.
+var throttleLogInterval = time.Minute
+
+var throttledCounter *prometheus.CounterVec
+
+func init() {
+	throttledCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kube_client_throttled_requests_total",
+		Help: "Number of Kubernetes API requests rejected with 429 Too Many Requests, by API Priority and Fairness priority level",
+	}, []string{"priority_level_uid"})
+	prometheus.MustRegister(throttledCounter)
+}
+
+// Configure sets the user agent and request timeout of the given Kubernetes client config, and
+// wraps its transport so that requests throttled by API Priority and Fairness are counted and
+// logged. The client already retries throttled requests after the delay requested by the API
+// server, so this just makes the throttling visible.
+//
+// An empty userAgent leaves the default user agent in place, and a zero timeout means requests
+// don't time out.
+func Configure(cfg *rest.Config, userAgent string, timeout time.Duration) {
+	if userAgent != "" {
+		cfg.UserAgent = userAgent
+	}
+	if timeout > 0 {
+		cfg.Timeout = timeout
+	}
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &throttleRoundTripper{rt: rt}
+	})
+}
+
+type throttleRoundTripper struct {
+	rt http.RoundTripper
+
+	lock    sync.Mutex
+	lastLog time.Time
+}
+
+func (t *throttleRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.rt.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+
+	priorityLevel := resp.Header.Get(flowcontrolv1beta3.ResponseHeaderMatchedPriorityLevelConfigurationUID)
+	throttledCounter.WithLabelValues(priorityLevel).Inc()
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if time.Since(t.lastLog) >= throttleLogInterval {
+		t.lastLog = time.Now()
+		log.WithFields(log.Fields{
+			"url":           req.URL.Path,
+			"flowSchema":    resp.Header.Get(flowcontrolv1beta3.ResponseHeaderMatchedFlowSchemaUID),
+			"priorityLevel": priorityLevel,
+			"retryAfter":    resp.Header.Get("Retry-After"),
+		}).Warning("Kubernetes API server is throttling requests, they will be retried")
+	}
+	return resp, nil
+}
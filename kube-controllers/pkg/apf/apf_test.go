@@ -0,0 +1,130 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apf_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	flowcontrolv1beta3 "k8s.io/api/flowcontrol/v1beta3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/apf"
+)
+
+var _ = Describe("API Priority and Fairness client config", func() {
+	var server *httptest.Server
+	var requests int32
+	var userAgent atomic.Value
+
+	BeforeEach(func() {
+		requests = 0
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userAgent.Store(r.UserAgent())
+			// Throttle the first request, then succeed.
+			if atomic.AddInt32(&requests, 1) == 1 {
+				w.Header().Set(flowcontrolv1beta3.ResponseHeaderMatchedPriorityLevelConfigurationUID, "pl-uid")
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Success"}`))
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should set the user agent and timeout", func() {
+		cfg := &rest.Config{}
+		apf.Configure(cfg, "calico-kube-controllers/test", 30*time.Second)
+		Expect(cfg.UserAgent).To(Equal("calico-kube-controllers/test"))
+		Expect(cfg.Timeout).To(Equal(30 * time.Second))
+	})
+
+	It("should leave the defaults if not configured", func() {
+		cfg := &rest.Config{UserAgent: "default"}
+		apf.Configure(cfg, "", 0)
+		Expect(cfg.UserAgent).To(Equal("default"))
+		Expect(cfg.Timeout).To(BeZero())
+	})
+
+	It("should retry throttled requests", func() {
+		cfg := &rest.Config{
+			Host: server.URL,
+			ContentConfig: rest.ContentConfig{
+				GroupVersion:         &schema.GroupVersion{Version: "v1"},
+				NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+			},
+		}
+		apf.Configure(cfg, "calico-kube-controllers/test", 0)
+		rc, err := rest.UnversionedRESTClientFor(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(rc.Get().AbsPath("/api").Do(context.Background()).Error()).NotTo(HaveOccurred())
+		Expect(atomic.LoadInt32(&requests)).To(Equal(int32(2)))
+		Expect(userAgent.Load()).To(Equal("calico-kube-controllers/test"))
+	})
+
+	It("should report the client's request metrics", func() {
+		cfg := &rest.Config{
+			Host: server.URL,
+			ContentConfig: rest.ContentConfig{
+				GroupVersion:         &schema.GroupVersion{Version: "v1"},
+				NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+			},
+		}
+		apf.Configure(cfg, "", 0)
+		rc, err := rest.UnversionedRESTClientFor(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rc.Get().AbsPath("/api").Do(context.Background()).Error()).NotTo(HaveOccurred())
+
+		// Returns the total of the samples of the given metric with the given label.
+		total := func(name, label, value string) float64 {
+			mfs, err := prometheus.DefaultGatherer.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			var sum float64
+			for _, mf := range mfs {
+				if mf.GetName() != name {
+					continue
+				}
+				for _, m := range mf.GetMetric() {
+					for _, l := range m.GetLabel() {
+						if l.GetName() == label && l.GetValue() == value {
+							sum += m.GetCounter().GetValue() + float64(m.GetHistogram().GetSampleCount())
+						}
+					}
+				}
+			}
+			return sum
+		}
+		Expect(total("kube_client_requests_total", "code", "429")).To(BeNumerically(">=", 1))
+		Expect(total("kube_client_requests_total", "code", "200")).To(BeNumerically(">=", 1))
+		Expect(total("kube_client_request_retries_total", "method", "GET")).To(BeNumerically(">=", 1))
+		Expect(total("kube_client_request_duration_seconds", "verb", "GET")).To(BeNumerically(">=", 1))
+		Expect(total("kube_client_rate_limiter_duration_seconds", "verb", "GET")).To(BeNumerically(">=", 1))
+	})
+})
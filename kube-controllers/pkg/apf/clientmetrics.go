@@ -0,0 +1,90 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apf
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/tools/metrics"
+)
+
+var (
+	requestLatency     *prometheus.HistogramVec
+	rateLimiterLatency *prometheus.HistogramVec
+	requestResults     *prometheus.CounterVec
+	requestRetries     *prometheus.CounterVec
+)
+
+func init() {
+	requestLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kube_client_request_duration_seconds",
+		Help:    "Latency of requests to the Kubernetes API server, by verb and host",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"verb", "host"})
+	rateLimiterLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kube_client_rate_limiter_duration_seconds",
+		Help:    "Time that requests to the Kubernetes API server were delayed by the client-side rate limiter, by verb and host",
+		Buckets: prometheus.ExponentialBuckets(0.005, 2, 14),
+	}, []string{"verb", "host"})
+	requestResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kube_client_requests_total",
+		Help: "Number of requests to the Kubernetes API server, by status code, method and host",
+	}, []string{"code", "method", "host"})
+	requestRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kube_client_request_retries_total",
+		Help: "Number of requests to the Kubernetes API server that were retried, by status code, method and host",
+	}, []string{"code", "method", "host"})
+	prometheus.MustRegister(requestLatency, rateLimiterLatency, requestResults, requestRetries)
+
+	// Have client-go report into the metrics above. This only takes effect for the first call,
+	// so it must happen before anything else registers client-go metrics.
+	metrics.Register(metrics.RegisterOpts{
+		RequestLatency:     latencyAdapter{requestLatency},
+		RateLimiterLatency: latencyAdapter{rateLimiterLatency},
+		RequestResult:      resultAdapter{requestResults},
+		RequestRetry:       retryAdapter{requestRetries},
+	})
+}
+
+// latencyAdapter implements client-go's LatencyMetric. The URL path isn't used as a label, since
+// it includes the names of individual resources.
+type latencyAdapter struct {
+	m *prometheus.HistogramVec
+}
+
+func (l latencyAdapter) Observe(_ context.Context, verb string, u url.URL, latency time.Duration) {
+	l.m.WithLabelValues(verb, u.Host).Observe(latency.Seconds())
+}
+
+// resultAdapter implements client-go's ResultMetric.
+type resultAdapter struct {
+	m *prometheus.CounterVec
+}
+
+func (r resultAdapter) Increment(_ context.Context, code, method, host string) {
+	r.m.WithLabelValues(code, method, host).Inc()
+}
+
+// retryAdapter implements client-go's RetryMetric.
+type retryAdapter struct {
+	m *prometheus.CounterVec
+}
+
+func (r retryAdapter) IncrementRetry(_ context.Context, code, method, host string) {
+	r.m.WithLabelValues(code, method, host).Inc()
+}
@@ -16,29 +16,33 @@ package cache
 
 import (
 	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/patrickmn/go-cache"
 	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/workqueue"
 )
 
 // ResourceCache stores resources and queues updates when those resources
 // are created, modified, or deleted. It de-duplicates updates by ensuring
 // updates are only queued when an object has changed.
-type ResourceCache interface {
+type ResourceCache[T any] interface {
 	// Set sets the key to the provided value, and generates an update
 	// on the queue the value has changed.
-	Set(key string, value interface{})
+	Set(key string, value T)
 
-	// Get gets the value associated with the given key.  Returns nil
-	// if the key is not present.
-	Get(key string) (interface{}, bool)
+	// Get gets the value associated with the given key.  Returns the zero
+	// value if the key is not present.
+	Get(key string) (T, bool)
 
 	// Prime sets the key to the provided value, but does not generate
 	// and update on the queue ever.
-	Prime(key string, value interface{})
+	Prime(key string, value T)
 
 	// Delete deletes the value identified by the given key from the cache, and
 	// generates an update on the queue if a value was deleted.
@@ -51,6 +55,17 @@ type ResourceCache interface {
 	// ListKeys lists the keys currently in the cache.
 	ListKeys() []string
 
+	// ListKeysWithPrefix lists the keys currently in the cache that start with the given
+	// prefix.
+	ListKeysWithPrefix(prefix string) []string
+
+	// ListKeysMatching lists the keys currently in the cache that match the given regular
+	// expression.
+	ListKeysMatching(re *regexp.Regexp) []string
+
+	// GetAll returns a snapshot of the keys and values currently in the cache.
+	GetAll() map[string]T
+
 	// Run enables the generation of events on the output queue starts
 	// cache reconciliation.
 	Run(reconcilerPeriod string)
@@ -58,21 +73,119 @@ type ResourceCache interface {
 	// GetQueue returns the cache's output queue, which emits a stream
 	// of any keys which have been created, modified, or deleted.
 	GetQueue() workqueue.RateLimitingInterface
+
+	// Synced indicates that the update for the given key has been written to
+	// the datastore, so that the sync lag can be measured.
+	Synced(key string)
+
+	// Resync triggers a reconciliation with the datastore now, rather than
+	// waiting for the next periodic one.
+	Resync()
+
+	// Dump returns a snapshot of the keys in the cache and the outstanding
+	// updates on its queue.
+	Dump() Dump
+
+	// ConfirmDeletes allows the next reconciliation to delete keys from the datastore even if
+	// there are more of them than the mass delete threshold, and triggers it now.
+	ConfirmDeletes()
+
+	// Quarantine moves a key whose update has repeatedly failed to be written to the datastore
+	// to the quarantine, from which it's queued again on a slow schedule until it's Synced.
+	Quarantine(key string)
+
+	// IsQuarantined returns whether the given key is quarantined.
+	IsQuarantined(key string) bool
 }
 
+// DiffReason describes how the reconciler found a key to be out of sync with the datastore.
+type DiffReason string
+
+const (
+	// DiffChanged means the value in the datastore differs from the cached value.
+	DiffChanged DiffReason = "changed"
+
+	// DiffMissingInDatastore means the key is cached but not in the datastore.
+	DiffMissingInDatastore DiffReason = "missing_in_datastore"
+
+	// DiffMissingInCache means the key is in the datastore but not cached.
+	DiffMissingInCache DiffReason = "missing_in_cache"
+)
+
 // ResourceCacheArgs struct passed to constructor of ResourceCache.
 // Groups together all the arguments to pass in single struct.
-type ResourceCacheArgs struct {
+type ResourceCacheArgs[T any] struct {
 	// ListFunc returns a mapping of keys to objects from the Calico datastore.
-	ListFunc func() (map[string]interface{}, error)
-
-	// ObjectType is the type of object which is to be stored in this cache.
-	ObjectType reflect.Type
+	ListFunc func() (map[string]T, error)
 
 	// LogTypeDesc (optional) to log the type of object stored in the cache.
-	// If not provided it is derived from the ObjectType.
+	// If not provided it is derived from the type of object.
 	LogTypeDesc string
 
+	// ControllerName (optional) is the name of the controller that owns the cache, used to
+	// label the metrics of its workqueue and its sync lag. Neither are recorded if not provided.
+	ControllerName string
+
+	// SourceGetFunc (optional) returns the value that the given key should have, read directly
+	// from the source of truth (typically the Kubernetes API) rather than through an informer.
+	// It returns false if the key should not exist. Used for spot checks.
+	SourceGetFunc func(key string) (T, bool, error)
+
+	// DatastoreGetFunc (optional) returns the value of the given key in the Calico datastore,
+	// in the same form as the values returned by ListFunc. Used for spot checks.
+	DatastoreGetFunc func(key string) (T, bool, error)
+
+	// OnSet (optional) is called when Set stores a new or changed value for a key.
+	OnSet func(key string, value T)
+
+	// OnDelete (optional) is called when Delete removes a key.
+	OnDelete func(key string)
+
+	// OnReconcileDiff (optional) is called for each key that the reconciler finds out of sync
+	// with the datastore and queues for repair.
+	//
+	// The hooks are called synchronously, so they must not block or call back into the cache.
+	OnReconcileDiff func(key string, reason DiffReason)
+
+	// EqualFunc (optional) reports whether two values for a key are the same, and so whether an
+	// update is needed. It's used both to compare new values with cached ones and cached values
+	// with the datastore, so it can be used to ignore fields populated by the server, such as
+	// the UID and resource version. Defaults to reflect.DeepEqual.
+	EqualFunc func(a, b T) bool
+
+	// PrioritizeDeletes makes the output queue hand out keys that are no longer in the cache,
+	// which are to be deleted from the datastore, before keys that are to be created or updated.
+	// This means that, for example, a renamed resource's old copy is removed before its new copy
+	// is written, so the two are never in effect at the same time.
+	PrioritizeDeletes bool
+
+	// Shards (optional) is the number of queues to spread keys across by hash, when greater
+	// than one. Each key is always queued on the same shard, so updates to it stay in order, and
+	// the queue returned by GetQueue hands out keys from the shards in turn, so that a burst of
+	// updates to some keys can't hold up the rest.
+	Shards int
+
+	// CoalesceWindow (optional) delays the update queued by Set or Delete by this long, when
+	// greater than zero. Further changes to the key within the window are merged into the same
+	// update, so a burst of changes results in a single write of the final value.
+	CoalesceWindow time.Duration
+
+	// MaxQueueDepth (optional) applies backpressure when greater than zero. While the output
+	// queue holds this many keys or more, for example because the datastore is unavailable,
+	// Set and Delete block until it drains below the limit. This pauses the informer event
+	// handlers that call them, rather than letting the backlog grow without bound.
+	MaxQueueDepth int
+
+	// SnapshotStore (optional) persists the contents of the datastore listed by each
+	// reconciliation. When the cache starts, its first reconciliation uses the saved snapshot
+	// rather than listing the datastore, to avoid a full list of the datastore on every restart.
+	// Later reconciliations list the datastore as usual. The values must be serializable as JSON.
+	SnapshotStore SnapshotStore
+
+	// QuarantineRetryPeriod (optional) is how often keys that have been quarantined after
+	// repeatedly failing to sync are queued again. Defaults to 10 minutes.
+	QuarantineRetryPeriod time.Duration
+
 	ReconcilerConfig ReconcilerConfig
 }
 
@@ -89,92 +202,301 @@ type ReconcilerConfig struct {
 	// DisableMissingInCache disables queueing of updates when reconciler detects
 	// that a value that is still in the datastore no longer is in the cache.
 	DisableMissingInCache bool
+
+	// MaxReconcilerPeriod enables an adaptive reconciler period when it is greater than the
+	// configured period. Each reconciliation that finds no drift doubles the period, up to
+	// this maximum, and any drift resets it back to the configured period.
+	MaxReconcilerPeriod time.Duration
+
+	// ReconcilerJitter randomly lengthens each wait between periodic reconciliations by up to
+	// this fraction of the period, so that caches started at the same time, in this or other
+	// instances, don't all list the datastore at the same instant. Zero disables jitter.
+	ReconcilerJitter float64
+
+	// ReconcileAtStartup performs a single reconciliation when the cache starts, even if the
+	// periodic reconciler is disabled. This cleans up resources whose source was deleted while
+	// the controller wasn't running. The periodic reconciler always starts with a reconciliation.
+	ReconcileAtStartup bool
+
+	// SpotCheckPeriod enables periodic spot checks when greater than zero. Each spot check
+	// compares a random sample of SpotCheckSampleSize keys in the cache with the source of
+	// truth and the datastore, to catch the cache silently diverging from either of them.
+	SpotCheckPeriod     time.Duration
+	SpotCheckSampleSize int
+
+	// ResyncWindow enables throttled resyncs when greater than zero. If a reconciliation finds
+	// more than ResyncThreshold keys out of sync, the resulting writes are spread evenly over
+	// the window rather than queued all at once, to avoid overloading the datastore and
+	// churning the dataplane.
+	ResyncWindow    time.Duration
+	ResyncThreshold int
+
+	// MassDeleteThreshold enables the mass delete failsafe when greater than zero. If a
+	// reconciliation finds more than this fraction of the keys in the datastore missing from the
+	// cache, for example because the source of truth transiently returned an empty list, it
+	// doesn't delete any of them until the deletes are confirmed with ConfirmDeletes.
+	MassDeleteThreshold float64
+
+	// MaxSnapshotAge is the age beyond which a saved snapshot is ignored, and the first
+	// reconciliation lists the datastore instead. Zero means snapshots of any age are used.
+	MaxSnapshotAge time.Duration
 }
 
+// massDeleteMinKeys is the number of deletes below which the mass delete failsafe never triggers,
+// so that deleting a few keys from a small datastore doesn't need confirmation.
+const massDeleteMinKeys = 10
+
+// backpressurePollInterval is how often a paused Set or Delete checks whether the output queue has
+// drained.
+var backpressurePollInterval = 100 * time.Millisecond
+
+// startupRetryInterval is how long to wait before retrying a failed start of day reconciliation
+// when the periodic reconciler is disabled.
+var startupRetryInterval = 5 * time.Second
+
 // calicoCache implements the ResourceCache interface
-type calicoCache struct {
+type calicoCache[T any] struct {
 	threadSafeCache  *cache.Cache
 	workqueue        workqueue.RateLimitingInterface
-	ListFunc         func() (map[string]interface{}, error)
-	ObjectType       reflect.Type
+	trackers         []*trackingQueue
+	ListFunc         func() (map[string]T, error)
 	log              *log.Entry
 	running          bool
 	mut              *sync.Mutex
 	reconcilerConfig ReconcilerConfig
+	typeDesc         string
+	sourceGetFunc    func(key string) (T, bool, error)
+	datastoreGetFunc func(key string) (T, bool, error)
+	equal            func(a, b T) bool
+	maxQueueDepth    int
+	coalesceWindow   time.Duration
+	snapshots        SnapshotStore
+	controllerName   string
+	onSet            func(key string, value T)
+	onDelete         func(key string)
+	onReconcileDiff  func(key string, reason DiffReason)
+
+	// Time at which each key with an outstanding update was first queued, used to measure the
+	// sync lag. Protected by mut.
+	pending map[string]time.Time
+
+	// Signals the reconciler to reconcile now.
+	resync chan struct{}
+
+	// Whether the next reconciliation may delete more keys than the mass delete threshold.
+	// Protected by mut.
+	deletesConfirmed bool
+
+	// Keys that have repeatedly failed to sync, and when they were quarantined, and how often
+	// to retry them. Protected by mut.
+	quarantined           map[string]time.Time
+	quarantineRetryPeriod time.Duration
+
+	// Whether the saved snapshot has been considered, which only the first reconciliation does.
+	// Only accessed by the reconciler.
+	snapshotChecked bool
 }
 
 // NewResourceCache builds and returns a resource cache using the provided arguments.
-func NewResourceCache(args ResourceCacheArgs) ResourceCache {
+func NewResourceCache[T any](args ResourceCacheArgs[T]) ResourceCache[T] {
+	var c *calicoCache[T]
+	newQueue := func() workqueue.DelayingInterface {
+		if args.PrioritizeDeletes {
+			return workqueue.NewDelayingQueueWithConfig(workqueue.DelayingQueueConfig{
+				Name:  args.ControllerName,
+				Queue: newPriorityQueue(args.ControllerName, func(item interface{}) bool { return c.isDeletion(item) }),
+			})
+		}
+		return workqueue.NewNamedDelayingQueue(args.ControllerName)
+	}
+
+	// Track the contents of the queues so that they can be dumped. The shards share a rate
+	// limiter, so that sharding doesn't multiply the overall rate of retries.
+	var trackers []*trackingQueue
+	var queue workqueue.RateLimitingInterface
+	rateLimiter := workqueue.DefaultControllerRateLimiter()
+	if args.Shards > 1 {
+		var shards []workqueue.RateLimitingInterface
+		for i := 0; i < args.Shards; i++ {
+			tracker := newTrackingQueue(newQueue())
+			trackers = append(trackers, tracker)
+			shards = append(shards, workqueue.NewRateLimitingQueueWithDelayingInterface(tracker, rateLimiter))
+		}
+		queue = newShardedQueue(shards)
+	} else {
+		tracker := newTrackingQueue(newQueue())
+		trackers = append(trackers, tracker)
+		queue = workqueue.NewRateLimitingQueueWithDelayingInterface(tracker, rateLimiter)
+	}
+
+	objectType := reflect.TypeOf((*T)(nil)).Elem()
+
 	// Make sure logging is context aware.
-	return &calicoCache{
+	c = &calicoCache[T]{
 		threadSafeCache: cache.New(cache.NoExpiration, cache.DefaultExpiration),
-		workqueue:       workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		workqueue:       queue,
+		trackers:        trackers,
 		ListFunc:        args.ListFunc,
-		ObjectType:      args.ObjectType,
 		log: func() *log.Entry {
 			if args.LogTypeDesc == "" {
-				return log.WithFields(log.Fields{"type": args.ObjectType})
+				return log.WithFields(log.Fields{"type": objectType})
 			}
 			return log.WithFields(log.Fields{"type": args.LogTypeDesc})
 		}(),
 		mut:              &sync.Mutex{},
 		reconcilerConfig: args.ReconcilerConfig,
+		typeDesc: func() string {
+			if args.LogTypeDesc == "" {
+				return objectType.Name()
+			}
+			return args.LogTypeDesc
+		}(),
+		sourceGetFunc:    args.SourceGetFunc,
+		datastoreGetFunc: args.DatastoreGetFunc,
+		equal:            args.EqualFunc,
+		maxQueueDepth:    args.MaxQueueDepth,
+		coalesceWindow:   args.CoalesceWindow,
+		snapshots:        args.SnapshotStore,
+		controllerName:   args.ControllerName,
+		onSet:            args.OnSet,
+		onDelete:         args.OnDelete,
+		onReconcileDiff:  args.OnReconcileDiff,
+		pending:          map[string]time.Time{},
+		resync:           make(chan struct{}, 1),
+		quarantined:      map[string]time.Time{},
+
+		quarantineRetryPeriod: args.QuarantineRetryPeriod,
 	}
-}
-
-func (c *calicoCache) Set(key string, newObj interface{}) {
-	if reflect.TypeOf(newObj) != c.ObjectType {
-		c.log.Fatalf("Wrong object type received to store in cache. Expected: %s, Found: %s", c.ObjectType, reflect.TypeOf(newObj))
+	if c.equal == nil {
+		c.equal = func(a, b T) bool { return reflect.DeepEqual(a, b) }
+	}
+	if c.quarantineRetryPeriod <= 0 {
+		c.quarantineRetryPeriod = defaultQuarantineRetryPeriod
 	}
+	caches.add(c.typeDesc, c)
+	return c
+}
 
+func (c *calicoCache[T]) Set(key string, newObj T) {
 	// Check if the object exists in the cache already.  If it does and hasn't changed,
 	// then we don't need to send an update on the queue.
 	if existingObj, found := c.threadSafeCache.Get(key); found {
 		c.log.Debugf("%#v already exists in cache - comparing.", existingObj)
-		if !reflect.DeepEqual(existingObj, newObj) {
+		if !c.equal(existingObj.(T), newObj) {
 			// The objects do not match - send an update over the queue.
 			c.threadSafeCache.Set(key, newObj, cache.NoExpiration)
 			if c.isRunning() {
 				c.log.Debugf("Queueing update - %#v and %#v do not match.", newObj, existingObj)
-				c.workqueue.Add(key)
+				c.queueUpdate(key)
+			}
+			if c.onSet != nil {
+				c.onSet(key, newObj)
 			}
 		}
 	} else {
 		c.threadSafeCache.Set(key, newObj, cache.NoExpiration)
 		if c.isRunning() {
 			c.log.Debugf("%#v not found in cache, adding it + queuing update.", newObj)
-			c.workqueue.Add(key)
+			c.queueUpdate(key)
+		}
+		if c.onSet != nil {
+			c.onSet(key, newObj)
 		}
 	}
 }
 
-func (c *calicoCache) Delete(key string) {
+func (c *calicoCache[T]) Delete(key string) {
 	c.log.Debugf("Deleting %s from cache", key)
+	_, found := c.threadSafeCache.Get(key)
 	c.threadSafeCache.Delete(key)
+	c.queueUpdate(key)
+	if found && c.onDelete != nil {
+		c.onDelete(key)
+	}
+}
+
+// Resync requests an immediate reconciliation. Requests made while one is already pending are
+// merged.
+func (c *calicoCache[T]) Resync() {
+	select {
+	case c.resync <- struct{}{}:
+	default:
+	}
+}
+
+// queueUpdate queues an update for the given key, noting when the earliest outstanding update for
+// the key was queued.
+func (c *calicoCache[T]) queueUpdate(key string) {
+	c.waitForQueue()
+	if c.controllerName != "" {
+		c.mut.Lock()
+		if _, ok := c.pending[key]; !ok {
+			c.pending[key] = time.Now()
+		}
+		c.mut.Unlock()
+	}
+	if c.coalesceWindow > 0 {
+		// A key that's already waiting keeps its original due time, so the update isn't
+		// postponed indefinitely by a steady stream of changes.
+		c.workqueue.AddAfter(key, c.coalesceWindow)
+		return
+	}
 	c.workqueue.Add(key)
 }
 
-func (c *calicoCache) Clean(key string) {
+// waitForQueue blocks while the output queue is at or above the maximum depth, if one is set.
+func (c *calicoCache[T]) waitForQueue() {
+	if c.maxQueueDepth <= 0 || c.workqueue.Len() < c.maxQueueDepth {
+		return
+	}
+	c.log.WithField("maxQueueDepth", c.maxQueueDepth).Warn("Output queue is full, pausing until it drains")
+	start := time.Now()
+	enqueuePaused.WithLabelValues(c.typeDesc).Set(1)
+	for c.workqueue.Len() >= c.maxQueueDepth && !c.workqueue.ShuttingDown() {
+		time.Sleep(backpressurePollInterval)
+	}
+	enqueuePaused.WithLabelValues(c.typeDesc).Set(0)
+	enqueuePausedSeconds.WithLabelValues(c.typeDesc).Add(time.Since(start).Seconds())
+	c.log.WithField("paused", time.Since(start)).Info("Output queue has drained, resuming")
+}
+
+// Synced records the time between the update for the given key being queued and it being written
+// to the datastore. Keys queued by the reconciler rather than by a change to the source of truth
+// aren't measured. A quarantined key is released from quarantine.
+func (c *calicoCache[T]) Synced(key string) {
+	c.mut.Lock()
+	queued, ok := c.pending[key]
+	delete(c.pending, key)
+	c.mut.Unlock()
+	if ok {
+		syncLag.WithLabelValues(c.controllerName).Observe(time.Since(queued).Seconds())
+	}
+	c.unquarantine(key)
+}
+
+func (c *calicoCache[T]) Clean(key string) {
 	c.log.Debugf("Cleaning %s from cache, no update required", key)
 	c.threadSafeCache.Delete(key)
 }
 
-func (c *calicoCache) Get(key string) (interface{}, bool) {
+func (c *calicoCache[T]) Get(key string) (T, bool) {
 	obj, found := c.threadSafeCache.Get(key)
 	if found {
-		return obj, true
+		// Only values of type T are ever stored.
+		return obj.(T), true
 	}
-	return nil, false
+	var zero T
+	return zero, false
 }
 
 // Prime adds the key and value to the cache but will never generate
 // an update on the queue.
-func (c *calicoCache) Prime(key string, value interface{}) {
+func (c *calicoCache[T]) Prime(key string, value T) {
 	c.threadSafeCache.Set(key, value, cache.NoExpiration)
 }
 
 // ListKeys returns a list of all the keys in the cache.
-func (c *calicoCache) ListKeys() []string {
+func (c *calicoCache[T]) ListKeys() []string {
 	cacheItems := c.threadSafeCache.Items()
 	keys := make([]string, 0, len(cacheItems))
 	for k := range cacheItems {
@@ -184,16 +506,79 @@ func (c *calicoCache) ListKeys() []string {
 	return keys
 }
 
+// ListKeysWithPrefix returns the keys in the cache that start with the given prefix.
+func (c *calicoCache[T]) ListKeysWithPrefix(prefix string) []string {
+	return c.listKeys(func(key string) bool { return strings.HasPrefix(key, prefix) })
+}
+
+// ListKeysMatching returns the keys in the cache that match the given regular expression.
+func (c *calicoCache[T]) ListKeysMatching(re *regexp.Regexp) []string {
+	return c.listKeys(re.MatchString)
+}
+
+func (c *calicoCache[T]) listKeys(match func(key string) bool) []string {
+	var keys []string
+	for k := range c.threadSafeCache.Items() {
+		if match(k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// GetAll returns a snapshot of the cache. Changes to the cache after it returns aren't
+// reflected in the snapshot.
+func (c *calicoCache[T]) GetAll() map[string]T {
+	items := c.threadSafeCache.Items()
+	all := make(map[string]T, len(items))
+	for k, item := range items {
+		all[k] = item.Object.(T)
+	}
+	return all
+}
+
+// Dump returns a snapshot of the cache and its queue.
+func (c *calicoCache[T]) Dump() Dump {
+	keys := c.ListKeys()
+	sort.Strings(keys)
+	var items []QueueItem
+	for _, tracker := range c.trackers {
+		items = append(items, tracker.items(c.workqueue)...)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	return Dump{Keys: keys, Queue: items, Quarantined: c.quarantinedKeys()}
+}
+
+// isDeletion reports whether the given queued key is to be deleted, because it's no longer in the
+// cache.
+func (c *calicoCache[T]) isDeletion(item interface{}) bool {
+	key, _ := item.(string)
+	_, found := c.threadSafeCache.Get(key)
+	return !found
+}
+
+func (c *calicoCache[T]) size() int {
+	return c.threadSafeCache.ItemCount()
+}
+
+func (c *calicoCache[T]) queueLength() int {
+	return c.workqueue.Len()
+}
+
 // GetQueue returns the output queue from the cache.  Whenever a key/value pair
 // is modified, an event will appear on this queue.
-func (c *calicoCache) GetQueue() workqueue.RateLimitingInterface {
+func (c *calicoCache[T]) GetQueue() workqueue.RateLimitingInterface {
 	return c.workqueue
 }
 
 // Run starts the cache.  Any Set() calls prior to calling Run() will
 // prime the cache, but not trigger any updates on the output queue.
-func (c *calicoCache) Run(reconcilerPeriod string) {
+func (c *calicoCache[T]) Run(reconcilerPeriod string) {
 	go c.reconcile(reconcilerPeriod)
+	if c.reconcilerConfig.SpotCheckPeriod > 0 {
+		go c.runSpotChecks(c.reconcilerConfig.SpotCheckPeriod)
+	}
+	go c.retryQuarantined(c.quarantineRetryPeriod)
 
 	// Indicate that the cache is running, and so updates
 	// can be queued.
@@ -202,7 +587,7 @@ func (c *calicoCache) Run(reconcilerPeriod string) {
 	c.mut.Unlock()
 }
 
-func (c *calicoCache) isRunning() bool {
+func (c *calicoCache[T]) isRunning() bool {
 	c.mut.Lock()
 	defer c.mut.Unlock()
 	return c.running
@@ -211,39 +596,118 @@ func (c *calicoCache) isRunning() bool {
 // reconcile ensures a reconciliation is run every `reconcilerPeriod` in order to bring the datastore
 // in sync with the cache. This is to correct any manual changes made in the datastore
 // without the cache being aware.
-func (c *calicoCache) reconcile(reconcilerPeriod string) {
+func (c *calicoCache[T]) reconcile(reconcilerPeriod string) {
 	duration, err := time.ParseDuration(reconcilerPeriod)
 	if err != nil {
 		c.log.Fatalf("Invalid time duration format for reconciler: %s. Some valid examples: 5m, 30s, 2m30s etc.", reconcilerPeriod)
 	}
 
-	// If user has set duration to 0 then disable the reconciler job.
+	// If user has set duration to 0 then disable the reconciler job, other than the start of day
+	// reconciliation if that is required, and any requested resyncs.
 	if duration.Nanoseconds() == 0 {
 		c.log.Infof("Reconciler period set to %d. Disabling reconciler.", duration.Nanoseconds())
+		if c.reconcilerConfig.ReconcileAtStartup {
+			c.reconcileAtStartup()
+		}
+		for range c.resync {
+			c.log.Info("Performing requested reconciliation")
+			if _, err := c.performDatastoreSync(); err != nil {
+				c.log.WithError(err).Error("Reconciliation failed")
+			}
+		}
 		return
 	}
 
 	// Loop forever, performing a datastore reconciliation periodically.
+	period := duration
 	for {
 		c.log.Debugf("Performing reconciliation")
-		err := c.performDatastoreSync()
+		drift, err := c.performDatastoreSync()
 		if err != nil {
 			c.log.WithError(err).Error("Reconciliation failed")
 			continue
 		}
 
-		// Reconciliation was successful, sleep the configured duration.
-		c.log.Debugf("Reconciliation complete, %+v until next one.", duration)
-		time.Sleep(duration)
+		// Reconciliation was successful, sleep until the next one, or until a resync is requested.
+		period = c.nextReconcilerPeriod(period, duration, drift)
+		c.log.Debugf("Reconciliation complete, %+v until next one.", period)
+		select {
+		case <-time.After(c.jitter(period)):
+		case <-c.resync:
+			c.log.Info("Performing requested reconciliation")
+			period = duration
+		}
 	}
 }
 
-func (c *calicoCache) performDatastoreSync() error {
-	// Get all the objects we care about from the datastore using ListFunc.
-	objMap, err := c.ListFunc()
-	if err != nil {
-		c.log.WithError(err).Errorf("unable to list objects from datastore while reconciling.")
-		return err
+// reconcileAtStartup performs a single reconciliation, retrying until it succeeds.
+func (c *calicoCache[T]) reconcileAtStartup() {
+	c.log.Info("Performing start of day reconciliation")
+	for {
+		if _, err := c.performDatastoreSync(); err == nil {
+			return
+		}
+		c.log.Errorf("Start of day reconciliation failed, retrying in %v", startupRetryInterval)
+		time.Sleep(startupRetryInterval)
+	}
+}
+
+// nextReconcilerPeriod returns the period to wait before the next reconciliation, given the
+// current period and the number of out of sync keys found by the last reconciliation.
+func (c *calicoCache[T]) nextReconcilerPeriod(current, base time.Duration, drift int) time.Duration {
+	max := c.reconcilerConfig.MaxReconcilerPeriod
+	if max <= base {
+		// Adaptive reconciliation is disabled.
+		return base
+	}
+	if drift > 0 {
+		if current > base {
+			c.log.WithField("drift", drift).Infof("Reconciler detected drift, reducing reconciler period to %v", base)
+		}
+		return base
+	}
+	next := current * 2
+	if next > max {
+		next = max
+	}
+	return next
+}
+
+// jitter returns the given reconciler period lengthened by the configured jitter.
+func (c *calicoCache[T]) jitter(period time.Duration) time.Duration {
+	if c.reconcilerConfig.ReconcilerJitter <= 0 {
+		return period
+	}
+	return wait.Jitter(period, c.reconcilerConfig.ReconcilerJitter)
+}
+
+// performDatastoreSync queues updates for any keys that are out of sync between the cache and
+// the datastore, and returns the number of keys queued.
+func (c *calicoCache[T]) performDatastoreSync() (int, error) {
+	start := time.Now()
+	defer func() {
+		reconcileDuration.WithLabelValues(c.typeDesc).Observe(time.Since(start).Seconds())
+	}()
+
+	// Get all the objects we care about from the datastore, from the saved snapshot if this is
+	// the first reconciliation and there is one, and otherwise using ListFunc.
+	var objMap map[string]T
+	var snap *snapshot
+	if !c.snapshotChecked {
+		c.snapshotChecked = true
+		var err error
+		if objMap, snap, err = c.loadSnapshot(); err != nil {
+			c.log.WithError(err).Warn("Failed to load snapshot of the datastore, listing it instead")
+		}
+	}
+	if snap == nil {
+		var err error
+		objMap, err = c.ListFunc()
+		if err != nil {
+			c.log.WithError(err).Errorf("unable to list objects from datastore while reconciling.")
+			return 0, err
+		}
+		c.saveSnapshot(objMap)
 	}
 
 	// Build a map of existing keys in the datastore.
@@ -258,14 +722,14 @@ func (c *calicoCache) performDatastoreSync() error {
 	}
 
 	c.log.Debugf("Reconciling %d keys in total", len(allKeys))
+	var drift, deletes []string
 	for key := range allKeys {
 		cachedObj, existsInCache := c.Get(key)
 		if !existsInCache {
 			// Key does not exist in the cache, queue an update to
 			// remove it from the datastore if configured to do so.
 			if !c.reconcilerConfig.DisableMissingInCache {
-				c.log.WithField("key", key).Warn("Value for key should not exist, queueing update to remove")
-				c.workqueue.Add(key)
+				deletes = append(deletes, key)
 			}
 			continue
 		}
@@ -276,21 +740,94 @@ func (c *calicoCache) performDatastoreSync() error {
 			// to re-add it if configured to do so.
 			if !c.reconcilerConfig.DisableMissingInDatastore {
 				c.log.WithField("key", key).Warn("Value for key is missing in datastore, queueing update to reprogram")
-				c.workqueue.Add(key)
+				c.recordDiff(key, DiffMissingInDatastore)
+				drift = append(drift, key)
 			}
 			continue
 		}
 
-		if !reflect.DeepEqual(obj, cachedObj) {
+		if !c.equal(obj, cachedObj) && !snap.matches(key, cachedObj) {
 			// Objects differ - queue an update to re-program if configured to do so.
 			if !c.reconcilerConfig.DisableUpdateOnChange {
 				c.log.WithField("key", key).Warn("Value for key has changed, queueing update to reprogram")
 				c.log.Debugf("Cached:  %#v", cachedObj)
 				c.log.Debugf("Updated: %#v", obj)
-				c.workqueue.Add(key)
+				c.recordDiff(key, DiffChanged)
+				drift = append(drift, key)
 			}
 			continue
 		}
 	}
-	return nil
+	if c.allowDeletes(len(deletes), len(objMap)) {
+		for _, key := range deletes {
+			c.log.WithField("key", key).Warn("Value for key should not exist, queueing update to remove")
+			c.recordDiff(key, DiffMissingInCache)
+			drift = append(drift, key)
+		}
+	}
+	c.queueDrift(drift)
+	reconcileRepairs.WithLabelValues(c.typeDesc).Observe(float64(len(drift)))
+	return len(drift), nil
+}
+
+// allowDeletes reports whether a reconciliation may delete the given number of keys from a
+// datastore holding total keys, applying the mass delete failsafe.
+func (c *calicoCache[T]) allowDeletes(deletes, total int) bool {
+	c.mut.Lock()
+	confirmed := c.deletesConfirmed
+	c.deletesConfirmed = false
+	c.mut.Unlock()
+
+	threshold := c.reconcilerConfig.MassDeleteThreshold
+	if threshold <= 0 || deletes < massDeleteMinKeys || float64(deletes) <= threshold*float64(total) {
+		blockedDeletes.WithLabelValues(c.typeDesc).Set(0)
+		return true
+	}
+	clog := c.log.WithFields(log.Fields{"deletes": deletes, "total": total, "threshold": threshold})
+	if confirmed {
+		clog.Warn("Mass delete confirmed, queueing updates to remove")
+		blockedDeletes.WithLabelValues(c.typeDesc).Set(0)
+		return true
+	}
+	clog.Error("Reconciler would delete more than the mass delete threshold of the keys in the datastore, " +
+		"not deleting any until the deletes are confirmed through the admin API")
+	blockedDeletes.WithLabelValues(c.typeDesc).Set(float64(deletes))
+	return false
+}
+
+// ConfirmDeletes allows the next reconciliation to exceed the mass delete threshold, and requests
+// it now.
+func (c *calicoCache[T]) ConfirmDeletes() {
+	c.log.Info("Deletes confirmed, requesting reconciliation")
+	c.mut.Lock()
+	c.deletesConfirmed = true
+	c.mut.Unlock()
+	c.Resync()
+}
+
+// recordDiff records that the reconciler found the given key out of sync with the datastore.
+func (c *calicoCache[T]) recordDiff(key string, reason DiffReason) {
+	driftCounter.WithLabelValues(c.typeDesc, string(reason)).Inc()
+	if c.onReconcileDiff != nil {
+		c.onReconcileDiff(key, reason)
+	}
+}
+
+// queueDrift queues updates for the given keys found out of sync by the reconciler. If throttled
+// resyncs are enabled and there are too many keys to queue at once, they are spread evenly over
+// the resync window instead.
+func (c *calicoCache[T]) queueDrift(keys []string) {
+	window := c.reconcilerConfig.ResyncWindow
+	if window <= 0 || len(keys) <= c.reconcilerConfig.ResyncThreshold {
+		for _, key := range keys {
+			c.workqueue.Add(key)
+		}
+		return
+	}
+
+	c.log.WithFields(log.Fields{"keys": len(keys), "window": window}).Info("Large resync, spreading updates over the resync window")
+	interval := window / time.Duration(len(keys))
+	for i, key := range keys {
+		c.workqueue.AddAfter(key, time.Duration(i)*interval)
+	}
 }
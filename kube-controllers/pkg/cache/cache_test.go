@@ -16,20 +16,30 @@ package cache_test
 
 import (
 	"fmt"
-	"reflect"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 
 	"github.com/projectcalico/calico/kube-controllers/pkg/cache"
 )
 
 type resource struct {
 	name string
+
+	// version is populated by the server, like a resource version.
+	version string
 }
 
-func listFunc() (map[string]interface{}, error) {
-	m := make(map[string]interface{})
+func listFunc() (map[string]resource, error) {
+	m := make(map[string]resource)
 	for i := 1; i <= 10; i++ {
 		resourceName := fmt.Sprintf("ns%d", i)
 		obj := resource{
@@ -41,11 +51,29 @@ func listFunc() (map[string]interface{}, error) {
 	return m, nil
 }
 
+// metric returns the named metric with the given type label.
+func metric(name, typ string) *dto.Metric {
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	Expect(err).NotTo(HaveOccurred())
+	for _, mf := range mfs {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, m := range mf.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "type" && l.GetValue() == typ {
+					return m
+				}
+			}
+		}
+	}
+	return nil
+}
+
 var _ = Describe("Cache", func() {
 
-	rcargs := cache.ResourceCacheArgs{
-		ListFunc:   listFunc,
-		ObjectType: reflect.TypeOf(resource{}),
+	rcargs := cache.ResourceCacheArgs[resource]{
+		ListFunc: listFunc,
 	}
 
 	Context("Get operation", func() {
@@ -56,7 +84,7 @@ var _ = Describe("Cache", func() {
 			returnedObject, exists := rc.Get("nokey")
 			It("should return nil", func() {
 				Expect(exists).Should(BeFalse())
-				Expect(returnedObject).Should(BeNil())
+				Expect(returnedObject).Should(BeZero())
 			})
 		})
 
@@ -67,7 +95,7 @@ var _ = Describe("Cache", func() {
 			returnedObject, exists := rc.Get("")
 			It("should return nil", func() {
 				Expect(exists).Should(BeFalse())
-				Expect(returnedObject).Should(BeNil())
+				Expect(returnedObject).Should(BeZero())
 			})
 		})
 
@@ -184,6 +212,54 @@ var _ = Describe("Cache", func() {
 		})
 	})
 
+	Context("Custom equality", func() {
+		ignoreVersion := func(a, b resource) bool { return a.name == b.name }
+
+		It("should not queue an update when only ignored fields change", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc:  listFunc,
+				EqualFunc: ignoreVersion,
+			})
+			rc.Run("0m")
+			rc.Set("ns1", resource{name: "ns1", version: "1"})
+			Expect(rc.GetQueue().Len()).To(Equal(1))
+			key, _ := rc.GetQueue().Get()
+			rc.GetQueue().Done(key)
+
+			rc.Set("ns1", resource{name: "ns1", version: "2"})
+			Expect(rc.GetQueue().Len()).To(Equal(0))
+
+			rc.Set("ns1", resource{name: "ns1-renamed", version: "2"})
+			Expect(rc.GetQueue().Len()).To(Equal(1))
+		})
+
+		It("should not report drift when only ignored fields differ from the datastore", func() {
+			var diffs []string
+			var lock sync.Mutex
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: func() (map[string]resource, error) {
+					return map[string]resource{"ns1": {name: "ns1", version: "7"}}, nil
+				},
+				EqualFunc: ignoreVersion,
+				OnReconcileDiff: func(key string, _ cache.DiffReason) {
+					lock.Lock()
+					defer lock.Unlock()
+					diffs = append(diffs, key)
+				},
+				ReconcilerConfig: cache.ReconcilerConfig{ReconcileAtStartup: true},
+			})
+			rc.Prime("ns1", resource{name: "ns1"})
+			rc.Prime("ns2", resource{name: "ns2"})
+			rc.Run("0m")
+
+			// Only ns2, which is missing from the datastore, is out of sync.
+			Eventually(rc.GetQueue().Len).Should(Equal(1))
+			lock.Lock()
+			defer lock.Unlock()
+			Expect(diffs).To(ConsistOf("ns2"))
+		})
+	})
+
 	Context("Delete Operation", func() {
 		Context("delete valid resource in cache", func() {
 			rc := cache.NewResourceCache(rcargs)
@@ -206,7 +282,7 @@ var _ = Describe("Cache", func() {
 			It("should remove resource from cache", func() {
 				Expect(len(rc.ListKeys())).To(Equal(0))
 				Expect(exists1).To(BeFalse())
-				Expect(storedObj1).To(BeNil())
+				Expect(storedObj1).To(BeZero())
 			})
 
 			// Assert that key gets added in queue
@@ -242,8 +318,787 @@ var _ = Describe("Cache", func() {
 			storedObj, existsAfterClean := rc.Get(resourceName)
 			It("should remove resource from cache", func() {
 				Expect(existsAfterClean).To(BeFalse())
-				Expect(storedObj).To(BeNil())
+				Expect(storedObj).To(BeZero())
+			})
+		})
+	})
+
+	Context("Queries", func() {
+		var rc cache.ResourceCache[resource]
+
+		BeforeEach(func() {
+			rc = cache.NewResourceCache(rcargs)
+			for _, k := range []string{"node/a/1", "node/a/2", "node/b/1", "other"} {
+				rc.Prime(k, resource{name: k})
+			}
+		})
+
+		It("should list keys with a prefix", func() {
+			Expect(rc.ListKeysWithPrefix("node/a/")).To(ConsistOf("node/a/1", "node/a/2"))
+			Expect(rc.ListKeysWithPrefix("node/c/")).To(BeEmpty())
+		})
+
+		It("should list keys matching a regular expression", func() {
+			Expect(rc.ListKeysMatching(regexp.MustCompile(`^node/[ab]/1$`))).To(ConsistOf("node/a/1", "node/b/1"))
+		})
+
+		It("should return a snapshot of the cache", func() {
+			all := rc.GetAll()
+			Expect(all).To(HaveLen(4))
+			Expect(all).To(HaveKeyWithValue("other", resource{name: "other"}))
+
+			// Later changes to the cache don't affect the snapshot, nor vice versa.
+			rc.Clean("other")
+			delete(all, "node/a/1")
+			Expect(all).To(HaveKey("other"))
+			Expect(rc.ListKeys()).To(ContainElement("node/a/1"))
+		})
+	})
+
+	Context("Adaptive reconciler period", func() {
+		var calls int32
+		countingListFunc := func() (map[string]resource, error) {
+			atomic.AddInt32(&calls, 1)
+			return map[string]resource{}, nil
+		}
+
+		BeforeEach(func() {
+			atomic.StoreInt32(&calls, 0)
+		})
+
+		It("should back off while there is no drift", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc:         countingListFunc,
+				ReconcilerConfig: cache.ReconcilerConfig{MaxReconcilerPeriod: 160 * time.Millisecond},
+			})
+			rc.Run("20ms")
+
+			// Reconciliations at roughly 0, 20, 60, 140, 300 and 460ms.
+			time.Sleep(500 * time.Millisecond)
+			Expect(atomic.LoadInt32(&calls)).To(BeNumerically("<", 10))
+		})
+
+		It("should use the configured period while there is drift", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc:         countingListFunc,
+				ReconcilerConfig: cache.ReconcilerConfig{MaxReconcilerPeriod: 160 * time.Millisecond},
+			})
+
+			// The key is missing from the datastore, so every reconciliation finds drift.
+			rc.Set("namespace1", resource{name: "namespace1"})
+			rc.Run("20ms")
+
+			time.Sleep(500 * time.Millisecond)
+			Expect(atomic.LoadInt32(&calls)).To(BeNumerically(">", 12))
+		})
+
+		It("should lengthen the period by up to the configured jitter", func() {
+			// Caches from the other tests are still running, so count calls separately.
+			var jitteredCalls int32
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: func() (map[string]resource, error) {
+					atomic.AddInt32(&jitteredCalls, 1)
+					return map[string]resource{}, nil
+				},
+				ReconcilerConfig: cache.ReconcilerConfig{ReconcilerJitter: 1},
+			})
+			rc.Run("20ms")
+
+			// Reconciliations every 20 to 40ms, rather than every 20ms.
+			time.Sleep(500 * time.Millisecond)
+			Expect(atomic.LoadInt32(&jitteredCalls)).To(BeNumerically(">", 8))
+			Expect(atomic.LoadInt32(&jitteredCalls)).To(BeNumerically("<", 23))
+		})
+	})
+
+	Context("Spot checks", func() {
+		emptyListFunc := func() (map[string]resource, error) {
+			return map[string]resource{}, nil
+		}
+
+		It("should update stale values from the source of truth", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: emptyListFunc,
+				SourceGetFunc: func(key string) (resource, bool, error) {
+					if key == "deleted" {
+						return resource{}, false, nil
+					}
+					return resource{name: key + "-updated"}, true, nil
+				},
+				ReconcilerConfig: cache.ReconcilerConfig{SpotCheckPeriod: 10 * time.Millisecond},
+			})
+			rc.Prime("ns1", resource{name: "ns1"})
+			rc.Prime("deleted", resource{name: "deleted"})
+			rc.Run("0m")
+
+			Eventually(func() interface{} {
+				v, _ := rc.Get("ns1")
+				return v
+			}).Should(Equal(resource{name: "ns1-updated"}))
+			Eventually(func() bool {
+				_, ok := rc.Get("deleted")
+				return ok
+			}).Should(BeFalse())
+		})
+
+		It("should queue keys that are out of sync with the datastore", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: emptyListFunc,
+				DatastoreGetFunc: func(key string) (resource, bool, error) {
+					if key == "ns1" {
+						return resource{name: "ns1"}, true, nil
+					}
+					return resource{name: "changed"}, true, nil
+				},
+				ReconcilerConfig: cache.ReconcilerConfig{SpotCheckPeriod: 10 * time.Millisecond},
+			})
+			rc.Prime("ns1", resource{name: "ns1"})
+			rc.Prime("ns2", resource{name: "ns2"})
+			rc.Run("0m")
+
+			key, _ := rc.GetQueue().Get()
+			Expect(key).To(Equal("ns2"))
+		})
+
+		It("should only check a sample of the keys", func() {
+			var checked int32
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: emptyListFunc,
+				DatastoreGetFunc: func(key string) (resource, bool, error) {
+					atomic.AddInt32(&checked, 1)
+					return resource{}, false, nil
+				},
+				ReconcilerConfig: cache.ReconcilerConfig{SpotCheckPeriod: 100 * time.Millisecond, SpotCheckSampleSize: 3},
+			})
+			for i := 0; i < 10; i++ {
+				rc.Prime(fmt.Sprintf("ns%d", i), resource{name: fmt.Sprintf("ns%d", i)})
+			}
+			rc.Run("0m")
+
+			Eventually(func() int32 { return atomic.LoadInt32(&checked) }).Should(Equal(int32(3)))
+			Consistently(func() int32 { return atomic.LoadInt32(&checked) }, 50*time.Millisecond).Should(Equal(int32(3)))
+		})
+	})
+
+	Context("Start of day reconciliation", func() {
+		It("should remove orphaned keys when the periodic reconciler is disabled", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc:         listFunc,
+				ReconcilerConfig: cache.ReconcilerConfig{ReconcileAtStartup: true},
+			})
+			for i := 1; i <= 9; i++ {
+				rc.Prime(fmt.Sprintf("ns%d", i), resource{name: fmt.Sprintf("ns%d", i)})
+			}
+			rc.Run("0m")
+
+			// Only ns10 is in the datastore but not the cache.
+			key, _ := rc.GetQueue().Get()
+			Expect(key).To(Equal("ns10"))
+			Consistently(rc.GetQueue().Len, 100*time.Millisecond).Should(BeZero())
+		})
+	})
+
+	Context("Requested resync", func() {
+		var lists int32
+		countingListFunc := func() (map[string]resource, error) {
+			atomic.AddInt32(&lists, 1)
+			return map[string]resource{}, nil
+		}
+		numLists := func() int32 {
+			return atomic.LoadInt32(&lists)
+		}
+
+		BeforeEach(func() {
+			atomic.StoreInt32(&lists, 0)
+		})
+
+		It("should reconcile on request when the periodic reconciler is disabled", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: countingListFunc,
+			})
+			rc.Run("0m")
+			Consistently(numLists, 100*time.Millisecond).Should(BeZero())
+
+			rc.Resync()
+			Eventually(numLists).Should(Equal(int32(1)))
+		})
+
+		It("should reconcile on request without waiting for the next period", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: countingListFunc,
+			})
+			rc.Run("1h")
+			Eventually(numLists).Should(Equal(int32(1)))
+
+			rc.Resync()
+			Eventually(numLists).Should(Equal(int32(2)))
+		})
+	})
+
+	Context("Throttled resync", func() {
+		It("should spread updates over the resync window when there is a lot of drift", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: listFunc,
+				ReconcilerConfig: cache.ReconcilerConfig{
+					ReconcileAtStartup: true,
+					ResyncWindow:       time.Second,
+					ResyncThreshold:    5,
+				},
 			})
+			rc.Run("0m")
+
+			// All ten keys are in the datastore but not the cache, so they are queued over the
+			// next second rather than all at once.
+			Eventually(rc.GetQueue().Len).Should(BeNumerically(">", 0))
+			Expect(rc.GetQueue().Len()).To(BeNumerically("<", 10))
+			Eventually(rc.GetQueue().Len, 2*time.Second).Should(Equal(10))
+		})
+
+		It("should queue updates at once when there is little drift", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: listFunc,
+				ReconcilerConfig: cache.ReconcilerConfig{
+					ReconcileAtStartup: true,
+					ResyncWindow:       time.Hour,
+					ResyncThreshold:    10,
+				},
+			})
+			rc.Run("0m")
+			Eventually(rc.GetQueue().Len).Should(Equal(10))
+		})
+	})
+
+	Context("Workqueue metrics", func() {
+		queueDepth := func(controller string) float64 {
+			mfs, err := prometheus.DefaultGatherer.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			for _, mf := range mfs {
+				if mf.GetName() != "workqueue_depth" {
+					continue
+				}
+				for _, m := range mf.GetMetric() {
+					for _, l := range m.GetLabel() {
+						if l.GetName() == "controller" && l.GetValue() == controller {
+							return m.GetGauge().GetValue()
+						}
+					}
+				}
+			}
+			return -1
+		}
+
+		It("should export the depth of the workqueue labelled by controller", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ControllerName: "test",
+				ListFunc:       listFunc,
+			})
+			rc.Run("0m")
+			rc.Set("ns1", resource{name: "ns1"})
+			rc.Set("ns2", resource{name: "ns2"})
+			Expect(queueDepth("test")).To(Equal(2.0))
+
+			key, _ := rc.GetQueue().Get()
+			rc.GetQueue().Done(key)
+			Expect(queueDepth("test")).To(Equal(1.0))
+		})
+	})
+
+	Context("Sync lag", func() {
+		syncLagCount := func(controller string) uint64 {
+			mfs, err := prometheus.DefaultGatherer.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			for _, mf := range mfs {
+				if mf.GetName() != "controller_sync_lag_seconds" {
+					continue
+				}
+				for _, m := range mf.GetMetric() {
+					for _, l := range m.GetLabel() {
+						if l.GetName() == "controller" && l.GetValue() == controller {
+							return m.GetHistogram().GetSampleCount()
+						}
+					}
+				}
+			}
+			return 0
+		}
+
+		It("should measure the time from queueing an update to it being synced", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ControllerName: "lag-test",
+				ListFunc:       listFunc,
+			})
+			rc.Run("0m")
+			rc.Set("ns1", resource{name: "ns1"})
+			rc.Set("ns2", resource{name: "ns2"})
+			rc.Synced("ns1")
+			rc.Synced("ns2")
+			Expect(syncLagCount("lag-test")).To(Equal(uint64(2)))
+
+			// Keys without an outstanding update aren't measured.
+			rc.Synced("ns1")
+			rc.Synced("ns3")
+			Expect(syncLagCount("lag-test")).To(Equal(uint64(2)))
+
+			rc.Delete("ns1")
+			rc.Synced("ns1")
+			Expect(syncLagCount("lag-test")).To(Equal(uint64(3)))
+		})
+	})
+
+	Context("Cache metrics", func() {
+		It("should export the size and queue length of the cache labelled by type", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				LogTypeDesc: "size-test",
+				ListFunc:    listFunc,
+			})
+			rc.Prime("ns1", resource{name: "ns1"})
+			rc.Run("0m")
+			rc.Set("ns2", resource{name: "ns2"})
+			rc.Set("ns3", resource{name: "ns3"})
+			Expect(metric("cache_size", "size-test").GetGauge().GetValue()).To(Equal(3.0))
+			Expect(metric("cache_queue_length", "size-test").GetGauge().GetValue()).To(Equal(2.0))
+
+			rc.Clean("ns1")
+			key, _ := rc.GetQueue().Get()
+			rc.GetQueue().Done(key)
+			Expect(metric("cache_size", "size-test").GetGauge().GetValue()).To(Equal(2.0))
+			Expect(metric("cache_queue_length", "size-test").GetGauge().GetValue()).To(Equal(1.0))
+		})
+
+		It("should measure each reconciliation and the repairs it makes", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				LogTypeDesc: "reconcile-test",
+				ListFunc:    listFunc,
+			})
+			rc.Run("0m")
+			rc.Resync()
+
+			// All ten keys are in the datastore but not the cache.
+			Eventually(func() uint64 {
+				return metric("cache_reconcile_repairs", "reconcile-test").GetHistogram().GetSampleCount()
+			}).Should(Equal(uint64(1)))
+			Expect(metric("cache_reconcile_repairs", "reconcile-test").GetHistogram().GetSampleSum()).To(Equal(10.0))
+			Expect(metric("cache_reconcile_duration_seconds", "reconcile-test").GetHistogram().GetSampleCount()).To(Equal(uint64(1)))
+		})
+	})
+
+	Context("Quarantine", func() {
+		var rc cache.ResourceCache[resource]
+
+		BeforeEach(func() {
+			rc = cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				LogTypeDesc:           "quarantine-test",
+				ListFunc:              listFunc,
+				QuarantineRetryPeriod: 50 * time.Millisecond,
+			})
+			rc.Run("0m")
+		})
+
+		It("should retry quarantined keys until they are synced", func() {
+			rc.Quarantine("ns1")
+			Expect(rc.IsQuarantined("ns1")).To(BeTrue())
+			Expect(metric("cache_quarantined_keys", "quarantine-test").GetGauge().GetValue()).To(Equal(1.0))
+			Expect(rc.Dump().Quarantined).To(HaveLen(1))
+			Expect(rc.Dump().Quarantined[0].Key).To(Equal("ns1"))
+
+			// The key is queued again on the slow schedule.
+			key, _ := rc.GetQueue().Get()
+			Expect(key).To(Equal("ns1"))
+			rc.GetQueue().Done(key)
+			key, _ = rc.GetQueue().Get()
+			Expect(key).To(Equal("ns1"))
+			rc.GetQueue().Done(key)
+
+			rc.Synced("ns1")
+			Expect(rc.IsQuarantined("ns1")).To(BeFalse())
+			Expect(rc.Dump().Quarantined).To(BeEmpty())
+			Expect(metric("cache_quarantined_keys", "quarantine-test").GetGauge().GetValue()).To(Equal(0.0))
+			Consistently(rc.GetQueue().Len, 150*time.Millisecond).Should(BeZero())
+		})
+	})
+
+	Context("Prioritized deletes", func() {
+		var rc cache.ResourceCache[resource]
+
+		BeforeEach(func() {
+			rc = cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc:          listFunc,
+				PrioritizeDeletes: true,
+			})
+			rc.Run("0m")
+		})
+
+		get := func() string {
+			key, shutdown := rc.GetQueue().Get()
+			Expect(shutdown).To(BeFalse())
+			rc.GetQueue().Done(key)
+			return key.(string)
+		}
+
+		It("should hand out deletes before creates and updates", func() {
+			for _, k := range []string{"a", "b", "c"} {
+				rc.Set(k, resource{name: k})
+			}
+			rc.Delete("b")
+			rc.Delete("x")
+			rc.Set("d", resource{name: "d"})
+
+			Expect([]string{get(), get(), get(), get(), get()}).To(Equal([]string{"b", "x", "a", "c", "d"}))
+			Expect(rc.GetQueue().Len()).To(BeZero())
+		})
+
+		It("should hand out a key recreated before it's processed once, with the updates", func() {
+			for _, k := range []string{"a", "b", "c"} {
+				rc.Set(k, resource{name: k})
+			}
+			rc.Delete("a")
+			rc.Delete("c")
+			rc.Set("a", resource{name: "a", version: "2"})
+			Expect(rc.GetQueue().Len()).To(Equal(3))
+
+			Expect([]string{get(), get(), get()}).To(Equal([]string{"c", "b", "a"}))
+			Expect(rc.GetQueue().Len()).To(BeZero())
+		})
+
+		It("should hand out many deletes before many updates", func() {
+			var updates, deletes []string
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprintf("ns%d", i)
+				rc.Set(key, resource{name: key})
+				if i%2 == 0 {
+					rc.Delete(key)
+					deletes = append(deletes, key)
+				} else {
+					updates = append(updates, key)
+				}
+			}
+
+			var got []string
+			for range append(deletes, updates...) {
+				got = append(got, get())
+			}
+			Expect(got).To(Equal(append(deletes, updates...)))
+			Expect(rc.GetQueue().Len()).To(BeZero())
+		})
+
+		It("should requeue a key updated while it's being processed once it's done", func() {
+			rc.Set("a", resource{name: "a"})
+			key, _ := rc.GetQueue().Get()
+			rc.Set("a", resource{name: "a", version: "2"})
+			Expect(rc.GetQueue().Len()).To(BeZero())
+
+			rc.GetQueue().Done(key)
+			Expect(rc.GetQueue().Len()).To(Equal(1))
+			Expect(get()).To(Equal("a"))
+		})
+	})
+
+	Context("Sharded queues", func() {
+		var rc cache.ResourceCache[resource]
+
+		BeforeEach(func() {
+			rc = cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: listFunc,
+				Shards:   4,
+			})
+			rc.Run("0m")
+		})
+
+		It("should hand out every queued key once", func() {
+			var keys []string
+			for i := 0; i < 20; i++ {
+				key := fmt.Sprintf("ns%d", i)
+				keys = append(keys, key)
+				rc.Set(key, resource{name: key})
+			}
+			Expect(rc.GetQueue().Len()).To(Equal(20))
+			Expect(rc.Dump().Queue).To(HaveLen(20))
+
+			var got []string
+			for range keys {
+				key, shutdown := rc.GetQueue().Get()
+				Expect(shutdown).To(BeFalse())
+				got = append(got, key.(string))
+				rc.GetQueue().Done(key)
+			}
+			Expect(got).To(ConsistOf(keys))
+			Expect(rc.GetQueue().Len()).To(BeZero())
+		})
+
+		It("should not hand out a key again while it's being processed", func() {
+			rc.Set("a", resource{name: "a"})
+			key, _ := rc.GetQueue().Get()
+			rc.Set("a", resource{name: "a", version: "2"})
+			rc.Set("b", resource{name: "b"})
+
+			next, _ := rc.GetQueue().Get()
+			Expect(next).To(Equal("b"))
+			rc.GetQueue().Done(next)
+
+			rc.GetQueue().Done(key)
+			next, _ = rc.GetQueue().Get()
+			Expect(next).To(Equal("a"))
+		})
+
+		It("should shut down all the shards", func() {
+			rc.GetQueue().ShutDown()
+			_, shutdown := rc.GetQueue().Get()
+			Expect(shutdown).To(BeTrue())
+		})
+	})
+
+	Context("Coalescing", func() {
+		It("should merge a burst of changes to a key into one update", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc:       listFunc,
+				CoalesceWindow: 200 * time.Millisecond,
+			})
+			rc.Run("0m")
+			for i := 0; i < 5; i++ {
+				rc.Set("a", resource{name: "a", version: fmt.Sprint(i)})
+			}
+			rc.Delete("b")
+			Expect(rc.GetQueue().Len()).To(BeZero())
+
+			Eventually(rc.GetQueue().Len).Should(Equal(2))
+			Consistently(rc.GetQueue().Len, "300ms").Should(Equal(2))
+			obj, _ := rc.Get("a")
+			Expect(obj.version).To(Equal("4"))
+		})
+	})
+
+	Context("Backpressure", func() {
+		It("should pause updates while the queue is full", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				LogTypeDesc:   "backpressure-test",
+				ListFunc:      listFunc,
+				MaxQueueDepth: 2,
+			})
+			rc.Run("0m")
+			rc.Set("a", resource{name: "a"})
+			rc.Set("b", resource{name: "b"})
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				rc.Set("c", resource{name: "c"})
+			}()
+			Consistently(done, "300ms").ShouldNot(BeClosed())
+			Expect(metric("cache_enqueue_paused", "backpressure-test").GetGauge().GetValue()).To(Equal(1.0))
+
+			key, _ := rc.GetQueue().Get()
+			rc.GetQueue().Done(key)
+			Eventually(done).Should(BeClosed())
+			Expect(rc.GetQueue().Len()).To(Equal(2))
+			Expect(metric("cache_enqueue_paused", "backpressure-test").GetGauge().GetValue()).To(BeZero())
+			Expect(metric("cache_enqueue_paused_seconds_total", "backpressure-test").GetCounter().GetValue()).To(BeNumerically(">=", 0.3))
+		})
+	})
+
+	Context("Mass delete failsafe", func() {
+		newCache := func(typ string) cache.ResourceCache[resource] {
+			return cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				LogTypeDesc: typ,
+				ListFunc:    listFunc,
+				ReconcilerConfig: cache.ReconcilerConfig{
+					ReconcileAtStartup:  true,
+					MassDeleteThreshold: 0.5,
+				},
+			})
+		}
+
+		It("should withhold deletes above the threshold until they are confirmed", func() {
+			// None of the ten keys in the datastore are in the cache.
+			rc := newCache("mass-delete-test")
+			rc.Run("0m")
+			Eventually(func() float64 {
+				return metric("reconciler_blocked_deletes", "mass-delete-test").GetGauge().GetValue()
+			}).Should(Equal(10.0))
+			Expect(rc.GetQueue().Len()).To(BeZero())
+
+			rc.ConfirmDeletes()
+			Eventually(rc.GetQueue().Len).Should(Equal(10))
+			Expect(metric("reconciler_blocked_deletes", "mass-delete-test").GetGauge().GetValue()).To(BeZero())
+		})
+
+		It("should allow deletes below the threshold", func() {
+			rc := newCache("few-deletes-test")
+			for i := 1; i <= 8; i++ {
+				name := fmt.Sprintf("ns%d", i)
+				rc.Prime(name, resource{name: name})
+			}
+			rc.Run("0m")
+			Eventually(rc.GetQueue().Len).Should(Equal(2))
+		})
+	})
+
+	Context("Snapshots", func() {
+		// Snapshots are serialized as JSON, so need a type with exported fields.
+		type item struct {
+			Name string
+			Tags []string
+		}
+
+		var dir string
+		var lists int32
+		var datastore map[string]item
+
+		newCache := func(maxAge time.Duration) cache.ResourceCache[item] {
+			// Caches from earlier tests are still running, so don't share state with them.
+			ds, path := datastore, filepath.Join(dir, "test.json")
+			return cache.NewResourceCache(cache.ResourceCacheArgs[item]{
+				LogTypeDesc: "snapshot-test",
+				ListFunc: func() (map[string]item, error) {
+					atomic.AddInt32(&lists, 1)
+					return ds, nil
+				},
+				SnapshotStore: cache.NewFileSnapshotStore(path),
+				ReconcilerConfig: cache.ReconcilerConfig{
+					ReconcileAtStartup: true,
+					MaxSnapshotAge:     maxAge,
+				},
+			})
+		}
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "snapshot")
+			Expect(err).NotTo(HaveOccurred())
+			atomic.StoreInt32(&lists, 0)
+			datastore = map[string]item{}
+			for i := 1; i <= 10; i++ {
+				name := fmt.Sprintf("ns%d", i)
+				datastore[name] = item{Name: name}
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("should reconcile against the snapshot after a restart", func() {
+			rc := newCache(time.Hour)
+			rc.Run("0m")
+			Eventually(rc.GetQueue().Len).Should(Equal(10))
+			Expect(atomic.LoadInt32(&lists)).To(Equal(int32(1)))
+			Expect(filepath.Join(dir, "test.json")).To(BeAnExistingFile())
+
+			// After a restart the cache is populated from the source of truth, and the first
+			// reconciliation uses the snapshot instead of listing the datastore.
+			rc = newCache(time.Hour)
+			for k, v := range datastore {
+				rc.Prime(k, v)
+			}
+			rc.Clean("ns1")
+			rc.Run("0m")
+			Eventually(rc.GetQueue().Len).Should(Equal(1))
+			Consistently(rc.GetQueue().Len, "200ms").Should(Equal(1))
+			Expect(atomic.LoadInt32(&lists)).To(Equal(int32(1)))
+
+			// Later reconciliations list the datastore.
+			rc.Resync()
+			Eventually(func() int32 { return atomic.LoadInt32(&lists) }).Should(Equal(int32(2)))
+		})
+
+		It("should list the datastore if the snapshot is too old", func() {
+			newCache(time.Hour).Run("0m")
+			Eventually(func() int32 { return atomic.LoadInt32(&lists) }).Should(Equal(int32(1)))
+			Eventually(filepath.Join(dir, "test.json")).Should(BeAnExistingFile())
+
+			time.Sleep(10 * time.Millisecond)
+			newCache(time.Millisecond).Run("0m")
+			Eventually(func() int32 { return atomic.LoadInt32(&lists) }).Should(Equal(int32(2)))
+		})
+
+		It("should list the datastore if the snapshot is unreadable", func() {
+			Expect(os.WriteFile(filepath.Join(dir, "test.json"), []byte("{"), 0o600)).To(Succeed())
+			newCache(time.Hour).Run("0m")
+			Eventually(func() int32 { return atomic.LoadInt32(&lists) }).Should(Equal(int32(1)))
+		})
+	})
+
+	Context("Dump", func() {
+		It("should report the cache keys and the state of outstanding updates", func() {
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: listFunc,
+			})
+			rc.Prime("ns0", resource{name: "ns0"})
+			rc.Run("0m")
+			rc.Set("ns1", resource{name: "ns1"})
+			rc.Set("ns2", resource{name: "ns2"})
+			rc.GetQueue().AddAfter("ns3", time.Hour)
+
+			// Fail the update for ns1, so that it's retried.
+			key, _ := rc.GetQueue().Get()
+			Expect(key).To(Equal("ns1"))
+			rc.GetQueue().AddRateLimited(key)
+			rc.GetQueue().Done(key)
+
+			// Start processing ns2.
+			key, _ = rc.GetQueue().Get()
+			Expect(key).To(Equal("ns2"))
+
+			dump := rc.Dump()
+			Expect(dump.Keys).To(Equal([]string{"ns0", "ns1", "ns2"}))
+			Expect(dump.Queue).To(HaveLen(3))
+			Expect(dump.Queue[0].Key).To(Equal("ns1"))
+			Expect(dump.Queue[0].Retries).To(Equal(1))
+			Expect(dump.Queue[1]).To(Equal(cache.QueueItem{Key: "ns2", State: cache.QueueStateProcessing}))
+			Expect(dump.Queue[2].Key).To(Equal("ns3"))
+			Expect(dump.Queue[2].State).To(Equal(cache.QueueStateDelayed))
+			Expect(*dump.Queue[2].Due).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+
+			// Once processed, ns2 is no longer outstanding.
+			rc.GetQueue().Forget(key)
+			rc.GetQueue().Done(key)
+			Expect(rc.Dump().Queue).To(HaveLen(2))
+
+			// The retry of ns1 is queued once its short initial backoff expires.
+			Eventually(func() []cache.QueueItem { return rc.Dump().Queue }).Should(ContainElement(
+				cache.QueueItem{Key: "ns1", State: cache.QueueStateQueued, Retries: 1},
+			))
+		})
+	})
+
+	Context("Hooks", func() {
+		It("should call the hooks on cache transitions", func() {
+			var lock sync.Mutex
+			var events []string
+			record := func(e string) {
+				lock.Lock()
+				defer lock.Unlock()
+				events = append(events, e)
+			}
+			recorded := func() []string {
+				lock.Lock()
+				defer lock.Unlock()
+				return append([]string{}, events...)
+			}
+
+			rc := cache.NewResourceCache(cache.ResourceCacheArgs[resource]{
+				ListFunc: func() (map[string]resource, error) {
+					return map[string]resource{"ns1": {name: "changed"}, "stale": {name: "stale"}}, nil
+				},
+				OnSet:    func(key string, value resource) { record("set " + key + " " + value.name) },
+				OnDelete: func(key string) { record("delete " + key) },
+				OnReconcileDiff: func(key string, reason cache.DiffReason) {
+					record(fmt.Sprintf("diff %s %s", key, reason))
+				},
+			})
+
+			rc.Set("ns1", resource{name: "ns1"})
+			rc.Set("ns1", resource{name: "ns1"})
+			rc.Set("ns2", resource{name: "ns2"})
+			rc.Delete("ns2")
+			rc.Delete("ns2")
+			rc.Delete("ns3")
+			Expect(recorded()).To(Equal([]string{"set ns1 ns1", "set ns2 ns2", "delete ns2"}))
+
+			rc.Run("1h")
+			Eventually(recorded).Should(ContainElements(
+				"diff ns1 "+string(cache.DiffChanged),
+				"diff stale "+string(cache.DiffMissingInCache),
+			))
 		})
 	})
 })
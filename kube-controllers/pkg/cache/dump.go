@@ -0,0 +1,152 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Queue item states reported in a Dump.
+const (
+	QueueStateQueued     = "queued"
+	QueueStateDelayed    = "delayed"
+	QueueStateProcessing = "processing"
+)
+
+// Dump is a snapshot of the contents of a ResourceCache, for troubleshooting.
+type Dump struct {
+	// Keys are the keys in the cache.
+	Keys []string `json:"keys"`
+
+	// Queue holds the keys with an outstanding update.
+	Queue []QueueItem `json:"queue"`
+
+	// Quarantined holds the keys that have repeatedly failed to sync, and are only retried
+	// occasionally.
+	Quarantined []QuarantinedKey `json:"quarantined"`
+}
+
+// QueueItem is a key with an outstanding update.
+type QueueItem struct {
+	Key   string `json:"key"`
+	State string `json:"state"`
+
+	// Retries is the number of times the update has failed and been requeued.
+	Retries int `json:"retries"`
+
+	// Due is when a delayed update, such as a retry, will be queued.
+	Due *time.Time `json:"due,omitempty"`
+}
+
+// trackingQueue wraps a delaying workqueue, tracking the keys it holds so that they can be dumped.
+// The workqueue itself doesn't expose its contents.
+type trackingQueue struct {
+	workqueue.DelayingInterface
+
+	mut        sync.Mutex
+	queued     map[interface{}]bool
+	delayed    map[interface{}]time.Time
+	processing map[interface{}]bool
+}
+
+func newTrackingQueue(q workqueue.DelayingInterface) *trackingQueue {
+	return &trackingQueue{
+		DelayingInterface: q,
+		queued:            map[interface{}]bool{},
+		delayed:           map[interface{}]time.Time{},
+		processing:        map[interface{}]bool{},
+	}
+}
+
+func (q *trackingQueue) Add(item interface{}) {
+	q.mut.Lock()
+	q.queued[item] = true
+	delete(q.delayed, item)
+	q.mut.Unlock()
+	q.DelayingInterface.Add(item)
+}
+
+// AddAfter is also used by the rate limiting queue to requeue failed updates after a backoff.
+func (q *trackingQueue) AddAfter(item interface{}, duration time.Duration) {
+	if duration <= 0 {
+		q.Add(item)
+		return
+	}
+	q.mut.Lock()
+	due := time.Now().Add(duration)
+	if existing, ok := q.delayed[item]; !q.queued[item] && (!ok || due.Before(existing)) {
+		q.delayed[item] = due
+	}
+	q.mut.Unlock()
+	q.DelayingInterface.AddAfter(item, duration)
+}
+
+func (q *trackingQueue) Get() (interface{}, bool) {
+	item, shutdown := q.DelayingInterface.Get()
+	if !shutdown {
+		q.mut.Lock()
+		delete(q.queued, item)
+		delete(q.delayed, item)
+		q.processing[item] = true
+		q.mut.Unlock()
+	}
+	return item, shutdown
+}
+
+func (q *trackingQueue) Done(item interface{}) {
+	q.mut.Lock()
+	delete(q.processing, item)
+	q.mut.Unlock()
+	q.DelayingInterface.Done(item)
+}
+
+// items returns the keys held by the queue, sorted by key, with retry counts from the given rate
+// limited queue.
+func (q *trackingQueue) items(rl workqueue.RateLimitingInterface) []QueueItem {
+	q.mut.Lock()
+	var items []QueueItem
+	add := func(item interface{}, state string, due *time.Time) {
+		key, _ := item.(string)
+		items = append(items, QueueItem{Key: key, State: state, Due: due})
+	}
+	for item := range q.processing {
+		add(item, QueueStateProcessing, nil)
+	}
+	for item := range q.queued {
+		add(item, QueueStateQueued, nil)
+	}
+	now := time.Now()
+	for item, due := range q.delayed {
+		// The workqueue moves delayed items onto the queue internally, so infer the move from
+		// the due time.
+		if due.After(now) {
+			due := due
+			add(item, QueueStateDelayed, &due)
+		} else {
+			add(item, QueueStateQueued, nil)
+		}
+	}
+	q.mut.Unlock()
+
+	for i := range items {
+		items[i].Retries = rl.NumRequeues(items[i].Key)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	return items
+}
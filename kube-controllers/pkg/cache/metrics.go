@@ -0,0 +1,215 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var (
+	driftCounter      *prometheus.CounterVec
+	divergenceCounter *prometheus.CounterVec
+
+	reconcileDuration *prometheus.HistogramVec
+	reconcileRepairs  *prometheus.HistogramVec
+	blockedDeletes    *prometheus.GaugeVec
+	caches            = &cacheCollector{caches: map[string]sizer{}}
+
+	enqueuePaused        *prometheus.GaugeVec
+	enqueuePausedSeconds *prometheus.CounterVec
+
+	queueDepth              *prometheus.GaugeVec
+	queueAdds               *prometheus.CounterVec
+	queueLatency            *prometheus.HistogramVec
+	queueWorkDuration       *prometheus.HistogramVec
+	queueUnfinishedWork     *prometheus.GaugeVec
+	queueLongestRunningWork *prometheus.GaugeVec
+	queueRetries            *prometheus.CounterVec
+
+	syncLag *prometheus.HistogramVec
+)
+
+func init() {
+	driftCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "reconciler_drift_total",
+		Help: "Number of keys found out of sync with the Calico datastore by the reconciler, and queued for repair",
+	}, []string{"type", "reason"})
+	prometheus.MustRegister(driftCounter)
+
+	divergenceCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_divergence_total",
+		Help: "Number of spot checked keys whose cached value didn't match the source of truth or the Calico datastore",
+	}, []string{"type", "source"})
+	prometheus.MustRegister(divergenceCounter)
+
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cache_reconcile_duration_seconds",
+		Help:    "How long reconciling the cache with the Calico datastore takes",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 16),
+	}, []string{"type"})
+	reconcileRepairs = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "cache_reconcile_repairs",
+		Help:    "Number of keys queued for repair by each reconciliation of the cache with the Calico datastore",
+		Buckets: []float64{0, 1, 5, 10, 50, 100, 500, 1000, 5000},
+	}, []string{"type"})
+	blockedDeletes = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "reconciler_blocked_deletes",
+		Help: "Number of deletes withheld by the mass delete failsafe at the last reconciliation, pending confirmation",
+	}, []string{"type"})
+	prometheus.MustRegister(reconcileDuration, reconcileRepairs, blockedDeletes, caches)
+
+	enqueuePaused = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "cache_enqueue_paused",
+		Help: "Whether updates to the cache are paused because its output queue is full",
+	}, []string{"type"})
+	enqueuePausedSeconds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_enqueue_paused_seconds_total",
+		Help: "Total time that updates to the cache have been paused because its output queue was full",
+	}, []string{"type"})
+	prometheus.MustRegister(enqueuePaused, enqueuePausedSeconds)
+
+	syncLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_sync_lag_seconds",
+		Help:    "Time from a change to a Kubernetes resource being received to the corresponding write to the Calico datastore completing",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+	}, []string{"controller"})
+	prometheus.MustRegister(syncLag)
+
+	queueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workqueue_depth",
+		Help: "Current number of keys waiting in the controller's workqueue",
+	}, []string{"controller"})
+	queueAdds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workqueue_adds_total",
+		Help: "Number of keys added to the controller's workqueue",
+	}, []string{"controller"})
+	queueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "workqueue_queue_duration_seconds",
+		Help:    "How long keys wait in the controller's workqueue before being processed",
+		Buckets: prometheus.ExponentialBuckets(10e-6, 10, 8),
+	}, []string{"controller"})
+	queueWorkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "workqueue_work_duration_seconds",
+		Help:    "How long processing a key from the controller's workqueue takes",
+		Buckets: prometheus.ExponentialBuckets(10e-6, 10, 8),
+	}, []string{"controller"})
+	queueUnfinishedWork = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workqueue_unfinished_work_seconds",
+		Help: "Total time that keys currently being processed by the controller have been in progress",
+	}, []string{"controller"})
+	queueLongestRunningWork = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workqueue_longest_running_processor_seconds",
+		Help: "How long the longest running key currently being processed by the controller has been in progress",
+	}, []string{"controller"})
+	queueRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workqueue_retries_total",
+		Help: "Number of keys requeued by the controller after failing to be processed",
+	}, []string{"controller"})
+	prometheus.MustRegister(queueDepth, queueAdds, queueLatency, queueWorkDuration, queueUnfinishedWork, queueLongestRunningWork, queueRetries)
+
+	workqueue.SetProvider(queueMetricsProvider{})
+}
+
+// queueMetricsProvider exports the metrics of named workqueues to Prometheus, labelled by the
+// name of the queue, which is the name of the controller that owns it.
+type queueMetricsProvider struct{}
+
+func (queueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return queueDepth.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return queueAdds.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return queueLatency.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return queueWorkDuration.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return queueUnfinishedWork.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return queueLongestRunningWork.WithLabelValues(name)
+}
+
+func (queueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return queueRetries.WithLabelValues(name)
+}
+
+var (
+	cacheSizeDesc = prometheus.NewDesc(
+		"cache_size",
+		"Current number of keys in the cache",
+		[]string{"type"}, nil,
+	)
+	cacheQueueLengthDesc = prometheus.NewDesc(
+		"cache_queue_length",
+		"Current number of keys with updates waiting on the cache's output queue",
+		[]string{"type"}, nil,
+	)
+	cacheQuarantinedDesc = prometheus.NewDesc(
+		"cache_quarantined_keys",
+		"Current number of keys quarantined after repeatedly failing to sync to the Calico datastore",
+		[]string{"type"}, nil,
+	)
+)
+
+// sizer is implemented by the caches whose size is reported by the cacheCollector.
+type sizer interface {
+	size() int
+	queueLength() int
+	quarantineLength() int
+}
+
+// cacheCollector reports the size, queue length and number of quarantined keys of each cache,
+// labelled by the type of object it stores, reading them from the caches when scraped so that
+// they are never stale.
+type cacheCollector struct {
+	lock   sync.Mutex
+	caches map[string]sizer
+}
+
+// add starts reporting the metrics of the given cache, replacing any cache previously added for
+// the same type.
+func (cc *cacheCollector) add(typ string, c sizer) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	cc.caches[typ] = c
+}
+
+func (cc *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cacheSizeDesc
+	ch <- cacheQueueLengthDesc
+	ch <- cacheQuarantinedDesc
+}
+
+func (cc *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	for typ, c := range cc.caches {
+		ch <- prometheus.MustNewConstMetric(cacheSizeDesc, prometheus.GaugeValue, float64(c.size()), typ)
+		ch <- prometheus.MustNewConstMetric(cacheQueueLengthDesc, prometheus.GaugeValue, float64(c.queueLength()), typ)
+		ch <- prometheus.MustNewConstMetric(cacheQuarantinedDesc, prometheus.GaugeValue, float64(c.quarantineLength()), typ)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// priorityQueue is a workqueue.Interface that hands out prioritized items before all others, and
+// otherwise in the order they were queued. It has the same semantics as workqueue.Type: an item
+// is only queued once, however many times it's added, and isn't handed out again while it's being
+// processed. The cache uses it to process deletions before creates and updates.
+//
+// Items are classified when they're queued, and prioritized items are kept in their own FIFO, so
+// that Get doesn't have to search the queue. An item that's added again while queued is
+// reclassified, for example if it was updated and then deleted: it's appended to the other FIFO,
+// and its earlier entry is skipped when reached.
+//
+// Named queues report the same depth, adds, latency and work duration metrics as a named
+// workqueue.Type.
+type priorityQueue struct {
+	prioritize func(item interface{}) bool
+
+	cond        *sync.Cond
+	prioritized []*queuedItem
+	queue       []*queuedItem
+	queued      map[interface{}]*queuedItem
+	dirty       map[interface{}]bool
+	processing  map[interface{}]time.Time
+	added       map[interface{}]time.Time
+
+	shuttingDown bool
+	drain        bool
+
+	depth        workqueue.GaugeMetric
+	adds         workqueue.CounterMetric
+	latency      workqueue.HistogramMetric
+	workDuration workqueue.HistogramMetric
+}
+
+// queuedItem is an entry in one of a priorityQueue's FIFOs. It's stale, and skipped, if the item
+// has since been handed out or moved to the other FIFO.
+type queuedItem struct {
+	item        interface{}
+	prioritized bool
+}
+
+func newPriorityQueue(name string, prioritize func(item interface{}) bool) *priorityQueue {
+	q := &priorityQueue{
+		prioritize: prioritize,
+		cond:       sync.NewCond(&sync.Mutex{}),
+		queued:     map[interface{}]*queuedItem{},
+		dirty:      map[interface{}]bool{},
+		processing: map[interface{}]time.Time{},
+		added:      map[interface{}]time.Time{},
+	}
+	if name != "" {
+		q.depth = queueMetricsProvider{}.NewDepthMetric(name)
+		q.adds = queueMetricsProvider{}.NewAddsMetric(name)
+		q.latency = queueMetricsProvider{}.NewLatencyMetric(name)
+		q.workDuration = queueMetricsProvider{}.NewWorkDurationMetric(name)
+	}
+	return q
+}
+
+func (q *priorityQueue) Add(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown {
+		return
+	}
+	if q.dirty[item] {
+		if e, ok := q.queued[item]; ok && e.prioritized != q.prioritize(item) {
+			q.push(item)
+		}
+		return
+	}
+	if q.adds != nil {
+		q.adds.Inc()
+		q.depth.Inc()
+	}
+	q.added[item] = time.Now()
+	q.dirty[item] = true
+	if _, ok := q.processing[item]; ok {
+		return
+	}
+	q.push(item)
+	q.cond.Signal()
+}
+
+// push classifies the given item and appends it to the corresponding FIFO.
+func (q *priorityQueue) push(item interface{}) {
+	e := &queuedItem{item: item, prioritized: q.prioritize(item)}
+	if e.prioritized {
+		q.prioritized = append(q.prioritized, e)
+	} else {
+		q.queue = append(q.queue, e)
+	}
+	q.queued[item] = e
+}
+
+// pop removes and returns the first item in the given FIFO that's still queued there, if any.
+func (q *priorityQueue) pop(fifo *[]*queuedItem) (interface{}, bool) {
+	for len(*fifo) > 0 {
+		e := (*fifo)[0]
+		(*fifo)[0] = nil
+		*fifo = (*fifo)[1:]
+		if q.queued[e.item] == e {
+			delete(q.queued, e.item)
+			return e.item, true
+		}
+	}
+	return nil, false
+}
+
+func (q *priorityQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return len(q.queued)
+}
+
+// Get returns the first prioritized item on the queue, or the first item if none are prioritized.
+func (q *priorityQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for len(q.queued) == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if len(q.queued) == 0 {
+		return nil, true
+	}
+
+	item, ok := q.pop(&q.prioritized)
+	if !ok {
+		item, _ = q.pop(&q.queue)
+	}
+
+	if q.latency != nil {
+		q.depth.Dec()
+		q.latency.Observe(time.Since(q.added[item]).Seconds())
+	}
+	delete(q.added, item)
+	q.processing[item] = time.Now()
+	delete(q.dirty, item)
+	return item, false
+}
+
+func (q *priorityQueue) Done(item interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if started, ok := q.processing[item]; ok && q.workDuration != nil {
+		q.workDuration.Observe(time.Since(started).Seconds())
+	}
+	delete(q.processing, item)
+	if q.dirty[item] {
+		q.push(item)
+		q.cond.Signal()
+	} else if len(q.processing) == 0 {
+		// Wake any ShutDownWithDrain as well as a worker.
+		q.cond.Broadcast()
+	}
+}
+
+func (q *priorityQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// ShutDownWithDrain shuts the queue down and waits for the items being processed to be done.
+func (q *priorityQueue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+	for len(q.processing) > 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+func (q *priorityQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"sort"
+	"time"
+)
+
+// defaultQuarantineRetryPeriod is how often quarantined keys are retried if the cache's
+// arguments don't say otherwise.
+const defaultQuarantineRetryPeriod = 10 * time.Minute
+
+// QuarantinedKey is a key whose update has repeatedly failed to be written to the datastore.
+type QuarantinedKey struct {
+	Key string `json:"key"`
+
+	// Since is when the key was quarantined.
+	Since time.Time `json:"since"`
+}
+
+// Quarantine records that the update for the given key has failed too many times to keep retrying
+// it with the usual backoff. The key is queued again once every quarantine retry period until it
+// is synced.
+func (c *calicoCache[T]) Quarantine(key string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if _, ok := c.quarantined[key]; !ok {
+		c.log.WithField("key", key).Warn("Quarantining key after repeated failures to sync it")
+		c.quarantined[key] = time.Now()
+	}
+}
+
+// IsQuarantined returns whether the given key is quarantined.
+func (c *calicoCache[T]) IsQuarantined(key string) bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	_, ok := c.quarantined[key]
+	return ok
+}
+
+// unquarantine releases the given key from quarantine, if it's there.
+func (c *calicoCache[T]) unquarantine(key string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if _, ok := c.quarantined[key]; ok {
+		c.log.WithField("key", key).Info("Quarantined key has been synced")
+		delete(c.quarantined, key)
+	}
+}
+
+// quarantinedKeys returns the quarantined keys, sorted by key.
+func (c *calicoCache[T]) quarantinedKeys() []QuarantinedKey {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	keys := make([]QuarantinedKey, 0, len(c.quarantined))
+	for k, since := range c.quarantined {
+		keys = append(keys, QuarantinedKey{Key: k, Since: since})
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Key < keys[j].Key })
+	return keys
+}
+
+func (c *calicoCache[T]) quarantineLength() int {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return len(c.quarantined)
+}
+
+// retryQuarantined queues each quarantined key once every period, forever.
+func (c *calicoCache[T]) retryQuarantined(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for range ticker.C {
+		keys := c.quarantinedKeys()
+		if len(keys) == 0 {
+			continue
+		}
+		c.log.WithField("keys", len(keys)).Info("Retrying quarantined keys")
+		for _, k := range keys {
+			c.workqueue.Add(k.Key)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+// shardedQueue is a workqueue.RateLimitingInterface that spreads keys across several queues by
+// hash. Each key always lands on the same shard, so it's never processed by two workers at once
+// and its updates stay in order. Get hands out keys from the shards in turn, so that a burst of
+// updates to the keys on one shard, such as a namespace being flapped, can't hold up the keys on
+// the others.
+type shardedQueue struct {
+	shards []workqueue.RateLimitingInterface
+
+	start sync.Once
+	items chan interface{}
+
+	// The number of keys taken from a shard but not yet handed to a worker.
+	held int32
+}
+
+func newShardedQueue(shards []workqueue.RateLimitingInterface) *shardedQueue {
+	return &shardedQueue{shards: shards, items: make(chan interface{})}
+}
+
+// shard returns the shard for the given key.
+func (q *shardedQueue) shard(item interface{}) workqueue.RateLimitingInterface {
+	key, _ := item.(string)
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return q.shards[h.Sum32()%uint32(len(q.shards))]
+}
+
+func (q *shardedQueue) Add(item interface{}) {
+	q.shard(item).Add(item)
+}
+
+func (q *shardedQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.shard(item).AddAfter(item, duration)
+}
+
+func (q *shardedQueue) AddRateLimited(item interface{}) {
+	q.shard(item).AddRateLimited(item)
+}
+
+func (q *shardedQueue) Forget(item interface{}) {
+	q.shard(item).Forget(item)
+}
+
+func (q *shardedQueue) NumRequeues(item interface{}) int {
+	return q.shard(item).NumRequeues(item)
+}
+
+func (q *shardedQueue) Done(item interface{}) {
+	q.shard(item).Done(item)
+}
+
+func (q *shardedQueue) Len() int {
+	n := int(atomic.LoadInt32(&q.held))
+	for _, s := range q.shards {
+		n += s.Len()
+	}
+	return n
+}
+
+// Get returns the next key from any shard. The first call starts a goroutine per shard that
+// takes keys from the shard and hands them to the workers.
+func (q *shardedQueue) Get() (interface{}, bool) {
+	q.start.Do(func() {
+		var wg sync.WaitGroup
+		for _, s := range q.shards {
+			wg.Add(1)
+			go func(s workqueue.RateLimitingInterface) {
+				defer wg.Done()
+				for {
+					item, shutdown := s.Get()
+					if shutdown {
+						return
+					}
+					atomic.AddInt32(&q.held, 1)
+					q.items <- item
+				}
+			}(s)
+		}
+		go func() {
+			wg.Wait()
+			close(q.items)
+		}()
+	})
+	item, ok := <-q.items
+	if !ok {
+		return nil, true
+	}
+	atomic.AddInt32(&q.held, -1)
+	return item, false
+}
+
+func (q *shardedQueue) ShutDown() {
+	for _, s := range q.shards {
+		s.ShutDown()
+	}
+}
+
+func (q *shardedQueue) ShutDownWithDrain() {
+	var wg sync.WaitGroup
+	for _, s := range q.shards {
+		wg.Add(1)
+		go func(s workqueue.RateLimitingInterface) {
+			defer wg.Done()
+			s.ShutDownWithDrain()
+		}(s)
+	}
+	wg.Wait()
+}
+
+func (q *shardedQueue) ShuttingDown() bool {
+	return q.shards[0].ShuttingDown()
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SnapshotStore persists the snapshots of the Calico datastore taken by a ResourceCache, so that
+// after a restart the cache can reconcile against the latest snapshot rather than listing the
+// whole datastore.
+type SnapshotStore interface {
+	// Load returns the saved snapshot, or nil if there isn't one.
+	Load() ([]byte, error)
+
+	// Save replaces the saved snapshot.
+	Save(data []byte) error
+}
+
+// NewFileSnapshotStore returns a SnapshotStore that saves snapshots to the file at the given path.
+func NewFileSnapshotStore(path string) SnapshotStore {
+	return &fileSnapshotStore{path: path}
+}
+
+type fileSnapshotStore struct {
+	path string
+}
+
+func (s *fileSnapshotStore) Load() ([]byte, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Save writes the snapshot to a temporary file and renames it over the previous one, so that a
+// crash part way through never leaves a truncated snapshot behind.
+func (s *fileSnapshotStore) Save(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// snapshot is the contents of the datastore as listed by a reconciliation.
+type snapshot struct {
+	Type  string                     `json:"type"`
+	Time  time.Time                  `json:"time"`
+	Items map[string]json.RawMessage `json:"items"`
+}
+
+// matches reports whether the snapshot holds exactly the given value for the key. Values are
+// compared in their serialized form, since decoding the snapshot doesn't always reproduce the
+// original values exactly, for example nil slices decode as empty ones.
+func (s *snapshot) matches(key string, value any) bool {
+	if s == nil {
+		return false
+	}
+	raw, err := json.Marshal(value)
+	return err == nil && bytes.Equal(raw, s.Items[key])
+}
+
+// saveSnapshot saves the given contents of the datastore, if snapshots are enabled. Failures are
+// only logged, since the snapshot is just an optimization.
+func (c *calicoCache[T]) saveSnapshot(objMap map[string]T) {
+	if c.snapshots == nil {
+		return
+	}
+	s := snapshot{Type: c.typeDesc, Time: time.Now(), Items: make(map[string]json.RawMessage, len(objMap))}
+	for key, obj := range objMap {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			c.log.WithError(err).WithField("key", key).Warn("Failed to serialize value, not saving snapshot")
+			return
+		}
+		s.Items[key] = raw
+	}
+	data, err := json.Marshal(s)
+	if err == nil {
+		err = c.snapshots.Save(data)
+	}
+	if err != nil {
+		c.log.WithError(err).Warn("Failed to save snapshot of the datastore")
+	}
+}
+
+// loadSnapshot returns the contents of the datastore recorded by the saved snapshot, if snapshots
+// are enabled and there is a recent enough snapshot for this type of object.
+func (c *calicoCache[T]) loadSnapshot() (map[string]T, *snapshot, error) {
+	if c.snapshots == nil {
+		return nil, nil, nil
+	}
+	data, err := c.snapshots.Load()
+	if err != nil || data == nil {
+		return nil, nil, err
+	}
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, nil, err
+	}
+	if s.Type != c.typeDesc {
+		return nil, nil, fmt.Errorf("snapshot is of %s, not %s", s.Type, c.typeDesc)
+	}
+	if age := time.Since(s.Time); c.reconcilerConfig.MaxSnapshotAge > 0 && age > c.reconcilerConfig.MaxSnapshotAge {
+		c.log.WithField("age", age).Info("Ignoring snapshot of the datastore that is too old")
+		return nil, nil, nil
+	}
+	objMap := make(map[string]T, len(s.Items))
+	for key, raw := range s.Items {
+		var obj T
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode %s: %w", key, err)
+		}
+		objMap[key] = obj
+	}
+	c.log.WithFields(log.Fields{"keys": len(objMap), "age": time.Since(s.Time)}).Info("Reconciling against snapshot of the datastore")
+	return objMap, &s, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	divergenceSourceKubernetes = "kubernetes"
+	divergenceSourceDatastore  = "datastore"
+)
+
+// runSpotChecks spot checks a sample of the cache once every period.
+func (c *calicoCache[T]) runSpotChecks(period time.Duration) {
+	for {
+		time.Sleep(period)
+		c.spotCheck()
+	}
+}
+
+// spotCheck compares a random sample of keys in the cache directly with the source of truth and
+// the datastore. If the source of truth differs, the cache is updated to match it. If the
+// datastore differs, the key is queued to be reprogrammed.
+func (c *calicoCache[T]) spotCheck() {
+	keys := c.ListKeys()
+	rand.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	if n := c.reconcilerConfig.SpotCheckSampleSize; n > 0 && len(keys) > n {
+		keys = keys[:n]
+	}
+
+	typ := c.typeDesc
+	for _, key := range keys {
+		clog := c.log.WithField("key", key)
+		cached, ok := c.Get(key)
+		if !ok {
+			// Deleted since we listed the keys.
+			continue
+		}
+
+		if c.sourceGetFunc != nil {
+			obj, exists, err := c.sourceGetFunc(key)
+			if err != nil {
+				clog.WithError(err).Warning("Failed to spot check key against the source of truth")
+				continue
+			}
+			if !exists {
+				clog.Warning("Spot check found a cached value for a key that no longer exists, removing it")
+				divergenceCounter.WithLabelValues(typ, divergenceSourceKubernetes).Inc()
+				c.Delete(key)
+				continue
+			}
+			if !c.equal(obj, cached) {
+				clog.Warning("Spot check found a stale cached value, updating it")
+				divergenceCounter.WithLabelValues(typ, divergenceSourceKubernetes).Inc()
+				c.Set(key, obj)
+				cached = obj
+			}
+		}
+
+		if c.datastoreGetFunc != nil {
+			obj, exists, err := c.datastoreGetFunc(key)
+			if err != nil {
+				clog.WithError(err).Warning("Failed to spot check key against the datastore")
+				continue
+			}
+			if !exists || !c.equal(obj, cached) {
+				clog.Warning("Spot check found the datastore out of sync with the cache, queueing update to reprogram")
+				divergenceCounter.WithLabelValues(typ, divergenceSourceDatastore).Inc()
+				c.workqueue.Add(key)
+			}
+		}
+	}
+}
@@ -15,6 +15,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/kelseyhightower/envconfig"
 )
 
@@ -30,7 +32,8 @@ const (
 
 var AllEnvs = []string{EnvLogLevel, EnvReconcilerPeriod, EnvEnabledControllers, EnvCompactionPeriod, EnvHealthEnabled, EnvSyncNodeLabels, EnvAutoHostEndpoints}
 
-// Config represents the configuration we load from the environment variables
+// Config represents the configuration we load from the environment variables, which are
+// described in docs/configuration.md.
 type Config struct {
 	// Minimum log level to emit.
 	LogLevel string `default:"info" split_words:"true"`
@@ -41,9 +44,197 @@ type Config struct {
 	PolicyWorkers           int `default:"1" split_words:"true"`
 	NodeWorkers             int `default:"1" split_words:"true"`
 
+	// Maximum length of the names of the policies generated by the policy controller.
+	PolicyNameMaxLength int `default:"253" split_words:"true"`
+
+	// Prefix of the names of the policies generated and managed by the policy controller.
+	PolicyNamePrefix string `default:"knp.default." split_words:"true"`
+
+	// Policy name prefixes of other writers to the datastore, which PolicyNamePrefix must not overlap.
+	PolicyOtherNamePrefixes []string `split_words:"true"`
+
+	// Tenants to label the policy controller's sync metrics with, or empty to disable tenant labels.
+	PolicyMetricsTenants []string `split_words:"true"`
+
+	// Namespace label identifying the tenant of a namespace, or empty to use the namespace.
+	PolicyMetricsTenantLabel string `split_words:"true"`
+
+	// Allow DNS to kube-dns in every generated policy that applies to egress.
+	PolicyAllowDNS bool `default:"false" split_words:"true"`
+
+	// Lowest order that the projectcalico.org/order annotation may give a generated policy.
+	PolicyMinOrder float64 `default:"1000" split_words:"true"`
+
+	// Report the changes the controllers would make to the datastore rather than making them.
+	DryRun bool `default:"false" split_words:"true"`
+
+	// Maximum period that the reconcilers back off to while no drift is detected, or 0 to not back off.
+	MaxReconcilerPeriod time.Duration `default:"0" split_words:"true"`
+
+	// Fraction of the reconciler period to randomly lengthen each wait by, or 0 to disable.
+	ReconcilerJitter float64 `default:"0" split_words:"true"`
+
+	// Maximum number of resources queued for the datastore by each controller, or 0 for no limit.
+	MaxQueueDepth int `default:"0" split_words:"true"`
+
+	// How long to wait after a change to a resource before writing it, or 0 to write it at once.
+	UpdateCoalesceWindow time.Duration `default:"0" split_words:"true"`
+
+	// Maximum time to spend syncing a single resource to the datastore, or 0 for no limit.
+	SyncTimeout time.Duration `default:"1m" split_words:"true"`
+
+	// Consecutive failed writes after which writes to the datastore are suspended, or 0 to disable.
+	DatastoreFailureThreshold int           `default:"0" split_words:"true"`
+	DatastoreMaxProbeInterval time.Duration `default:"1m" split_words:"true"`
+
+	// Fraction of syncs that may be retries of failed syncs, or 0 for no limit.
+	RetryBudgetRatio float64 `default:"0" split_words:"true"`
+
+	// How long a worker may spend on one sync before kube-controllers reports not live, or 0 to disable.
+	WorkerStallTimeout time.Duration `default:"0" split_words:"true"`
+
+	// How often to write a heartbeat to the KubeControllersConfiguration status, or 0 to disable.
+	HeartbeatPeriod time.Duration `default:"0" split_words:"true"`
+
+	// How long to wait at startup for the datastore to become reachable, or 0 to wait indefinitely.
+	DatastoreStartupTimeout time.Duration `default:"60s" split_words:"true"`
+
+	// How long the datastore may be unreachable before the connection is rebuilt, or 0 to disable.
+	DatastoreReconnectAfter time.Duration `default:"0" split_words:"true"`
+
+	// How often to spot check a sample of the cached resources, or 0 to disable.
+	SpotCheckPeriod     time.Duration `default:"0" split_words:"true"`
+	SpotCheckSampleSize int           `default:"10" split_words:"true"`
+
+	// Maximum rate of deletes from the datastore, in deletes per second, or 0 for no limit.
+	DeleteRateLimit float64 `default:"0" split_words:"true"`
+	DeleteBurst     int     `default:"1" split_words:"true"`
+
+	// Period to spread the writes of large reconciliations over, or 0 to disable.
+	ResyncWindow    time.Duration `default:"0" split_words:"true"`
+	ResyncThreshold int           `default:"100" split_words:"true"`
+
+	// Fraction of resources that a reconciliation may delete without confirmation, or 0 to disable.
+	MassDeleteThreshold float64 `default:"0" split_words:"true"`
+
+	// Directory to save snapshots of the datastore in, or empty to disable.
+	SnapshotDir    string        `default:"" split_words:"true"`
+	MaxSnapshotAge time.Duration `default:"1h" split_words:"true"`
+
+	// Page size for lists from the k8s API, or 0 to list without pagination.
+	ListPageSize int64 `default:"0" split_words:"true"`
+
+	// Glob patterns of namespaces to ignore.
+	ExcludeNamespaces []string `split_words:"true"`
+
+	// Prefixes of the node labels to sync onto Calico nodes, or empty to sync all labels.
+	SyncNodeLabelPrefixes []string `split_words:"true"`
+
+	// Calico node label that makes a node a route reflector, or empty to disable.
+	RouteReflectorLabel     string `default:"" split_words:"true"`
+	RouteReflectorClusterID string `default:"244.0.0.1" split_words:"true"`
+
+	// Generate BGP peers from the projectcalico.org/bgp-peers annotation of nodes.
+	NodeBGPPeerAnnotations bool `default:"false" split_words:"true"`
+
+	// Selectors restricting the namespaces and network policies that are managed.
+	NamespaceLabelSelector string `default:"" split_words:"true"`
+	NamespaceFieldSelector string `default:"" split_words:"true"`
+	PolicyLabelSelector    string `default:"" split_words:"true"`
+	PolicyFieldSelector    string `default:"" split_words:"true"`
+
+	// Namespace labels, or prefixes ending in "*", never copied to profiles.
+	ProfileLabelDenyList []string `split_words:"true"`
+
+	// Namespace annotations, or prefixes ending in "*", copied to profiles as labels.
+	ProfileAnnotationLabels      []string `split_words:"true"`
+	ProfileAnnotationLabelPrefix string   `default:"annotation." split_words:"true"`
+
+	// Make the generated namespace profiles deny traffic by default.
+	ProfileDefaultDeny bool `default:"false" split_words:"true"`
+
+	// Maintain the policies allowing the traffic of critical system components.
+	SystemPolicies bool `default:"false" split_words:"true"`
+
+	// Maintain a policy allowing the given ports to and from all host endpoints.
+	FailsafePolicy              bool     `default:"false" split_words:"true"`
+	FailsafePolicyInboundPorts  []string `default:"tcp:22,udp:68,tcp:179,tcp:2379,tcp:2380,tcp:5473,tcp:6443,tcp:10250" split_words:"true"`
+	FailsafePolicyOutboundPorts []string `default:"udp:53,tcp:53,udp:67,tcp:179,tcp:2379,tcp:2380,tcp:5473,tcp:6443,tcp:10250" split_words:"true"`
+
+	// Create IP pools for namespaces with the projectcalico.org/ippool annotation.
+	NamespaceIPPools      bool     `default:"false" split_words:"true"`
+	NamespaceIPPoolRanges []string `split_words:"true"`
+
+	// Assign namespaces to the IP pools named in their projectcalico.org/ippools annotation.
+	NamespacePoolAssignment bool `default:"false" split_words:"true"`
+
+	// Allocate the addresses of LoadBalancer services from IP pools.
+	LoadBalancerIPAllocation bool `default:"false" split_words:"true"`
+
+	// ConfigMap, as namespace/name, applied to the default FelixConfiguration, or empty to disable.
+	FelixConfigMap string `default:"" split_words:"true"`
+
+	// Threat feeds, as name=url, to write to GlobalNetworkSets.
+	ThreatFeeds      []string      `split_words:"true"`
+	ThreatFeedPeriod time.Duration `default:"1h" split_words:"true"`
+
+	// Label selector of the ConfigMaps to project into NetworkSets, or empty to disable.
+	NetworkSetConfigMapLabel string `default:"" split_words:"true"`
+
+	// Resolve the domains of NetworkSets with the projectcalico.org/domains annotation.
+	DNSNetworkSets      bool          `default:"false" split_words:"true"`
+	DNSNetworkSetMinTTL time.Duration `default:"30s" split_words:"true"`
+	DNSNetworkSetMaxTTL time.Duration `default:"1h" split_words:"true"`
+
+	// Maintain NetworkSets of the endpoints of annotated Services.
+	ServiceNetworkSets bool `default:"false" split_words:"true"`
+
+	// Convert ExternalHost resources into HostEndpoints.
+	ExternalHosts bool `default:"false" split_words:"true"`
+
+	// How often to check the consistency of the resources written by the controllers, or 0 to disable.
+	ConsistencyCheckPeriod time.Duration `default:"0" split_words:"true"`
+
+	// How often to check for duplicate IPs, or 0 to disable.
+	DuplicateIPCheckPeriod   time.Duration `default:"0" split_words:"true"`
+	ReleaseDuplicateIPClaims bool          `default:"false" split_words:"true"`
+
+	// How often to check the WireGuard keys of the nodes, or 0 to disable.
+	WireguardKeyCheckPeriod time.Duration `default:"0" split_words:"true"`
+	ClearStaleWireguardKeys bool          `default:"false" split_words:"true"`
+
 	// Path to a kubeconfig file to use for accessing the k8s API.
 	Kubeconfig string `default:"" split_words:"false"`
 
+	// User agent to use for requests to the k8s API, or empty to use the client default.
+	KubeClientUserAgent string `default:"" split_words:"true"`
+
+	// Timeout for requests to the k8s API, or 0 for no timeout.
+	KubeClientTimeout time.Duration `default:"0" split_words:"true"`
+
+	// Rate limits of the k8s and Calico API clients, or 0 to use the client defaults.
+	KubeClientQPS   float32 `default:"0" split_words:"true"`
+	KubeClientBurst int     `default:"0" split_words:"true"`
+
+	// Address (host:port) of an OTLP/gRPC collector to send traces to, or empty to disable tracing.
+	OTLPEndpoint string `default:"" split_words:"true"`
+
+	// Address (host:port) of a StatsD server to push metrics to, or empty to disable.
+	StatsdAddress      string        `default:"" split_words:"true"`
+	StatsdPrefix       string        `default:"calico_kube_controllers." split_words:"true"`
+	StatsdPushInterval time.Duration `default:"10s" split_words:"true"`
+
+	// Address to bind the Prometheus metrics server to, or empty to listen on all addresses.
+	MetricsBindAddress string `default:"" split_words:"true"`
+
+	// Certificate, key and client CA for serving metrics over TLS.
+	MetricsTLSCertFile  string `default:"" split_words:"true"`
+	MetricsTLSKeyFile   string `default:"" split_words:"true"`
+	MetricsClientCAFile string `default:"" split_words:"true"`
+
+	// File containing the bearer token for the admin API, or empty to disable it.
+	AdminTokenFile string `default:"" split_words:"true"`
+
 	// etcdv3 or kubernetes
 	DatastoreType string `default:"etcdv3" split_words:"true"`
 }
@@ -44,11 +44,82 @@ var _ = Describe("Config", func() {
 		os.Unsetenv("PROFILE_WORKERS")
 		os.Unsetenv("POLICY_WORKERS")
 		os.Unsetenv("KUBECONFIG")
+		os.Unsetenv("KUBE_CLIENT_USER_AGENT")
+		os.Unsetenv("KUBE_CLIENT_TIMEOUT")
+		os.Unsetenv("KUBE_CLIENT_QPS")
+		os.Unsetenv("KUBE_CLIENT_BURST")
+		os.Unsetenv("OTLP_ENDPOINT")
+		os.Unsetenv("DATASTORE_FAILURE_THRESHOLD")
+		os.Unsetenv("RETRY_BUDGET_RATIO")
+		os.Unsetenv("DATASTORE_MAX_PROBE_INTERVAL")
+		os.Unsetenv("WORKER_STALL_TIMEOUT")
+		os.Unsetenv("HEARTBEAT_PERIOD")
+		os.Unsetenv("DATASTORE_STARTUP_TIMEOUT")
+		os.Unsetenv("DATASTORE_RECONNECT_AFTER")
+		os.Unsetenv("STATSD_ADDRESS")
+		os.Unsetenv("STATSD_PREFIX")
+		os.Unsetenv("STATSD_PUSH_INTERVAL")
+		os.Unsetenv("METRICS_BIND_ADDRESS")
+		os.Unsetenv("METRICS_TLS_CERT_FILE")
+		os.Unsetenv("METRICS_TLS_KEY_FILE")
+		os.Unsetenv("METRICS_CLIENT_CA_FILE")
+		os.Unsetenv("ADMIN_TOKEN_FILE")
 		os.Unsetenv("DATASTORE_TYPE")
 		os.Unsetenv("HEALTH_ENABLED")
 		os.Unsetenv("COMPACTION_PERIOD")
 		os.Unsetenv("SYNC_NODE_LABELS")
 		os.Unsetenv("AUTO_HOST_ENDPOINTS")
+		os.Unsetenv("POLICY_NAME_MAX_LENGTH")
+		os.Unsetenv("POLICY_NAME_PREFIX")
+		os.Unsetenv("POLICY_OTHER_NAME_PREFIXES")
+		os.Unsetenv("DRY_RUN")
+		os.Unsetenv("CONSISTENCY_CHECK_PERIOD")
+		os.Unsetenv("DUPLICATE_IP_CHECK_PERIOD")
+		os.Unsetenv("RELEASE_DUPLICATE_IP_CLAIMS")
+		os.Unsetenv("WIREGUARD_KEY_CHECK_PERIOD")
+		os.Unsetenv("CLEAR_STALE_WIREGUARD_KEYS")
+		os.Unsetenv("SYSTEM_POLICIES")
+		os.Unsetenv("NAMESPACE_IP_POOLS")
+		os.Unsetenv("NAMESPACE_IP_POOL_RANGES")
+		os.Unsetenv("NAMESPACE_POOL_ASSIGNMENT")
+		os.Unsetenv("LOAD_BALANCER_IP_ALLOCATION")
+		os.Unsetenv("FELIX_CONFIG_MAP")
+		os.Unsetenv("THREAT_FEEDS")
+		os.Unsetenv("THREAT_FEED_PERIOD")
+		os.Unsetenv("NETWORK_SET_CONFIG_MAP_LABEL")
+		os.Unsetenv("DNS_NETWORK_SETS")
+		os.Unsetenv("DNS_NETWORK_SET_MIN_TTL")
+		os.Unsetenv("DNS_NETWORK_SET_MAX_TTL")
+		os.Unsetenv("SERVICE_NETWORK_SETS")
+		os.Unsetenv("EXTERNAL_HOSTS")
+		os.Unsetenv("FAILSAFE_POLICY")
+		os.Unsetenv("FAILSAFE_POLICY_INBOUND_PORTS")
+		os.Unsetenv("FAILSAFE_POLICY_OUTBOUND_PORTS")
+		os.Unsetenv("MAX_RECONCILER_PERIOD")
+		os.Unsetenv("SPOT_CHECK_PERIOD")
+		os.Unsetenv("SPOT_CHECK_SAMPLE_SIZE")
+		os.Unsetenv("DELETE_RATE_LIMIT")
+		os.Unsetenv("DELETE_BURST")
+		os.Unsetenv("LIST_PAGE_SIZE")
+		os.Unsetenv("EXCLUDE_NAMESPACES")
+		os.Unsetenv("SYNC_NODE_LABEL_PREFIXES")
+		os.Unsetenv("ROUTE_REFLECTOR_LABEL")
+		os.Unsetenv("ROUTE_REFLECTOR_CLUSTER_ID")
+		os.Unsetenv("NODE_BGP_PEER_ANNOTATIONS")
+		os.Unsetenv("PROFILE_LABEL_DENY_LIST")
+		os.Unsetenv("PROFILE_ANNOTATION_LABELS")
+		os.Unsetenv("PROFILE_ANNOTATION_LABEL_PREFIX")
+		os.Unsetenv("PROFILE_DEFAULT_DENY")
+		os.Unsetenv("NAMESPACE_LABEL_SELECTOR")
+		os.Unsetenv("NAMESPACE_FIELD_SELECTOR")
+		os.Unsetenv("POLICY_LABEL_SELECTOR")
+		os.Unsetenv("POLICY_FIELD_SELECTOR")
+		os.Unsetenv("RESYNC_WINDOW")
+		os.Unsetenv("RESYNC_THRESHOLD")
+		os.Unsetenv("POLICY_METRICS_TENANTS")
+		os.Unsetenv("POLICY_METRICS_TENANT_LABEL")
+		os.Unsetenv("POLICY_ALLOW_DNS")
+		os.Unsetenv("POLICY_MIN_ORDER")
 	}
 
 	// setEnv() function that sets environment variables
@@ -61,11 +132,48 @@ var _ = Describe("Config", func() {
 		os.Setenv("PROFILE_WORKERS", "3")
 		os.Setenv("POLICY_WORKERS", "4")
 		os.Setenv("KUBECONFIG", "/home/user/.kube/config")
+		os.Setenv("KUBE_CLIENT_USER_AGENT", "calico-kube-controllers/test")
+		os.Setenv("KUBE_CLIENT_TIMEOUT", "45s")
+		os.Setenv("KUBE_CLIENT_QPS", "50")
+		os.Setenv("KUBE_CLIENT_BURST", "100")
+		os.Setenv("OTLP_ENDPOINT", "otel-collector:4317")
+		os.Setenv("DATASTORE_FAILURE_THRESHOLD", "20")
+		os.Setenv("RETRY_BUDGET_RATIO", "0.2")
+		os.Setenv("DATASTORE_MAX_PROBE_INTERVAL", "5m")
+		os.Setenv("WORKER_STALL_TIMEOUT", "10m")
+		os.Setenv("HEARTBEAT_PERIOD", "30s")
+		os.Setenv("DATASTORE_STARTUP_TIMEOUT", "0")
+		os.Setenv("DATASTORE_RECONNECT_AFTER", "30s")
+		os.Setenv("STATSD_ADDRESS", "localhost:8125")
+		os.Setenv("STATSD_PREFIX", "kc.")
+		os.Setenv("STATSD_PUSH_INTERVAL", "30s")
+		os.Setenv("METRICS_BIND_ADDRESS", "10.0.0.1")
+		os.Setenv("METRICS_TLS_CERT_FILE", "/certs/tls.crt")
+		os.Setenv("METRICS_TLS_KEY_FILE", "/certs/tls.key")
+		os.Setenv("METRICS_CLIENT_CA_FILE", "/certs/ca.crt")
+		os.Setenv("ADMIN_TOKEN_FILE", "/admin/token")
 		os.Setenv("DATASTORE_TYPE", "etcdv3")
 		os.Setenv("HEALTH_ENABLED", "false")
 		os.Setenv("COMPACTION_PERIOD", "33m")
 		os.Setenv("SYNC_NODE_LABELS", "false")
 		os.Setenv("AUTO_HOST_ENDPOINTS", "enabled")
+		os.Setenv("POLICY_METRICS_TENANTS", "team-a,team-b")
+		os.Setenv("EXCLUDE_NAMESPACES", "ci-*,scratch")
+		os.Setenv("DUPLICATE_IP_CHECK_PERIOD", "5m")
+		os.Setenv("RELEASE_DUPLICATE_IP_CLAIMS", "true")
+		os.Setenv("WIREGUARD_KEY_CHECK_PERIOD", "2m")
+		os.Setenv("CLEAR_STALE_WIREGUARD_KEYS", "true")
+		os.Setenv("PROFILE_LABEL_DENY_LIST", "internal.example.com/*,cost-center")
+		os.Setenv("PROFILE_ANNOTATION_LABELS", "example.com/team,owner.example.com/*")
+		os.Setenv("PROFILE_ANNOTATION_LABEL_PREFIX", "ann.")
+		os.Setenv("PROFILE_DEFAULT_DENY", "true")
+		os.Setenv("NAMESPACE_LABEL_SELECTOR", "owner=team-a")
+		os.Setenv("NAMESPACE_FIELD_SELECTOR", "metadata.name!=default")
+		os.Setenv("POLICY_LABEL_SELECTOR", "owner in (team-a,team-b)")
+		os.Setenv("POLICY_FIELD_SELECTOR", "metadata.namespace!=kube-system")
+		os.Setenv("POLICY_METRICS_TENANT_LABEL", "example.com/tenant")
+		os.Setenv("POLICY_ALLOW_DNS", "true")
+		os.Setenv("POLICY_MIN_ORDER", "100")
 	}
 
 	// setWrongEnv() function sets environment variables
@@ -95,6 +203,45 @@ var _ = Describe("Config", func() {
 			Expect(cfg.ProfileWorkers).To(Equal(1))
 			Expect(cfg.PolicyWorkers).To(Equal(1))
 			Expect(cfg.Kubeconfig).To(Equal(""))
+			Expect(cfg.KubeClientUserAgent).To(Equal(""))
+			Expect(cfg.KubeClientTimeout).To(BeZero())
+			Expect(cfg.KubeClientQPS).To(BeZero())
+			Expect(cfg.KubeClientBurst).To(BeZero())
+			Expect(cfg.ExcludeNamespaces).To(BeEmpty())
+			Expect(cfg.DuplicateIPCheckPeriod).To(BeZero())
+			Expect(cfg.ReleaseDuplicateIPClaims).To(BeFalse())
+			Expect(cfg.WireguardKeyCheckPeriod).To(BeZero())
+			Expect(cfg.ClearStaleWireguardKeys).To(BeFalse())
+			Expect(cfg.NamespaceIPPools).To(BeFalse())
+			Expect(cfg.NamespaceIPPoolRanges).To(BeEmpty())
+			Expect(cfg.NamespacePoolAssignment).To(BeFalse())
+			Expect(cfg.LoadBalancerIPAllocation).To(BeFalse())
+			Expect(cfg.PolicyAllowDNS).To(BeFalse())
+			Expect(cfg.PolicyMinOrder).To(Equal(1000.0))
+			Expect(cfg.ProfileLabelDenyList).To(BeEmpty())
+			Expect(cfg.ProfileAnnotationLabels).To(BeEmpty())
+			Expect(cfg.ProfileAnnotationLabelPrefix).To(Equal("annotation."))
+			Expect(cfg.ProfileDefaultDeny).To(BeFalse())
+			Expect(cfg.NamespaceLabelSelector).To(Equal(""))
+			Expect(cfg.NamespaceFieldSelector).To(Equal(""))
+			Expect(cfg.PolicyLabelSelector).To(Equal(""))
+			Expect(cfg.PolicyFieldSelector).To(Equal(""))
+			Expect(cfg.OTLPEndpoint).To(Equal(""))
+			Expect(cfg.DatastoreFailureThreshold).To(Equal(0))
+			Expect(cfg.RetryBudgetRatio).To(Equal(0.0))
+			Expect(cfg.DatastoreMaxProbeInterval).To(Equal(time.Minute))
+			Expect(cfg.WorkerStallTimeout).To(BeZero())
+			Expect(cfg.HeartbeatPeriod).To(BeZero())
+			Expect(cfg.DatastoreStartupTimeout).To(Equal(time.Minute))
+			Expect(cfg.DatastoreReconnectAfter).To(Equal(time.Duration(0)))
+			Expect(cfg.StatsdAddress).To(Equal(""))
+			Expect(cfg.StatsdPrefix).To(Equal("calico_kube_controllers."))
+			Expect(cfg.StatsdPushInterval).To(Equal(10 * time.Second))
+			Expect(cfg.MetricsBindAddress).To(Equal(""))
+			Expect(cfg.MetricsTLSCertFile).To(Equal(""))
+			Expect(cfg.MetricsTLSKeyFile).To(Equal(""))
+			Expect(cfg.MetricsClientCAFile).To(Equal(""))
+			Expect(cfg.AdminTokenFile).To(Equal(""))
 		})
 
 		Context("with default API values", func() {
@@ -126,21 +273,43 @@ var _ = Describe("Config", func() {
 					DeleteNodes:       true,
 					LeakGracePeriod:   &v1.Duration{Duration: 15 * time.Minute},
 				}))
-				Expect(rc.Policy).To(Equal(&config.GenericControllerConfig{
-					ReconcilerPeriod: time.Minute * 5,
-					NumberOfWorkers:  1,
+				Expect(rc.Policy).To(Equal(&config.PolicyControllerConfig{
+					GenericControllerConfig: config.GenericControllerConfig{
+						ReconcilerPeriod:    time.Minute * 5,
+						NumberOfWorkers:     1,
+						SyncTimeout:         time.Minute,
+						SpotCheckSampleSize: 10,
+						DeleteBurst:         1,
+						ResyncThreshold:     100,
+						MaxSnapshotAge:      time.Hour,
+					},
+					MaxNameLength: 253,
+					NamePrefix:    "knp.default.",
+					MinOrder:      1000,
 				}))
 				Expect(rc.Namespace).To(Equal(&config.GenericControllerConfig{
-					ReconcilerPeriod: time.Minute * 5,
-					NumberOfWorkers:  1,
+					ReconcilerPeriod:      time.Minute * 5,
+					NumberOfWorkers:       1,
+					SyncTimeout:           time.Minute,
+					SpotCheckSampleSize:   10,
+					DeleteBurst:           1,
+					ResyncThreshold:       100,
+					MaxSnapshotAge:        time.Hour,
+					AnnotationLabelPrefix: "annotation.",
 				}))
 				Expect(rc.WorkloadEndpoint).To(Equal(&config.GenericControllerConfig{
 					ReconcilerPeriod: time.Minute * 5,
 					NumberOfWorkers:  1,
+					SyncTimeout:      time.Minute,
 				}))
 				Expect(rc.ServiceAccount).To(Equal(&config.GenericControllerConfig{
-					ReconcilerPeriod: time.Minute * 5,
-					NumberOfWorkers:  1,
+					ReconcilerPeriod:    time.Minute * 5,
+					NumberOfWorkers:     1,
+					SyncTimeout:         time.Minute,
+					SpotCheckSampleSize: 10,
+					DeleteBurst:         1,
+					ResyncThreshold:     100,
+					MaxSnapshotAge:      time.Hour,
 				}))
 				close(done)
 			})
@@ -224,21 +393,43 @@ var _ = Describe("Config", func() {
 					DeleteNodes:       true,
 					LeakGracePeriod:   &v1.Duration{Duration: 20 * time.Minute},
 				}))
-				Expect(rc.Policy).To(Equal(&config.GenericControllerConfig{
-					ReconcilerPeriod: time.Second * 30,
-					NumberOfWorkers:  1,
+				Expect(rc.Policy).To(Equal(&config.PolicyControllerConfig{
+					GenericControllerConfig: config.GenericControllerConfig{
+						ReconcilerPeriod:    time.Second * 30,
+						NumberOfWorkers:     1,
+						SyncTimeout:         time.Minute,
+						SpotCheckSampleSize: 10,
+						DeleteBurst:         1,
+						ResyncThreshold:     100,
+						MaxSnapshotAge:      time.Hour,
+					},
+					MaxNameLength: 253,
+					NamePrefix:    "knp.default.",
+					MinOrder:      1000,
 				}))
 				Expect(rc.WorkloadEndpoint).To(Equal(&config.GenericControllerConfig{
 					ReconcilerPeriod: time.Second * 31,
 					NumberOfWorkers:  1,
+					SyncTimeout:      time.Minute,
 				}))
 				Expect(rc.Namespace).To(Equal(&config.GenericControllerConfig{
-					ReconcilerPeriod: time.Second * 32,
-					NumberOfWorkers:  1,
+					ReconcilerPeriod:      time.Second * 32,
+					NumberOfWorkers:       1,
+					SyncTimeout:           time.Minute,
+					SpotCheckSampleSize:   10,
+					DeleteBurst:           1,
+					ResyncThreshold:       100,
+					MaxSnapshotAge:        time.Hour,
+					AnnotationLabelPrefix: "annotation.",
 				}))
 				Expect(rc.ServiceAccount).To(Equal(&config.GenericControllerConfig{
-					ReconcilerPeriod: time.Second * 33,
-					NumberOfWorkers:  1,
+					ReconcilerPeriod:    time.Second * 33,
+					NumberOfWorkers:     1,
+					SyncTimeout:         time.Minute,
+					SpotCheckSampleSize: 10,
+					DeleteBurst:         1,
+					ResyncThreshold:     100,
+					MaxSnapshotAge:      time.Hour,
 				}))
 				close(done)
 			})
@@ -416,6 +607,43 @@ var _ = Describe("Config", func() {
 			Expect(cfg.ProfileWorkers).To(Equal(3))
 			Expect(cfg.PolicyWorkers).To(Equal(4))
 			Expect(cfg.Kubeconfig).To(Equal("/home/user/.kube/config"))
+			Expect(cfg.KubeClientUserAgent).To(Equal("calico-kube-controllers/test"))
+			Expect(cfg.KubeClientTimeout).To(Equal(45 * time.Second))
+			Expect(cfg.KubeClientQPS).To(Equal(float32(50)))
+			Expect(cfg.KubeClientBurst).To(Equal(100))
+			Expect(cfg.OTLPEndpoint).To(Equal("otel-collector:4317"))
+			Expect(cfg.DatastoreFailureThreshold).To(Equal(20))
+			Expect(cfg.RetryBudgetRatio).To(Equal(0.2))
+			Expect(cfg.DatastoreMaxProbeInterval).To(Equal(5 * time.Minute))
+			Expect(cfg.WorkerStallTimeout).To(Equal(10 * time.Minute))
+			Expect(cfg.HeartbeatPeriod).To(Equal(30 * time.Second))
+			Expect(cfg.DatastoreStartupTimeout).To(BeZero())
+			Expect(cfg.DatastoreReconnectAfter).To(Equal(30 * time.Second))
+			Expect(cfg.StatsdAddress).To(Equal("localhost:8125"))
+			Expect(cfg.StatsdPrefix).To(Equal("kc."))
+			Expect(cfg.StatsdPushInterval).To(Equal(30 * time.Second))
+			Expect(cfg.MetricsBindAddress).To(Equal("10.0.0.1"))
+			Expect(cfg.MetricsTLSCertFile).To(Equal("/certs/tls.crt"))
+			Expect(cfg.MetricsTLSKeyFile).To(Equal("/certs/tls.key"))
+			Expect(cfg.MetricsClientCAFile).To(Equal("/certs/ca.crt"))
+			Expect(cfg.AdminTokenFile).To(Equal("/admin/token"))
+			Expect(cfg.PolicyMetricsTenants).To(Equal([]string{"team-a", "team-b"}))
+			Expect(cfg.ExcludeNamespaces).To(Equal([]string{"ci-*", "scratch"}))
+			Expect(cfg.DuplicateIPCheckPeriod).To(Equal(5 * time.Minute))
+			Expect(cfg.ReleaseDuplicateIPClaims).To(BeTrue())
+			Expect(cfg.WireguardKeyCheckPeriod).To(Equal(2 * time.Minute))
+			Expect(cfg.ClearStaleWireguardKeys).To(BeTrue())
+			Expect(cfg.ProfileLabelDenyList).To(Equal([]string{"internal.example.com/*", "cost-center"}))
+			Expect(cfg.ProfileAnnotationLabels).To(Equal([]string{"example.com/team", "owner.example.com/*"}))
+			Expect(cfg.ProfileAnnotationLabelPrefix).To(Equal("ann."))
+			Expect(cfg.ProfileDefaultDeny).To(BeTrue())
+			Expect(cfg.NamespaceLabelSelector).To(Equal("owner=team-a"))
+			Expect(cfg.NamespaceFieldSelector).To(Equal("metadata.name!=default"))
+			Expect(cfg.PolicyLabelSelector).To(Equal("owner in (team-a,team-b)"))
+			Expect(cfg.PolicyFieldSelector).To(Equal("metadata.namespace!=kube-system"))
+			Expect(cfg.PolicyMetricsTenantLabel).To(Equal("example.com/tenant"))
+			Expect(cfg.PolicyAllowDNS).To(BeTrue())
+			Expect(cfg.PolicyMinOrder).To(Equal(100.0))
 		})
 
 		Context("with default API values", func() {
@@ -447,9 +675,26 @@ var _ = Describe("Config", func() {
 					DeleteNodes:       true,
 					LeakGracePeriod:   &v1.Duration{Duration: 15 * time.Minute},
 				}))
-				Expect(rc.Policy).To(Equal(&config.GenericControllerConfig{
-					ReconcilerPeriod: time.Second * 105,
-					NumberOfWorkers:  4,
+				Expect(rc.Policy).To(Equal(&config.PolicyControllerConfig{
+					GenericControllerConfig: config.GenericControllerConfig{
+						ReconcilerPeriod:    time.Second * 105,
+						NumberOfWorkers:     4,
+						SyncTimeout:         time.Minute,
+						RetryBudgetRatio:    0.2,
+						SpotCheckSampleSize: 10,
+						DeleteBurst:         1,
+						ResyncThreshold:     100,
+						MaxSnapshotAge:      time.Hour,
+						ExcludeNamespaces:   []string{"ci-*", "scratch"},
+						LabelSelector:       "owner in (team-a,team-b)",
+						FieldSelector:       "metadata.namespace!=kube-system",
+					},
+					MaxNameLength:      253,
+					NamePrefix:         "knp.default.",
+					MetricsTenants:     []string{"team-a", "team-b"},
+					MetricsTenantLabel: "example.com/tenant",
+					AllowDNS:           true,
+					MinOrder:           100,
 				}))
 				Expect(rc.Namespace).To(BeNil())
 				Expect(rc.WorkloadEndpoint).To(BeNil())
@@ -539,9 +784,26 @@ var _ = Describe("Config", func() {
 					AutoHostEndpoints: true,
 					DeleteNodes:       true,
 				}))
-				Expect(rc.Policy).To(Equal(&config.GenericControllerConfig{
-					ReconcilerPeriod: time.Second * 105,
-					NumberOfWorkers:  4,
+				Expect(rc.Policy).To(Equal(&config.PolicyControllerConfig{
+					GenericControllerConfig: config.GenericControllerConfig{
+						ReconcilerPeriod:    time.Second * 105,
+						NumberOfWorkers:     4,
+						SyncTimeout:         time.Minute,
+						RetryBudgetRatio:    0.2,
+						SpotCheckSampleSize: 10,
+						DeleteBurst:         1,
+						ResyncThreshold:     100,
+						MaxSnapshotAge:      time.Hour,
+						ExcludeNamespaces:   []string{"ci-*", "scratch"},
+						LabelSelector:       "owner in (team-a,team-b)",
+						FieldSelector:       "metadata.namespace!=kube-system",
+					},
+					MaxNameLength:      253,
+					NamePrefix:         "knp.default.",
+					MetricsTenants:     []string{"team-a", "team-b"},
+					MetricsTenantLabel: "example.com/tenant",
+					AllowDNS:           true,
+					MinOrder:           100,
 				}))
 				Expect(rc.WorkloadEndpoint).To(BeNil())
 				Expect(rc.Namespace).To(BeNil())
@@ -654,6 +916,468 @@ var _ = Describe("Config", func() {
 			Expect(runCfg.Controllers.ServiceAccount.ReconcilerPeriod).To(Equal(time.Second * 33))
 			close(done)
 		})
+
+		It("should apply the list page size to the policy, namespace and service account controllers", func(done Done) {
+			Expect(os.Setenv("LIST_PAGE_SIZE", "250")).To(Succeed())
+			cfg := new(config.Config)
+			err := cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.Policy.ListPageSize).To(Equal(int64(250)))
+			Expect(runCfg.Controllers.Namespace.ListPageSize).To(Equal(int64(250)))
+			Expect(runCfg.Controllers.ServiceAccount.ListPageSize).To(Equal(int64(250)))
+			close(done)
+		})
+
+		It("should apply the excluded namespaces to the policy, namespace and service account controllers", func(done Done) {
+			Expect(os.Setenv("EXCLUDE_NAMESPACES", "ci-*")).To(Succeed())
+			cfg := new(config.Config)
+			err := cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.Policy.ExcludeNamespaces).To(Equal([]string{"ci-*"}))
+			Expect(runCfg.Controllers.Namespace.ExcludeNamespaces).To(Equal([]string{"ci-*"}))
+			Expect(runCfg.Controllers.ServiceAccount.ExcludeNamespaces).To(Equal([]string{"ci-*"}))
+			Expect(runCfg.Controllers.WorkloadEndpoint.ExcludeNamespaces).To(BeEmpty())
+			close(done)
+		})
+
+		It("should apply the node label prefixes to the node controller", func(done Done) {
+			Expect(os.Setenv("SYNC_NODE_LABEL_PREFIXES", "topology.kubernetes.io/,example.com/rack")).To(Succeed())
+			cfg := new(config.Config)
+			err := cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.Node.SyncLabels).To(BeTrue())
+			Expect(runCfg.Controllers.Node.SyncLabelPrefixes).To(Equal([]string{"topology.kubernetes.io/", "example.com/rack"}))
+			close(done)
+		})
+
+		It("should configure route reflectors in the node controller if requested", func(done Done) {
+			Expect(os.Setenv("ROUTE_REFLECTOR_LABEL", "example.com/route-reflector")).To(Succeed())
+			cfg := new(config.Config)
+			err := cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.Node.RouteReflectorLabel).To(Equal("example.com/route-reflector"))
+			Expect(runCfg.Controllers.Node.RouteReflectorClusterID).To(Equal("244.0.0.1"))
+			close(done)
+		})
+
+		It("should create BGP peers from node annotations if requested", func(done Done) {
+			Expect(os.Setenv("NODE_BGP_PEER_ANNOTATIONS", "true")).To(Succeed())
+			cfg := new(config.Config)
+			err := cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.Node.BGPPeerAnnotations).To(BeTrue())
+			close(done)
+		})
+
+		It("should apply the selectors to the namespace and policy controllers", func(done Done) {
+			Expect(os.Setenv("NAMESPACE_LABEL_SELECTOR", "owner=team-a")).To(Succeed())
+			Expect(os.Setenv("POLICY_FIELD_SELECTOR", "metadata.namespace!=kube-system")).To(Succeed())
+			cfg := new(config.Config)
+			err := cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.Namespace.LabelSelector).To(Equal("owner=team-a"))
+			Expect(runCfg.Controllers.Namespace.FieldSelector).To(Equal(""))
+			Expect(runCfg.Controllers.Policy.LabelSelector).To(Equal(""))
+			Expect(runCfg.Controllers.Policy.FieldSelector).To(Equal("metadata.namespace!=kube-system"))
+			Expect(runCfg.Controllers.ServiceAccount.LabelSelector).To(Equal(""))
+			close(done)
+		})
+
+		It("should apply the profile label settings to the namespace controller", func(done Done) {
+			Expect(os.Setenv("PROFILE_LABEL_DENY_LIST", "internal.example.com/*")).To(Succeed())
+			Expect(os.Setenv("PROFILE_ANNOTATION_LABELS", "example.com/team")).To(Succeed())
+			Expect(os.Setenv("PROFILE_DEFAULT_DENY", "true")).To(Succeed())
+			cfg := new(config.Config)
+			err := cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.Namespace.LabelDenyList).To(Equal([]string{"internal.example.com/*"}))
+			Expect(runCfg.Controllers.Namespace.AnnotationLabels).To(Equal([]string{"example.com/team"}))
+			Expect(runCfg.Controllers.Namespace.AnnotationLabelPrefix).To(Equal("annotation."))
+			Expect(runCfg.Controllers.Namespace.DefaultDeny).To(BeTrue())
+			Expect(runCfg.Controllers.ServiceAccount.LabelDenyList).To(BeEmpty())
+			close(done)
+		})
+
+		It("should apply the delete rate limit to the policy, namespace and service account controllers", func(done Done) {
+			Expect(os.Setenv("DELETE_RATE_LIMIT", "0.5")).To(Succeed())
+			Expect(os.Setenv("DELETE_BURST", "5")).To(Succeed())
+			cfg := new(config.Config)
+			err := cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.Policy.DeleteRateLimit).To(Equal(0.5))
+			Expect(runCfg.Controllers.Policy.DeleteBurst).To(Equal(5))
+			Expect(runCfg.Controllers.Namespace.DeleteRateLimit).To(Equal(0.5))
+			Expect(runCfg.Controllers.Namespace.DeleteBurst).To(Equal(5))
+			Expect(runCfg.Controllers.ServiceAccount.DeleteRateLimit).To(Equal(0.5))
+			Expect(runCfg.Controllers.ServiceAccount.DeleteBurst).To(Equal(5))
+			Expect(runCfg.Controllers.WorkloadEndpoint.DeleteRateLimit).To(BeZero())
+			close(done)
+		})
+	})
+
+	Context("with DATASTORE_TYPE=kubernetes", func() {
+
+		BeforeEach(func() {
+			unsetEnv()
+			err := os.Setenv("DATASTORE_TYPE", "kubernetes")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			unsetEnv()
+		})
+
+		It("should only enable the node controller", func(done Done) {
+			cfg := new(config.Config)
+			err := cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			rc := runCfg.Controllers
+			Expect(rc.Node).ToNot(BeNil())
+			Expect(rc.Node.DeleteNodes).To(BeFalse())
+			Expect(rc.Policy).To(BeNil())
+			Expect(rc.Namespace).To(BeNil())
+			Expect(rc.ServiceAccount).To(BeNil())
+			Expect(rc.WorkloadEndpoint).To(BeNil())
+			Expect(rc.SystemPolicy).To(BeNil())
+
+			sc := m.update.Status.RunningConfig.Controllers
+			Expect(sc.Node).ToNot(BeNil())
+			Expect(sc.Policy).To(BeNil())
+			Expect(sc.Namespace).To(BeNil())
+			Expect(sc.ServiceAccount).To(BeNil())
+			Expect(sc.WorkloadEndpoint).To(BeNil())
+			close(done)
+		})
+
+		It("should ignore redundant controllers in ENABLED_CONTROLLERS", func(done Done) {
+			err := os.Setenv("ENABLED_CONTROLLERS", "node,policy,namespace")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.Node).ToNot(BeNil())
+			Expect(runCfg.Controllers.Policy).To(BeNil())
+			Expect(runCfg.Controllers.Namespace).To(BeNil())
+			close(done)
+		})
+
+		It("should enable the system policy controller if requested", func(done Done) {
+			err := os.Setenv("SYSTEM_POLICIES", "true")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.SystemPolicy).To(Equal(&config.SystemPolicyControllerConfig{
+				GenericControllerConfig: config.GenericControllerConfig{
+					ReconcilerPeriod: 5 * time.Minute,
+					NumberOfWorkers:  1,
+					SyncTimeout:      time.Minute,
+				},
+				Curated: true,
+			}))
+			Expect(runCfg.Controllers.NamespaceIPPool).To(BeNil())
+			close(done)
+		})
+
+		It("should enable the failsafe policy if requested", func(done Done) {
+			err := os.Setenv("FAILSAFE_POLICY", "true")
+			Expect(err).ToNot(HaveOccurred())
+			err = os.Setenv("FAILSAFE_POLICY_OUTBOUND_PORTS", "udp:53, 6443")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.SystemPolicy).NotTo(BeNil())
+			Expect(runCfg.Controllers.SystemPolicy.Curated).To(BeFalse())
+			Expect(runCfg.Controllers.SystemPolicy.Failsafe).To(BeTrue())
+			Expect(runCfg.Controllers.SystemPolicy.FailsafeInbound).To(ContainElements(
+				v3.ProtoPort{Protocol: "TCP", Port: 22},
+				v3.ProtoPort{Protocol: "UDP", Port: 68},
+				v3.ProtoPort{Protocol: "TCP", Port: 6443},
+				v3.ProtoPort{Protocol: "TCP", Port: 10250},
+			))
+			Expect(runCfg.Controllers.SystemPolicy.FailsafeOutbound).To(Equal([]v3.ProtoPort{
+				{Protocol: "UDP", Port: 53},
+				{Protocol: "TCP", Port: 6443},
+			}))
+			close(done)
+		})
+
+		It("should enable the namespace IP pool controller if requested", func(done Done) {
+			err := os.Setenv("NAMESPACE_IP_POOLS", "true")
+			Expect(err).ToNot(HaveOccurred())
+			err = os.Setenv("NAMESPACE_IP_POOL_RANGES", "10.64.0.0/16,fd00:64::/48")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.NamespaceIPPool).To(Equal(&config.NamespaceIPPoolControllerConfig{
+				GenericControllerConfig: config.GenericControllerConfig{
+					ReconcilerPeriod: 5 * time.Minute,
+					NumberOfWorkers:  1,
+					SyncTimeout:      time.Minute,
+				},
+				Ranges: []string{"10.64.0.0/16", "fd00:64::/48"},
+			}))
+			close(done)
+		})
+
+		It("should enable the LoadBalancer IP allocation controller if requested", func(done Done) {
+			err := os.Setenv("LOAD_BALANCER_IP_ALLOCATION", "true")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.LoadBalancer).To(Equal(&config.GenericControllerConfig{
+				ReconcilerPeriod: 5 * time.Minute,
+				NumberOfWorkers:  1,
+				SyncTimeout:      time.Minute,
+			}))
+			close(done)
+		})
+
+		It("should enable the IP pool assignment controller if requested", func(done Done) {
+			err := os.Setenv("NAMESPACE_POOL_ASSIGNMENT", "true")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.PoolAssignment).To(Equal(&config.GenericControllerConfig{
+				ReconcilerPeriod: 5 * time.Minute,
+				NumberOfWorkers:  1,
+				SyncTimeout:      time.Minute,
+			}))
+			close(done)
+		})
+
+		It("should enable the Felix ConfigMap controller if requested", func(done Done) {
+			err := os.Setenv("FELIX_CONFIG_MAP", "calico-system/felix-config")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.FelixConfig).To(Equal(&config.FelixConfigControllerConfig{
+				GenericControllerConfig: config.GenericControllerConfig{
+					ReconcilerPeriod: 5 * time.Minute,
+					NumberOfWorkers:  1,
+					SyncTimeout:      time.Minute,
+				},
+				Namespace: "calico-system",
+				Name:      "felix-config",
+			}))
+			close(done)
+		})
+
+		It("should enable the threat feed controller if feeds are configured", func(done Done) {
+			err := os.Setenv("THREAT_FEEDS", "drop=https://example.com/drop.txt, edrop=http://example.com/edrop.txt")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.ThreatFeed).To(Equal(&config.ThreatFeedControllerConfig{
+				GenericControllerConfig: config.GenericControllerConfig{
+					ReconcilerPeriod: time.Hour,
+					NumberOfWorkers:  1,
+					SyncTimeout:      time.Minute,
+				},
+				Feeds: []config.ThreatFeed{
+					{Name: "drop", URL: "https://example.com/drop.txt"},
+					{Name: "edrop", URL: "http://example.com/edrop.txt"},
+				},
+			}))
+			close(done)
+		})
+
+		It("should enable the ConfigMap NetworkSet controller if a label is configured", func(done Done) {
+			err := os.Setenv("NETWORK_SET_CONFIG_MAP_LABEL", "projectcalico.org/network-set")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.NetworkSet).To(Equal(&config.NetworkSetControllerConfig{
+				GenericControllerConfig: config.GenericControllerConfig{
+					ReconcilerPeriod: 5 * time.Minute,
+					NumberOfWorkers:  1,
+					SyncTimeout:      time.Minute,
+				},
+				Label: "projectcalico.org/network-set",
+			}))
+			close(done)
+		})
+
+		It("should enable the DNS NetworkSet controller if requested", func(done Done) {
+			err := os.Setenv("DNS_NETWORK_SETS", "true")
+			Expect(err).ToNot(HaveOccurred())
+			err = os.Setenv("DNS_NETWORK_SET_MIN_TTL", "10s")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.DNSNetworkSet).To(Equal(&config.DNSNetworkSetControllerConfig{
+				GenericControllerConfig: config.GenericControllerConfig{
+					ReconcilerPeriod: time.Minute,
+					NumberOfWorkers:  1,
+					SyncTimeout:      time.Minute,
+				},
+				MinTTL: 10 * time.Second,
+				MaxTTL: time.Hour,
+			}))
+			close(done)
+		})
+
+		It("should enable the Service NetworkSet controller if requested", func(done Done) {
+			err := os.Setenv("SERVICE_NETWORK_SETS", "true")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.ServiceNetworkSet).To(Equal(&config.GenericControllerConfig{
+				ReconcilerPeriod: 5 * time.Minute,
+				NumberOfWorkers:  1,
+				SyncTimeout:      time.Minute,
+			}))
+			close(done)
+		})
+
+		It("should enable the ExternalHost controller if requested", func(done Done) {
+			err := os.Setenv("EXTERNAL_HOSTS", "true")
+			Expect(err).ToNot(HaveOccurred())
+			cfg := new(config.Config)
+			err = cfg.Parse()
+			Expect(err).ToNot(HaveOccurred())
+			m := &mockKCC{get: config.DefaultKCC.DeepCopy()}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			ctrl := config.NewRunConfigController(ctx, *cfg, m)
+			runCfg := <-ctrl.ConfigChan()
+
+			Expect(runCfg.Controllers.ExternalHost).To(Equal(&config.GenericControllerConfig{
+				ReconcilerPeriod: 5 * time.Minute,
+				NumberOfWorkers:  1,
+				SyncTimeout:      time.Minute,
+			}))
+			close(done)
+		})
 	})
 })
 
@@ -15,6 +15,9 @@ package config
 
 import (
 	"context"
+	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"reflect"
 	"strconv"
@@ -27,9 +30,14 @@ import (
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	"github.com/projectcalico/calico/kube-controllers/pkg/termination"
 	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
@@ -76,31 +84,205 @@ func init() {
 // merged information from environment variables (Config) and the Calico
 // resource KubeControllersConfiguration
 type RunConfig struct {
-	LogLevelScreen         log.Level
-	Controllers            ControllersConfig
-	EtcdV3CompactionPeriod time.Duration
-	HealthEnabled          bool
-	PrometheusPort         int
-	DebugProfilePort       int32
+	LogLevelScreen           log.Level
+	Controllers              ControllersConfig
+	EtcdV3CompactionPeriod   time.Duration
+	HealthEnabled            bool
+	PrometheusPort           int
+	DebugProfilePort         int32
+	ConsistencyCheckPeriod   time.Duration
+	DuplicateIPCheckPeriod   time.Duration
+	ReleaseDuplicateIPClaims bool
+	WireguardKeyCheckPeriod  time.Duration
+	ClearStaleWireguardKeys  bool
 }
 
 type ControllersConfig struct {
-	Node             *NodeControllerConfig
-	Policy           *GenericControllerConfig
-	WorkloadEndpoint *GenericControllerConfig
-	ServiceAccount   *GenericControllerConfig
-	Namespace        *GenericControllerConfig
+	Node              *NodeControllerConfig
+	Policy            *PolicyControllerConfig
+	WorkloadEndpoint  *GenericControllerConfig
+	ServiceAccount    *GenericControllerConfig
+	Namespace         *GenericControllerConfig
+	SystemPolicy      *SystemPolicyControllerConfig
+	NamespaceIPPool   *NamespaceIPPoolControllerConfig
+	PoolAssignment    *GenericControllerConfig
+	LoadBalancer      *GenericControllerConfig
+	FelixConfig       *FelixConfigControllerConfig
+	ThreatFeed        *ThreatFeedControllerConfig
+	NetworkSet        *NetworkSetControllerConfig
+	DNSNetworkSet     *DNSNetworkSetControllerConfig
+	ServiceNetworkSet *GenericControllerConfig
+	ExternalHost      *GenericControllerConfig
 }
 
 type GenericControllerConfig struct {
 	ReconcilerPeriod time.Duration
 	NumberOfWorkers  int
+
+	// The maximum time to spend syncing a single resource to the datastore, or 0 for no limit.
+	SyncTimeout time.Duration
+
+	// The maximum reconciler period to back off to while no drift is detected, or 0 to
+	// disable adaptive reconciliation.
+	MaxReconcilerPeriod time.Duration
+
+	// The fraction of the reconciler period by which each wait between reconciliations is
+	// randomly lengthened, or 0 to disable jitter.
+	ReconcilerJitter float64
+
+	// The maximum number of keys waiting on the controller's queue before it stops processing
+	// events, or 0 for no limit.
+	MaxQueueDepth int
+
+	// How long to wait after a change before writing it, so that further changes are merged
+	// into the same write, or 0 to write changes straight away.
+	CoalesceWindow time.Duration
+
+	// The fraction of the controller's syncs that may be retried after failing, or 0 for no
+	// limit.
+	RetryBudgetRatio float64
+
+	// The period and sample size of spot checks of the controller's cache, or a period of 0 to
+	// disable spot checks.
+	SpotCheckPeriod     time.Duration
+	SpotCheckSampleSize int
+
+	// The maximum rate of deletes from the datastore, in deletes per second, and the size of
+	// bursts allowed above that rate. A rate of 0 disables the limit.
+	DeleteRateLimit float64
+	DeleteBurst     int
+
+	// The window to spread writes over when a reconciliation finds more than ResyncThreshold
+	// resources out of sync, or 0 to make all the writes at once.
+	ResyncWindow    time.Duration
+	ResyncThreshold int
+
+	// The fraction of the controller's resources in the datastore that a reconciliation may
+	// delete without confirmation, or 0 to disable the failsafe.
+	MassDeleteThreshold float64
+
+	// The directory to save snapshots of the datastore in, or empty to disable snapshots, and
+	// the age beyond which a snapshot is ignored.
+	SnapshotDir    string
+	MaxSnapshotAge time.Duration
+
+	// The maximum number of resources in each page of lists from the k8s API, or 0 to disable
+	// pagination.
+	ListPageSize int64
+
+	// Glob patterns of namespaces that the controller ignores.
+	ExcludeNamespaces []string
+
+	// Label and field selectors restricting the resources that the controller watches, or empty
+	// to watch all of them.
+	LabelSelector string
+	FieldSelector string
+
+	// Namespace labels that aren't copied to generated profiles, and namespace annotations that
+	// are copied to them as labels with the given prefix. Only used by the namespace controller.
+	LabelDenyList         []string
+	AnnotationLabels      []string
+	AnnotationLabelPrefix string
+
+	// Whether generated profiles are default-deny. Only used by the namespace controller.
+	DefaultDeny bool
+
+	// Should the controller only report the changes it would make, instead of
+	// writing them to the datastore?
+	DryRun bool
+}
+
+type PolicyControllerConfig struct {
+	GenericControllerConfig
+
+	// The maximum length of generated policy names.
+	MaxNameLength int
+
+	// The prefix of generated policy names.
+	NamePrefix string
+
+	// The tenants to label sync metrics with, and the namespace label that identifies the
+	// tenant of each namespace.
+	MetricsTenants     []string
+	MetricsTenantLabel string
+
+	// Whether to allow DNS requests to kube-dns in generated policies that apply to egress.
+	AllowDNS bool
+
+	// The lowest order that the order annotation may give a generated policy.
+	MinOrder float64
+}
+
+type SystemPolicyControllerConfig struct {
+	GenericControllerConfig
+
+	// Whether to install the curated policies for system components.
+	Curated bool
+
+	// Whether to install the failsafe policy for host endpoints, and the ports it allows.
+	Failsafe         bool
+	FailsafeInbound  []v3.ProtoPort
+	FailsafeOutbound []v3.ProtoPort
+}
+
+type NamespaceIPPoolControllerConfig struct {
+	GenericControllerConfig
+
+	// The CIDRs to choose the CIDRs of pools requested by size from.
+	Ranges []string
+}
+
+type FelixConfigControllerConfig struct {
+	GenericControllerConfig
+
+	// The namespace and name of the ConfigMap to apply to the default FelixConfiguration.
+	Namespace string
+	Name      string
+}
+
+type ThreatFeedControllerConfig struct {
+	GenericControllerConfig
+
+	// The feeds to maintain GlobalNetworkSets for.
+	Feeds []ThreatFeed
+}
+
+type NetworkSetControllerConfig struct {
+	GenericControllerConfig
+
+	// The label selector of the ConfigMaps to maintain NetworkSets for.
+	Label string
+}
+
+type DNSNetworkSetControllerConfig struct {
+	GenericControllerConfig
+
+	// The bounds of how long resolved addresses are used for, whatever the TTLs of the answers.
+	MinTTL time.Duration
+	MaxTTL time.Duration
+}
+
+// ThreatFeed is a list of IPs and CIDRs to fetch from a URL.
+type ThreatFeed struct {
+	Name string
+	URL  string
 }
 
 type NodeControllerConfig struct {
 	SyncLabels        bool
 	AutoHostEndpoints bool
 
+	// The prefixes of the keys of the labels to sync, or empty to sync all labels.
+	SyncLabelPrefixes []string
+
+	// The label that makes a node a route reflector, or empty to not manage route reflectors,
+	// and the cluster ID to give route reflectors.
+	RouteReflectorLabel     string
+	RouteReflectorClusterID string
+
+	// Should the Node controller create BGP peers from the annotations of Kubernetes nodes?
+	BGPPeerAnnotations bool
+
 	// Should the Node controller delete Calico nodes?  Generally, this is
 	// true for etcdv3 datastores.
 	DeleteNodes bool
@@ -173,6 +355,8 @@ MAINLOOP:
 		// Ok, we should now have a snapshot.  Combine it with the environment variable
 		// config to get the running config.
 		new, status := mergeConfig(env, cfg, snapshot.Spec)
+		status.DryRunPlans = dryRunPlans(cfg, snapshot.Status)
+		status.Heartbeat = snapshot.Status.Heartbeat
 
 		// Write the status back to the API datastore, so that end users can inspect the current
 		// running config.
@@ -237,6 +421,8 @@ MAINLOOP:
 				}
 				snapshot = newKCC
 				new, status = mergeConfig(env, cfg, snapshot.Spec)
+				status.DryRunPlans = dryRunPlans(cfg, snapshot.Status)
+				status.Heartbeat = snapshot.Status.Heartbeat
 
 				// Update the status, but only if it's different, otherwise
 				// our update will trigger a watch update in an infinite loop
@@ -279,6 +465,16 @@ MAINLOOP:
 	}
 }
 
+// dryRunPlans returns the dry-run plans to retain in the status. The plans are written by the
+// controllers themselves, so we keep them when running in dry-run mode, and clear out any stale
+// plans otherwise.
+func dryRunPlans(cfg Config, status v3.KubeControllersConfigurationStatus) []v3.ReconcilePlan {
+	if !cfg.DryRun {
+		return nil
+	}
+	return status.DryRunPlans
+}
+
 // getOrCreateSnapshot gets the current KubeControllersConfig from the datastore,
 // or creates and returns a default if it doesn't exist
 func getOrCreateSnapshot(ctx context.Context, kcc clientv3.KubeControllersConfigurationInterface) (*v3.KubeControllersConfiguration, error) {
@@ -308,7 +504,7 @@ func mergeConfig(envVars map[string]string, envCfg Config, apiCfg v3.KubeControl
 
 	mergeLogLevel(envVars, &status, &rCfg, apiCfg)
 
-	mergeEnabledControllers(envVars, &status, &rCfg, apiCfg)
+	mergeEnabledControllers(envVars, &status, &rCfg, apiCfg, envCfg)
 
 	mergeReconcilerPeriod(envVars, &status, &rCfg)
 
@@ -324,12 +520,44 @@ func mergeConfig(envVars map[string]string, envCfg Config, apiCfg v3.KubeControl
 		rCfg.DebugProfilePort = *apiCfg.DebugProfilePort
 	}
 
+	// The consistency checker verifies resources that are only written by the controllers in
+	// etcd mode, so there is nothing for it to do when using the Kubernetes datastore.
+	if envCfg.DatastoreType != "kubernetes" {
+		rCfg.ConsistencyCheckPeriod = envCfg.ConsistencyCheckPeriod
+	}
+	rCfg.DuplicateIPCheckPeriod = envCfg.DuplicateIPCheckPeriod
+	rCfg.ReleaseDuplicateIPClaims = envCfg.ReleaseDuplicateIPClaims
+	rCfg.WireguardKeyCheckPeriod = envCfg.WireguardKeyCheckPeriod
+	rCfg.ClearStaleWireguardKeys = envCfg.ClearStaleWireguardKeys
+
+	if err := controller.ValidateNamespacePatterns(envCfg.ExcludeNamespaces); err != nil {
+		log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("invalid excluded namespaces")
+	}
+	if _, err := controller.ParseSelectors(envCfg.NamespaceLabelSelector, envCfg.NamespaceFieldSelector); err != nil {
+		log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("invalid namespace selector")
+	}
+	if _, err := controller.ParseSelectors(envCfg.PolicyLabelSelector, envCfg.PolicyFieldSelector); err != nil {
+		log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("invalid policy selector")
+	}
+
 	// Don't bother looking at this unless the node controller is enabled.
 	if rc.Node != nil {
 		mergeSyncNodeLabels(envVars, &status, &rCfg, apiCfg, envCfg)
+		if rc.Node.SyncLabels {
+			rc.Node.SyncLabelPrefixes = envCfg.SyncNodeLabelPrefixes
+		}
 
 		mergeAutoHostEndpoints(envVars, &status, &rCfg, apiCfg)
 
+		if envCfg.RouteReflectorLabel != "" {
+			if ip := net.ParseIP(envCfg.RouteReflectorClusterID); ip == nil || ip.To4() == nil {
+				log.WithFields(log.Fields{"clusterID": envCfg.RouteReflectorClusterID, termination.CodeField: termination.CodeConfig}).Fatal("invalid route reflector cluster ID, must be an IPv4 address")
+			}
+			rc.Node.RouteReflectorLabel = envCfg.RouteReflectorLabel
+			rc.Node.RouteReflectorClusterID = envCfg.RouteReflectorClusterID
+		}
+		rc.Node.BGPPeerAnnotations = envCfg.NodeBGPPeerAnnotations
+
 		// There is no env var config for this, so always merge from the API config.
 		if apiCfg.Controllers.Node != nil {
 			rc.Node.LeakGracePeriod = apiCfg.Controllers.Node.LeakGracePeriod
@@ -347,20 +575,284 @@ func mergeConfig(envVars map[string]string, envCfg Config, apiCfg v3.KubeControl
 	//       bother setting it.
 	if rc.Policy != nil {
 		rc.Policy.NumberOfWorkers = envCfg.PolicyWorkers
+		rc.Policy.DryRun = envCfg.DryRun
+		rc.Policy.MaxReconcilerPeriod = envCfg.MaxReconcilerPeriod
+		rc.Policy.ReconcilerJitter = envCfg.ReconcilerJitter
+		rc.Policy.MaxQueueDepth = envCfg.MaxQueueDepth
+		rc.Policy.CoalesceWindow = envCfg.UpdateCoalesceWindow
+		rc.Policy.SyncTimeout = envCfg.SyncTimeout
+		rc.Policy.RetryBudgetRatio = envCfg.RetryBudgetRatio
+		rc.Policy.SpotCheckPeriod = envCfg.SpotCheckPeriod
+		rc.Policy.SpotCheckSampleSize = envCfg.SpotCheckSampleSize
+		rc.Policy.DeleteRateLimit = envCfg.DeleteRateLimit
+		rc.Policy.DeleteBurst = envCfg.DeleteBurst
+		rc.Policy.ListPageSize = envCfg.ListPageSize
+		rc.Policy.ExcludeNamespaces = envCfg.ExcludeNamespaces
+		rc.Policy.LabelSelector = envCfg.PolicyLabelSelector
+		rc.Policy.FieldSelector = envCfg.PolicyFieldSelector
+		rc.Policy.ResyncWindow = envCfg.ResyncWindow
+		rc.Policy.ResyncThreshold = envCfg.ResyncThreshold
+		rc.Policy.MassDeleteThreshold = envCfg.MassDeleteThreshold
+		rc.Policy.SnapshotDir = envCfg.SnapshotDir
+		rc.Policy.MaxSnapshotAge = envCfg.MaxSnapshotAge
+		rc.Policy.MaxNameLength = envCfg.PolicyNameMaxLength
+		rc.Policy.NamePrefix = envCfg.PolicyNamePrefix
+		rc.Policy.MetricsTenants = envCfg.PolicyMetricsTenants
+		rc.Policy.MetricsTenantLabel = envCfg.PolicyMetricsTenantLabel
+		rc.Policy.AllowDNS = envCfg.PolicyAllowDNS
+		rc.Policy.MinOrder = envCfg.PolicyMinOrder
+		if rc.Policy.MaxNameLength < converter.MinMaxNameLength || rc.Policy.MaxNameLength > converter.DefaultMaxNameLength {
+			log.WithFields(log.Fields{"PolicyNameMaxLength": envCfg.PolicyNameMaxLength, termination.CodeField: termination.CodeConfig}).Fatalf(
+				"invalid policy name max length, must be between %d and %d", converter.MinMaxNameLength, converter.DefaultMaxNameLength)
+		}
+		if err := converter.ValidateNamePrefix(rc.Policy.NamePrefix, envCfg.PolicyOtherNamePrefixes...); err != nil {
+			log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("invalid policy name prefix")
+		}
 	}
 	if rc.WorkloadEndpoint != nil {
 		rc.WorkloadEndpoint.NumberOfWorkers = envCfg.WorkloadEndpointWorkers
+		rc.WorkloadEndpoint.DryRun = envCfg.DryRun
+		rc.WorkloadEndpoint.MaxReconcilerPeriod = envCfg.MaxReconcilerPeriod
+		rc.WorkloadEndpoint.ReconcilerJitter = envCfg.ReconcilerJitter
+		rc.WorkloadEndpoint.MaxQueueDepth = envCfg.MaxQueueDepth
+		rc.WorkloadEndpoint.CoalesceWindow = envCfg.UpdateCoalesceWindow
+		rc.WorkloadEndpoint.SyncTimeout = envCfg.SyncTimeout
+		rc.WorkloadEndpoint.RetryBudgetRatio = envCfg.RetryBudgetRatio
 	}
 	if rc.ServiceAccount != nil {
 		rc.ServiceAccount.NumberOfWorkers = envCfg.ProfileWorkers
+		rc.ServiceAccount.DryRun = envCfg.DryRun
+		rc.ServiceAccount.MaxReconcilerPeriod = envCfg.MaxReconcilerPeriod
+		rc.ServiceAccount.ReconcilerJitter = envCfg.ReconcilerJitter
+		rc.ServiceAccount.MaxQueueDepth = envCfg.MaxQueueDepth
+		rc.ServiceAccount.CoalesceWindow = envCfg.UpdateCoalesceWindow
+		rc.ServiceAccount.SyncTimeout = envCfg.SyncTimeout
+		rc.ServiceAccount.RetryBudgetRatio = envCfg.RetryBudgetRatio
+		rc.ServiceAccount.SpotCheckPeriod = envCfg.SpotCheckPeriod
+		rc.ServiceAccount.SpotCheckSampleSize = envCfg.SpotCheckSampleSize
+		rc.ServiceAccount.DeleteRateLimit = envCfg.DeleteRateLimit
+		rc.ServiceAccount.DeleteBurst = envCfg.DeleteBurst
+		rc.ServiceAccount.ListPageSize = envCfg.ListPageSize
+		rc.ServiceAccount.ExcludeNamespaces = envCfg.ExcludeNamespaces
+		rc.ServiceAccount.ResyncWindow = envCfg.ResyncWindow
+		rc.ServiceAccount.ResyncThreshold = envCfg.ResyncThreshold
+		rc.ServiceAccount.MassDeleteThreshold = envCfg.MassDeleteThreshold
+		rc.ServiceAccount.SnapshotDir = envCfg.SnapshotDir
+		rc.ServiceAccount.MaxSnapshotAge = envCfg.MaxSnapshotAge
+	}
+	// The system policy controller is only configured through the environment, since it isn't
+	// part of the KubeControllersConfiguration API. It manages both the curated policies and the
+	// failsafe policy, so runs if either is enabled.
+	if envCfg.SystemPolicies || envCfg.FailsafePolicy {
+		rc.SystemPolicy = &SystemPolicyControllerConfig{
+			GenericControllerConfig: GenericControllerConfig{
+				ReconcilerPeriod:    time.Minute * 5,
+				NumberOfWorkers:     1,
+				MaxReconcilerPeriod: envCfg.MaxReconcilerPeriod,
+				ReconcilerJitter:    envCfg.ReconcilerJitter,
+				MaxQueueDepth:       envCfg.MaxQueueDepth,
+				CoalesceWindow:      envCfg.UpdateCoalesceWindow,
+				SyncTimeout:         envCfg.SyncTimeout,
+				RetryBudgetRatio:    envCfg.RetryBudgetRatio,
+			},
+			Curated:  envCfg.SystemPolicies,
+			Failsafe: envCfg.FailsafePolicy,
+		}
+		if envCfg.FailsafePolicy {
+			var err error
+			if rc.SystemPolicy.FailsafeInbound, err = parseProtoPorts(envCfg.FailsafePolicyInboundPorts); err != nil {
+				log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("invalid failsafe policy inbound ports")
+			}
+			if rc.SystemPolicy.FailsafeOutbound, err = parseProtoPorts(envCfg.FailsafePolicyOutboundPorts); err != nil {
+				log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("invalid failsafe policy outbound ports")
+			}
+		}
+	}
+	// Likewise the namespace IP pool, pool assignment, LoadBalancer, Felix ConfigMap, threat
+	// feed, NetworkSet and ExternalHost controllers.
+	if envCfg.NamespaceIPPools {
+		rc.NamespaceIPPool = &NamespaceIPPoolControllerConfig{
+			GenericControllerConfig: GenericControllerConfig{
+				ReconcilerPeriod:    time.Minute * 5,
+				NumberOfWorkers:     1,
+				MaxReconcilerPeriod: envCfg.MaxReconcilerPeriod,
+				ReconcilerJitter:    envCfg.ReconcilerJitter,
+				MaxQueueDepth:       envCfg.MaxQueueDepth,
+				CoalesceWindow:      envCfg.UpdateCoalesceWindow,
+				SyncTimeout:         envCfg.SyncTimeout,
+				RetryBudgetRatio:    envCfg.RetryBudgetRatio,
+			},
+			Ranges: envCfg.NamespaceIPPoolRanges,
+		}
+	}
+	if envCfg.NamespacePoolAssignment {
+		rc.PoolAssignment = &GenericControllerConfig{
+			ReconcilerPeriod: time.Minute * 5,
+			NumberOfWorkers:  1,
+			SyncTimeout:      envCfg.SyncTimeout,
+		}
+	}
+	if envCfg.LoadBalancerIPAllocation {
+		rc.LoadBalancer = &GenericControllerConfig{
+			ReconcilerPeriod: time.Minute * 5,
+			NumberOfWorkers:  1,
+			SyncTimeout:      envCfg.SyncTimeout,
+		}
+	}
+	if envCfg.FelixConfigMap != "" {
+		namespace, name, ok := strings.Cut(envCfg.FelixConfigMap, "/")
+		if !ok || namespace == "" || name == "" {
+			log.WithFields(log.Fields{"FelixConfigMap": envCfg.FelixConfigMap, termination.CodeField: termination.CodeConfig}).Fatal("invalid Felix ConfigMap, must be namespace/name")
+		}
+		rc.FelixConfig = &FelixConfigControllerConfig{
+			GenericControllerConfig: GenericControllerConfig{
+				ReconcilerPeriod: time.Minute * 5,
+				NumberOfWorkers:  1,
+				SyncTimeout:      envCfg.SyncTimeout,
+			},
+			Namespace: namespace,
+			Name:      name,
+		}
+	}
+	if len(envCfg.ThreatFeeds) > 0 {
+		feeds, err := parseThreatFeeds(envCfg.ThreatFeeds)
+		if err != nil {
+			log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("invalid threat feeds")
+		}
+		rc.ThreatFeed = &ThreatFeedControllerConfig{
+			GenericControllerConfig: GenericControllerConfig{
+				ReconcilerPeriod: envCfg.ThreatFeedPeriod,
+				NumberOfWorkers:  1,
+				SyncTimeout:      envCfg.SyncTimeout,
+			},
+			Feeds: feeds,
+		}
+	}
+	if envCfg.NetworkSetConfigMapLabel != "" {
+		if _, err := labels.Parse(envCfg.NetworkSetConfigMapLabel); err != nil {
+			log.WithError(err).WithField(termination.CodeField, termination.CodeConfig).Fatal("invalid NetworkSet ConfigMap label selector")
+		}
+		rc.NetworkSet = &NetworkSetControllerConfig{
+			GenericControllerConfig: GenericControllerConfig{
+				ReconcilerPeriod: time.Minute * 5,
+				NumberOfWorkers:  1,
+				SyncTimeout:      envCfg.SyncTimeout,
+			},
+			Label: envCfg.NetworkSetConfigMapLabel,
+		}
+	}
+	if envCfg.DNSNetworkSets {
+		if envCfg.DNSNetworkSetMinTTL <= 0 || envCfg.DNSNetworkSetMaxTTL < envCfg.DNSNetworkSetMinTTL {
+			log.WithFields(log.Fields{
+				"DNSNetworkSetMinTTL": envCfg.DNSNetworkSetMinTTL,
+				"DNSNetworkSetMaxTTL": envCfg.DNSNetworkSetMaxTTL,
+				termination.CodeField: termination.CodeConfig,
+			}).Fatal("invalid DNS NetworkSet TTLs, the minimum must be positive and no more than the maximum")
+		}
+		rc.DNSNetworkSet = &DNSNetworkSetControllerConfig{
+			GenericControllerConfig: GenericControllerConfig{
+				ReconcilerPeriod: time.Minute,
+				NumberOfWorkers:  1,
+				SyncTimeout:      envCfg.SyncTimeout,
+			},
+			MinTTL: envCfg.DNSNetworkSetMinTTL,
+			MaxTTL: envCfg.DNSNetworkSetMaxTTL,
+		}
+	}
+	if envCfg.ServiceNetworkSets {
+		rc.ServiceNetworkSet = &GenericControllerConfig{
+			ReconcilerPeriod: time.Minute * 5,
+			NumberOfWorkers:  1,
+			SyncTimeout:      envCfg.SyncTimeout,
+		}
+	}
+	if envCfg.ExternalHosts {
+		rc.ExternalHost = &GenericControllerConfig{
+			ReconcilerPeriod: time.Minute * 5,
+			NumberOfWorkers:  1,
+			SyncTimeout:      envCfg.SyncTimeout,
+		}
 	}
 	if rc.Namespace != nil {
 		rc.Namespace.NumberOfWorkers = envCfg.ProfileWorkers
+		rc.Namespace.DryRun = envCfg.DryRun
+		rc.Namespace.MaxReconcilerPeriod = envCfg.MaxReconcilerPeriod
+		rc.Namespace.ReconcilerJitter = envCfg.ReconcilerJitter
+		rc.Namespace.MaxQueueDepth = envCfg.MaxQueueDepth
+		rc.Namespace.CoalesceWindow = envCfg.UpdateCoalesceWindow
+		rc.Namespace.SyncTimeout = envCfg.SyncTimeout
+		rc.Namespace.RetryBudgetRatio = envCfg.RetryBudgetRatio
+		rc.Namespace.SpotCheckPeriod = envCfg.SpotCheckPeriod
+		rc.Namespace.SpotCheckSampleSize = envCfg.SpotCheckSampleSize
+		rc.Namespace.DeleteRateLimit = envCfg.DeleteRateLimit
+		rc.Namespace.DeleteBurst = envCfg.DeleteBurst
+		rc.Namespace.ListPageSize = envCfg.ListPageSize
+		rc.Namespace.ExcludeNamespaces = envCfg.ExcludeNamespaces
+		rc.Namespace.LabelSelector = envCfg.NamespaceLabelSelector
+		rc.Namespace.FieldSelector = envCfg.NamespaceFieldSelector
+		rc.Namespace.LabelDenyList = envCfg.ProfileLabelDenyList
+		rc.Namespace.AnnotationLabels = envCfg.ProfileAnnotationLabels
+		rc.Namespace.AnnotationLabelPrefix = envCfg.ProfileAnnotationLabelPrefix
+		rc.Namespace.DefaultDeny = envCfg.ProfileDefaultDeny
+		rc.Namespace.ResyncWindow = envCfg.ResyncWindow
+		rc.Namespace.ResyncThreshold = envCfg.ResyncThreshold
+		rc.Namespace.MassDeleteThreshold = envCfg.MassDeleteThreshold
+		rc.Namespace.SnapshotDir = envCfg.SnapshotDir
+		rc.Namespace.MaxSnapshotAge = envCfg.MaxSnapshotAge
 	}
 
 	return rCfg, status
 }
 
+// parseThreatFeeds parses the THREAT_FEEDS environment variable, a list of name=url pairs. The
+// names are used in the names and labels of the feeds' GlobalNetworkSets, so must be DNS labels.
+func parseThreatFeeds(values []string) ([]ThreatFeed, error) {
+	var feeds []ThreatFeed
+	names := map[string]bool{}
+	for _, v := range values {
+		name, rawURL, ok := strings.Cut(strings.TrimSpace(v), "=")
+		if !ok {
+			return nil, fmt.Errorf("threat feed %q must be name=url", v)
+		}
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid threat feed name %q: %s", name, strings.Join(errs, ", "))
+		}
+		if names[name] {
+			return nil, fmt.Errorf("duplicate threat feed name %q", name)
+		}
+		names[name] = true
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid URL %q for threat feed %q, must be an http or https URL", rawURL, name)
+		}
+		feeds = append(feeds, ThreatFeed{Name: name, URL: rawURL})
+	}
+	return feeds, nil
+}
+
+// parseProtoPorts parses a list of ports in the same [protocol:]port form as Felix's failsafe
+// ports, with the protocol defaulting to tcp.
+func parseProtoPorts(values []string) ([]v3.ProtoPort, error) {
+	var ports []v3.ProtoPort
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		protocol, port, ok := strings.Cut(v, ":")
+		if !ok {
+			protocol, port = "tcp", v
+		}
+		protocol = strings.ToUpper(protocol)
+		if protocol != "TCP" && protocol != "UDP" && protocol != "SCTP" {
+			return nil, fmt.Errorf("invalid protocol in port %q, must be tcp, udp or sctp", v)
+		}
+		n, err := strconv.ParseUint(port, 10, 16)
+		if err != nil || n == 0 {
+			return nil, fmt.Errorf("invalid port %q", v)
+		}
+		ports = append(ports, v3.ProtoPort{Protocol: protocol, Port: uint16(n)})
+	}
+	return ports, nil
+}
+
 func mergeAutoHostEndpoints(envVars map[string]string, status *v3.KubeControllersConfigurationStatus, rCfg *RunConfig, apiCfg v3.KubeControllersConfigurationSpec) {
 	// make these names shorter
 	rc := &rCfg.Controllers
@@ -373,7 +865,7 @@ func mergeAutoHostEndpoints(envVars map[string]string, status *v3.KubeController
 		if strings.ToLower(v) == "enabled" {
 			rc.Node.AutoHostEndpoints = true
 		} else if strings.ToLower(v) != "disabled" {
-			log.WithField(EnvAutoHostEndpoints, v).Fatal("invalid environment variable value")
+			log.WithFields(log.Fields{EnvAutoHostEndpoints: v, termination.CodeField: termination.CodeConfig}).Fatal("invalid environment variable value")
 		}
 	} else {
 		if ac.Node != nil && ac.Node.HostEndpoint != nil && ac.Node.HostEndpoint.AutoCreate == v3.Enabled {
@@ -404,7 +896,7 @@ func mergeSyncNodeLabels(envVars map[string]string, status *v3.KubeControllersCo
 			status.EnvironmentVars[EnvSyncNodeLabels] = v
 			snl, err := strconv.ParseBool(v)
 			if err != nil {
-				log.WithField(EnvSyncNodeLabels, v).Fatal("invalid environment variable value")
+				log.WithFields(log.Fields{EnvSyncNodeLabels: v, termination.CodeField: termination.CodeConfig}).Fatal("invalid environment variable value")
 			}
 			rc.Node.SyncLabels = snl
 		} else {
@@ -430,7 +922,7 @@ func mergeHealthEnabled(envVars map[string]string, status *v3.KubeControllersCon
 		status.EnvironmentVars[EnvHealthEnabled] = v
 		he, err := strconv.ParseBool(v)
 		if err != nil {
-			log.WithField(EnvHealthEnabled, v).Fatal("invalid environment variable value")
+			log.WithFields(log.Fields{EnvHealthEnabled: v, termination.CodeField: termination.CodeConfig}).Fatal("invalid environment variable value")
 		}
 		rCfg.HealthEnabled = he
 	} else {
@@ -453,7 +945,7 @@ func mergeCompactionPeriod(envVars map[string]string, status *v3.KubeControllers
 		status.EnvironmentVars[EnvCompactionPeriod] = v
 		d, err := time.ParseDuration(v)
 		if err != nil {
-			log.WithField(EnvCompactionPeriod, v).Fatal("invalid environment variable value")
+			log.WithFields(log.Fields{EnvCompactionPeriod: v, termination.CodeField: termination.CodeConfig}).Fatal("invalid environment variable value")
 		}
 		rCfg.EtcdV3CompactionPeriod = d
 	} else {
@@ -478,7 +970,7 @@ func mergeReconcilerPeriod(envVars map[string]string, status *v3.KubeControllers
 		status.EnvironmentVars[EnvReconcilerPeriod] = v
 		d, err := time.ParseDuration(v)
 		if err != nil {
-			log.WithField(EnvReconcilerPeriod, v).Fatal("invalid environment variable value")
+			log.WithFields(log.Fields{EnvReconcilerPeriod: v, termination.CodeField: termination.CodeConfig}).Fatal("invalid environment variable value")
 		}
 		// Valid env value, set on every enabled controller
 		// NOTE: Node controller doesn't use a cache, so ignores reconciler period
@@ -501,7 +993,7 @@ func mergeReconcilerPeriod(envVars map[string]string, status *v3.KubeControllers
 	}
 }
 
-func mergeEnabledControllers(envVars map[string]string, status *v3.KubeControllersConfigurationStatus, rCfg *RunConfig, apiCfg v3.KubeControllersConfigurationSpec) {
+func mergeEnabledControllers(envVars map[string]string, status *v3.KubeControllersConfigurationStatus, rCfg *RunConfig, apiCfg v3.KubeControllersConfigurationSpec, cfg Config) {
 	// make these names shorter
 	rc := &rCfg.Controllers
 	ac := apiCfg.Controllers
@@ -524,7 +1016,7 @@ func mergeEnabledControllers(envVars map[string]string, status *v3.KubeControlle
 				rc.Namespace = &GenericControllerConfig{}
 				sc.Namespace = &v3.NamespaceControllerConfig{}
 			case "policy":
-				rc.Policy = &GenericControllerConfig{}
+				rc.Policy = &PolicyControllerConfig{}
 				sc.Policy = &v3.PolicyControllerConfig{}
 			case "node":
 				rc.Node = &NodeControllerConfig{}
@@ -533,9 +1025,9 @@ func mergeEnabledControllers(envVars map[string]string, status *v3.KubeControlle
 				rc.ServiceAccount = &GenericControllerConfig{}
 				sc.ServiceAccount = &v3.ServiceAccountControllerConfig{}
 			case "flannelmigration":
-				log.WithField(EnvEnabledControllers, v).Fatal("cannot run flannelmigration with other controllers")
+				log.WithFields(log.Fields{EnvEnabledControllers: v, termination.CodeField: termination.CodeConfig}).Fatal("cannot run flannelmigration with other controllers")
 			default:
-				log.Fatalf("Invalid controller '%s' provided.", controllerType)
+				log.WithField(termination.CodeField, termination.CodeConfig).Fatalf("Invalid controller '%s' provided.", controllerType)
 			}
 		}
 	} else {
@@ -552,7 +1044,7 @@ func mergeEnabledControllers(envVars map[string]string, status *v3.KubeControlle
 		}
 
 		if pol != nil {
-			rc.Policy = &GenericControllerConfig{}
+			rc.Policy = &PolicyControllerConfig{}
 			sc.Policy = &v3.PolicyControllerConfig{}
 		}
 
@@ -605,6 +1097,53 @@ func mergeEnabledControllers(envVars map[string]string, status *v3.KubeControlle
 		}
 		sc.ServiceAccount.ReconcilerPeriod = s.ReconcilerPeriod
 	}
+
+	if cfg.DatastoreType == "kubernetes" {
+		disableKDDRedundantControllers(envVars, status, rCfg)
+	}
+}
+
+// disableKDDRedundantControllers disables the controllers that are not needed when Calico uses the
+// Kubernetes API as its datastore. In that mode, profiles, policies and workload endpoints are derived
+// directly from the corresponding Kubernetes resources, so there is nothing for these controllers to sync.
+// The node controller is still required, for example to clean up IPAM allocations.
+func disableKDDRedundantControllers(envVars map[string]string, status *v3.KubeControllersConfigurationStatus, rCfg *RunConfig) {
+	rc := &rCfg.Controllers
+	sc := &status.RunningConfig.Controllers
+	status.EnvironmentVars["DATASTORE_TYPE"] = "kubernetes"
+
+	var disabled []string
+	if rc.Policy != nil {
+		disabled = append(disabled, "policy")
+		rc.Policy = nil
+		sc.Policy = nil
+	}
+	if rc.Namespace != nil {
+		disabled = append(disabled, "namespace")
+		rc.Namespace = nil
+		sc.Namespace = nil
+	}
+	if rc.ServiceAccount != nil {
+		disabled = append(disabled, "serviceaccount")
+		rc.ServiceAccount = nil
+		sc.ServiceAccount = nil
+	}
+	if rc.WorkloadEndpoint != nil {
+		disabled = append(disabled, "workloadendpoint")
+		rc.WorkloadEndpoint = nil
+		sc.WorkloadEndpoint = nil
+	}
+	if len(disabled) == 0 {
+		return
+	}
+
+	logCtx := log.WithField("controllers", strings.Join(disabled, ","))
+	if _, p := envVars[EnvEnabledControllers]; p {
+		// Explicitly requested, so let the user know that their config is being ignored.
+		logCtx.Warning("Ignoring controllers that are not required when using the Kubernetes datastore")
+	} else {
+		logCtx.Info("Disabling controllers that are not required when using the Kubernetes datastore")
+	}
 }
 
 func mergeLogLevel(envVars map[string]string, status *v3.KubeControllersConfigurationStatus, rCfg *RunConfig, apiCfg v3.KubeControllersConfigurationSpec) {
@@ -613,7 +1152,7 @@ func mergeLogLevel(envVars map[string]string, status *v3.KubeControllersConfigur
 		status.EnvironmentVars[EnvLogLevel] = v
 		l, err := log.ParseLevel(v)
 		if err != nil {
-			log.WithField(EnvLogLevel, v).Fatal("invalid environment variable value")
+			log.WithFields(log.Fields{EnvLogLevel: v, termination.CodeField: termination.CodeConfig}).Fatal("invalid environment variable value")
 		}
 		rCfg.LogLevelScreen = l
 	} else {
@@ -0,0 +1,216 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consistency
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	kdd "github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+var violationsGauge *prometheus.GaugeVec
+
+func init() {
+	violationsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "consistency_violations",
+		Help: "Number of resources that violate an invariant spanning multiple controllers",
+	}, []string{"invariant"})
+	prometheus.MustRegister(violationsGauge)
+}
+
+// checker periodically checks that the resources written by the different controllers are
+// consistent with each other. Partial syncs can leave resources that one controller depends on
+// missing or stale, so this catches bugs that wouldn't be visible from any single controller.
+type checker struct {
+	ctx          context.Context
+	k8sClientset *kubernetes.Clientset
+	calicoClient client.Interface
+	period       time.Duration
+	invariants   []Invariant
+	recorder     record.EventRecorder
+
+	// The namespaces whose profiles are maintained by the namespace controller, or nil if it
+	// isn't enabled, and the namespaces excluded from the policy controller.
+	namespaceScope *NamespaceScope
+	policyFilter   *controller.NamespaceFilter
+
+	// Violations found by the previous check. A violation is only reported once it is seen in
+	// two consecutive checks, since the controllers may simply not have caught up yet.
+	previous map[string]bool
+
+	// Violations that have already been reported.
+	reported map[string]bool
+}
+
+// NewConsistencyChecker returns a controller which checks the invariants involving the given
+// controllers once every period.
+func NewConsistencyChecker(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, period time.Duration, controllers config.ControllersConfig) controller.Controller {
+	ch := &checker{
+		ctx:          ctx,
+		k8sClientset: k8sClientset,
+		calicoClient: c,
+		period:       period,
+		invariants:   Invariants(controllers),
+		previous:     map[string]bool{},
+		reported:     map[string]bool{},
+	}
+	if controllers.Namespace != nil {
+		// The selectors are validated when loading the config.
+		scope, err := NewNamespaceScope(*controllers.Namespace)
+		if err != nil {
+			log.WithError(err).Fatal("Invalid namespace selector")
+		}
+		ch.namespaceScope = scope
+	}
+	if controllers.Policy != nil {
+		ch.policyFilter = controller.NewNamespaceFilter(controllers.Policy.ExcludeNamespaces)
+	}
+	return ch
+}
+
+// Run starts the checker.
+func (c *checker) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+
+	log.Info("Starting consistency checker")
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.k8sClientset.CoreV1().Events("")})
+	defer broadcaster.Shutdown()
+	c.recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "calico-kube-controllers"})
+
+	ticker := time.NewTicker(c.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			log.Info("Stopping consistency checker")
+			return
+		case <-ticker.C:
+			if err := c.check(); err != nil {
+				log.WithError(err).Warning("Failed to check consistency of Calico resources")
+			}
+		}
+	}
+}
+
+func (c *checker) check() error {
+	s, err := c.snapshot()
+	if err != nil {
+		return err
+	}
+
+	counts := map[Invariant]int{}
+	current := map[string]bool{}
+	for _, v := range Check(s, c.invariants) {
+		id := v.ID()
+		current[id] = true
+		if !c.previous[id] {
+			continue
+		}
+		counts[v.Invariant]++
+		if c.reported[id] {
+			continue
+		}
+		log.WithFields(log.Fields{"invariant": v.Invariant, "key": v.Key}).Warning(v.Message)
+		if v.Object != nil {
+			c.recorder.Event(v.Object, v1.EventTypeWarning, "CalicoInconsistency", v.Message)
+		}
+		c.reported[id] = true
+	}
+	for id := range c.reported {
+		if !current[id] {
+			log.WithField("violation", id).Info("Consistency violation has been resolved")
+			delete(c.reported, id)
+		}
+	}
+	c.previous = current
+
+	for _, inv := range AllInvariants {
+		violationsGauge.WithLabelValues(string(inv)).Set(float64(counts[inv]))
+	}
+	return nil
+}
+
+// snapshot lists the resources needed to check the invariants. Resources in namespaces that the
+// controllers leave alone are left out.
+func (c *checker) snapshot() (Snapshot, error) {
+	s := Snapshot{Namespaces: map[string]bool{}, Nodes: map[string]bool{}}
+
+	namespaces, err := c.k8sClientset.CoreV1().Namespaces().List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return s, err
+	}
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		s.Namespaces[ns.Name] = c.namespaceScope != nil && c.namespaceScope.Namespace(ns)
+	}
+
+	nodes, err := c.calicoClient.Nodes().List(c.ctx, options.ListOptions{})
+	if err != nil {
+		return s, err
+	}
+	for _, n := range nodes.Items {
+		s.Nodes[n.Name] = true
+	}
+
+	profiles, err := c.calicoClient.Profiles().List(c.ctx, options.ListOptions{})
+	if err != nil {
+		return s, err
+	}
+	for _, p := range profiles.Items {
+		if c.namespaceScope == nil {
+			continue
+		}
+		maintained, exists := s.Namespaces[strings.TrimPrefix(p.Name, kdd.NamespaceProfileNamePrefix)]
+		if (exists && maintained) || (!exists && c.namespaceScope.Profile(p)) {
+			s.Profiles = append(s.Profiles, p)
+		}
+	}
+
+	policies, err := c.calicoClient.NetworkPolicies().List(c.ctx, options.ListOptions{})
+	if err != nil {
+		return s, err
+	}
+	for _, p := range policies.Items {
+		if c.policyFilter != nil && !c.policyFilter.Excluded(p.Namespace) {
+			s.NetworkPolicies = append(s.NetworkPolicies, p)
+		}
+	}
+
+	weps, err := c.calicoClient.WorkloadEndpoints().List(c.ctx, options.ListOptions{})
+	if err != nil {
+		return s, err
+	}
+	s.WorkloadEndpoints = weps.Items
+
+	return s, nil
+}
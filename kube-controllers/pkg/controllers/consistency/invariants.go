@@ -0,0 +1,169 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consistency
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	kdd "github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
+)
+
+// Invariant identifies a property that should hold across the resources written by the
+// different controllers.
+type Invariant string
+
+const (
+	// Every policy written by the policy controller should be in a namespace that has a profile
+	// written by the namespace controller.
+	InvariantPolicyNamespaceProfile Invariant = "policy-namespace-profile"
+
+	// Every Kubernetes workload endpoint should be on a node that exists.
+	InvariantWorkloadEndpointNode Invariant = "workload-endpoint-node"
+
+	// Every profile written by the namespace controller should correspond to a namespace that exists.
+	InvariantProfileNamespace Invariant = "profile-namespace"
+)
+
+// AllInvariants lists every invariant checked by the consistency checker.
+var AllInvariants = []Invariant{InvariantPolicyNamespaceProfile, InvariantWorkloadEndpointNode, InvariantProfileNamespace}
+
+// Invariants returns the invariants that can be checked with the given controllers. Each
+// invariant is only checked if all the controllers writing the resources it involves are enabled.
+func Invariants(controllers config.ControllersConfig) []Invariant {
+	var invariants []Invariant
+	if controllers.Policy != nil && controllers.Namespace != nil {
+		invariants = append(invariants, InvariantPolicyNamespaceProfile)
+	}
+	if controllers.Node != nil {
+		invariants = append(invariants, InvariantWorkloadEndpointNode)
+	}
+	if controllers.Namespace != nil {
+		invariants = append(invariants, InvariantProfileNamespace)
+	}
+	return invariants
+}
+
+// Snapshot is the set of resources that the invariants are checked against.
+type Snapshot struct {
+	// The Kubernetes namespaces, by name, and whether the namespace controller maintains a
+	// profile for each of them.
+	Namespaces map[string]bool
+
+	// Names of the Calico nodes.
+	Nodes map[string]bool
+
+	// Profiles and policies in the Calico datastore. Only the resources labelled as written by
+	// the namespace and policy controllers are checked; any others are ignored.
+	Profiles          []api.Profile
+	NetworkPolicies   []api.NetworkPolicy
+	WorkloadEndpoints []libapi.WorkloadEndpoint
+}
+
+// Violation describes a resource that breaks an invariant.
+type Violation struct {
+	Invariant Invariant
+
+	// Key uniquely identifies the violation for the given invariant.
+	Key string
+
+	Message string
+
+	// Object is the Kubernetes object that any Event for the violation should be attached to,
+	// or nil if there is no suitable object.
+	Object *v1.ObjectReference
+}
+
+// ID returns a unique identifier for the violation.
+func (v Violation) ID() string {
+	return string(v.Invariant) + "/" + v.Key
+}
+
+// Check returns the violations of the given invariants in the given snapshot, sorted by ID.
+func Check(s Snapshot, invariants []Invariant) []Violation {
+	var violations []Violation
+	profiles := map[string]bool{}
+	for _, p := range s.Profiles {
+		if !converter.IsManaged(p.ObjectMeta, "Namespace") {
+			continue
+		}
+		profiles[p.Name] = true
+
+		ns := strings.TrimPrefix(p.Name, kdd.NamespaceProfileNamePrefix)
+		if _, ok := s.Namespaces[ns]; !ok {
+			violations = append(violations, Violation{
+				Invariant: InvariantProfileNamespace,
+				Key:       p.Name,
+				Message:   fmt.Sprintf("Profile %s exists but namespace %s does not", p.Name, ns),
+			})
+		}
+	}
+
+	// Only report each namespace once, however many policies it contains. Namespaces that the
+	// namespace controller doesn't maintain a profile for aren't expected to have one.
+	reported := map[string]bool{}
+	for _, p := range s.NetworkPolicies {
+		if !converter.IsManaged(p.ObjectMeta, "NetworkPolicy") || !s.Namespaces[p.Namespace] || reported[p.Namespace] {
+			continue
+		}
+		if !profiles[kdd.NamespaceProfileNamePrefix+p.Namespace] {
+			reported[p.Namespace] = true
+			violations = append(violations, Violation{
+				Invariant: InvariantPolicyNamespaceProfile,
+				Key:       p.Namespace,
+				Message:   fmt.Sprintf("Namespace %s contains policy %s but has no profile", p.Namespace, p.Name),
+				Object:    &v1.ObjectReference{APIVersion: "v1", Kind: "Namespace", Name: p.Namespace},
+			})
+		}
+	}
+
+	for _, wep := range s.WorkloadEndpoints {
+		if wep.Spec.Orchestrator != "k8s" {
+			continue
+		}
+		if !s.Nodes[wep.Spec.Node] {
+			violations = append(violations, Violation{
+				Invariant: InvariantWorkloadEndpointNode,
+				Key:       wep.Namespace + "/" + wep.Name,
+				Message:   fmt.Sprintf("WorkloadEndpoint %s/%s is on node %s which does not exist", wep.Namespace, wep.Name, wep.Spec.Node),
+				Object:    &v1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: wep.Namespace, Name: wep.Spec.Pod},
+			})
+		}
+	}
+
+	// Drop the violations of invariants that aren't checked.
+	checked := map[Invariant]bool{}
+	for _, inv := range invariants {
+		checked[inv] = true
+	}
+	filtered := violations[:0]
+	for _, v := range violations {
+		if checked[v.Invariant] {
+			filtered = append(filtered, v)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].ID() < filtered[j].ID()
+	})
+	return filtered
+}
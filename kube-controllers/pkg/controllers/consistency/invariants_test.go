@@ -0,0 +1,177 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consistency_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/consistency"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/namespace"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+)
+
+func profile(name string) api.Profile {
+	p := api.Profile{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	converter.SetOwnership(&p.ObjectMeta, "Namespace", "")
+	return p
+}
+
+func policy(namespace, name string) api.NetworkPolicy {
+	p := api.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	converter.SetOwnership(&p.ObjectMeta, "NetworkPolicy", "")
+	return p
+}
+
+func wep(namespace, name, orchestrator, node string) libapi.WorkloadEndpoint {
+	return libapi.WorkloadEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec:       libapi.WorkloadEndpointSpec{Orchestrator: orchestrator, Node: node, Pod: "pod-" + name},
+	}
+}
+
+var _ = Describe("Consistency invariants", func() {
+	var s consistency.Snapshot
+
+	BeforeEach(func() {
+		s = consistency.Snapshot{
+			Namespaces: map[string]bool{"default": true, "kube-system": true, "other": true},
+			Nodes:      map[string]bool{"node1": true},
+			Profiles: []api.Profile{
+				profile("kns.default"),
+				profile("kns.kube-system"),
+				{ObjectMeta: metav1.ObjectMeta{Name: "kns.custom"}},
+			},
+			NetworkPolicies: []api.NetworkPolicy{
+				policy("default", "knp.default.allow"),
+				{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "knp.default.user-policy"}},
+			},
+			WorkloadEndpoints: []libapi.WorkloadEndpoint{
+				wep("default", "wep1", "k8s", "node1"),
+				wep("default", "wep2", "openstack", "node2"),
+			},
+		}
+	})
+
+	It("should report no violations for a consistent snapshot", func() {
+		Expect(consistency.Check(s, consistency.AllInvariants)).To(BeEmpty())
+	})
+
+	It("should report a managed policy in a namespace without a profile", func() {
+		s.NetworkPolicies = append(s.NetworkPolicies,
+			policy("other", "knp.default.a"),
+			policy("other", "knp.default.b"),
+		)
+		v := consistency.Check(s, consistency.AllInvariants)
+		Expect(v).To(HaveLen(1))
+		Expect(v[0].Invariant).To(Equal(consistency.InvariantPolicyNamespaceProfile))
+		Expect(v[0].Key).To(Equal("other"))
+		Expect(v[0].Object.Kind).To(Equal("Namespace"))
+		Expect(v[0].Object.Name).To(Equal("other"))
+	})
+
+	It("should not report namespaces whose profiles aren't maintained", func() {
+		s.Namespaces["other"] = false
+		s.NetworkPolicies = append(s.NetworkPolicies, policy("other", "knp.default.a"))
+		Expect(consistency.Check(s, consistency.AllInvariants)).To(BeEmpty())
+	})
+
+	It("should only report the given invariants", func() {
+		s.NetworkPolicies = append(s.NetworkPolicies, policy("other", "knp.default.a"))
+		s.WorkloadEndpoints = append(s.WorkloadEndpoints, wep("default", "wep3", "k8s", "node2"))
+		v := consistency.Check(s, []consistency.Invariant{consistency.InvariantWorkloadEndpointNode})
+		Expect(v).To(HaveLen(1))
+		Expect(v[0].Invariant).To(Equal(consistency.InvariantWorkloadEndpointNode))
+	})
+
+	It("should report a workload endpoint on a node that does not exist", func() {
+		s.WorkloadEndpoints = append(s.WorkloadEndpoints, wep("default", "wep3", "k8s", "node2"))
+		v := consistency.Check(s, consistency.AllInvariants)
+		Expect(v).To(HaveLen(1))
+		Expect(v[0].Invariant).To(Equal(consistency.InvariantWorkloadEndpointNode))
+		Expect(v[0].Key).To(Equal("default/wep3"))
+		Expect(v[0].Object.Kind).To(Equal("Pod"))
+		Expect(v[0].Object.Name).To(Equal("pod-wep3"))
+	})
+
+	It("should report a profile for a namespace that does not exist", func() {
+		delete(s.Namespaces, "kube-system")
+		v := consistency.Check(s, consistency.AllInvariants)
+		Expect(v).To(HaveLen(1))
+		Expect(v[0].Invariant).To(Equal(consistency.InvariantProfileNamespace))
+		Expect(v[0].Key).To(Equal("kns.kube-system"))
+		Expect(v[0].Object).To(BeNil())
+	})
+})
+
+var _ = Describe("Consistency invariant selection", func() {
+	It("should only check the invariants involving enabled controllers", func() {
+		Expect(consistency.Invariants(config.ControllersConfig{})).To(BeEmpty())
+		Expect(consistency.Invariants(config.ControllersConfig{
+			Policy: &config.PolicyControllerConfig{},
+		})).To(BeEmpty())
+		Expect(consistency.Invariants(config.ControllersConfig{
+			Namespace: &config.GenericControllerConfig{},
+		})).To(ConsistOf(consistency.InvariantProfileNamespace))
+		Expect(consistency.Invariants(config.ControllersConfig{
+			Node:      &config.NodeControllerConfig{},
+			Policy:    &config.PolicyControllerConfig{},
+			Namespace: &config.GenericControllerConfig{},
+		})).To(ConsistOf(consistency.AllInvariants))
+	})
+})
+
+var _ = Describe("Namespace scope", func() {
+	var scope *consistency.NamespaceScope
+
+	BeforeEach(func() {
+		var err error
+		scope, err = consistency.NewNamespaceScope(config.GenericControllerConfig{
+			ExcludeNamespaces: []string{"kube-*"},
+			LabelSelector:     "team=a",
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	namespaceWith := func(name string, labels, annotations map[string]string) *v1.Namespace {
+		return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels, Annotations: annotations}}
+	}
+
+	It("should only include selected namespaces that aren't excluded or skipped", func() {
+		Expect(scope.Namespace(namespaceWith("default", map[string]string{"team": "a"}, nil))).To(BeTrue())
+		Expect(scope.Namespace(namespaceWith("other", map[string]string{"team": "b"}, nil))).To(BeFalse())
+		Expect(scope.Namespace(namespaceWith("kube-system", map[string]string{"team": "a"}, nil))).To(BeFalse())
+		Expect(scope.Namespace(namespaceWith("skipped", map[string]string{"team": "a"},
+			map[string]string{namespace.SkipProfileAnnotation: "true"}))).To(BeFalse())
+	})
+
+	It("should select the profiles of deleted namespaces by their labels", func() {
+		p := profile("kns.default")
+		p.Spec.LabelsToApply = map[string]string{"pcns.team": "a"}
+		Expect(scope.Profile(p)).To(BeTrue())
+		p.Spec.LabelsToApply = map[string]string{"pcns.team": "b"}
+		Expect(scope.Profile(p)).To(BeFalse())
+
+		p = profile("kns.kube-public")
+		p.Spec.LabelsToApply = map[string]string{"pcns.team": "a"}
+		Expect(scope.Profile(p)).To(BeFalse())
+	})
+})
@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consistency
+
+import (
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/namespace"
+	kdd "github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
+)
+
+// NamespaceScope decides which namespaces the namespace controller maintains profiles for, by
+// applying the same exclusions, selectors and opt-out annotation as the controller. Namespaces
+// that it leaves alone aren't expected to be consistent, so they aren't reported.
+type NamespaceScope struct {
+	filter    *controller.NamespaceFilter
+	selectors controller.Selectors
+}
+
+// NewNamespaceScope returns the scope of a namespace controller with the given config.
+func NewNamespaceScope(cfg config.GenericControllerConfig) (*NamespaceScope, error) {
+	selectors, err := controller.ParseSelectors(cfg.LabelSelector, cfg.FieldSelector)
+	if err != nil {
+		return nil, err
+	}
+	return &NamespaceScope{filter: controller.NewNamespaceFilter(cfg.ExcludeNamespaces), selectors: selectors}, nil
+}
+
+// Namespace returns true if the namespace controller maintains a profile for the namespace.
+func (s *NamespaceScope) Namespace(ns *v1.Namespace) bool {
+	return !s.filter.Excluded(ns.Name) &&
+		s.selectors.Matches(labels.Set(ns.Labels), fields.Set{"metadata.name": ns.Name, "status.phase": string(ns.Status.Phase)}) &&
+		ns.Annotations[namespace.SkipProfileAnnotation] != "true"
+}
+
+// Profile returns true if the namespace controller maintains the given profile, whose namespace
+// no longer exists. As in the controller, the namespace's labels are taken from the profile.
+func (s *NamespaceScope) Profile(p api.Profile) bool {
+	name := strings.TrimPrefix(p.Name, kdd.NamespaceProfileNamePrefix)
+	return !s.filter.Excluded(name) &&
+		(s.selectors.Empty() || s.selectors.Matches(namespace.ProfileNamespaceLabels(p), fields.Set{"metadata.name": name}))
+}
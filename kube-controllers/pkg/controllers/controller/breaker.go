@@ -0,0 +1,227 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// DatastoreBreaker is the circuit breaker shared by the controllers that write to the Calico
+// datastore. It's nil, disabling the breaker, unless set up by main before the controllers are
+// created.
+var DatastoreBreaker *CircuitBreaker
+
+var (
+	breakerOpenGauge   prometheus.Gauge
+	breakerTripCounter prometheus.Counter
+)
+
+func init() {
+	breakerOpenGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "controller_datastore_circuit_open",
+		Help: "Whether writes to the Calico datastore are suspended after repeated failures (1) or not (0)",
+	})
+	breakerTripCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "controller_datastore_circuit_trips_total",
+		Help: "Number of times writes to the Calico datastore have been suspended after repeated failures",
+	})
+	prometheus.MustRegister(breakerOpenGauge, breakerTripCounter)
+}
+
+// CircuitBreaker stops the controllers from hammering the Calico datastore while it's failing.
+// After threshold consecutive failed syncs the circuit opens, and the workers block rather than
+// each retrying independently. While the circuit is open, a single sync is let through as a
+// probe at exponentially increasing intervals, and the circuit closes again once a probe
+// succeeds. All methods are no-ops on a nil CircuitBreaker.
+type CircuitBreaker struct {
+	threshold int
+	minProbe  time.Duration
+	maxProbe  time.Duration
+
+	mu            sync.Mutex
+	failures      int
+	open          bool
+	probing       bool
+	probeInterval time.Duration
+	nextProbe     time.Time
+	changed       chan struct{}
+	onStateChange func(open bool, reason string)
+}
+
+// NewCircuitBreaker returns a CircuitBreaker that opens after threshold consecutive failures and
+// then probes the datastore after minProbe, doubling the interval after each failed probe up to
+// maxProbe. A threshold of 0 disables the breaker, returning nil.
+func NewCircuitBreaker(threshold int, minProbe, maxProbe time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	if maxProbe < minProbe {
+		maxProbe = minProbe
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		minProbe:  minProbe,
+		maxProbe:  maxProbe,
+		changed:   make(chan struct{}),
+	}
+}
+
+// OnStateChange registers a function that's called whenever the circuit opens or closes, for
+// example to report readiness. The reason is empty when the circuit closes.
+func (b *CircuitBreaker) OnStateChange(fn func(open bool, reason string)) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onStateChange = fn
+}
+
+// IsOpen returns whether writes to the datastore are currently suspended.
+func (b *CircuitBreaker) IsOpen() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+// Wait returns immediately while the circuit is closed. While it's open, Wait blocks until either
+// the circuit closes or it's the caller's turn to probe the datastore, or the context is done.
+// A caller that's let through must report the outcome of its sync with Record.
+func (b *CircuitBreaker) Wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	for {
+		b.mu.Lock()
+		if !b.open {
+			b.mu.Unlock()
+			return nil
+		}
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		if !b.probing {
+			delay := time.Until(b.nextProbe)
+			if delay <= 0 {
+				b.probing = true
+				b.mu.Unlock()
+				log.Info("Probing Calico datastore")
+				return nil
+			}
+			timer = time.NewTimer(delay)
+			timerC = timer.C
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-timerC:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// Record reports the outcome of a sync to the datastore. Errors returned by the datastore itself,
+// such as validation errors or update conflicts, show that it's reachable and count as successes.
+func (b *CircuitBreaker) Record(err error) {
+	if b == nil {
+		return
+	}
+	if _, ok := IsDeleteDelayed(err); ok {
+		// The sync didn't reach the datastore.
+		return
+	}
+	failed := isDatastoreFailure(err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.failures = 0
+		b.probing = false
+		if b.open {
+			log.Info("Calico datastore has recovered, resuming writes")
+			b.setOpen(false, "")
+		}
+		return
+	}
+
+	b.failures++
+	switch {
+	case b.open && b.probing:
+		b.probing = false
+		b.probeInterval *= 2
+		if b.probeInterval > b.maxProbe {
+			b.probeInterval = b.maxProbe
+		}
+		b.nextProbe = time.Now().Add(b.probeInterval)
+		log.WithError(err).WithField("retry", b.probeInterval).Warn("Calico datastore probe failed")
+		b.notify()
+	case !b.open && b.failures >= b.threshold:
+		b.probeInterval = b.minProbe
+		b.nextProbe = time.Now().Add(b.probeInterval)
+		log.WithError(err).WithField("failures", b.failures).Error("Repeated failures writing to Calico datastore, suspending writes")
+		breakerTripCounter.Inc()
+		b.setOpen(true, fmt.Sprintf("%d consecutive failures writing to datastore: %v", b.failures, err))
+	}
+}
+
+// setOpen opens or closes the circuit. The caller must hold the lock.
+func (b *CircuitBreaker) setOpen(open bool, reason string) {
+	b.open = open
+	if open {
+		breakerOpenGauge.Set(1)
+	} else {
+		breakerOpenGauge.Set(0)
+	}
+	b.notify()
+	if b.onStateChange != nil {
+		b.onStateChange(open, reason)
+	}
+}
+
+// notify wakes any callers blocked in Wait. The caller must hold the lock.
+func (b *CircuitBreaker) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// isDatastoreFailure returns whether the given error from a sync means that the datastore is
+// failing, rather than rejecting the particular write.
+func isDatastoreFailure(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	switch datastoreResult(err) {
+	case "error", "timeout":
+		return true
+	}
+	return false
+}
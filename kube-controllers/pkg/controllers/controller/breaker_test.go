@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var _ = Describe("CircuitBreaker", func() {
+	var b *controller.CircuitBreaker
+	var mu sync.Mutex
+	var states []bool
+
+	failure := errors.New("connection refused")
+
+	BeforeEach(func() {
+		b = controller.NewCircuitBreaker(3, 50*time.Millisecond, 100*time.Millisecond)
+		states = nil
+		b.OnStateChange(func(open bool, reason string) {
+			mu.Lock()
+			defer mu.Unlock()
+			states = append(states, open)
+		})
+	})
+
+	getStates := func() []bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]bool(nil), states...)
+	}
+
+	// waitReturned returns a channel that's closed once Wait returns.
+	waitReturned := func() chan struct{} {
+		done := make(chan struct{})
+		go func() {
+			defer GinkgoRecover()
+			Expect(b.Wait(context.Background())).To(Succeed())
+			close(done)
+		}()
+		return done
+	}
+
+	It("should be disabled with a zero threshold", func() {
+		b := controller.NewCircuitBreaker(0, time.Second, time.Second)
+		Expect(b).To(BeNil())
+		b.Record(failure)
+		Expect(b.IsOpen()).To(BeFalse())
+		Expect(b.Wait(context.Background())).To(Succeed())
+	})
+
+	It("should open after consecutive failures", func() {
+		b.Record(failure)
+		b.Record(failure)
+		b.Record(nil)
+		b.Record(failure)
+		b.Record(failure)
+		Expect(b.IsOpen()).To(BeFalse())
+		b.Record(failure)
+		Expect(b.IsOpen()).To(BeTrue())
+		Expect(getStates()).To(Equal([]bool{true}))
+	})
+
+	It("should not count errors returned by the datastore itself", func() {
+		for i := 0; i < 5; i++ {
+			b.Record(cerrors.ErrorResourceUpdateConflict{})
+			b.Record(cerrors.ErrorValidation{})
+			b.Record(context.Canceled)
+		}
+		Expect(b.IsOpen()).To(BeFalse())
+	})
+
+	It("should let a single probe through while open, and close once it succeeds", func() {
+		for i := 0; i < 3; i++ {
+			b.Record(failure)
+		}
+		probe := waitReturned()
+		Consistently(probe, 30*time.Millisecond).ShouldNot(BeClosed())
+		Eventually(probe).Should(BeClosed())
+
+		// Other callers are held while the probe is in flight.
+		other := waitReturned()
+		Consistently(other, 100*time.Millisecond).ShouldNot(BeClosed())
+
+		b.Record(nil)
+		Eventually(other).Should(BeClosed())
+		Expect(b.IsOpen()).To(BeFalse())
+		Expect(getStates()).To(Equal([]bool{true, false}))
+	})
+
+	It("should back off between failed probes", func() {
+		for i := 0; i < 3; i++ {
+			b.Record(failure)
+		}
+		Eventually(waitReturned()).Should(BeClosed())
+		start := time.Now()
+		b.Record(failure)
+		Eventually(waitReturned()).Should(BeClosed())
+		Expect(time.Since(start)).To(BeNumerically(">=", 100*time.Millisecond))
+		Expect(b.IsOpen()).To(BeTrue())
+	})
+
+	It("should stop waiting when the context is done", func() {
+		for i := 0; i < 3; i++ {
+			b.Record(failure)
+		}
+		Expect(b.Wait(context.Background())).To(Succeed())
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		Expect(b.Wait(ctx)).To(Equal(context.Canceled))
+	})
+})
@@ -14,8 +14,30 @@
 
 package controller
 
+import "github.com/projectcalico/calico/kube-controllers/pkg/cache"
+
 // Controller interface
 type Controller interface {
 	// Run method
 	Run(stopCh chan struct{})
 }
+
+// Resyncer is implemented by controllers that can reconcile the Calico datastore on request,
+// rather than waiting for their next periodic reconciliation.
+type Resyncer interface {
+	// Resync triggers a full reconciliation of the controller's resources.
+	Resync()
+}
+
+// DeleteConfirmer is implemented by controllers whose reconciliation can withhold a mass delete
+// from the Calico datastore until it is confirmed.
+type DeleteConfirmer interface {
+	// ConfirmDeletes allows the next reconciliation to make the withheld deletes, and triggers it.
+	ConfirmDeletes()
+}
+
+// Dumper is implemented by controllers that can report the contents of their cache and queue,
+// for troubleshooting.
+type Dumper interface {
+	Dump() cache.Dump
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/cache"
+)
+
+// MaxRecentConversionErrors is the maximum number of failing objects reported for each kind.
+const MaxRecentConversionErrors = 10
+
+var (
+	conversionErrorCounter *prometheus.CounterVec
+	conversionErrorObjects *prometheus.GaugeVec
+)
+
+func init() {
+	conversionErrorCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_conversion_errors_total",
+		Help: "Number of Kubernetes objects that couldn't be converted to Calico resources, by kind",
+	}, []string{"kind"})
+	prometheus.MustRegister(conversionErrorCounter)
+
+	conversionErrorObjects = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_conversion_error_last_timestamp_seconds",
+		Help: "Time of the last failed conversion of each of the most recently failing Kubernetes objects, by kind and key",
+	}, []string{"kind", "key"})
+	prometheus.MustRegister(conversionErrorObjects)
+}
+
+// ConversionErrors keeps track of the Kubernetes objects of a given kind that a controller failed
+// to convert to Calico resources. Such objects are skipped until they next change, so the most
+// recently failing objects are exported as metrics rather than only appearing in the logs.
+type ConversionErrors struct {
+	kind string
+
+	lock sync.Mutex
+	// Keys of the failing objects, oldest failure first.
+	recent []string
+}
+
+// NewConversionErrors returns a ConversionErrors for objects of the given kind.
+func NewConversionErrors(kind string) *ConversionErrors {
+	return &ConversionErrors{kind: kind}
+}
+
+// Record records that the given object couldn't be converted.
+func (e *ConversionErrors) Record(obj interface{}, err error) {
+	conversionErrorCounter.WithLabelValues(e.kind).Inc()
+	key, kerr := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if kerr != nil {
+		log.WithError(kerr).Warn("Failed to generate key for object that failed conversion")
+		return
+	}
+	log.WithError(err).WithFields(log.Fields{"kind": e.kind, "key": key}).Debug("Recording conversion error")
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.remove(key)
+	e.recent = append(e.recent, key)
+	conversionErrorObjects.WithLabelValues(e.kind, key).Set(float64(time.Now().Unix()))
+	if len(e.recent) > MaxRecentConversionErrors {
+		conversionErrorObjects.DeleteLabelValues(e.kind, e.recent[0])
+		e.recent = e.recent[1:]
+	}
+}
+
+// Clear records that the given object was converted successfully, so it's no longer reported as
+// failing.
+func (e *ConversionErrors) Clear(obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if e.remove(key) {
+		conversionErrorObjects.DeleteLabelValues(e.kind, key)
+	}
+}
+
+// Recent returns the keys of the most recently failing objects, oldest failure first.
+func (e *ConversionErrors) Recent() []string {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return append([]string(nil), e.recent...)
+}
+
+// remove removes the given key from the recent failures, returning whether it was present.
+func (e *ConversionErrors) remove(key string) bool {
+	for i, k := range e.recent {
+		if k == key {
+			e.recent = append(e.recent[:i], e.recent[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
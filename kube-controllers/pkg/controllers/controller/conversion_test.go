@@ -0,0 +1,61 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+)
+
+var _ = Describe("ConversionErrors", func() {
+	pod := func(name string) *v1.Pod {
+		return &v1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name}}
+	}
+	convErr := errors.New("unconvertible")
+
+	It("should report the most recently failing objects", func() {
+		e := controller.NewConversionErrors("Pod")
+		e.Record(pod("a"), convErr)
+		e.Record(pod("b"), convErr)
+		e.Record(pod("a"), convErr)
+		Expect(e.Recent()).To(Equal([]string{"default/b", "default/a"}))
+	})
+
+	It("should forget objects that later convert successfully", func() {
+		e := controller.NewConversionErrors("Pod")
+		e.Record(pod("a"), convErr)
+		e.Record(pod("b"), convErr)
+		e.Clear(pod("a"))
+		e.Clear(pod("c"))
+		Expect(e.Recent()).To(Equal([]string{"default/b"}))
+	})
+
+	It("should only keep a bounded number of failing objects", func() {
+		e := controller.NewConversionErrors("Pod")
+		for i := 0; i < controller.MaxRecentConversionErrors+5; i++ {
+			e.Record(pod(fmt.Sprintf("pod-%d", i)), convErr)
+		}
+		recent := e.Recent()
+		Expect(recent).To(HaveLen(controller.MaxRecentConversionErrors))
+		Expect(recent[0]).To(Equal("default/pod-5"))
+	})
+})
@@ -0,0 +1,86 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/tracing"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+// Datastore operations recorded by ObserveDatastoreOp.
+const (
+	DatastoreOpGet    = "get"
+	DatastoreOpList   = "list"
+	DatastoreOpCreate = "create"
+	DatastoreOpUpdate = "update"
+	DatastoreOpDelete = "delete"
+)
+
+var datastoreOpDuration *prometheus.HistogramVec
+
+func init() {
+	datastoreOpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "controller_datastore_operation_duration_seconds",
+		Help:    "Latency of the controllers' calls to the Calico datastore, by operation and result",
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 15),
+	}, []string{"controller", "operation", "result"})
+	prometheus.MustRegister(datastoreOpDuration)
+}
+
+// ObserveDatastoreOp records the latency of a call to the Calico datastore that was started at
+// the given time, labelled with the outcome of the call. The call is also traced as a child of
+// any span in the given context.
+func ObserveDatastoreOp(ctx context.Context, controller, op string, start time.Time, err error) {
+	datastoreOpDuration.WithLabelValues(controller, op, datastoreResult(err)).Observe(time.Since(start).Seconds())
+	tracing.Record(ctx, "datastore "+op, start, err)
+}
+
+// WithSyncTimeout returns a context for syncing a single key to the datastore that's cancelled
+// after the given timeout, so that a hung datastore connection fails the sync, and it's retried,
+// rather than blocking the worker indefinitely. A timeout of zero means no timeout.
+func WithSyncTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// datastoreResult maps the error returned by a Calico client call to a short result code.
+func datastoreResult(err error) string {
+	switch err.(type) {
+	case nil:
+		return "ok"
+	case cerrors.ErrorResourceDoesNotExist:
+		return "not_found"
+	case cerrors.ErrorResourceAlreadyExists:
+		return "already_exists"
+	case cerrors.ErrorResourceUpdateConflict:
+		return "conflict"
+	case cerrors.ErrorValidation:
+		return "invalid"
+	case cerrors.ErrorConnectionUnauthorized:
+		return "unauthorized"
+	}
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		return "timeout"
+	}
+	return "error"
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var _ = Describe("ObserveDatastoreOp", func() {
+	sampleCount := func(op, result string) uint64 {
+		mfs, err := prometheus.DefaultGatherer.Gather()
+		Expect(err).NotTo(HaveOccurred())
+		for _, mf := range mfs {
+			if mf.GetName() != "controller_datastore_operation_duration_seconds" {
+				continue
+			}
+			for _, m := range mf.GetMetric() {
+				labels := map[string]string{}
+				for _, l := range m.GetLabel() {
+					labels[l.GetName()] = l.GetValue()
+				}
+				if labels["controller"] == "test" && labels["operation"] == op && labels["result"] == result {
+					return m.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+		return 0
+	}
+
+	It("should record the latency of each operation labelled by its result", func() {
+		controller.ObserveDatastoreOp(context.Background(), "test", controller.DatastoreOpCreate, time.Now(), nil)
+		controller.ObserveDatastoreOp(context.Background(), "test", controller.DatastoreOpCreate, time.Now(), nil)
+		controller.ObserveDatastoreOp(context.Background(), "test", controller.DatastoreOpDelete, time.Now(), cerrors.ErrorResourceDoesNotExist{})
+		controller.ObserveDatastoreOp(context.Background(), "test", controller.DatastoreOpUpdate, time.Now(), cerrors.ErrorResourceUpdateConflict{})
+
+		Expect(sampleCount(controller.DatastoreOpCreate, "ok")).To(Equal(uint64(2)))
+		Expect(sampleCount(controller.DatastoreOpDelete, "not_found")).To(Equal(uint64(1)))
+		Expect(sampleCount(controller.DatastoreOpUpdate, "conflict")).To(Equal(uint64(1)))
+	})
+})
+
+var _ = Describe("WithSyncTimeout", func() {
+	It("should cancel the context after the timeout", func() {
+		ctx, cancel := controller.WithSyncTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		Eventually(ctx.Done()).Should(BeClosed())
+		Expect(ctx.Err()).To(Equal(context.DeadlineExceeded))
+	})
+
+	It("should not time out if the timeout is zero", func() {
+		ctx, cancel := controller.WithSyncTimeout(context.Background(), 0)
+		_, ok := ctx.Deadline()
+		Expect(ok).To(BeFalse())
+		cancel()
+		Expect(ctx.Err()).To(Equal(context.Canceled))
+	})
+})
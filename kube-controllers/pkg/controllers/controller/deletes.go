@@ -0,0 +1,108 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+)
+
+var deleteWaitCounter *prometheus.CounterVec
+
+func init() {
+	deleteWaitCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_delete_throttle_seconds_total",
+		Help: "Total time that deletes from the Calico datastore were delayed by the delete rate limit",
+	}, []string{"controller"})
+	prometheus.MustRegister(deleteWaitCounter)
+}
+
+// DeleteLimiter limits the rate at which a controller deletes resources from the Calico datastore,
+// so that a mass deletion is rolled out gradually rather than in one burst. Creates and updates
+// are not limited.
+//
+// A delete that isn't allowed yet is delayed by requeueing its key, rather than by blocking the
+// worker, so that the controller's other syncs carry on in the meantime.
+type DeleteLimiter struct {
+	name    string
+	limiter *rate.Limiter
+
+	// The time from which each delayed key may be deleted, as reserved from the limiter.
+	mu       sync.Mutex
+	reserved map[string]time.Time
+}
+
+// DeleteDelayedError is returned by a sync whose delete has been delayed by the delete rate limit.
+// It isn't a failure: the key should simply be requeued after the delay.
+type DeleteDelayedError struct {
+	Delay time.Duration
+}
+
+func (e DeleteDelayedError) Error() string {
+	return fmt.Sprintf("delete delayed by %v due to delete rate limit", e.Delay)
+}
+
+// IsDeleteDelayed returns the delay if the given sync error is a DeleteDelayedError.
+func IsDeleteDelayed(err error) (time.Duration, bool) {
+	var delayed DeleteDelayedError
+	if errors.As(err, &delayed) {
+		return delayed.Delay, true
+	}
+	return 0, false
+}
+
+// NewDeleteLimiter returns a DeleteLimiter allowing the given number of deletes per second, with
+// bursts of up to burst deletes. A limit of 0 disables rate limiting.
+func NewDeleteLimiter(name string, limit float64, burst int) *DeleteLimiter {
+	if limit <= 0 {
+		return &DeleteLimiter{name: name, limiter: rate.NewLimiter(rate.Inf, 0), reserved: map[string]time.Time{}}
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &DeleteLimiter{name: name, limiter: rate.NewLimiter(rate.Limit(limit), burst), reserved: map[string]time.Time{}}
+}
+
+// Reserve returns nil if the given key may be deleted now, or a DeleteDelayedError if the delete
+// must wait. The wait is reserved for the key, so that it is allowed to delete without waiting
+// again once the delay has passed.
+func (l *DeleteLimiter) Reserve(key string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if at, ok := l.reserved[key]; ok {
+		if delay := at.Sub(now); delay > 0 {
+			return DeleteDelayedError{Delay: delay}
+		}
+		delete(l.reserved, key)
+		return nil
+	}
+
+	delay := l.limiter.ReserveN(now, 1).DelayFrom(now)
+	if delay == 0 {
+		return nil
+	}
+	log.WithFields(log.Fields{"controller": l.name, "key": key, "delay": delay}).Info("Delaying delete due to delete rate limit")
+	deleteWaitCounter.WithLabelValues(l.name).Add(delay.Seconds())
+	l.reserved[key] = now.Add(delay)
+	return DeleteDelayedError{Delay: delay}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+)
+
+var _ = Describe("DeleteLimiter", func() {
+	It("should not delay deletes if the limit is disabled", func() {
+		l := controller.NewDeleteLimiter("test", 0, 0)
+		for i := 0; i < 100; i++ {
+			Expect(l.Reserve(fmt.Sprintf("key%d", i))).To(Succeed())
+		}
+	})
+
+	It("should allow a burst and then delay deletes", func() {
+		l := controller.NewDeleteLimiter("test", 10, 2)
+		Expect(l.Reserve("a")).To(Succeed())
+		Expect(l.Reserve("b")).To(Succeed())
+
+		err := l.Reserve("c")
+		delay, ok := controller.IsDeleteDelayed(err)
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(BeNumerically("~", 100*time.Millisecond, 20*time.Millisecond))
+
+		// Each delayed key waits its turn.
+		delay, ok = controller.IsDeleteDelayed(l.Reserve("d"))
+		Expect(ok).To(BeTrue())
+		Expect(delay).To(BeNumerically("~", 200*time.Millisecond, 20*time.Millisecond))
+	})
+
+	It("should keep a delayed key's reservation", func() {
+		l := controller.NewDeleteLimiter("test", 20, 1)
+		Expect(l.Reserve("a")).To(Succeed())
+		delay, ok := controller.IsDeleteDelayed(l.Reserve("b"))
+		Expect(ok).To(BeTrue())
+
+		// Trying again early doesn't reserve another delete.
+		again, ok := controller.IsDeleteDelayed(l.Reserve("b"))
+		Expect(ok).To(BeTrue())
+		Expect(again).To(BeNumerically("<=", delay))
+
+		time.Sleep(delay)
+		Expect(l.Reserve("b")).To(Succeed())
+	})
+})
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+// Classes of sync error, as reported by the controller_sync_errors_total metric.
+const (
+	ErrorClassTransient = "transient"
+	ErrorClassPermanent = "permanent"
+)
+
+var syncErrorsCounter *prometheus.CounterVec
+
+func init() {
+	syncErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_sync_errors_total",
+		Help: "Number of failed syncs of a resource to the Calico datastore, by controller and class of error",
+	}, []string{"controller", "class"})
+	prometheus.MustRegister(syncErrorsCounter)
+}
+
+// IsPermanentError returns whether the given sync error can't be resolved by retrying, because the
+// datastore rejected the resource as invalid or doesn't support the operation. Other errors, such
+// as connection failures and update conflicts, are transient.
+func IsPermanentError(err error) bool {
+	var invalid cerrors.ErrorValidation
+	var unsupported cerrors.ErrorOperationNotSupported
+	return errors.As(err, &invalid) || errors.As(err, &unsupported)
+}
+
+// ErrorClass returns the class of the given sync error.
+func ErrorClass(err error) string {
+	if IsPermanentError(err) {
+		return ErrorClassPermanent
+	}
+	return ErrorClassTransient
+}
+
+// RecordSyncError counts a failed sync by the named controller in the
+// controller_sync_errors_total metric.
+func RecordSyncError(controller string, err error) {
+	syncErrorsCounter.WithLabelValues(controller, ErrorClass(err)).Inc()
+}
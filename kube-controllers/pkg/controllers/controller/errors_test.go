@@ -0,0 +1,43 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var _ = Describe("IsPermanentError", func() {
+	It("should treat errors that the datastore would return again as permanent", func() {
+		Expect(controller.IsPermanentError(cerrors.ErrorValidation{})).To(BeTrue())
+		Expect(controller.IsPermanentError(cerrors.ErrorOperationNotSupported{})).To(BeTrue())
+		Expect(controller.IsPermanentError(fmt.Errorf("failed to write: %w", cerrors.ErrorValidation{}))).To(BeTrue())
+		Expect(controller.ErrorClass(cerrors.ErrorValidation{})).To(Equal(controller.ErrorClassPermanent))
+	})
+
+	It("should treat other errors as transient", func() {
+		Expect(controller.IsPermanentError(errors.New("connection refused"))).To(BeFalse())
+		Expect(controller.IsPermanentError(cerrors.ErrorResourceUpdateConflict{})).To(BeFalse())
+		Expect(controller.IsPermanentError(context.DeadlineExceeded)).To(BeFalse())
+		Expect(controller.ErrorClass(errors.New("connection refused"))).To(Equal(controller.ErrorClassTransient))
+	})
+})
@@ -0,0 +1,91 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// PagedListWatch returns a copy of the given ListWatch whose lists are split into pages of at
+// most pageSize items, so that listing a large number of resources doesn't require a single
+// huge response from the API server. A pageSize of 0 leaves lists unpaginated.
+//
+// The API server serves lists at resource version "0" from its watch cache, ignoring the limit,
+// so those are read from etcd instead. This reduces the memory used by each list, at the cost
+// of more load on etcd.
+func PagedListWatch(lw *cache.ListWatch, pageSize int64) *cache.ListWatch {
+	if pageSize <= 0 {
+		return lw
+	}
+	listFunc := lw.ListFunc
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.Limit = pageSize
+			if options.ResourceVersion == "0" && options.Continue == "" {
+				options.ResourceVersion = ""
+			}
+			return listFunc(options)
+		},
+		WatchFunc:       lw.WatchFunc,
+		DisableChunking: lw.DisableChunking,
+	}
+}
+
+// Selectors restrict the resources that a controller lists and watches from the k8s API to those
+// matching a label selector and a field selector, in the syntax of kubectl's --selector and
+// --field-selector.
+type Selectors struct {
+	Label labels.Selector
+	Field fields.Selector
+}
+
+// ParseSelectors parses the given label and field selectors. Empty selectors select everything.
+func ParseSelectors(labelSelector, fieldSelector string) (Selectors, error) {
+	l, err := labels.Parse(labelSelector)
+	if err != nil {
+		return Selectors{}, fmt.Errorf("invalid label selector %q: %w", labelSelector, err)
+	}
+	f, err := fields.ParseSelector(fieldSelector)
+	if err != nil {
+		return Selectors{}, fmt.Errorf("invalid field selector %q: %w", fieldSelector, err)
+	}
+	return Selectors{Label: l, Field: f}, nil
+}
+
+// Empty returns true if the selectors select everything.
+func (s Selectors) Empty() bool {
+	return s.Label.Empty() && s.Field.Empty()
+}
+
+// Matches returns true if the given labels and fields are selected. A field that the field
+// selector requires but that isn't in the given set doesn't match.
+func (s Selectors) Matches(l labels.Set, f fields.Set) bool {
+	return s.Label.Matches(l) && s.Field.Matches(f)
+}
+
+// ListWatch returns a ListWatch for the given resource that only lists and watches the selected
+// resources.
+func (s Selectors) ListWatch(c cache.Getter, resource string) *cache.ListWatch {
+	return cache.NewFilteredListWatchFromClient(c, resource, metav1.NamespaceAll, func(options *metav1.ListOptions) {
+		options.LabelSelector = s.Label.String()
+		options.FieldSelector = s.Field.String()
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+)
+
+var _ = Describe("PagedListWatch", func() {
+	var lw *cache.ListWatch
+	var listed []metav1.ListOptions
+
+	BeforeEach(func() {
+		listed = nil
+		lw = &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				listed = append(listed, options)
+				return &v1.NamespaceList{}, nil
+			},
+		}
+	})
+
+	It("should not change lists if pagination is disabled", func() {
+		Expect(controller.PagedListWatch(lw, 0)).To(BeIdenticalTo(lw))
+	})
+
+	It("should request pages from etcd rather than the watch cache", func() {
+		plw := controller.PagedListWatch(lw, 100)
+		_, err := plw.List(metav1.ListOptions{ResourceVersion: "0", Limit: 500})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = plw.List(metav1.ListOptions{Continue: "token"})
+		Expect(err).NotTo(HaveOccurred())
+		_, err = plw.List(metav1.ListOptions{ResourceVersion: "1234"})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(listed).To(Equal([]metav1.ListOptions{
+			{Limit: 100},
+			{Limit: 100, Continue: "token"},
+			{Limit: 100, ResourceVersion: "1234"},
+		}))
+	})
+})
+
+var _ = Describe("Selectors", func() {
+	It("should select everything if empty", func() {
+		s, err := controller.ParseSelectors("", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Empty()).To(BeTrue())
+		Expect(s.Matches(labels.Set{"a": "b"}, fields.Set{"metadata.name": "x"})).To(BeTrue())
+	})
+
+	It("should match both the label and field selectors", func() {
+		s, err := controller.ParseSelectors("owner=team-a", "metadata.name!=default")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Empty()).To(BeFalse())
+		Expect(s.Matches(labels.Set{"owner": "team-a"}, fields.Set{"metadata.name": "ns1"})).To(BeTrue())
+		Expect(s.Matches(labels.Set{"owner": "team-b"}, fields.Set{"metadata.name": "ns1"})).To(BeFalse())
+		Expect(s.Matches(labels.Set{"owner": "team-a"}, fields.Set{"metadata.name": "default"})).To(BeFalse())
+	})
+
+	It("should reject invalid selectors", func() {
+		_, err := controller.ParseSelectors("owner in (", "")
+		Expect(err).To(HaveOccurred())
+		_, err = controller.ParseSelectors("", "metadata.name~default")
+		Expect(err).To(HaveOccurred())
+	})
+})
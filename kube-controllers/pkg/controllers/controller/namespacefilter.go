@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"fmt"
+	"path"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NamespaceFilter excludes namespaces whose names match any of a set of glob patterns, such as
+// "ci-*", from being managed by a controller.
+type NamespaceFilter struct {
+	patterns []string
+}
+
+// ValidateNamespacePatterns checks that the given glob patterns are well formed.
+func ValidateNamespacePatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// NewNamespaceFilter returns a filter that excludes namespaces matching any of the given glob
+// patterns, in the syntax of path.Match. Malformed patterns match nothing, so they should be
+// checked with ValidateNamespacePatterns first.
+func NewNamespaceFilter(patterns []string) *NamespaceFilter {
+	return &NamespaceFilter{patterns: patterns}
+}
+
+// Excluded returns true if the given namespace should not be managed.
+func (f *NamespaceFilter) Excluded(namespace string) bool {
+	for _, p := range f.patterns {
+		if ok, _ := path.Match(p, namespace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// ListWatch returns a copy of the given ListWatch that omits objects in excluded namespaces, or
+// excluded Namespaces themselves, so that the informer using it never sees them. The ListWatch
+// is returned unchanged if nothing is excluded.
+func (f *NamespaceFilter) ListWatch(lw *cache.ListWatch) *cache.ListWatch {
+	if len(f.patterns) == 0 {
+		return lw
+	}
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			list, err := lw.ListFunc(options)
+			if err != nil {
+				return nil, err
+			}
+			items, err := meta.ExtractList(list)
+			if err != nil {
+				return nil, err
+			}
+			filtered := make([]runtime.Object, 0, len(items))
+			for _, item := range items {
+				if !f.excludedObject(item) {
+					filtered = append(filtered, item)
+				}
+			}
+			if err := meta.SetList(list, filtered); err != nil {
+				return nil, err
+			}
+			return list, nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			w, err := lw.WatchFunc(options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(w, func(e watch.Event) (watch.Event, bool) {
+				switch e.Type {
+				case watch.Added, watch.Modified, watch.Deleted:
+					return e, !f.excludedObject(e.Object)
+				}
+				return e, true
+			}), nil
+		},
+		DisableChunking: lw.DisableChunking,
+	}
+}
+
+// excludedObject returns true if the given object is in an excluded namespace, or is an excluded
+// Namespace.
+func (f *NamespaceFilter) excludedObject(obj runtime.Object) bool {
+	if ns, ok := obj.(*v1.Namespace); ok {
+		return f.Excluded(ns.Name)
+	}
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		log.WithError(err).Warnf("Unable to get the namespace of %T", obj)
+		return false
+	}
+	return f.Excluded(m.GetNamespace())
+}
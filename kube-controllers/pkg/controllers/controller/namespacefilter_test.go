@@ -0,0 +1,90 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+)
+
+var _ = Describe("NamespaceFilter", func() {
+	filter := controller.NewNamespaceFilter([]string{"ci-*", "scratch"})
+
+	sa := func(ns, name string) *v1.ServiceAccount {
+		return &v1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: name}}
+	}
+
+	It("should exclude namespaces matching any pattern", func() {
+		Expect(filter.Excluded("ci-1234")).To(BeTrue())
+		Expect(filter.Excluded("scratch")).To(BeTrue())
+		Expect(filter.Excluded("scratch-2")).To(BeFalse())
+		Expect(filter.Excluded("default")).To(BeFalse())
+		Expect(controller.NewNamespaceFilter(nil).Excluded("default")).To(BeFalse())
+	})
+
+	It("should validate patterns", func() {
+		Expect(controller.ValidateNamespacePatterns([]string{"ci-*", "team-[ab]"})).To(Succeed())
+		Expect(controller.ValidateNamespacePatterns([]string{"ci-["})).NotTo(Succeed())
+	})
+
+	It("should filter lists and watches of namespaced objects", func() {
+		fw := watch.NewFake()
+		lw := filter.ListWatch(&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return &v1.ServiceAccountList{Items: []v1.ServiceAccount{*sa("default", "a"), *sa("ci-1", "b")}}, nil
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return fw, nil
+			},
+		})
+
+		list, err := lw.List(metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.(*v1.ServiceAccountList).Items).To(ConsistOf(*sa("default", "a")))
+
+		w, err := lw.Watch(metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		defer w.Stop()
+		go func() {
+			fw.Add(sa("ci-1", "c"))
+			fw.Add(sa("default", "d"))
+		}()
+		var e watch.Event
+		Eventually(w.ResultChan()).Should(Receive(&e))
+		Expect(e.Object).To(Equal(sa("default", "d")))
+	})
+
+	It("should filter lists of Namespaces by name", func() {
+		lw := filter.ListWatch(&cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return &v1.NamespaceList{Items: []v1.Namespace{
+					{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+					{ObjectMeta: metav1.ObjectMeta{Name: "scratch"}},
+				}}, nil
+			},
+		})
+		list, err := lw.List(metav1.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list.(*v1.NamespaceList).Items).To(HaveLen(1))
+		Expect(list.(*v1.NamespaceList).Items[0].Name).To(Equal("default"))
+	})
+})
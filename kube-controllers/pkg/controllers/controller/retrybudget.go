@@ -0,0 +1,120 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Parameters of the retry budget. A budget always allows retryBudgetMinPerSecond retries per
+// second, and can save up at most retryBudgetMaxBalance retries.
+var (
+	retryBudgetMinPerSecond = 1.0
+	retryBudgetMaxBalance   = 100.0
+)
+
+// RetryBudgetBackoff is the base delay of a retry that's postponed because the retry budget is
+// exhausted. Postponed retries are spread over up to twice this delay, so that they don't all
+// happen at once.
+var RetryBudgetBackoff = 30 * time.Second
+
+var retryBudgetExhaustedCounter *prometheus.CounterVec
+
+func init() {
+	retryBudgetExhaustedCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "controller_retry_budget_exhausted_total",
+		Help: "Number of failed syncs whose retry was postponed because the controller's retry budget was exhausted",
+	}, []string{"controller"})
+	prometheus.MustRegister(retryBudgetExhaustedCounter)
+}
+
+// RetryBudget limits the retries of failed syncs made by a controller to a fraction of the syncs
+// it makes, so that when a large fraction of syncs are failing the controller backs off as a
+// whole, rather than retrying every failed key in lockstep. Each first attempt to sync a key
+// adds ratio to the budget, which also accrues a small number of retries per second, and each
+// retry spends one. All methods are no-ops on a nil RetryBudget, which allows every retry.
+type RetryBudget struct {
+	name  string
+	ratio float64
+
+	mu      sync.Mutex
+	balance float64
+	last    time.Time
+}
+
+// NewRetryBudget returns a RetryBudget for the named controller that allows retries of up to the
+// given fraction of its syncs, for example 0.2 for 20%. A ratio of 0 disables the budget,
+// returning nil.
+func NewRetryBudget(name string, ratio float64) *RetryBudget {
+	if ratio <= 0 {
+		return nil
+	}
+	return &RetryBudget{name: name, ratio: ratio, balance: retryBudgetMaxBalance, last: time.Now()}
+}
+
+// Deposit records a first attempt to sync a key, adding to the budget.
+func (b *RetryBudget) Deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.accrue()
+	b.balance = math.Min(b.balance+b.ratio, retryBudgetMaxBalance)
+}
+
+// TryWithdraw spends one retry from the budget, returning false if the budget is exhausted. In
+// that case the retry should be postponed by RetryDelay rather than made with the usual backoff.
+func (b *RetryBudget) TryWithdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.accrue()
+	if b.balance < 1 {
+		retryBudgetExhaustedCounter.WithLabelValues(b.name).Inc()
+		return false
+	}
+	b.balance--
+	return true
+}
+
+// RetryDelay returns how long to postpone a retry that the budget didn't allow.
+func (b *RetryBudget) RetryDelay() time.Duration {
+	return wait.Jitter(RetryBudgetBackoff, 1.0)
+}
+
+// Postpone requeues the given key once RetryDelay has passed, for a retry that the budget didn't
+// allow. The retry is still counted, with AddRateLimited, so that a key that keeps failing while
+// the budget is exhausted reaches the retry limit and is quarantined as usual.
+func (b *RetryBudget) Postpone(queue workqueue.RateLimitingInterface, key interface{}) {
+	time.AfterFunc(b.RetryDelay(), func() {
+		queue.AddRateLimited(key)
+	})
+}
+
+// accrue adds the retries accrued since the last update. The caller must hold the lock.
+func (b *RetryBudget) accrue() {
+	now := time.Now()
+	b.balance = math.Min(b.balance+now.Sub(b.last).Seconds()*retryBudgetMinPerSecond, retryBudgetMaxBalance)
+	b.last = now
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+)
+
+var _ = Describe("RetryBudget", func() {
+	It("should allow every retry when disabled", func() {
+		b := controller.NewRetryBudget("test", 0)
+		Expect(b).To(BeNil())
+		for i := 0; i < 1000; i++ {
+			Expect(b.TryWithdraw()).To(BeTrue())
+		}
+	})
+
+	It("should limit retries to a fraction of syncs once the initial balance is spent", func() {
+		b := controller.NewRetryBudget("test", 0.5)
+
+		// Spend the initial balance, plus anything accrued over time while doing so.
+		for b.TryWithdraw() {
+		}
+
+		// Four syncs allow two retries.
+		for i := 0; i < 4; i++ {
+			b.Deposit()
+		}
+		Expect(b.TryWithdraw()).To(BeTrue())
+		Expect(b.TryWithdraw()).To(BeTrue())
+		Expect(b.TryWithdraw()).To(BeFalse())
+	})
+
+	It("should spread postponed retries", func() {
+		b := controller.NewRetryBudget("test", 0.5)
+		d := b.RetryDelay()
+		Expect(d).To(BeNumerically(">=", controller.RetryBudgetBackoff))
+		Expect(d).To(BeNumerically("<=", 2*controller.RetryBudgetBackoff))
+	})
+	It("should count postponed retries of a key that keeps failing", func() {
+		backoff := controller.RetryBudgetBackoff
+		controller.RetryBudgetBackoff = time.Millisecond
+		defer func() { controller.RetryBudgetBackoff = backoff }()
+
+		b := controller.NewRetryBudget("test", 0.5)
+		for b.TryWithdraw() {
+		}
+		queue := workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, 10*time.Millisecond))
+		defer queue.ShutDown()
+
+		// Fail the key on every attempt, as the controllers do, postponing each retry since the
+		// budget stays exhausted.
+		queue.Add("key")
+		for attempt := 0; queue.NumRequeues("key") < 5; attempt++ {
+			Expect(attempt).To(BeNumerically("<", 5))
+			key, _ := queue.Get()
+			Expect(b.TryWithdraw()).To(BeFalse())
+			b.Postpone(queue, key)
+			queue.Done(key)
+			Eventually(queue.Len).Should(Equal(1))
+		}
+	})
+})
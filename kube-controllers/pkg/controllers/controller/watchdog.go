@@ -0,0 +1,171 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+// WorkerWatchdog is the watchdog shared by the controllers' workers. It's nil, disabling the
+// watchdog, unless set up by main before the controllers are created.
+var WorkerWatchdog *Watchdog
+
+var stalledWorkersGauge *prometheus.GaugeVec
+
+func init() {
+	stalledWorkersGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "controller_stalled_workers",
+		Help: "Number of workers that have been processing a single item for longer than the stall deadline",
+	}, []string{"controller"})
+	prometheus.MustRegister(stalledWorkersGauge)
+}
+
+// Watchdog detects workers that are stuck processing a single item, for example because of a
+// deadlock or a call that never returns. Workers report each item they process with Begin, and
+// if any item takes longer than the deadline the watchdog reports the workers as stalled, so that
+// the liveness probe fails and kube-controllers is restarted. All methods are no-ops on a nil
+// Watchdog.
+type Watchdog struct {
+	deadline time.Duration
+
+	mu            sync.Mutex
+	nextID        uint64
+	inFlight      map[uint64]workItem
+	stalled       bool
+	onStateChange func(stalled bool, reason string)
+}
+
+// workItem records the processing of a single item by one of a controller's workers.
+type workItem struct {
+	controller string
+	start      time.Time
+}
+
+// NewWatchdog returns a Watchdog that reports workers that have been processing a single item for
+// longer than the deadline. A deadline of 0 disables the watchdog, returning nil.
+func NewWatchdog(deadline time.Duration) *Watchdog {
+	if deadline <= 0 {
+		return nil
+	}
+	return &Watchdog{deadline: deadline, inFlight: map[uint64]workItem{}}
+}
+
+// OnStateChange registers a function that's called whenever the workers become stalled or
+// recover, for example to report liveness. The reason is empty when the workers recover.
+func (w *Watchdog) OnStateChange(fn func(stalled bool, reason string)) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onStateChange = fn
+}
+
+// Begin records that one of the named controller's workers has started processing an item. The
+// returned function must be called when the worker has finished with the item.
+func (w *Watchdog) Begin(controller string) (done func()) {
+	if w == nil {
+		return func() {}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	id := w.nextID
+	w.nextID++
+	w.inFlight[id] = workItem{controller: controller, start: time.Now()}
+	return func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		delete(w.inFlight, id)
+	}
+}
+
+// Stalled returns whether any worker was stuck at the last check.
+func (w *Watchdog) Stalled() bool {
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.stalled
+}
+
+// Run checks for stalled workers until the context is done.
+func (w *Watchdog) Run(ctx context.Context) {
+	if w == nil {
+		return
+	}
+	ticker := time.NewTicker(w.deadline / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.check()
+		}
+	}
+}
+
+// check reports any workers that have exceeded the deadline.
+func (w *Watchdog) check() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	counts := map[string]int{}
+	var oldest time.Duration
+	for _, item := range w.inFlight {
+		age := now.Sub(item.start)
+		if age <= w.deadline {
+			continue
+		}
+		counts[item.controller]++
+		if age > oldest {
+			oldest = age
+		}
+	}
+
+	stalledWorkersGauge.Reset()
+	var controllers []string
+	for controller, n := range counts {
+		stalledWorkersGauge.WithLabelValues(controller).Set(float64(n))
+		controllers = append(controllers, controller)
+	}
+	sort.Strings(controllers)
+
+	stalled := len(controllers) > 0
+	if stalled == w.stalled {
+		return
+	}
+	w.stalled = stalled
+	var reason string
+	if stalled {
+		reason = fmt.Sprintf("%s workers stalled processing an item for %s", strings.Join(controllers, ", "), oldest.Round(time.Second))
+		log.WithFields(log.Fields{"controllers": controllers, "duration": oldest}).Error("Controller workers have stalled processing an item")
+	} else {
+		log.Info("Stalled controller workers have recovered")
+	}
+	if w.onStateChange != nil {
+		w.onStateChange(stalled, reason)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+)
+
+var _ = Describe("Watchdog", func() {
+	var w *controller.Watchdog
+	var ctx context.Context
+	var cancel context.CancelFunc
+	var mu sync.Mutex
+	var reasons []string
+
+	BeforeEach(func() {
+		w = controller.NewWatchdog(50 * time.Millisecond)
+		reasons = nil
+		w.OnStateChange(func(stalled bool, reason string) {
+			mu.Lock()
+			defer mu.Unlock()
+			reasons = append(reasons, reason)
+		})
+		ctx, cancel = context.WithCancel(context.Background())
+		go w.Run(ctx)
+	})
+
+	AfterEach(func() {
+		cancel()
+	})
+
+	getReasons := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), reasons...)
+	}
+
+	It("should be disabled with a zero deadline", func() {
+		w := controller.NewWatchdog(0)
+		Expect(w).To(BeNil())
+		w.Begin("test")()
+		Expect(w.Stalled()).To(BeFalse())
+	})
+
+	It("should not report workers that finish within the deadline", func() {
+		for i := 0; i < 5; i++ {
+			done := w.Begin("test")
+			time.Sleep(10 * time.Millisecond)
+			done()
+		}
+		Consistently(w.Stalled, 150*time.Millisecond).Should(BeFalse())
+		Expect(getReasons()).To(BeEmpty())
+	})
+
+	It("should report a stalled worker until it finishes", func() {
+		done := w.Begin("test")
+		Eventually(w.Stalled).Should(BeTrue())
+		Expect(getReasons()).To(HaveLen(1))
+		Expect(getReasons()[0]).To(ContainSubstring("test workers stalled"))
+
+		done()
+		Eventually(w.Stalled).Should(BeFalse())
+		Expect(getReasons()).To(Equal([]string{getReasons()[0], ""}))
+	})
+})
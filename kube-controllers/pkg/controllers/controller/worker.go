@@ -0,0 +1,145 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/tracing"
+)
+
+// maxSyncRetries is the number of times a failed sync is retried with backoff before the key is
+// quarantined, and only retried occasionally.
+const maxSyncRetries = 5
+
+// SyncQueue is the part of a resource cache that a Worker uses.
+type SyncQueue interface {
+	GetQueue() workqueue.RateLimitingInterface
+	Synced(key string)
+	Quarantine(key string)
+	IsQuarantined(key string) bool
+}
+
+// SyncFunc syncs the resource with the given key to the datastore.
+type SyncFunc func(ctx context.Context, key string) error
+
+// Worker syncs the keys emitted by a resource cache's queue to the datastore, retrying failed
+// syncs within a retry budget and quarantining keys that keep failing.
+type Worker struct {
+	name        string
+	kind        string
+	queue       SyncQueue
+	syncTimeout time.Duration
+	sync        SyncFunc
+	retryBudget *RetryBudget
+}
+
+// NewWorker returns a Worker for the named controller, which syncs resources of the given kind
+// with the given function. The name is used in traces and metrics, and the kind in logs.
+func NewWorker(name, kind string, queue SyncQueue, syncTimeout time.Duration, retryBudgetRatio float64, sync SyncFunc) *Worker {
+	return &Worker{
+		name:        name,
+		kind:        kind,
+		queue:       queue,
+		syncTimeout: syncTimeout,
+		sync:        sync,
+		retryBudget: NewRetryBudget(name, retryBudgetRatio),
+	}
+}
+
+// ProcessNextItem waits for the next key in the queue and syncs it. It returns false once the
+// queue has been shut down.
+func (w *Worker) ProcessNextItem(ctx context.Context) bool {
+	// Wait until there is a new item in the work queue.
+	workqueue := w.queue.GetQueue()
+	key, quit := workqueue.Get()
+	if quit {
+		return false
+	}
+
+	// Sync the object to the Calico datastore, first holding off while writes to the datastore are
+	// suspended. Time spent waiting doesn't count against the sync timeout.
+	ctx, span := tracing.StartKey(ctx, w.name+" sync", key.(string))
+	err := DatastoreBreaker.Wait(ctx)
+	if err == nil {
+		done := WorkerWatchdog.Begin(w.name)
+		syncCtx, cancel := WithSyncTimeout(ctx, w.syncTimeout)
+		err = w.sync(syncCtx, key.(string))
+		cancel()
+		done()
+		DatastoreBreaker.Record(err)
+	}
+	tracing.End(span, err)
+	if err == nil {
+		w.queue.Synced(key.(string))
+	}
+	w.handleErr(err, key.(string))
+
+	// Indicate that we're done processing this key, allowing for safe parallel processing such that
+	// two objects with the same key are never processed in parallel.
+	workqueue.Done(key)
+	return true
+}
+
+// handleErr handles the result of syncing a key. A failed sync is retried with backoff up to
+// maxSyncRetries times, after which the key is quarantined.
+func (w *Worker) handleErr(err error, key string) {
+	workqueue := w.queue.GetQueue()
+	if delay, ok := IsDeleteDelayed(err); ok {
+		// Not a failure: the delete is allowed once the delay has passed.
+		workqueue.AddAfter(key, delay)
+		return
+	}
+	if workqueue.NumRequeues(key) == 0 {
+		// This was a first attempt to sync the key rather than a retry, so adds to the retry budget.
+		w.retryBudget.Deposit()
+	}
+	if err == nil {
+		// Forget about the #AddRateLimited history of the key on every successful synchronization.
+		// This ensures that future processing of updates for this key is not delayed because of
+		// an outdated error history.
+		workqueue.Forget(key)
+		return
+	}
+
+	RecordSyncError(w.name, err)
+
+	// A permanent error, such as a validation failure, would only fail again, so isn't retried
+	// with backoff, and nor are keys that are already quarantined.
+	if !IsPermanentError(err) && !w.queue.IsQuarantined(key) && workqueue.NumRequeues(key) < maxSyncRetries {
+		// Re-enqueue the key rate limited. Based on the rate limiter on the
+		// queue and the re-enqueue history, the key will be processed later again.
+		log.WithError(err).Errorf("Error syncing %s %v: %v", w.kind, key, err)
+		if !w.retryBudget.TryWithdraw() {
+			// Too many syncs are failing, so postpone the retry rather than adding to the load.
+			log.Warnf("Retry budget exhausted, postponing retry of %s %v", w.kind, key)
+			w.retryBudget.Postpone(workqueue, key)
+			return
+		}
+		workqueue.AddRateLimited(key)
+		return
+	}
+	workqueue.Forget(key)
+
+	// Report to an external entity that, even after several retries, we could not successfully process this key
+	uruntime.HandleError(err)
+	log.WithError(err).Errorf("Failed to sync %s %q, quarantining it: %v", w.kind, key, err)
+	w.queue.Quarantine(key)
+}
@@ -0,0 +1,172 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+// fakeSyncQueue is a SyncQueue that records which keys were synced and quarantined.
+type fakeSyncQueue struct {
+	queue       workqueue.RateLimitingInterface
+	lock        sync.Mutex
+	synced      []string
+	quarantined map[string]bool
+}
+
+func (q *fakeSyncQueue) GetQueue() workqueue.RateLimitingInterface {
+	return q.queue
+}
+
+func (q *fakeSyncQueue) Synced(key string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.synced = append(q.synced, key)
+}
+
+func (q *fakeSyncQueue) Quarantine(key string) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.quarantined[key] = true
+}
+
+func (q *fakeSyncQueue) IsQuarantined(key string) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.quarantined[key]
+}
+
+var _ = Describe("Worker", func() {
+	var q *fakeSyncQueue
+	var attempts int
+
+	BeforeEach(func() {
+		q = &fakeSyncQueue{
+			queue:       workqueue.NewRateLimitingQueue(workqueue.NewItemExponentialFailureRateLimiter(time.Millisecond, 10*time.Millisecond)),
+			quarantined: map[string]bool{},
+		}
+		attempts = 0
+	})
+
+	AfterEach(func() {
+		q.queue.ShutDown()
+	})
+
+	// process processes keys from the queue, as they become ready, until done returns true, and
+	// then checks that no more keys are queued.
+	process := func(w *controller.Worker, done func() bool) {
+		for !done() {
+			Eventually(q.queue.Len).Should(BeNumerically(">", 0))
+			Expect(w.ProcessNextItem(context.Background())).To(BeTrue())
+		}
+		Consistently(q.queue.Len, 50*time.Millisecond, time.Millisecond).Should(Equal(0))
+	}
+	synced := func() bool { return len(q.synced) > 0 }
+	quarantined := func() bool { return q.IsQuarantined("key") }
+
+	It("should sync a key and forget its failures", func() {
+		w := controller.NewWorker("test", "thing", q, 0, 0, func(ctx context.Context, key string) error {
+			attempts++
+			if attempts == 1 {
+				return errors.New("transient")
+			}
+			return nil
+		})
+		q.queue.Add("key")
+		process(w, synced)
+		Expect(attempts).To(Equal(2))
+		Expect(q.synced).To(Equal([]string{"key"}))
+		Expect(q.queue.NumRequeues("key")).To(Equal(0))
+		Expect(q.quarantined).To(BeEmpty())
+	})
+
+	It("should quarantine a key that keeps failing", func() {
+		w := controller.NewWorker("test", "thing", q, 0, 0, func(ctx context.Context, key string) error {
+			attempts++
+			return errors.New("transient")
+		})
+		q.queue.Add("key")
+		process(w, quarantined)
+		Expect(attempts).To(Equal(6))
+		Expect(q.synced).To(BeEmpty())
+		Expect(q.quarantined).To(HaveKey("key"))
+		Expect(q.queue.NumRequeues("key")).To(Equal(0))
+	})
+
+	It("should quarantine a key that keeps failing once the retry budget is exhausted", func() {
+		backoff := controller.RetryBudgetBackoff
+		controller.RetryBudgetBackoff = time.Millisecond
+		defer func() { controller.RetryBudgetBackoff = backoff }()
+
+		// A tiny ratio, so that the initial balance is soon spent by the retries of the many
+		// failing keys, and most of them are postponed.
+		w := controller.NewWorker("test", "thing", q, 0, 0.0001, func(ctx context.Context, key string) error {
+			attempts++
+			return errors.New("transient")
+		})
+		for i := 0; i < 200; i++ {
+			q.queue.Add(fmt.Sprint(i))
+		}
+
+		// Postponed retries still count towards quarantining a key.
+		process(w, func() bool { return len(q.quarantined) == 200 })
+		Expect(attempts).To(Equal(200 * 6))
+	})
+
+	It("should quarantine a key with a permanent error without retrying it", func() {
+		w := controller.NewWorker("test", "thing", q, 0, 0, func(ctx context.Context, key string) error {
+			attempts++
+			return cerrors.ErrorValidation{}
+		})
+		q.queue.Add("key")
+		process(w, quarantined)
+		Expect(attempts).To(Equal(1))
+		Expect(q.quarantined).To(HaveKey("key"))
+	})
+
+	It("should requeue a key whose delete was delayed without counting a failure", func() {
+		w := controller.NewWorker("test", "thing", q, 0, 0, func(ctx context.Context, key string) error {
+			attempts++
+			if attempts == 1 {
+				return controller.DeleteDelayedError{Delay: 10 * time.Millisecond}
+			}
+			return nil
+		})
+		q.queue.Add("key")
+		process(w, synced)
+		Expect(attempts).To(Equal(2))
+		Expect(q.synced).To(Equal([]string{"key"}))
+		Expect(q.quarantined).To(BeEmpty())
+	})
+
+	It("should stop once the queue is shut down", func() {
+		w := controller.NewWorker("test", "thing", q, 0, 0, func(ctx context.Context, key string) error {
+			return nil
+		})
+		q.queue.ShutDown()
+		Expect(w.ProcessNextItem(context.Background())).To(BeFalse())
+	})
+})
@@ -0,0 +1,197 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsnetworkset
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+var resolveErrorsCounter prometheus.Counter
+
+func init() {
+	resolveErrorsCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "dns_network_set_resolve_errors_total",
+		Help: "Number of failed attempts to resolve the domains of a NetworkSet",
+	})
+	prometheus.MustRegister(resolveErrorsCounter)
+}
+
+// schedule records when the domains of a NetworkSet are next due to be resolved.
+type schedule struct {
+	domains string
+	next    time.Time
+}
+
+// dnsNetworkSetController keeps the nets of NetworkSets with the domains annotation up to date
+// with the addresses of the domains, for egress policies to services whose addresses rotate.
+// Each set is resolved again when the shortest TTL of its answers expires, clamped to the
+// configured bounds, and NetworkSets are listed every ReconcilerPeriod to pick up new domains.
+type dnsNetworkSetController struct {
+	ctx          context.Context
+	calicoClient client.Interface
+	cfg          config.DNSNetworkSetControllerConfig
+	resolver     Resolver
+	schedules    map[string]*schedule
+}
+
+// NewDNSNetworkSetController returns a controller which resolves the domains of annotated
+// NetworkSets using the nameservers in /etc/resolv.conf.
+func NewDNSNetworkSetController(ctx context.Context, c client.Interface, cfg config.DNSNetworkSetControllerConfig) controller.Controller {
+	resolver, err := NewResolver("/etc/resolv.conf")
+	if err != nil {
+		log.WithError(err).Warning("Failed to read nameservers, using localhost")
+	}
+	return &dnsNetworkSetController{
+		ctx:          ctx,
+		calicoClient: c,
+		cfg:          cfg,
+		resolver:     resolver,
+		schedules:    map[string]*schedule{},
+	}
+}
+
+// Run starts the controller.
+func (c *dnsNetworkSetController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+
+	log.Info("Starting DNS NetworkSet controller")
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-stopCh:
+			log.Info("Stopping DNS NetworkSet controller")
+			return
+		case <-timer.C:
+			timer.Reset(c.syncAll(time.Now()))
+		}
+	}
+}
+
+// syncAll resolves the domains of the NetworkSets that are due, and returns how long to wait
+// before the next one is.
+func (c *dnsNetworkSetController) syncAll(now time.Time) time.Duration {
+	wait := c.cfg.ReconcilerPeriod
+
+	start := time.Now()
+	sets, err := c.calicoClient.NetworkSets().List(c.ctx, options.ListOptions{})
+	controller.ObserveDatastoreOp(c.ctx, "dnsnetworkset", controller.DatastoreOpList, start, err)
+	if err != nil {
+		log.WithError(err).Warning("Failed to list NetworkSets")
+		return c.cfg.MinTTL
+	}
+
+	seen := map[string]bool{}
+	for i := range sets.Items {
+		ns := &sets.Items[i]
+		value, ok := ns.Annotations[DomainsAnnotation]
+		if !ok {
+			continue
+		}
+		key := ns.Namespace + "/" + ns.Name
+		seen[key] = true
+
+		s := c.schedules[key]
+		if s == nil || s.domains != value || !now.Before(s.next) {
+			ctx, cancel := controller.WithSyncTimeout(c.ctx, c.cfg.SyncTimeout)
+			s = &schedule{domains: value, next: now.Add(c.sync(ctx, key, ns.Spec.Nets, value))}
+			cancel()
+			c.schedules[key] = s
+		}
+		if d := s.next.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	for key := range c.schedules {
+		if !seen[key] {
+			delete(c.schedules, key)
+		}
+	}
+	return wait
+}
+
+// sync resolves the given domains and writes their addresses to the NetworkSet with the given
+// key if they've changed. It returns how long the addresses are valid for. If resolution fails,
+// the NetworkSet keeps its current nets and is retried after the minimum TTL.
+func (c *dnsNetworkSetController) sync(ctx context.Context, key string, current []string, value string) time.Duration {
+	clog := log.WithField("networkSet", key)
+	domains, err := ParseDomains(value)
+	if err != nil {
+		// Nothing to do until the annotation changes.
+		clog.WithError(err).Warning("Invalid domains in NetworkSet")
+		return c.cfg.MaxTTL
+	}
+	nets, ttl, err := Resolve(ctx, c.resolver, domains)
+	if err != nil {
+		clog.WithError(err).Warning("Failed to resolve domains, keeping the current nets")
+		resolveErrorsCounter.Inc()
+		return c.cfg.MinTTL
+	}
+	if ttl < c.cfg.MinTTL {
+		ttl = c.cfg.MinTTL
+	} else if ttl > c.cfg.MaxTTL {
+		ttl = c.cfg.MaxTTL
+	}
+	if equalNets(nets, current) {
+		return ttl
+	}
+
+	// Re-read the NetworkSet, so that the update doesn't conflict with the list.
+	namespace, name, _ := strings.Cut(key, "/")
+	start := time.Now()
+	ns, err := c.calicoClient.NetworkSets().Get(ctx, namespace, name, options.GetOptions{})
+	controller.ObserveDatastoreOp(ctx, "dnsnetworkset", controller.DatastoreOpGet, start, err)
+	if err != nil {
+		clog.WithError(err).Warning("Failed to get NetworkSet")
+		return c.cfg.MinTTL
+	}
+	if ns.Annotations[DomainsAnnotation] != value {
+		// Changed since the list; it'll be resolved again on the next pass.
+		return 0
+	}
+	clog.WithField("networks", len(nets)).Info("Updating NetworkSet with the addresses of its domains")
+	ns.Spec.Nets = nets
+	start = time.Now()
+	_, err = c.calicoClient.NetworkSets().Update(ctx, ns, options.SetOptions{})
+	controller.ObserveDatastoreOp(ctx, "dnsnetworkset", controller.DatastoreOpUpdate, start, err)
+	if err != nil {
+		clog.WithError(err).Warning("Failed to update NetworkSet")
+		return c.cfg.MinTTL
+	}
+	return ttl
+}
+
+func equalNets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
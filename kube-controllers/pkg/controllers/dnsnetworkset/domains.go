@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsnetworkset
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	netsets "github.com/projectcalico/calico/kube-controllers/pkg/networkset"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+// DomainsAnnotation lists the domain names, separated by commas or whitespace, whose addresses
+// the controller writes to the nets of the annotated NetworkSet.
+const DomainsAnnotation = "projectcalico.org/domains"
+
+// ParseDomains returns the fully qualified domain names in the value of the domains annotation.
+// Wildcards aren't supported, since there's no way to resolve them.
+func ParseDomains(value string) ([]string, error) {
+	var domains []string
+	for _, d := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n'
+	}) {
+		d = strings.ToLower(strings.TrimSuffix(d, "."))
+		if errs := validation.IsDNS1123Subdomain(d); len(errs) > 0 {
+			return nil, cerrors.ErrorValidation{
+				ErroredFields: []cerrors.ErroredField{{
+					Name:   "metadata.annotations." + DomainsAnnotation,
+					Value:  d,
+					Reason: strings.Join(errs, ", "),
+				}},
+			}
+		}
+		domains = append(domains, d)
+	}
+	return domains, nil
+}
+
+// Resolve looks up all of the domains, and returns their addresses consolidated into the fewest
+// CIDRs that cover them, along with the lowest TTL of the answers. It fails if any of the domains can't be resolved,
+// so that a NetworkSet doesn't lose the addresses of a domain when its nameserver is unreachable.
+func Resolve(ctx context.Context, r Resolver, domains []string) ([]string, time.Duration, error) {
+	var nets []string
+	var ttl time.Duration
+	for i, d := range domains {
+		ips, t, err := r.LookupIP(ctx, d)
+		if err != nil {
+			return nil, 0, err
+		}
+		if i == 0 || t < ttl {
+			ttl = t
+		}
+		for _, ip := range ips {
+			nets = append(nets, hostNet(ip))
+		}
+	}
+
+	consolidated, err := netsets.ConsolidateCIDRs(nets)
+	if err != nil {
+		return nil, 0, err
+	}
+	return consolidated, ttl, nil
+}
+
+func hostNet(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	n := cnet.IPNet{IPNet: net.IPNet{IP: ip, Mask: net.CIDRMask(8*len(ip), 8*len(ip))}}
+	return n.String()
+}
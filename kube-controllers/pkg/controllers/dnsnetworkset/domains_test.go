@@ -0,0 +1,83 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsnetworkset_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/dnsnetworkset"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupIP(ctx context.Context, name string) ([]net.IP, time.Duration, error) {
+	addrs, ok := f[name]
+	if !ok {
+		return nil, 0, fmt.Errorf("%s not found", name)
+	}
+	var ips []net.IP
+	for _, a := range addrs {
+		ips = append(ips, net.ParseIP(a))
+	}
+	return ips, time.Duration(len(name)) * time.Second, nil
+}
+
+var _ = Describe("DNS NetworkSets", func() {
+	It("should parse the domains annotation", func() {
+		domains, err := dnsnetworkset.ParseDomains("api.example.com, Login.Example.COM.\n  cdn.example.net")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(domains).To(Equal([]string{"api.example.com", "login.example.com", "cdn.example.net"}))
+	})
+
+	It("should reject wildcards", func() {
+		_, err := dnsnetworkset.ParseDomains("api.example.com,*.example.com")
+		Expect(err).To(BeAssignableToTypeOf(cerrors.ErrorValidation{}))
+		Expect(err.Error()).To(ContainSubstring("*.example.com"))
+	})
+
+	It("should merge the addresses of all domains, with the lowest TTL", func() {
+		r := fakeResolver{
+			"api.example.com": {"192.0.2.10", "2001:db8::10"},
+			"cdn.example.net": {"198.51.100.1", "192.0.2.10"},
+		}
+		nets, ttl, err := dnsnetworkset.Resolve(context.Background(), r, []string{"api.example.com", "cdn.example.net"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nets).To(Equal([]string{"192.0.2.10/32", "198.51.100.1/32", "2001:db8::10/128"}))
+		Expect(ttl).To(Equal(15 * time.Second))
+	})
+
+	It("should consolidate adjacent addresses", func() {
+		r := fakeResolver{
+			"api.example.com": {"192.0.2.10", "192.0.2.11"},
+			"cdn.example.net": {"192.0.2.8", "192.0.2.9"},
+		}
+		nets, _, err := dnsnetworkset.Resolve(context.Background(), r, []string{"api.example.com", "cdn.example.net"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nets).To(Equal([]string{"192.0.2.8/30"}))
+	})
+
+	It("should fail if any domain can't be resolved", func() {
+		r := fakeResolver{"api.example.com": {"192.0.2.10"}}
+		_, _, err := dnsnetworkset.Resolve(context.Background(), r, []string{"api.example.com", "gone.example.com"})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,225 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsnetworkset
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Resolver looks up the addresses of a domain name, along with how long they may be cached for.
+type Resolver interface {
+	LookupIP(ctx context.Context, name string) ([]net.IP, time.Duration, error)
+}
+
+// dnsResolver queries the nameservers directly, rather than through the net package, because the
+// TTLs of the records are needed to know when to resolve them again.
+type dnsResolver struct {
+	servers []string
+}
+
+// NewResolver returns a Resolver that queries the nameservers in the given resolv.conf. Like the
+// net package, it falls back to a nameserver on localhost if the file lists none.
+func NewResolver(resolvConf string) (Resolver, error) {
+	r := &dnsResolver{}
+	f, err := os.Open(resolvConf)
+	if err != nil {
+		r.servers = []string{"127.0.0.1:53"}
+		return r, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "nameserver" {
+			continue
+		}
+		if ip := net.ParseIP(fields[1]); ip != nil {
+			r.servers = append(r.servers, net.JoinHostPort(ip.String(), "53"))
+		}
+	}
+	if len(r.servers) == 0 {
+		r.servers = []string{"127.0.0.1:53"}
+	}
+	return r, scanner.Err()
+}
+
+// LookupIP returns the IPv4 and IPv6 addresses of the given fully qualified name, and the lowest
+// TTL of the records that led to them. It fails if the name has no addresses at all.
+func (r *dnsResolver) LookupIP(ctx context.Context, name string) ([]net.IP, time.Duration, error) {
+	var ips []net.IP
+	var ttl uint32
+	for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+		var (
+			addrs []net.IP
+			t     uint32
+			err   error
+		)
+		for _, server := range r.servers {
+			addrs, t, err = r.query(ctx, server, name, qtype)
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(addrs) > 0 && (len(ips) == 0 || t < ttl) {
+			ttl = t
+		}
+		ips = append(ips, addrs...)
+	}
+	if len(ips) == 0 {
+		return nil, 0, fmt.Errorf("%s has no addresses", name)
+	}
+	return ips, time.Duration(ttl) * time.Second, nil
+}
+
+// query sends a single question to the server over UDP, retrying over TCP if the answer was
+// truncated.
+func (r *dnsResolver) query(ctx context.Context, server, name string, qtype dnsmessage.Type) ([]net.IP, uint32, error) {
+	qname, err := dnsmessage.NewName(strings.TrimSuffix(name, ".") + ".")
+	if err != nil {
+		return nil, 0, err
+	}
+	id := uint16(rand.Uint32())
+	msg := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}},
+	}
+	req, err := msg.Pack()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	resp, err := exchange(ctx, "udp", server, req)
+	if err != nil {
+		return nil, 0, err
+	}
+	var p dnsmessage.Parser
+	h, err := p.Start(resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	if h.Truncated {
+		if resp, err = exchange(ctx, "tcp", server, req); err != nil {
+			return nil, 0, err
+		}
+		if h, err = p.Start(resp); err != nil {
+			return nil, 0, err
+		}
+	}
+	if h.ID != id || !h.Response {
+		return nil, 0, fmt.Errorf("unexpected response from %s", server)
+	}
+	if h.RCode != dnsmessage.RCodeSuccess {
+		return nil, 0, fmt.Errorf("lookup of %s failed: %s", name, h.RCode)
+	}
+	if err := p.SkipAllQuestions(); err != nil {
+		return nil, 0, err
+	}
+
+	var ips []net.IP
+	var ttl uint32
+	first := true
+	for {
+		ah, err := p.AnswerHeader()
+		if err == dnsmessage.ErrSectionDone {
+			break
+		} else if err != nil {
+			return nil, 0, err
+		}
+		switch ah.Type {
+		case dnsmessage.TypeA:
+			a, err := p.AResource()
+			if err != nil {
+				return nil, 0, err
+			}
+			ips = append(ips, net.IP(a.A[:]))
+		case dnsmessage.TypeAAAA:
+			aaaa, err := p.AAAAResource()
+			if err != nil {
+				return nil, 0, err
+			}
+			ips = append(ips, net.IP(aaaa.AAAA[:]))
+		default:
+			// CNAMEs on the way to the addresses still limit how long they're valid for.
+			if err := p.SkipAnswer(); err != nil {
+				return nil, 0, err
+			}
+		}
+		if first || ah.TTL < ttl {
+			ttl = ah.TTL
+			first = false
+		}
+	}
+	return ips, ttl, nil
+}
+
+// exchange sends the request to the server and returns its response, using the 2 byte length
+// prefix that DNS over TCP requires.
+func exchange(ctx context.Context, network, server string, req []byte) ([]byte, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(5 * time.Second)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	if network == "udp" {
+		if _, err := conn.Write(req); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 65535)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	}
+
+	framed := make([]byte, 2+len(req))
+	binary.BigEndian.PutUint16(framed, uint16(len(req)))
+	copy(framed[2:], req)
+	if _, err := conn.Write(framed); err != nil {
+		return nil, err
+	}
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
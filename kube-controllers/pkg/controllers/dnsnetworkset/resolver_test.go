@@ -0,0 +1,126 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dnsnetworkset
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// serveDNS answers A and AAAA queries for api.example.com, through a CNAME, until the
+// connection is closed.
+func serveDNS(conn net.PacketConn) {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		var req dnsmessage.Message
+		if err := req.Unpack(buf[:n]); err != nil {
+			continue
+		}
+		q := req.Questions[0]
+		resp := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: req.ID, Response: true},
+			Questions: req.Questions,
+		}
+		target := dnsmessage.MustNewName("edge.example.net.")
+		if q.Name.String() == "api.example.com." {
+			resp.Answers = append(resp.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: q.Name, Type: dnsmessage.TypeCNAME, Class: dnsmessage.ClassINET, TTL: 300},
+				Body:   &dnsmessage.CNAMEResource{CNAME: target},
+			})
+			if q.Type == dnsmessage.TypeA {
+				for _, a := range [][4]byte{{192, 0, 2, 10}, {192, 0, 2, 11}} {
+					resp.Answers = append(resp.Answers, dnsmessage.Resource{
+						Header: dnsmessage.ResourceHeader{Name: target, Type: dnsmessage.TypeA, Class: dnsmessage.ClassINET, TTL: 60},
+						Body:   &dnsmessage.AResource{A: a},
+					})
+				}
+			}
+		} else {
+			resp.RCode = dnsmessage.RCodeNameError
+		}
+		out, err := resp.Pack()
+		if err != nil {
+			continue
+		}
+		_, _ = conn.WriteTo(out, addr)
+	}
+}
+
+var _ = Describe("DNS resolver", func() {
+	var (
+		conn     net.PacketConn
+		resolver Resolver
+	)
+
+	BeforeEach(func() {
+		var err error
+		conn, err = net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		go serveDNS(conn)
+
+		resolver = &dnsResolver{servers: []string{conn.LocalAddr().String()}}
+	})
+
+	AfterEach(func() {
+		conn.Close()
+	})
+
+	It("should return the addresses and the lowest TTL of the chain", func() {
+		ips, ttl, err := resolver.LookupIP(context.Background(), "api.example.com")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ips).To(HaveLen(2))
+		Expect(ips[0].String()).To(Equal("192.0.2.10"))
+		Expect(ips[1].String()).To(Equal("192.0.2.11"))
+		Expect(ttl).To(Equal(time.Minute))
+	})
+
+	It("should fail for a name that doesn't exist", func() {
+		_, _, err := resolver.LookupIP(context.Background(), "gone.example.com")
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("NameError"))
+	})
+
+	It("should read the nameservers from resolv.conf", func() {
+		dir, err := os.MkdirTemp("", "resolv")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		resolvConf := filepath.Join(dir, "resolv.conf")
+		Expect(os.WriteFile(resolvConf, []byte("search example.com\nnameserver 10.96.0.10\nnameserver fd00::a\noptions ndots:5\n"), 0o644)).To(Succeed())
+		r, err := NewResolver(resolvConf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.(*dnsResolver).servers).To(Equal([]string{"10.96.0.10:53", "[fd00::a]:53"}))
+	})
+
+	It("should fall back to localhost without resolv.conf", func() {
+		dir, err := os.MkdirTemp("", "resolv")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		r, err := NewResolver(filepath.Join(dir, "missing"))
+		Expect(err).To(HaveOccurred())
+		Expect(r.(*dnsResolver).servers).To(Equal([]string{"127.0.0.1:53"}))
+	})
+})
@@ -0,0 +1,200 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duplicateip
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+var (
+	duplicatesGauge *prometheus.GaugeVec
+	releasedCounter prometheus.Counter
+)
+
+func init() {
+	duplicatesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "duplicate_ips",
+		Help: "Number of IPs claimed by more than one workload endpoint or IPAM allocation",
+	}, []string{"source"})
+	releasedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "duplicate_ip_claims_released_total",
+		Help: "Number of stale IPAM claims on duplicate IPs that have been released",
+	})
+	prometheus.MustRegister(duplicatesGauge, releasedCounter)
+}
+
+// checker periodically looks for IPs that are claimed by more than one workload endpoint or IPAM
+// allocation. Duplicate IPs cause intermittent connectivity problems that are very hard to track
+// down, so each one is logged and reported as an Event on the pods involved.
+type checker struct {
+	ctx          context.Context
+	k8sClientset *kubernetes.Clientset
+	calicoClient client.Interface
+	period       time.Duration
+	release      bool
+	recorder     record.EventRecorder
+
+	// Duplicates found by the previous check. A duplicate is only reported once it is seen in two
+	// consecutive checks, since an IP can briefly appear twice while it is being reassigned.
+	previous map[string]bool
+
+	// Duplicates that have already been reported.
+	reported map[string]bool
+}
+
+// NewDuplicateIPChecker returns a controller which looks for duplicate IPs once every period. If
+// release is true, stale IPAM claims on duplicate IPs are released.
+func NewDuplicateIPChecker(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, period time.Duration, release bool) controller.Controller {
+	return &checker{
+		ctx:          ctx,
+		k8sClientset: k8sClientset,
+		calicoClient: c,
+		period:       period,
+		release:      release,
+		previous:     map[string]bool{},
+		reported:     map[string]bool{},
+	}
+}
+
+// Run starts the checker.
+func (c *checker) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+
+	log.Info("Starting duplicate IP checker")
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.k8sClientset.CoreV1().Events("")})
+	defer broadcaster.Shutdown()
+	c.recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "calico-kube-controllers"})
+
+	ticker := time.NewTicker(c.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			log.Info("Stopping duplicate IP checker")
+			return
+		case <-ticker.C:
+			if err := c.check(); err != nil {
+				log.WithError(err).Warning("Failed to check for duplicate IPs")
+			}
+		}
+	}
+}
+
+func (c *checker) check() error {
+	s, err := c.snapshot()
+	if err != nil {
+		return err
+	}
+
+	counts := map[Source]int{}
+	current := map[string]bool{}
+	for _, d := range Detect(s) {
+		id := d.ID()
+		current[id] = true
+		if !c.previous[id] {
+			continue
+		}
+		counts[d.Source]++
+		if !c.reported[id] {
+			log.WithFields(log.Fields{"source": d.Source, "ip": d.IP}).Warning(d.Message())
+			for _, claim := range d.Claims {
+				if obj := claim.Object(); obj != nil {
+					c.recorder.Event(obj, v1.EventTypeWarning, "CalicoDuplicateIP", d.Message())
+				}
+			}
+			c.reported[id] = true
+		}
+		if c.release {
+			c.releaseStaleClaims(d)
+		}
+	}
+	for id := range c.reported {
+		if !current[id] {
+			log.WithField("duplicate", id).Info("Duplicate IP has been resolved")
+			delete(c.reported, id)
+		}
+	}
+	c.previous = current
+
+	for _, src := range AllSources {
+		duplicatesGauge.WithLabelValues(string(src)).Set(float64(counts[src]))
+	}
+	return nil
+}
+
+// releaseStaleClaims releases the IPAM allocations of the stale claims on a duplicate IP. Any
+// errors are logged, and the release is retried on the next check if the duplicate remains.
+func (c *checker) releaseStaleClaims(d Duplicate) {
+	for _, claim := range d.Claims {
+		if !claim.Stale {
+			continue
+		}
+		logc := log.WithFields(log.Fields{"ip": d.IP, "handle": claim.Handle, "pod": claim.Namespace + "/" + claim.Pod})
+		logc.Info("Releasing stale claim on duplicate IP")
+		// Only release the duplicate IP. The handle covers all of the pod's IPs, and the others
+		// may well be in use.
+		_, err := c.calicoClient.IPAM().ReleaseIPs(c.ctx, ipam.ReleaseOptions{Address: d.IP, Handle: claim.Handle})
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); err != nil && !ok {
+			logc.WithError(err).Warning("Failed to release stale claim on duplicate IP")
+			continue
+		}
+		releasedCounter.Inc()
+	}
+}
+
+// snapshot lists the resources needed to detect duplicate IPs.
+func (c *checker) snapshot() (Snapshot, error) {
+	var s Snapshot
+
+	weps, err := c.calicoClient.WorkloadEndpoints().List(c.ctx, options.ListOptions{})
+	if err != nil {
+		return s, err
+	}
+	s.WorkloadEndpoints = weps.Items
+
+	type accessor interface {
+		Backend() bapi.Client
+	}
+	blocks, err := c.calicoClient.(accessor).Backend().List(c.ctx, model.BlockListOptions{}, "")
+	if err != nil {
+		return s, err
+	}
+	for _, kvp := range blocks.KVPairs {
+		if b, ok := kvp.Value.(*model.AllocationBlock); ok {
+			s.Blocks = append(s.Blocks, b)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duplicateip
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+// fakeIPAM records the IPs released. Any other IPAM call panics.
+type fakeIPAM struct {
+	ipam.Interface
+	released []ipam.ReleaseOptions
+}
+
+func (f *fakeIPAM) ReleaseIPs(ctx context.Context, opts ...ipam.ReleaseOptions) ([]cnet.IP, error) {
+	f.released = append(f.released, opts...)
+	return nil, nil
+}
+
+type fakeClient struct {
+	client.Interface
+	ipam *fakeIPAM
+}
+
+func (f fakeClient) IPAM() ipam.Interface {
+	return f.ipam
+}
+
+var _ = Describe("Duplicate IP checker", func() {
+	It("should only release the duplicate IP of a stale claim", func() {
+		handleA, handleB := "k8s-pod-network.a", "k8s-pod-network.b"
+		idx0, idx1 := 0, 1
+		attrs := func(handle *string, pod string) model.AllocationAttribute {
+			return model.AllocationAttribute{
+				AttrPrimary:   handle,
+				AttrSecondary: map[string]string{ipam.AttributeNamespace: "default", ipam.AttributePod: pod},
+			}
+		}
+
+		// Pod a is dual-stack, and its handle owns both 10.0.0.0 and fd00::1. It has moved off
+		// 10.0.0.0, which pod b now uses, but still uses fd00::1.
+		s := Snapshot{
+			WorkloadEndpoints: []libapi.WorkloadEndpoint{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "node1-k8s-a-eth0"},
+					Spec:       libapi.WorkloadEndpointSpec{Orchestrator: "k8s", Node: "node1", Pod: "a", IPNetworks: []string{"fd00::1/128"}},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "node1-k8s-b-eth0"},
+					Spec:       libapi.WorkloadEndpointSpec{Orchestrator: "k8s", Node: "node1", Pod: "b", IPNetworks: []string{"10.0.0.0/32"}},
+				},
+			},
+			Blocks: []*model.AllocationBlock{
+				{CIDR: cnet.MustParseCIDR("10.0.0.0/30"), Allocations: []*int{&idx0, nil, nil, nil}, Attributes: []model.AllocationAttribute{attrs(&handleA, "a")}},
+				{CIDR: cnet.MustParseCIDR("10.0.0.0/31"), Allocations: []*int{&idx0, nil}, Attributes: []model.AllocationAttribute{attrs(&handleB, "b")}},
+				{CIDR: cnet.MustParseCIDR("fd00::/126"), Allocations: []*int{nil, &idx1, nil, nil}, Attributes: []model.AllocationAttribute{{}, attrs(&handleA, "a")}},
+			},
+		}
+		d := Detect(s)
+		Expect(d).To(HaveLen(1))
+		Expect(d[0].IP).To(Equal("10.0.0.0"))
+
+		f := &fakeIPAM{}
+		c := &checker{ctx: context.Background(), calicoClient: fakeClient{ipam: f}}
+		c.releaseStaleClaims(d[0])
+		Expect(f.released).To(Equal([]ipam.ReleaseOptions{{Address: "10.0.0.0", Handle: handleA}}))
+	})
+})
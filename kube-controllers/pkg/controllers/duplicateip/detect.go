@@ -0,0 +1,190 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duplicateip
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+// Source identifies where the claims on a duplicate IP were found.
+type Source string
+
+const (
+	// More than one workload endpoint has the IP.
+	SourceWorkloadEndpoint Source = "workload-endpoint"
+
+	// More than one IPAM allocation, with different handles, is for the IP. This can only happen
+	// if IPAM blocks overlap.
+	SourceIPAM Source = "ipam"
+)
+
+// AllSources lists every source of duplicate IPs.
+var AllSources = []Source{SourceWorkloadEndpoint, SourceIPAM}
+
+// Snapshot is the set of resources that duplicate IPs are detected in.
+type Snapshot struct {
+	WorkloadEndpoints []libapi.WorkloadEndpoint
+	Blocks            []*model.AllocationBlock
+}
+
+// Claim is a workload endpoint or IPAM allocation that claims an IP.
+type Claim struct {
+	// Key uniquely identifies the claim among the claims on the same IP.
+	Key string
+
+	// The namespace and name of the pod that the claim belongs to, if known.
+	Namespace string
+	Pod       string
+
+	// The IPAM handle of the claim. Only set for IPAM claims.
+	Handle string
+
+	// Whether the claim is stale. An IPAM claim is stale if no workload endpoint for its pod has
+	// the IP, so the claim can be released. Workload endpoint claims are never marked stale.
+	Stale bool
+}
+
+// Object returns the Kubernetes object that any Event for the claim should be attached to, or nil
+// if there is no suitable object.
+func (c Claim) Object() *v1.ObjectReference {
+	if c.Pod == "" {
+		return nil
+	}
+	return &v1.ObjectReference{APIVersion: "v1", Kind: "Pod", Namespace: c.Namespace, Name: c.Pod}
+}
+
+// Duplicate describes an IP that is claimed more than once.
+type Duplicate struct {
+	Source Source
+	IP     string
+
+	// The claims on the IP, sorted by key.
+	Claims []Claim
+}
+
+// ID returns a unique identifier for the duplicate.
+func (d Duplicate) ID() string {
+	return string(d.Source) + "/" + d.IP
+}
+
+// Message returns a description of the duplicate, suitable for logs and Events.
+func (d Duplicate) Message() string {
+	keys := make([]string, len(d.Claims))
+	for i, c := range d.Claims {
+		keys[i] = c.Key
+	}
+	return fmt.Sprintf("IP %s is claimed by %d %s resources: %s", d.IP, len(d.Claims), d.Source, strings.Join(keys, ", "))
+}
+
+// Detect returns the IPs that are claimed more than once in the given snapshot, sorted by ID.
+func Detect(s Snapshot) []Duplicate {
+	// The claims on each IP from each source, and the IPs of the workload endpoints of each pod.
+	wepClaims := map[string][]Claim{}
+	podIPs := map[string]map[string]bool{}
+	for _, wep := range s.WorkloadEndpoints {
+		for _, n := range wep.Spec.IPNetworks {
+			ip, _, err := cnet.ParseCIDROrIP(n)
+			if err != nil {
+				continue
+			}
+			wepClaims[ip.String()] = append(wepClaims[ip.String()], Claim{
+				Key:       wep.Namespace + "/" + wep.Name,
+				Namespace: wep.Namespace,
+				Pod:       wep.Spec.Pod,
+			})
+			if wep.Spec.Pod != "" {
+				pod := wep.Namespace + "/" + wep.Spec.Pod
+				if podIPs[pod] == nil {
+					podIPs[pod] = map[string]bool{}
+				}
+				podIPs[pod][ip.String()] = true
+			}
+		}
+	}
+
+	ipamClaims := map[string][]Claim{}
+	for _, b := range s.Blocks {
+		for ord, idx := range b.Allocations {
+			if idx == nil || *idx >= len(b.Attributes) {
+				continue
+			}
+			attr := b.Attributes[*idx]
+			if attr.AttrPrimary == nil {
+				continue
+			}
+			ip := b.OrdinalToIP(ord).String()
+			ipamClaims[ip] = append(ipamClaims[ip], Claim{
+				Key:       b.CIDR.String() + "/" + *attr.AttrPrimary,
+				Namespace: attr.AttrSecondary[ipam.AttributeNamespace],
+				Pod:       attr.AttrSecondary[ipam.AttributePod],
+				Handle:    *attr.AttrPrimary,
+			})
+		}
+	}
+
+	var duplicates []Duplicate
+	for ip, claims := range wepClaims {
+		if len(claims) > 1 {
+			duplicates = append(duplicates, newDuplicate(SourceWorkloadEndpoint, ip, claims))
+		}
+	}
+	for ip, claims := range ipamClaims {
+		// Several IPs with the same handle aren't a conflict, so count the distinct handles.
+		handles := map[string]bool{}
+		for _, c := range claims {
+			handles[c.Handle] = true
+		}
+		if len(handles) < 2 {
+			continue
+		}
+
+		// A claim is stale if its pod doesn't have the IP. Only mark claims stale if at least one
+		// claim isn't, so that the IP is never released from every claim.
+		live := 0
+		for i, c := range claims {
+			claims[i].Stale = c.Pod != "" && !podIPs[c.Namespace+"/"+c.Pod][ip]
+			if !claims[i].Stale {
+				live++
+			}
+		}
+		if live == 0 {
+			for i := range claims {
+				claims[i].Stale = false
+			}
+		}
+		duplicates = append(duplicates, newDuplicate(SourceIPAM, ip, claims))
+	}
+
+	sort.Slice(duplicates, func(i, j int) bool {
+		return duplicates[i].ID() < duplicates[j].ID()
+	})
+	return duplicates
+}
+
+func newDuplicate(source Source, ip string, claims []Claim) Duplicate {
+	sort.Slice(claims, func(i, j int) bool {
+		return claims[i].Key < claims[j].Key
+	})
+	return Duplicate{Source: source, IP: ip, Claims: claims}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package duplicateip_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/duplicateip"
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+func wep(namespace, pod string, ips ...string) libapi.WorkloadEndpoint {
+	return libapi.WorkloadEndpoint{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "node1-k8s-" + pod + "-eth0"},
+		Spec:       libapi.WorkloadEndpointSpec{Orchestrator: "k8s", Node: "node1", Pod: pod, IPNetworks: ips},
+	}
+}
+
+// block returns a block with the given CIDR, and an allocation of its first IP for each of the
+// given pods, which must be fewer than the number of IPs in the block.
+func block(cidr string, pods ...string) *model.AllocationBlock {
+	b := &model.AllocationBlock{CIDR: cnet.MustParseCIDR(cidr), Allocations: make([]*int, 4)}
+	for i, pod := range pods {
+		idx := i
+		handle := "k8s-pod-network." + pod
+		b.Allocations[i] = &idx
+		b.Attributes = append(b.Attributes, model.AllocationAttribute{
+			AttrPrimary:   &handle,
+			AttrSecondary: map[string]string{ipam.AttributeNamespace: "default", ipam.AttributePod: pod},
+		})
+	}
+	return b
+}
+
+var _ = Describe("Duplicate IP detection", func() {
+	It("should find nothing if every IP is claimed once", func() {
+		Expect(duplicateip.Detect(duplicateip.Snapshot{
+			WorkloadEndpoints: []libapi.WorkloadEndpoint{wep("default", "a", "10.0.0.0/32"), wep("default", "b", "10.0.0.1/32")},
+			Blocks:            []*model.AllocationBlock{block("10.0.0.0/30", "a", "b")},
+		})).To(BeEmpty())
+	})
+
+	It("should find workload endpoints with the same IP", func() {
+		d := duplicateip.Detect(duplicateip.Snapshot{
+			WorkloadEndpoints: []libapi.WorkloadEndpoint{
+				wep("default", "a", "10.0.0.0/32"),
+				wep("default", "b", "10.0.0.0/32", "fd00::1/128"),
+			},
+		})
+		Expect(d).To(HaveLen(1))
+		Expect(d[0].ID()).To(Equal("workload-endpoint/10.0.0.0"))
+		Expect(d[0].Claims).To(HaveLen(2))
+		Expect(d[0].Claims[0].Object().Name).To(Equal("a"))
+		Expect(d[0].Claims[0].Stale).To(BeFalse())
+		Expect(d[0].Message()).To(ContainSubstring("default/node1-k8s-b-eth0"))
+	})
+
+	It("should find overlapping IPAM allocations and mark the one without a workload endpoint stale", func() {
+		d := duplicateip.Detect(duplicateip.Snapshot{
+			WorkloadEndpoints: []libapi.WorkloadEndpoint{wep("default", "b", "10.0.0.0/32")},
+			Blocks:            []*model.AllocationBlock{block("10.0.0.0/30", "a"), block("10.0.0.0/31", "b")},
+		})
+		Expect(d).To(HaveLen(1))
+		Expect(d[0].ID()).To(Equal("ipam/10.0.0.0"))
+		Expect(d[0].Claims).To(HaveLen(2))
+		Expect(d[0].Claims[0].Key).To(Equal("10.0.0.0/30/k8s-pod-network.a"))
+		Expect(d[0].Claims[0].Stale).To(BeTrue())
+		Expect(d[0].Claims[1].Handle).To(Equal("k8s-pod-network.b"))
+		Expect(d[0].Claims[1].Stale).To(BeFalse())
+	})
+
+	It("should not mark every IPAM claim stale", func() {
+		d := duplicateip.Detect(duplicateip.Snapshot{
+			Blocks: []*model.AllocationBlock{block("10.0.0.0/30", "a"), block("10.0.0.0/31", "b")},
+		})
+		Expect(d).To(HaveLen(1))
+		for _, c := range d[0].Claims {
+			Expect(c.Stale).To(BeFalse())
+		}
+	})
+})
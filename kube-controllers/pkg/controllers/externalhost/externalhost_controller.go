@@ -0,0 +1,261 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalhost
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// externalHostController implements the Controller interface for converting ExternalHosts into
+// HostEndpoints, so that hosts outside the cluster can be registered with Kubernetes RBAC and
+// tooling, and protected by the same policy model as the cluster's nodes.
+type externalHostController struct {
+	informer     cache.Controller
+	indexer      cache.Indexer
+	queue        workqueue.RateLimitingInterface
+	k8sClientset *kubernetes.Clientset
+	calicoClient client.Interface
+	ctx          context.Context
+	cfg          config.GenericControllerConfig
+	recorder     record.EventRecorder
+}
+
+// NewExternalHostController returns a controller which maintains a HostEndpoint for each
+// ExternalHost.
+func NewExternalHostController(ctx context.Context, k8sClientset *kubernetes.Clientset, dynamicClient dynamic.Interface, c client.Interface, cfg config.GenericControllerConfig) controller.Controller {
+	ehc := &externalHostController{
+		queue:        workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		k8sClientset: k8sClientset,
+		calicoClient: c,
+		ctx:          ctx,
+		cfg:          cfg,
+	}
+
+	enqueue := func(obj interface{}) {
+		if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+			ehc.queue.Add(key)
+		}
+	}
+	listWatcher := &cache.ListWatch{
+		ListFunc: func(o metav1.ListOptions) (runtime.Object, error) {
+			return dynamicClient.Resource(Resource).List(ctx, o)
+		},
+		WatchFunc: func(o metav1.ListOptions) (watch.Interface, error) {
+			return dynamicClient.Resource(Resource).Watch(ctx, o)
+		},
+	}
+	ehc.indexer, ehc.informer = cache.NewIndexerInformer(listWatcher, &unstructured.Unstructured{}, cfg.ReconcilerPeriod, cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			enqueue(newObj)
+		},
+		DeleteFunc: enqueue,
+	}, cache.Indexers{})
+
+	return ehc
+}
+
+// Run starts the controller.
+func (c *externalHostController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	log.Info("Starting ExternalHost controller")
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.k8sClientset.CoreV1().Events("")})
+	defer broadcaster.Shutdown()
+	c.recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "calico-kube-controllers"})
+
+	go c.informer.Run(stopCh)
+	if !cache.WaitForNamedCacheSync("externalhosts", stopCh, c.informer.HasSynced) {
+		log.Info("Failed to sync resources, received signal for controller to shut down.")
+		return
+	}
+
+	// Queue the HostEndpoints of ExternalHosts that were deleted while we weren't running, so
+	// that they are cleaned up.
+	if err := c.queueOrphans(); err != nil {
+		log.WithError(err).Warning("Failed to list HostEndpoints generated from ExternalHosts")
+	}
+
+	for i := 0; i < c.cfg.NumberOfWorkers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	log.Info("ExternalHost controller is now running")
+
+	<-stopCh
+	log.Info("Stopping ExternalHost controller")
+}
+
+func (c *externalHostController) queueOrphans() error {
+	start := time.Now()
+	heps, err := c.calicoClient.HostEndpoints().List(c.ctx, options.ListOptions{})
+	controller.ObserveDatastoreOp(c.ctx, "externalhost", controller.DatastoreOpList, start, err)
+	if err != nil {
+		return err
+	}
+	for i := range heps.Items {
+		hep := &heps.Items[i]
+		if !converter.IsManaged(hep.ObjectMeta, SourceKind) {
+			continue
+		}
+		key := SourceName(hep)
+		if _, exists, _ := c.indexer.GetByKey(key); !exists {
+			c.queue.Add(key)
+		}
+	}
+	return nil
+}
+
+func (c *externalHostController) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+// processNextItem waits for an ExternalHost on the queue and syncs its HostEndpoint.
+func (c *externalHostController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	done := controller.WorkerWatchdog.Begin("externalhost")
+	ctx, cancel := controller.WithSyncTimeout(c.ctx, c.cfg.SyncTimeout)
+	err := c.sync(ctx, key.(string))
+	cancel()
+	done()
+
+	if err == nil {
+		c.queue.Forget(key)
+		return true
+	}
+	controller.RecordSyncError("externalhost", err)
+	if !controller.IsPermanentError(err) && c.queue.NumRequeues(key) < 5 {
+		log.WithError(err).Errorf("Error syncing ExternalHost %v: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	uruntime.HandleError(err)
+	log.WithError(err).Errorf("Dropping ExternalHost %q out of the queue: %v", key, err)
+	return true
+}
+
+// sync makes the HostEndpoint of the ExternalHost with the given name match it, deleting the
+// HostEndpoint if the ExternalHost no longer exists. HostEndpoints that weren't generated from an
+// ExternalHost are never modified.
+func (c *externalHostController) sync(ctx context.Context, name string) error {
+	clog := log.WithField("externalHost", name)
+
+	var desired *api.HostEndpoint
+	obj, exists, err := c.indexer.GetByKey(name)
+	if err != nil {
+		return err
+	}
+	if exists {
+		u := obj.(*unstructured.Unstructured)
+		eh, err := FromUnstructured(u)
+		if err != nil {
+			err = cerrors.ErrorValidation{ErroredFields: []cerrors.ErroredField{{Name: "spec", Reason: err.Error()}}}
+		} else {
+			desired, err = DesiredHostEndpoint(eh)
+		}
+		if err != nil {
+			clog.WithError(err).Warning("Invalid ExternalHost")
+			c.recorder.Event(u, v1.EventTypeWarning, "CalicoExternalHostInvalid", err.Error())
+			return err
+		}
+	}
+
+	hepName := HostEndpointName(name)
+	start := time.Now()
+	current, err := c.calicoClient.HostEndpoints().Get(ctx, hepName, options.GetOptions{})
+	controller.ObserveDatastoreOp(ctx, "externalhost", controller.DatastoreOpGet, start, err)
+	if err != nil {
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			return err
+		}
+		if desired == nil {
+			return nil
+		}
+		clog.Info("Creating HostEndpoint for ExternalHost")
+		start = time.Now()
+		_, err = c.calicoClient.HostEndpoints().Create(ctx, desired, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "externalhost", controller.DatastoreOpCreate, start, err)
+		return err
+	}
+
+	if !converter.IsManaged(current.ObjectMeta, SourceKind) {
+		if desired != nil {
+			clog.WithField("hostEndpoint", hepName).Warning("HostEndpoint already exists, and wasn't generated from the ExternalHost")
+			return cerrors.ErrorResourceAlreadyExists{Identifier: hepName}
+		}
+		return nil
+	}
+
+	if desired == nil {
+		clog.Info("Deleting HostEndpoint of deleted ExternalHost")
+		start = time.Now()
+		_, err = c.calicoClient.HostEndpoints().Delete(ctx, hepName, options.DeleteOptions{ResourceVersion: current.ResourceVersion})
+		controller.ObserveDatastoreOp(ctx, "externalhost", controller.DatastoreOpDelete, start, err)
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); ok {
+			return nil
+		}
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(current.Labels, desired.Labels) &&
+		!converter.NeedsUpdate(current.ObjectMeta, current.Spec, desired.ObjectMeta, desired.Spec) {
+		return nil
+	}
+	clog.Info("Updating HostEndpoint from ExternalHost")
+	current.Labels = desired.Labels
+	converter.CopyManagedMetadata(&current.ObjectMeta, desired.ObjectMeta)
+	current.Spec = desired.Spec
+	start = time.Now()
+	_, err = c.calicoClient.HostEndpoints().Update(ctx, current, options.SetOptions{})
+	controller.ObserveDatastoreOp(ctx, "externalhost", controller.DatastoreOpUpdate, start, err)
+	return err
+}
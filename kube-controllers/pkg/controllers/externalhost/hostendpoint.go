@@ -0,0 +1,99 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalhost
+
+import (
+	"net"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+const (
+	// NamePrefix is the prefix of the names of the generated HostEndpoints.
+	NamePrefix = "kext-"
+
+	// SourceKind identifies the HostEndpoints generated from ExternalHosts in their ownership
+	// labels.
+	SourceKind = "ExternalHost"
+)
+
+// HostEndpointName returns the name of the HostEndpoint of the ExternalHost with the given name.
+func HostEndpointName(name string) string {
+	return converter.ShortenName(NamePrefix+name, converter.DefaultMaxNameLength)
+}
+
+// FromUnstructured converts an ExternalHost read with the dynamic client.
+func FromUnstructured(u *unstructured.Unstructured) (*ExternalHost, error) {
+	eh := &ExternalHost{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, eh); err != nil {
+		return nil, err
+	}
+	return eh, nil
+}
+
+// DesiredHostEndpoint returns the HostEndpoint for the given ExternalHost, or a validation error
+// if the ExternalHost doesn't describe a valid HostEndpoint.
+func DesiredHostEndpoint(eh *ExternalHost) (*api.HostEndpoint, error) {
+	if eh.Spec.InterfaceName == "" && len(eh.Spec.IPs) == 0 {
+		return nil, cerrors.ErrorValidation{
+			ErroredFields: []cerrors.ErroredField{{Name: "spec", Reason: "must set interfaceName or ips"}},
+		}
+	}
+	ips := make([]string, 0, len(eh.Spec.IPs))
+	for i, s := range eh.Spec.IPs {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, cerrors.ErrorValidation{
+				ErroredFields: []cerrors.ErroredField{{Name: "spec.ips", Value: eh.Spec.IPs[i], Reason: "must be an IP address"}},
+			}
+		}
+		ips = append(ips, ip.String())
+	}
+
+	hep := api.NewHostEndpoint()
+	hep.Name = HostEndpointName(eh.Name)
+	hep.Labels = map[string]string{}
+	for k, v := range eh.Labels {
+		hep.Labels[k] = v
+	}
+	hep.Spec.Node = eh.Spec.Node
+	if hep.Spec.Node == "" {
+		hep.Spec.Node = eh.Name
+	}
+	hep.Spec.InterfaceName = eh.Spec.InterfaceName
+	hep.Spec.ExpectedIPs = ips
+	converter.SetOwnership(&hep.ObjectMeta, SourceKind, eh.UID)
+	if hep.Name != NamePrefix+eh.Name {
+		if hep.Annotations == nil {
+			hep.Annotations = map[string]string{}
+		}
+		hep.Annotations[converter.SourceNameAnnotation] = eh.Name
+	}
+	return hep, nil
+}
+
+// SourceName returns the name of the ExternalHost that the given HostEndpoint was generated from.
+func SourceName(hep *api.HostEndpoint) string {
+	if name, ok := hep.Annotations[converter.SourceNameAnnotation]; ok {
+		return name
+	}
+	return strings.TrimPrefix(hep.Name, NamePrefix)
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalhost_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/externalhost"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var _ = Describe("ExternalHosts", func() {
+	parse := func(name string, spec map[string]interface{}) *externalhost.ExternalHost {
+		u := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "kubecontrollers.projectcalico.org/v1",
+			"kind":       "ExternalHost",
+			"metadata": map[string]interface{}{
+				"name":   name,
+				"uid":    "eh-uid",
+				"labels": map[string]interface{}{"role": "db"},
+			},
+			"spec": spec,
+		}}
+		eh, err := externalhost.FromUnstructured(u)
+		Expect(err).NotTo(HaveOccurred())
+		return eh
+	}
+
+	It("should convert an ExternalHost into a HostEndpoint", func() {
+		eh := parse("db-1", map[string]interface{}{
+			"interfaceName": "eth0",
+			"ips":           []interface{}{"192.0.2.5", "2001:DB8::5"},
+		})
+		hep, err := externalhost.DesiredHostEndpoint(eh)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hep.Name).To(Equal("kext-db-1"))
+		Expect(hep.Labels).To(HaveKeyWithValue("role", "db"))
+		Expect(hep.Spec.Node).To(Equal("db-1"))
+		Expect(hep.Spec.InterfaceName).To(Equal("eth0"))
+		Expect(hep.Spec.ExpectedIPs).To(Equal([]string{"192.0.2.5", "2001:db8::5"}))
+		Expect(converter.IsManaged(hep.ObjectMeta, externalhost.SourceKind)).To(BeTrue())
+		Expect(hep.Annotations).To(HaveKeyWithValue(converter.SourceUIDAnnotation, "eh-uid"))
+		Expect(externalhost.SourceName(hep)).To(Equal("db-1"))
+
+		// The ExternalHost's labels must not be modified.
+		Expect(eh.Labels).To(HaveLen(1))
+	})
+
+	It("should use the configured node name", func() {
+		hep, err := externalhost.DesiredHostEndpoint(parse("db-1", map[string]interface{}{
+			"node":          "db-1.example.com",
+			"interfaceName": "*",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hep.Spec.Node).To(Equal("db-1.example.com"))
+		Expect(hep.Spec.ExpectedIPs).To(BeEmpty())
+	})
+
+	It("should record the name of an ExternalHost whose HostEndpoint name was shortened", func() {
+		name := strings.Repeat("a", 253)
+		hep, err := externalhost.DesiredHostEndpoint(parse(name, map[string]interface{}{"interfaceName": "*"}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(hep.Name)).To(BeNumerically("<=", converter.DefaultMaxNameLength))
+		Expect(hep.Name).To(Equal(externalhost.HostEndpointName(name)))
+		Expect(externalhost.SourceName(hep)).To(Equal(name))
+	})
+
+	It("should reject an ExternalHost without an interface or IPs", func() {
+		_, err := externalhost.DesiredHostEndpoint(parse("db-1", map[string]interface{}{}))
+		Expect(err).To(BeAssignableToTypeOf(cerrors.ErrorValidation{}))
+	})
+
+	It("should reject invalid IPs", func() {
+		_, err := externalhost.DesiredHostEndpoint(parse("db-1", map[string]interface{}{
+			"ips": []interface{}{"192.0.2.5", "db-1.example.com"},
+		}))
+		Expect(err).To(BeAssignableToTypeOf(cerrors.ErrorValidation{}))
+		Expect(err.Error()).To(ContainSubstring("db-1.example.com"))
+	})
+})
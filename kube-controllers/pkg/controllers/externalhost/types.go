@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalhost
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Resource is the ExternalHost custom resource, defined by the CRD in
+// config/crd/kubecontrollers.projectcalico.org_externalhosts.yaml. It lives in its own API
+// group, rather than crd.projectcalico.org, because it's read by the controllers from the
+// Kubernetes API, not through the Calico client.
+var Resource = schema.GroupVersionResource{
+	Group:    "kubecontrollers.projectcalico.org",
+	Version:  "v1",
+	Resource: "externalhosts",
+}
+
+// ExternalHost registers a host outside the cluster, such as a VM, so that it can be protected
+// by the same policies as the cluster's nodes. The controller converts each ExternalHost into a
+// HostEndpoint, with the ExternalHost's labels, which Felix on the host then enforces.
+type ExternalHost struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ExternalHostSpec `json:"spec"`
+}
+
+type ExternalHostSpec struct {
+	// The hostname of the host, as configured for Felix there. Defaults to the name of the
+	// ExternalHost.
+	Node string `json:"node,omitempty"`
+
+	// The interface to protect, or "*" for all of the host's interfaces. If empty, the
+	// HostEndpoint only serves as a target for policies using its IPs.
+	InterfaceName string `json:"interfaceName,omitempty"`
+
+	// The IPs of the host, which policies selecting its labels match.
+	IPs []string `json:"ips,omitempty"`
+}
@@ -0,0 +1,201 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package felixconfig
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/fields"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// felixConfigController implements the Controller interface for applying the settings in a
+// ConfigMap to the default FelixConfiguration, so that they can be managed with Kubernetes
+// tooling and RBAC.
+type felixConfigController struct {
+	informer     cache.Controller
+	indexer      cache.Indexer
+	queue        workqueue.RateLimitingInterface
+	k8sClientset *kubernetes.Clientset
+	calicoClient client.Interface
+	ctx          context.Context
+	cfg          config.FelixConfigControllerConfig
+	key          string
+	recorder     record.EventRecorder
+}
+
+// NewFelixConfigController returns a controller which applies the configured ConfigMap to the
+// default FelixConfiguration.
+func NewFelixConfigController(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, cfg config.FelixConfigControllerConfig) controller.Controller {
+	fcc := &felixConfigController{
+		queue:        workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		k8sClientset: k8sClientset,
+		calicoClient: c,
+		ctx:          ctx,
+		cfg:          cfg,
+		key:          cfg.Namespace + "/" + cfg.Name,
+	}
+
+	// There is only one ConfigMap, so every event is for the same key. Resyncs of the ConfigMap
+	// revert any changes made to its settings in the FelixConfiguration by other means.
+	enqueue := func(interface{}) { fcc.queue.Add(fcc.key) }
+	listWatcher := cache.NewListWatchFromClient(k8sClientset.CoreV1().RESTClient(), "configmaps", cfg.Namespace, fields.OneTermEqualSelector("metadata.name", cfg.Name))
+	fcc.indexer, fcc.informer = cache.NewIndexerInformer(listWatcher, &v1.ConfigMap{}, cfg.ReconcilerPeriod, cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			enqueue(newObj)
+		},
+		DeleteFunc: enqueue,
+	}, cache.Indexers{})
+
+	return fcc
+}
+
+// Run starts the controller.
+func (c *felixConfigController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	log.WithField("configMap", c.key).Info("Starting FelixConfiguration ConfigMap controller")
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.k8sClientset.CoreV1().Events("")})
+	defer broadcaster.Shutdown()
+	c.recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "calico-kube-controllers"})
+
+	go c.informer.Run(stopCh)
+	if !cache.WaitForNamedCacheSync("configmaps", stopCh, c.informer.HasSynced) {
+		log.Info("Failed to sync resources, received signal for controller to shut down.")
+		return
+	}
+
+	// Sync once at start of day, in case the ConfigMap was deleted while we weren't running.
+	c.queue.Add(c.key)
+	go wait.Until(c.runWorker, time.Second, stopCh)
+	log.Info("FelixConfiguration ConfigMap controller is now running")
+
+	<-stopCh
+	log.Info("Stopping FelixConfiguration ConfigMap controller")
+}
+
+func (c *felixConfigController) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+// processNextItem waits for the ConfigMap to be queued and syncs it.
+func (c *felixConfigController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	done := controller.WorkerWatchdog.Begin("felixconfig")
+	ctx, cancel := controller.WithSyncTimeout(c.ctx, c.cfg.SyncTimeout)
+	err := c.sync(ctx)
+	cancel()
+	done()
+
+	if err == nil {
+		c.queue.Forget(key)
+		return true
+	}
+	controller.RecordSyncError("felixconfig", err)
+	if !controller.IsPermanentError(err) && c.queue.NumRequeues(key) < 5 {
+		log.WithError(err).Errorf("Error syncing ConfigMap %v: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	uruntime.HandleError(err)
+	log.WithError(err).Errorf("Dropping ConfigMap %q out of the queue: %v", key, err)
+	return true
+}
+
+// sync applies the settings in the ConfigMap to the default FelixConfiguration. If the ConfigMap
+// doesn't exist, the settings it applied before are reset.
+func (c *felixConfigController) sync(ctx context.Context) error {
+	clog := log.WithField("configMap", c.key)
+	data := map[string]string{}
+	obj, exists, err := c.indexer.GetByKey(c.key)
+	if err != nil {
+		return err
+	}
+	var cm *v1.ConfigMap
+	if exists {
+		cm = obj.(*v1.ConfigMap)
+		data = cm.Data
+	}
+
+	start := time.Now()
+	fc, err := c.calicoClient.FelixConfigurations().Get(ctx, DefaultName, options.GetOptions{})
+	controller.ObserveDatastoreOp(ctx, "felixconfig", controller.DatastoreOpGet, start, err)
+	create := false
+	if err != nil {
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			return err
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		fc = api.NewFelixConfiguration()
+		fc.Name = DefaultName
+		create = true
+	}
+
+	desired, err := Desired(fc, data)
+	if err == nil && equality.Semantic.DeepEqual(fc.Spec, desired.Spec) &&
+		fc.Annotations[AppliedKeysAnnotation] == desired.Annotations[AppliedKeysAnnotation] {
+		return nil
+	}
+	if err == nil {
+		start = time.Now()
+		if create {
+			clog.Info("Creating default FelixConfiguration from ConfigMap")
+			_, err = c.calicoClient.FelixConfigurations().Create(ctx, desired, options.SetOptions{})
+			controller.ObserveDatastoreOp(ctx, "felixconfig", controller.DatastoreOpCreate, start, err)
+		} else {
+			clog.Info("Updating default FelixConfiguration from ConfigMap")
+			_, err = c.calicoClient.FelixConfigurations().Update(ctx, desired, options.SetOptions{})
+			controller.ObserveDatastoreOp(ctx, "felixconfig", controller.DatastoreOpUpdate, start, err)
+		}
+	}
+	if err != nil && controller.IsPermanentError(err) && cm != nil {
+		clog.WithError(err).Warning("Invalid FelixConfiguration settings in ConfigMap")
+		c.recorder.Event(cm, v1.EventTypeWarning, "CalicoFelixConfigurationInvalid", err.Error())
+	}
+	return err
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package felixconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+const (
+	// DefaultName is the name of the cluster-wide FelixConfiguration that the ConfigMap's
+	// settings are applied to.
+	DefaultName = "default"
+
+	// AppliedKeysAnnotation records the ConfigMap keys most recently applied to the default
+	// FelixConfiguration, so that settings removed from the ConfigMap can be reset without
+	// touching settings that were made in other ways.
+	AppliedKeysAnnotation = "projectcalico.org/config-map-keys"
+)
+
+// Desired returns a copy of the given FelixConfiguration with the settings in the ConfigMap data
+// applied, and any settings applied from earlier versions of the data, but since removed from
+// it, reset to their defaults. The keys of the data are the field names of the spec, as in the
+// resource's YAML, such as "logSeverityScreen", and the values are as they would be written in
+// YAML, such as "Info", "true" or "10s".
+func Desired(fc *api.FelixConfiguration, data map[string]string) (*api.FelixConfiguration, error) {
+	fields := map[string]json.RawMessage{}
+	b, err := json.Marshal(fc.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	for _, k := range appliedKeys(fc) {
+		if _, ok := data[k]; !ok {
+			delete(fields, k)
+		}
+	}
+	keys := make([]string, 0, len(data))
+	for k, v := range data {
+		raw, err := settingValue(k, v)
+		if err != nil {
+			return nil, err
+		}
+		fields[k] = raw
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	desired := fc.DeepCopy()
+	desired.Spec = api.FelixConfigurationSpec{}
+	if b, err = json.Marshal(fields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &desired.Spec); err != nil {
+		return nil, err
+	}
+
+	if len(keys) == 0 {
+		delete(desired.Annotations, AppliedKeysAnnotation)
+		return desired, nil
+	}
+	if desired.Annotations == nil {
+		desired.Annotations = map[string]string{}
+	}
+	desired.Annotations[AppliedKeysAnnotation] = strings.Join(keys, ",")
+	return desired, nil
+}
+
+// appliedKeys returns the ConfigMap keys recorded on the FelixConfiguration.
+func appliedKeys(fc *api.FelixConfiguration) []string {
+	v := fc.Annotations[AppliedKeysAnnotation]
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ",")
+}
+
+// settingValue returns the JSON value of the given setting. Values are used as JSON if the field
+// accepts them that way, so that "true" and "9091" set boolean and numeric fields, and as strings
+// otherwise.
+func settingValue(key, value string) (json.RawMessage, error) {
+	var candidates []json.RawMessage
+	if json.Valid([]byte(value)) {
+		candidates = append(candidates, json.RawMessage(value))
+	}
+	quoted, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	candidates = append(candidates, quoted)
+
+	reason := "not a valid value for the setting"
+	for _, c := range candidates {
+		doc, err := json.Marshal(map[string]json.RawMessage{key: c})
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(doc))
+		dec.DisallowUnknownFields()
+		var spec api.FelixConfigurationSpec
+		if err := dec.Decode(&spec); err == nil {
+			return c, nil
+		} else if strings.Contains(err.Error(), "unknown field") {
+			reason = "not a FelixConfiguration setting"
+			break
+		}
+	}
+	return nil, cerrors.ErrorValidation{
+		ErroredFields: []cerrors.ErroredField{{Name: key, Value: value, Reason: reason}},
+	}
+}
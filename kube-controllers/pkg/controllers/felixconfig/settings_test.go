@@ -0,0 +1,91 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package felixconfig_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/felixconfig"
+)
+
+var _ = Describe("FelixConfiguration ConfigMap settings", func() {
+	var fc *api.FelixConfiguration
+
+	BeforeEach(func() {
+		fc = api.NewFelixConfiguration()
+		fc.Name = felixconfig.DefaultName
+		fc.Spec.LogSeverityScreen = "Warning"
+	})
+
+	It("should apply settings of each type", func() {
+		desired, err := felixconfig.Desired(fc, map[string]string{
+			"bpfEnabled":               "true",
+			"prometheusMetricsPort":    "9095",
+			"iptablesRefreshInterval":  "30s",
+			"bpfLogLevel":              "Debug",
+			"failsafeInboundHostPorts": `[{"protocol":"TCP","port":22}]`,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		enabled := true
+		port := 9095
+		Expect(desired.Spec.BPFEnabled).To(Equal(&enabled))
+		Expect(desired.Spec.PrometheusMetricsPort).To(Equal(&port))
+		Expect(desired.Spec.IptablesRefreshInterval).To(Equal(&metav1.Duration{Duration: 30 * time.Second}))
+		Expect(desired.Spec.BPFLogLevel).To(Equal("Debug"))
+		Expect(*desired.Spec.FailsafeInboundHostPorts).To(HaveLen(1))
+		Expect(desired.Spec.LogSeverityScreen).To(Equal("Warning"))
+		Expect(desired.Annotations).To(HaveKeyWithValue(felixconfig.AppliedKeysAnnotation,
+			"bpfEnabled,bpfLogLevel,failsafeInboundHostPorts,iptablesRefreshInterval,prometheusMetricsPort"))
+
+		// The original isn't modified.
+		Expect(fc.Spec.BPFEnabled).To(BeNil())
+	})
+
+	It("should only reset the settings removed from the ConfigMap", func() {
+		desired, err := felixconfig.Desired(fc, map[string]string{"bpfEnabled": "true", "logSeverityFile": "Info"})
+		Expect(err).NotTo(HaveOccurred())
+
+		desired, err = felixconfig.Desired(desired, map[string]string{"logSeverityFile": "Debug"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(desired.Spec.BPFEnabled).To(BeNil())
+		Expect(desired.Spec.LogSeverityFile).To(Equal("Debug"))
+		Expect(desired.Spec.LogSeverityScreen).To(Equal("Warning"))
+
+		desired, err = felixconfig.Desired(desired, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(desired.Spec).To(Equal(fc.Spec))
+		Expect(desired.Annotations).NotTo(HaveKey(felixconfig.AppliedKeysAnnotation))
+	})
+
+	It("should reject unknown settings and invalid values", func() {
+		for _, data := range []map[string]string{
+			{"notASetting": "true"},
+			{"bpfEnabled": "maybe"},
+			{"prometheusMetricsPort": "[1]"},
+		} {
+			_, err := felixconfig.Desired(fc, data)
+			Expect(err).To(HaveOccurred(), "%v", data)
+			Expect(controller.IsPermanentError(err)).To(BeTrue())
+		}
+	})
+})
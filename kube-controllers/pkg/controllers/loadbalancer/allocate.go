@@ -0,0 +1,184 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+// Manages returns true if the service's addresses are allocated by the controller. The controller
+// handles LoadBalancer services that don't name a load balancer class of their own.
+func Manages(svc *v1.Service) bool {
+	return svc.Spec.Type == v1.ServiceTypeLoadBalancer && svc.Spec.LoadBalancerClass == nil
+}
+
+// Pools returns the pools that are reserved for LoadBalancer services, ordered by name.
+func Pools(pools []api.IPPool) []api.IPPool {
+	var lb []api.IPPool
+	for _, p := range pools {
+		if len(p.Spec.AllowedUses) == 1 && p.Spec.AllowedUses[0] == api.IPPoolAllowedUseLoadBalancer {
+			lb = append(lb, p)
+		}
+	}
+	sort.Slice(lb, func(i, j int) bool { return lb[i].Name < lb[j].Name })
+	return lb
+}
+
+// IngressIPs returns the IPs in the service's status.
+func IngressIPs(svc *v1.Service) []string {
+	var ips []string
+	for _, ing := range svc.Status.LoadBalancer.Ingress {
+		if ing.IP != "" {
+			ips = append(ips, ing.IP)
+		}
+	}
+	return ips
+}
+
+// InPools returns the IPs that are in any of the given pools.
+func InPools(ips []string, pools []api.IPPool) []string {
+	var in []string
+	for _, ip := range ips {
+		if _, ok := poolFor(ip, pools); ok {
+			in = append(in, ip)
+		}
+	}
+	return in
+}
+
+// Allocate returns the ingress IPs of the given service: one per IP family of the service. IPs
+// that the service already has are kept as long as they are still in one of the pools, even if
+// it has been disabled. Otherwise the service's spec.loadBalancerIP is used if given, or the
+// first free IP in the enabled pools. used maps the IPs of the other services to their names.
+func Allocate(svc *v1.Service, used map[string]string, pools []api.IPPool) ([]string, error) {
+	families := svc.Spec.IPFamilies
+	if len(families) == 0 {
+		families = []v1.IPFamily{v1.IPv4Protocol}
+	}
+
+	var ips []string
+	for _, family := range families {
+		version := 4
+		if family == v1.IPv6Protocol {
+			version = 6
+		}
+
+		ip, ok := existingIP(svc, version, used, pools)
+		if !ok {
+			var err error
+			if svc.Spec.LoadBalancerIP != "" {
+				ip, err = requestedIP(svc.Spec.LoadBalancerIP, version, used, pools)
+			} else {
+				ip, err = freeIP(version, used, pools)
+			}
+			if err != nil {
+				return ips, err
+			}
+			if ip == "" {
+				// The requested IP is of the other family.
+				continue
+			}
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// existingIP returns the IP of the given version that the service already has, if it can keep it.
+func existingIP(svc *v1.Service, version int, used map[string]string, pools []api.IPPool) (string, bool) {
+	requested := cnet.ParseIP(svc.Spec.LoadBalancerIP)
+	for _, ip := range IngressIPs(svc) {
+		parsed := cnet.ParseIP(ip)
+		if parsed == nil || parsed.Version() != version {
+			continue
+		}
+		if _, taken := used[parsed.String()]; taken {
+			continue
+		}
+		if requested != nil && requested.Version() == version && requested.String() != parsed.String() {
+			// The requested IP has changed.
+			continue
+		}
+		if _, ok := poolFor(ip, pools); ok {
+			return parsed.String(), true
+		}
+	}
+	return "", false
+}
+
+// requestedIP returns the IP requested by the service, if it is of the given version, or an error
+// if it can't be assigned.
+func requestedIP(requested string, version int, used map[string]string, pools []api.IPPool) (string, error) {
+	ip := cnet.ParseIP(requested)
+	if ip == nil {
+		return "", fmt.Errorf("requested IP %q is not a valid IP", requested)
+	}
+	if ip.Version() != version {
+		return "", nil
+	}
+	if p, ok := poolFor(requested, pools); !ok || p.Spec.Disabled {
+		return "", fmt.Errorf("requested IP %s is not in an enabled LoadBalancer IP pool", requested)
+	}
+	if owner, taken := used[ip.String()]; taken {
+		return "", fmt.Errorf("requested IP %s is already used by service %s", requested, owner)
+	}
+	return ip.String(), nil
+}
+
+// freeIP returns the first IP of the given version in the enabled pools that isn't used. The
+// network and broadcast addresses of IPv4 pools are skipped.
+func freeIP(version int, used map[string]string, pools []api.IPPool) (string, error) {
+	for _, p := range pools {
+		_, cidr, err := cnet.ParseCIDR(p.Spec.CIDR)
+		if p.Spec.Disabled || err != nil || cidr.Version() != version {
+			continue
+		}
+
+		first := cnet.IPToBigInt(cnet.IP{IP: cidr.IP})
+		end := big.NewInt(0).Add(first, cidr.NumAddrs())
+		if ones, bits := cidr.Mask.Size(); version == 4 && bits-ones > 1 {
+			first.Add(first, big.NewInt(1))
+			end.Sub(end, big.NewInt(1))
+		}
+		for i := first; i.Cmp(end) < 0; i.Add(i, big.NewInt(1)) {
+			ip := cnet.BigIntToIP(i, version == 6).String()
+			if _, taken := used[ip]; !taken {
+				return ip, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no free IPv%d address in the LoadBalancer IP pools", version)
+}
+
+// poolFor returns the pool containing the given IP.
+func poolFor(ip string, pools []api.IPPool) (api.IPPool, bool) {
+	parsed := cnet.ParseIP(ip)
+	if parsed == nil {
+		return api.IPPool{}, false
+	}
+	for _, p := range pools {
+		if _, cidr, err := cnet.ParseCIDR(p.Spec.CIDR); err == nil && cidr.Contains(parsed.IP) {
+			return p, true
+		}
+	}
+	return api.IPPool{}, false
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/loadbalancer"
+)
+
+func lbPool(name, cidr string, disabled bool) api.IPPool {
+	return api.IPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: api.IPPoolSpec{
+			CIDR:        cidr,
+			Disabled:    disabled,
+			AllowedUses: []api.IPPoolAllowedUse{api.IPPoolAllowedUseLoadBalancer},
+		},
+	}
+}
+
+func service(ingress ...string) *v1.Service {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec:       v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+	}
+	for _, ip := range ingress {
+		svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, v1.LoadBalancerIngress{IP: ip})
+	}
+	return svc
+}
+
+var _ = Describe("LoadBalancer IP allocation", func() {
+	var pools []api.IPPool
+
+	BeforeEach(func() {
+		pools = loadbalancer.Pools([]api.IPPool{
+			lbPool("lb-b", "192.168.10.0/30", false),
+			lbPool("lb-a", "192.168.20.0/31", false),
+			lbPool("lb-v6", "fd00:10::/126", false),
+			lbPool("lb-old", "192.168.30.0/24", true),
+			{ObjectMeta: metav1.ObjectMeta{Name: "pods"}, Spec: api.IPPoolSpec{CIDR: "10.0.0.0/16"}},
+		})
+	})
+
+	It("should only use pools reserved for LoadBalancer services, by name", func() {
+		var names []string
+		for _, p := range pools {
+			names = append(names, p.Name)
+		}
+		Expect(names).To(Equal([]string{"lb-a", "lb-b", "lb-old", "lb-v6"}))
+	})
+
+	It("should only manage LoadBalancer services without a class", func() {
+		svc := service()
+		Expect(loadbalancer.Manages(svc)).To(BeTrue())
+		class := "example.com/lb"
+		svc.Spec.LoadBalancerClass = &class
+		Expect(loadbalancer.Manages(svc)).To(BeFalse())
+		Expect(loadbalancer.Manages(&v1.Service{Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP}})).To(BeFalse())
+	})
+
+	It("should allocate the first free IP", func() {
+		ips, err := loadbalancer.Allocate(service(), map[string]string{"192.168.20.0": "default/other"}, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ips).To(Equal([]string{"192.168.20.1"}))
+	})
+
+	It("should skip the network and broadcast addresses of IPv4 pools", func() {
+		used := map[string]string{"192.168.20.0": "a", "192.168.20.1": "b"}
+		ips, err := loadbalancer.Allocate(service(), used, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ips).To(Equal([]string{"192.168.10.1"}))
+
+		used["192.168.10.1"] = "c"
+		used["192.168.10.2"] = "d"
+		_, err = loadbalancer.Allocate(service(), used, pools)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should allocate an IP for each family of the service", func() {
+		svc := service()
+		svc.Spec.IPFamilies = []v1.IPFamily{v1.IPv6Protocol, v1.IPv4Protocol}
+		ips, err := loadbalancer.Allocate(svc, nil, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ips).To(Equal([]string{"fd00:10::", "192.168.20.0"}))
+	})
+
+	It("should keep existing IPs, even in disabled pools", func() {
+		ips, err := loadbalancer.Allocate(service("192.168.30.7"), nil, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ips).To(Equal([]string{"192.168.30.7"}))
+	})
+
+	It("should replace IPs outside of the pools or used by other services", func() {
+		ips, err := loadbalancer.Allocate(service("10.0.0.1"), nil, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ips).To(Equal([]string{"192.168.20.0"}))
+
+		ips, err = loadbalancer.Allocate(service("192.168.10.1"), map[string]string{"192.168.10.1": "default/other"}, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ips).To(Equal([]string{"192.168.20.0"}))
+	})
+
+	It("should assign the requested IP", func() {
+		svc := service("192.168.20.0")
+		svc.Spec.LoadBalancerIP = "192.168.10.2"
+		ips, err := loadbalancer.Allocate(svc, nil, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ips).To(Equal([]string{"192.168.10.2"}))
+	})
+
+	It("should reject requested IPs that can't be assigned", func() {
+		for _, ip := range []string{"bad", "10.0.0.1", "192.168.30.1", "192.168.10.2"} {
+			svc := service()
+			svc.Spec.LoadBalancerIP = ip
+			_, err := loadbalancer.Allocate(svc, map[string]string{"192.168.10.2": "default/other"}, pools)
+			Expect(err).To(HaveOccurred(), ip)
+		}
+	})
+
+	It("should find the IPs in the pools", func() {
+		Expect(loadbalancer.InPools([]string{"192.168.10.1", "10.0.0.1", "fd00:10::3"}, pools)).To(Equal([]string{"192.168.10.1", "fd00:10::3"}))
+	})
+})
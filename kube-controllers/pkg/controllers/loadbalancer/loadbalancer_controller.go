@@ -0,0 +1,278 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancer
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// loadBalancerController implements the Controller interface for allocating the addresses of
+// LoadBalancer services from the IP pools reserved for them, and writing them to the services'
+// status. The services' status is the record of which addresses are in use, so nothing else is
+// stored in the datastore.
+type loadBalancerController struct {
+	informer     cache.Controller
+	indexer      cache.Indexer
+	queue        workqueue.RateLimitingInterface
+	k8sClientset *kubernetes.Clientset
+	calicoClient client.Interface
+	ctx          context.Context
+	cfg          config.GenericControllerConfig
+	recorder     record.EventRecorder
+
+	// The IPs most recently written to the status of each service, by service key, until the
+	// informer catches up with them. Used so that an IP is never handed out twice.
+	lock        sync.Mutex
+	allocations map[string][]string
+}
+
+// NewLoadBalancerController returns a controller which allocates the addresses of LoadBalancer
+// services.
+func NewLoadBalancerController(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, cfg config.GenericControllerConfig) controller.Controller {
+	lbc := &loadBalancerController{
+		queue:        workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		k8sClientset: k8sClientset,
+		calicoClient: c,
+		ctx:          ctx,
+		cfg:          cfg,
+		allocations:  map[string][]string{},
+	}
+
+	// Only services that are, or were, LoadBalancers can have addresses to sync.
+	enqueue := func(obj interface{}) {
+		svc, ok := obj.(*v1.Service)
+		if !ok || (svc.Spec.Type != v1.ServiceTypeLoadBalancer && len(IngressIPs(svc)) == 0) {
+			return
+		}
+		if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+			lbc.queue.Add(key)
+		}
+	}
+	listWatcher := cache.NewListWatchFromClient(k8sClientset.CoreV1().RESTClient(), "services", "", fields.Everything())
+	lbc.indexer, lbc.informer = cache.NewIndexerInformer(listWatcher, &v1.Service{}, cfg.ReconcilerPeriod, cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			enqueue(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			if err != nil {
+				return
+			}
+			lbc.lock.Lock()
+			delete(lbc.allocations, key)
+			lbc.lock.Unlock()
+
+			// The service's addresses are free again, so retry any services waiting for one.
+			for _, obj := range lbc.indexer.List() {
+				if svc := obj.(*v1.Service); Manages(svc) && len(IngressIPs(svc)) == 0 {
+					enqueue(svc)
+				}
+			}
+		},
+	}, cache.Indexers{})
+
+	return lbc
+}
+
+// Run starts the controller.
+func (c *loadBalancerController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	log.Info("Starting LoadBalancer IP allocation controller")
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.k8sClientset.CoreV1().Events("")})
+	defer broadcaster.Shutdown()
+	c.recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "calico-kube-controllers"})
+
+	go c.informer.Run(stopCh)
+	if !cache.WaitForNamedCacheSync("services", stopCh, c.informer.HasSynced) {
+		log.Info("Failed to sync resources, received signal for controller to shut down.")
+		return
+	}
+
+	// Allocations depend on all the other services' addresses, so are made one at a time.
+	go wait.Until(c.runWorker, time.Second, stopCh)
+	log.Info("LoadBalancer IP allocation controller is now running")
+
+	<-stopCh
+	log.Info("Stopping LoadBalancer IP allocation controller")
+}
+
+func (c *loadBalancerController) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+// processNextItem waits for a service on the queue and syncs its addresses.
+func (c *loadBalancerController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	done := controller.WorkerWatchdog.Begin("loadbalancer")
+	ctx, cancel := controller.WithSyncTimeout(c.ctx, c.cfg.SyncTimeout)
+	err := c.sync(ctx, key.(string))
+	cancel()
+	done()
+
+	if err == nil {
+		c.queue.Forget(key)
+		return true
+	}
+	controller.RecordSyncError("loadbalancer", err)
+	if c.queue.NumRequeues(key) < 5 {
+		log.WithError(err).Errorf("Error syncing addresses of service %v: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	uruntime.HandleError(err)
+	log.WithError(err).Errorf("Dropping service %q out of the queue: %v", key, err)
+	return true
+}
+
+// sync allocates the addresses of the given service, and writes them to its status. Services
+// that are no longer handled by the controller have any addresses it allocated removed.
+func (c *loadBalancerController) sync(ctx context.Context, key string) error {
+	clog := log.WithField("service", key)
+	obj, exists, err := c.indexer.GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		c.lock.Lock()
+		delete(c.allocations, key)
+		c.lock.Unlock()
+		return nil
+	}
+	svc := c.latest(key, obj.(*v1.Service))
+
+	start := time.Now()
+	list, err := c.calicoClient.IPPools().List(ctx, options.ListOptions{})
+	controller.ObserveDatastoreOp(ctx, "loadbalancer", controller.DatastoreOpList, start, err)
+	if err != nil {
+		return err
+	}
+	pools := Pools(list.Items)
+
+	current := IngressIPs(svc)
+	var ingress []v1.LoadBalancerIngress
+	var allocErr error
+	if Manages(svc) {
+		var ips []string
+		ips, allocErr = Allocate(svc, c.usedIPs(key), pools)
+		if allocErr != nil {
+			clog.WithError(allocErr).Warning("Failed to allocate LoadBalancer IP for service")
+			c.recorder.Event(svc, v1.EventTypeWarning, "CalicoLoadBalancerIPAllocationFailed", allocErr.Error())
+		}
+		for _, ip := range ips {
+			ingress = append(ingress, v1.LoadBalancerIngress{IP: ip})
+		}
+	} else {
+		if len(InPools(current, pools)) == 0 {
+			return nil
+		}
+		// Remove just the addresses allocated by the controller.
+		for _, ing := range svc.Status.LoadBalancer.Ingress {
+			if len(InPools([]string{ing.IP}, pools)) == 0 {
+				ingress = append(ingress, ing)
+			}
+		}
+	}
+
+	if reflect.DeepEqual(ingressIPs(ingress), current) && len(ingress) == len(svc.Status.LoadBalancer.Ingress) {
+		return allocErr
+	}
+
+	clog.WithFields(log.Fields{"old": current, "new": ingressIPs(ingress)}).Info("Updating LoadBalancer IPs of service")
+	svc.Status.LoadBalancer.Ingress = ingress
+	_, err = c.k8sClientset.CoreV1().Services(svc.Namespace).UpdateStatus(ctx, svc, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	c.lock.Lock()
+	c.allocations[key] = ingressIPs(ingress)
+	c.lock.Unlock()
+	return allocErr
+}
+
+// latest returns a copy of the service with the addresses most recently written to its status,
+// if the informer hasn't caught up with them yet.
+func (c *loadBalancerController) latest(key string, svc *v1.Service) *v1.Service {
+	svc = svc.DeepCopy()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if ips, ok := c.allocations[key]; ok {
+		if reflect.DeepEqual(IngressIPs(svc), ips) {
+			delete(c.allocations, key)
+		} else {
+			svc.Status.LoadBalancer.Ingress = nil
+			for _, ip := range ips {
+				svc.Status.LoadBalancer.Ingress = append(svc.Status.LoadBalancer.Ingress, v1.LoadBalancerIngress{IP: ip})
+			}
+		}
+	}
+	return svc
+}
+
+// usedIPs returns the IPs used by all services other than the given one, mapped to the services
+// using them.
+func (c *loadBalancerController) usedIPs(except string) map[string]string {
+	used := map[string]string{}
+	for _, obj := range c.indexer.List() {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil || key == except {
+			continue
+		}
+		for _, ip := range IngressIPs(c.latest(key, obj.(*v1.Service))) {
+			if parsed := cnet.ParseIP(ip); parsed != nil {
+				used[parsed.String()] = key
+			}
+		}
+	}
+	return used
+}
+
+func ingressIPs(ingress []v1.LoadBalancerIngress) []string {
+	return IngressIPs(&v1.Service{Status: v1.ServiceStatus{LoadBalancer: v1.LoadBalancerStatus{Ingress: ingress}}})
+}
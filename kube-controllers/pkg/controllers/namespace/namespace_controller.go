@@ -16,8 +16,10 @@ package namespace
 
 import (
 	"context"
-	"reflect"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -27,53 +29,100 @@ import (
 	"github.com/projectcalico/calico/kube-controllers/pkg/config"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
 	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	"github.com/projectcalico/calico/kube-controllers/pkg/dryrun"
+	"github.com/projectcalico/calico/kube-controllers/pkg/tracing"
 	kdd "github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
 
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	uruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
 
+// SkipProfileAnnotation opts a namespace out of having a Profile generated for it, when set to
+// "true", for namespaces whose connectivity is managed by other tooling. The controller neither
+// creates, updates nor deletes the Profile of such a namespace, so an existing Profile is left in
+// place.
+const SkipProfileAnnotation = "projectcalico.org/skip-profile"
+
 // namespaceController implements the Controller interface for managing Kubernetes namespaces
 // and syncing them to the Calico datastore as Profiles.
 type namespaceController struct {
 	informer      cache.Controller
-	resourceCache rcache.ResourceCache
+	resourceCache rcache.ResourceCache[api.Profile]
 	calicoClient  client.Interface
 	ctx           context.Context
 	cfg           config.GenericControllerConfig
+	planner       *dryrun.Planner
+	deleteLimiter *controller.DeleteLimiter
+
+	// Syncs keys from the resource cache, limiting retries of failed syncs to a fraction of all syncs.
+	worker *controller.Worker
 }
 
 // NewNamespaceController returns a controller which manages Namespace objects.
 func NewNamespaceController(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, cfg config.GenericControllerConfig) controller.Controller {
-	namespaceConverter := converter.NewNamespaceConverter()
+	namespaceConverter := converter.NewNamespaceConverter(
+		converter.WithLabelDenyList(cfg.LabelDenyList),
+		converter.WithAnnotationLabels(cfg.AnnotationLabels, cfg.AnnotationLabelPrefix),
+		converter.WithDefaultDeny(cfg.DefaultDeny),
+	)
+	registry := converter.NewRegistry()
+	registry.MustRegister(converter.NamespaceGVK, namespaceConverter)
+	nsFilter := controller.NewNamespaceFilter(cfg.ExcludeNamespaces)
+
+	// Only manage the selected namespaces. The selectors are validated when loading the config.
+	selectors, err := controller.ParseSelectors(cfg.LabelSelector, cfg.FieldSelector)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid namespace selector")
+	}
+
+	// The namespaces that opt out of having a profile, so that the reconciler leaves their
+	// profiles alone.
+	optOuts := newOptOuts()
 
 	// Function returns map of profile_name:object stored by policy controller
 	// in the Calico datastore. Identifies controller written objects by
-	// their naming convention.
-	listFunc := func() (map[string]interface{}, error) {
+	// their ownership labels.
+	listFunc := func() (map[string]api.Profile, error) {
 		log.Debugf("Listing profiles from Calico datastore")
-		filteredProfiles := make(map[string]interface{})
+		filteredProfiles := make(map[string]api.Profile)
 
 		// Get all profile objects from Calico datastore.
+		start := time.Now()
 		profileList, err := c.Profiles().List(ctx, options.ListOptions{})
+		controller.ObserveDatastoreOp(ctx, "namespace", controller.DatastoreOpList, start, err)
 		if err != nil {
 			return nil, err
 		}
 
 		// Filter out only objects that are written by policy controller.
 		for _, profile := range profileList.Items {
-			if strings.HasPrefix(profile.Name, kdd.NamespaceProfileNamePrefix) {
-				// Update the profile's ObjectMeta so that it simply contains the name.
+			if nsFilter.Excluded(strings.TrimPrefix(profile.Name, kdd.NamespaceProfileNamePrefix)) {
+				// Leave the profiles of excluded namespaces alone.
+				continue
+			}
+			if !selectors.Empty() && !selectors.Matches(ProfileNamespaceLabels(profile), fields.Set{"metadata.name": strings.TrimPrefix(profile.Name, kdd.NamespaceProfileNamePrefix)}) {
+				// The namespace isn't selected, so its profile may be managed by another instance.
+				// Only its name and labels are known here, so namespaces selected by any other
+				// field are never matched, and so never cleaned up by the reconciler.
+				continue
+			}
+			if optOuts.Skips(strings.TrimPrefix(profile.Name, kdd.NamespaceProfileNamePrefix)) {
+				continue
+			}
+			if converter.IsManagedOrLegacy(profile.ObjectMeta, "Namespace", kdd.NamespaceProfileNamePrefix) {
+				// Update the profile's ObjectMeta so that it simply contains the name and ownership metadata.
 				// There is other metadata that we might receive (like resource version) that we don't want to
 				// compare in the cache.
-				profile.ObjectMeta = metav1.ObjectMeta{Name: profile.Name}
+				profile.ObjectMeta = converter.ManagedMetadata(profile.ObjectMeta)
 				key := namespaceConverter.GetKey(profile)
 				filteredProfiles[key] = profile
 			}
@@ -82,33 +131,107 @@ func NewNamespaceController(ctx context.Context, k8sClientset *kubernetes.Client
 		return filteredProfiles, nil
 	}
 
+	// Functions used to spot check the cache, reading directly from the Kubernetes API and the
+	// Calico datastore.
+	sourceGetFunc := func(key string) (api.Profile, bool, error) {
+		name := strings.TrimPrefix(key, kdd.NamespaceProfileNamePrefix)
+		ns, err := k8sClientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return api.Profile{}, false, nil
+		} else if err != nil {
+			return api.Profile{}, false, err
+		}
+		if !selectors.Matches(labels.Set(ns.Labels), fields.Set{"metadata.name": ns.Name, "status.phase": string(ns.Status.Phase)}) || skipProfile(ns) {
+			return api.Profile{}, false, nil
+		}
+		profile, err := converter.ConvertObject[api.Profile](registry, ns)
+		return profile, err == nil, err
+	}
+	datastoreGetFunc := func(key string) (api.Profile, bool, error) {
+		profile, err := c.Profiles().Get(ctx, key, options.GetOptions{})
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+			return api.Profile{}, false, nil
+		} else if err != nil {
+			return api.Profile{}, false, err
+		}
+		if !converter.IsManagedOrLegacy(profile.ObjectMeta, "Namespace", kdd.NamespaceProfileNamePrefix) {
+			return api.Profile{}, false, nil
+		}
+		profile.ObjectMeta = converter.ManagedMetadata(profile.ObjectMeta)
+		return *profile, true, nil
+	}
+
 	// Create a Cache to store Profiles in.
-	cacheArgs := rcache.ResourceCacheArgs{
-		ListFunc:    listFunc,
-		ObjectType:  reflect.TypeOf(api.Profile{}),
-		LogTypeDesc: "Namespace",
+	cacheArgs := rcache.ResourceCacheArgs[api.Profile]{
+		ControllerName:   "namespace",
+		Shards:           cfg.NumberOfWorkers,
+		MaxQueueDepth:    cfg.MaxQueueDepth,
+		CoalesceWindow:   cfg.CoalesceWindow,
+		ListFunc:         listFunc,
+		LogTypeDesc:      "Namespace",
+		SourceGetFunc:    sourceGetFunc,
+		DatastoreGetFunc: datastoreGetFunc,
+
+		// Remove stale resources before writing their replacements, so that the two are never
+		// in effect at the same time.
+		PrioritizeDeletes: true,
+
+		ReconcilerConfig: rcache.ReconcilerConfig{
+			MaxReconcilerPeriod: cfg.MaxReconcilerPeriod,
+			ReconcilerJitter:    cfg.ReconcilerJitter,
+			ReconcileAtStartup:  true,
+			SpotCheckPeriod:     cfg.SpotCheckPeriod,
+			SpotCheckSampleSize: cfg.SpotCheckSampleSize,
+			ResyncWindow:        cfg.ResyncWindow,
+			ResyncThreshold:     cfg.ResyncThreshold,
+			MaxSnapshotAge:      cfg.MaxSnapshotAge,
+			MassDeleteThreshold: cfg.MassDeleteThreshold,
+		},
+	}
+	if cfg.SnapshotDir != "" {
+		cacheArgs.SnapshotStore = rcache.NewFileSnapshotStore(filepath.Join(cfg.SnapshotDir, "namespace.json"))
 	}
 	ccache := rcache.NewResourceCache(cacheArgs)
+	conversionErrors := controller.NewConversionErrors("Namespace")
 
 	// Create a Namespace watcher.
-	listWatcher := cache.NewListWatchFromClient(k8sClientset.CoreV1().RESTClient(), "namespaces", "", fields.Everything())
+	listWatcher := nsFilter.ListWatch(controller.PagedListWatch(selectors.ListWatch(k8sClientset.CoreV1().RESTClient(), "namespaces"), cfg.ListPageSize))
 
 	// Bind the calico cache to kubernetes cache with the help of an informer. This way we make sure that
 	// whenever the kubernetes cache is updated, changes get reflected in the Calico cache as well.
 	_, informer := cache.NewIndexerInformer(listWatcher, &v1.Namespace{}, 0, cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
+			spanCtx, span := tracing.StartEvent("namespace", "add", obj)
+			defer span.End()
+			optOuts.Record(obj)
+
 			log.Debugf("Got ADD event for Namespace: %#v", obj)
-			profile, err := namespaceConverter.Convert(obj)
+			_, convertSpan := tracing.Start(spanCtx, "convert")
+			profile, err := converter.ConvertObject[api.Profile](registry, obj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error while converting %#v to calico profile.", obj)
+				conversionErrors.Record(obj, err)
 				return
 			}
+			conversionErrors.Clear(obj)
 
-			// Add to cache.
+			// Add to cache, unless the namespace opts out of having a profile.
 			k := namespaceConverter.GetKey(profile)
+			if skipProfile(obj) {
+				log.WithField("namespace", k).Debug("Namespace opts out of having a profile")
+				ccache.Clean(k)
+				return
+			}
+			_, cacheSpan := tracing.Start(spanCtx, "cache set")
 			ccache.Set(k, profile)
+			cacheSpan.End()
 		},
 		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			spanCtx, span := tracing.StartEvent("namespace", "update", newObj)
+			defer span.End()
+			optOuts.Record(newObj)
+
 			log.Debugf("Got UPDATE event for Namespace")
 			log.Debugf("Old object: \n%#v\n", oldObj)
 			log.Debugf("New object: \n%#v\n", newObj)
@@ -120,31 +243,138 @@ func NewNamespaceController(ctx context.Context, k8sClientset *kubernetes.Client
 			}
 
 			// Convert the namespace into a Profile.
-			profile, err := namespaceConverter.Convert(newObj)
+			_, convertSpan := tracing.Start(spanCtx, "convert")
+			profile, err := converter.ConvertObject[api.Profile](registry, newObj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error while converting %#v to calico profile.", newObj)
+				conversionErrors.Record(newObj, err)
 				return
 			}
+			conversionErrors.Clear(newObj)
 
-			// Update in the cache.
+			// Update in the cache. If the namespace now opts out of having a profile, stop
+			// managing the profile but leave it in place.
 			k := namespaceConverter.GetKey(profile)
+			if skipProfile(newObj) {
+				log.WithField("namespace", k).Debug("Namespace opts out of having a profile")
+				ccache.Clean(k)
+				return
+			}
+			_, cacheSpan := tracing.Start(spanCtx, "cache set")
 			ccache.Set(k, profile)
+			cacheSpan.End()
 		},
 		DeleteFunc: func(obj interface{}) {
+			spanCtx, span := tracing.StartEvent("namespace", "delete", obj)
+			defer span.End()
+
+			// A deleted namespace that opted out is still recorded, so that the reconciler
+			// leaves its profile in place too.
+			optOuts.Record(obj)
+
 			// Convert the namespace into a Profile.
 			log.Debugf("Got DELETE event for namespace: %#v", obj)
-			profile, err := namespaceConverter.Convert(obj)
+			_, convertSpan := tracing.Start(spanCtx, "convert")
+			profile, err := converter.ConvertObject[api.Profile](registry, obj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error converting %#v to Calico profile.", obj)
+				conversionErrors.Record(obj, err)
 				return
 			}
+			conversionErrors.Clear(obj)
 
 			k := namespaceConverter.GetKey(profile)
+			if skipProfile(obj) {
+				// Leave the profile of a namespace that opted out in place.
+				ccache.Clean(k)
+				return
+			}
+			_, cacheSpan := tracing.Start(spanCtx, "cache delete")
 			ccache.Delete(k)
+			cacheSpan.End()
 		},
 	}, cache.Indexers{})
 
-	return &namespaceController{informer, ccache, c, ctx, cfg}
+	var planner *dryrun.Planner
+	if cfg.DryRun {
+		planner = dryrun.NewPlanner("namespace", c.KubeControllersConfiguration())
+	}
+
+	deleteLimiter := controller.NewDeleteLimiter("namespace", cfg.DeleteRateLimit, cfg.DeleteBurst)
+
+	ctrl := &namespaceController{informer, ccache, c, ctx, cfg, planner, deleteLimiter, nil}
+	ctrl.worker = controller.NewWorker("namespace", "Profile", ccache, cfg.SyncTimeout, cfg.RetryBudgetRatio, ctrl.syncToDatastore)
+	return ctrl
+}
+
+// skipProfile returns true if the given namespace, which may be a tombstone, opts out of having a
+// profile.
+func skipProfile(obj interface{}) bool {
+	ns, ok := namespaceFromObj(obj)
+	return ok && ns.Annotations[SkipProfileAnnotation] == "true"
+}
+
+// namespaceFromObj returns the namespace in the given object, which may be a tombstone.
+func namespaceFromObj(obj interface{}) (*v1.Namespace, bool) {
+	ns, ok := obj.(*v1.Namespace)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return nil, false
+		}
+		ns, ok = tombstone.Obj.(*v1.Namespace)
+		return ns, ok
+	}
+	return ns, true
+}
+
+// optOutSet records the names of the namespaces that opt out of having a profile. Unlike the
+// informer's store, it keeps the namespaces that were deleted while opted out, since their
+// profiles are left in place and must not be cleaned up by the reconciler.
+type optOutSet struct {
+	sync.Mutex
+	names map[string]bool
+}
+
+func newOptOuts() *optOutSet {
+	return &optOutSet{names: map[string]bool{}}
+}
+
+// Record records whether the given namespace, which may be a tombstone, opts out. A deleted
+// namespace is recorded in the same way, so one that opted out stays in the set.
+func (o *optOutSet) Record(obj interface{}) {
+	ns, ok := namespaceFromObj(obj)
+	if !ok {
+		return
+	}
+	o.Lock()
+	defer o.Unlock()
+	if skipProfile(ns) {
+		o.names[ns.Name] = true
+	} else {
+		delete(o.names, ns.Name)
+	}
+}
+
+// Skips returns true if the named namespace opts out of having a profile.
+func (o *optOutSet) Skips(name string) bool {
+	o.Lock()
+	defer o.Unlock()
+	return o.names[name]
+}
+
+// ProfileNamespaceLabels returns the labels of the namespace that the given profile was generated
+// from. Labels on the deny list aren't copied to profiles, so they are missing.
+func ProfileNamespaceLabels(profile api.Profile) labels.Set {
+	l := labels.Set{}
+	for k, v := range profile.Spec.LabelsToApply {
+		if strings.HasPrefix(k, kdd.NamespaceLabelPrefix) {
+			l[strings.TrimPrefix(k, kdd.NamespaceLabelPrefix)] = v
+		}
+	}
+	return l
 }
 
 // Run starts the controller.
@@ -169,6 +399,12 @@ func (c *namespaceController) Run(stopCh chan struct{}) {
 	// Start Calico cache.
 	c.resourceCache.Run(c.cfg.ReconcilerPeriod.String())
 
+	// In dry-run mode, report the planned changes once per reconciliation.
+	if c.planner != nil {
+		log.Info("Namespace/Profile controller is running in dry-run mode")
+		go c.planner.Run(c.ctx, c.cfg.ReconcilerPeriod)
+	}
+
 	// Start a number of worker threads to read from the queue.
 	for i := 0; i < c.cfg.NumberOfWorkers; i++ {
 		go c.runWorker()
@@ -179,45 +415,60 @@ func (c *namespaceController) Run(stopCh chan struct{}) {
 	log.Info("Stopping Namespace/Profile controller")
 }
 
-func (c *namespaceController) runWorker() {
-	for c.processNextItem() {
-	}
+// Resync triggers an immediate reconciliation of the Calico datastore.
+func (c *namespaceController) Resync() {
+	c.resourceCache.Resync()
 }
 
-// processNextItem waits for an event on the output queue from the resource cache and syncs
-// any received keys to the datastore.
-func (c *namespaceController) processNextItem() bool {
-	// Wait until there is a new item in the work queue.
-	workqueue := c.resourceCache.GetQueue()
-	key, quit := workqueue.Get()
-	if quit {
-		return false
-	}
+// ConfirmDeletes allows the next reconciliation to make deletes withheld by the mass delete
+// failsafe, and triggers it.
+func (c *namespaceController) ConfirmDeletes() {
+	c.resourceCache.ConfirmDeletes()
+}
 
-	// Sync the object to the Calico datastore.
-	err := c.syncToDatastore(key.(string))
-	c.handleErr(err, key.(string))
+// Dump returns a snapshot of the controller's cache and queue.
+func (c *namespaceController) Dump() rcache.Dump {
+	return c.resourceCache.Dump()
+}
 
-	// Indicate that we're done processing this key, allowing for safe parallel processing such that
-	// two objects with the same key are never processed in parallel.
-	workqueue.Done(key)
-	return true
+func (c *namespaceController) runWorker() {
+	for c.worker.ProcessNextItem(c.ctx) {
+	}
 }
 
 // syncToDatastore syncs the given update to the Calico datastore. The provided key can be used to
 // find the corresponding resource within the resource cache. If the resource for the provided key
 // exists in the cache, then the value should be written to the datastore. If it does not exist
 // in the cache, then it should be deleted from the datastore.
-func (c *namespaceController) syncToDatastore(key string) error {
+func (c *namespaceController) syncToDatastore(ctx context.Context, key string) error {
 	clog := log.WithField("key", key)
 
+	// In dry-run mode, the key is recorded again below if the datastore still differs.
+	if c.planner != nil {
+		c.planner.Resolve(key)
+	}
+
 	// Check if it exists in the controller's cache.
-	obj, exists := c.resourceCache.Get(key)
+	p, exists := c.resourceCache.Get(key)
 	if !exists {
 		// The object no longer exists - delete from the datastore.
-		clog.Infof("Deleting Profile from Calico datastore")
 		_, name := converter.NewNamespaceConverter().DeleteArgsFromKey(key)
-		_, err := c.calicoClient.Profiles().Delete(c.ctx, name, options.DeleteOptions{})
+		if c.planner != nil {
+			_, err := c.calicoClient.Profiles().Get(ctx, name, options.GetOptions{})
+			if err == nil {
+				c.planner.Record(dryrun.OpDelete, key)
+			} else if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+				return err
+			}
+			return nil
+		}
+		if err := c.deleteLimiter.Reserve(key); err != nil {
+			return err
+		}
+		clog.Infof("Deleting Profile from Calico datastore")
+		start := time.Now()
+		_, err := c.calicoClient.Profiles().Delete(ctx, name, options.DeleteOptions{})
+		controller.ObserveDatastoreOp(ctx, "namespace", controller.DatastoreOpDelete, start, err)
 		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
 			// We hit an error other than "does not exist".
 			return err
@@ -226,10 +477,11 @@ func (c *namespaceController) syncToDatastore(key string) error {
 	} else {
 		// The object exists - update the datastore to reflect.
 		clog.Info("Create/Update Profile in Calico datastore")
-		p := obj.(api.Profile)
 
 		// Lookup to see if this object already exists in the datastore.
-		gp, err := c.calicoClient.Profiles().Get(c.ctx, p.Name, options.GetOptions{})
+		start := time.Now()
+		gp, err := c.calicoClient.Profiles().Get(ctx, p.Name, options.GetOptions{})
+		controller.ObserveDatastoreOp(ctx, "namespace", controller.DatastoreOpGet, start, err)
 		if err != nil {
 			if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
 				clog.WithError(err).Warning("Failed to get profile from datastore")
@@ -237,7 +489,13 @@ func (c *namespaceController) syncToDatastore(key string) error {
 			}
 
 			// Doesn't exist - create it.
-			_, err := c.calicoClient.Profiles().Create(c.ctx, &p, options.SetOptions{})
+			if c.planner != nil {
+				c.planner.Record(dryrun.OpCreate, key)
+				return nil
+			}
+			start = time.Now()
+			_, err := c.calicoClient.Profiles().Create(ctx, &p, options.SetOptions{})
+			controller.ObserveDatastoreOp(ctx, "namespace", controller.DatastoreOpCreate, start, err)
 			if err != nil {
 				clog.WithError(err).Warning("Failed to create profile")
 				return err
@@ -246,10 +504,21 @@ func (c *namespaceController) syncToDatastore(key string) error {
 			return nil
 		}
 
-		// The profile already exists, update it and write it back to the datastore.
+		// The profile already exists. Only update it if it has changed, to avoid churn for Felix.
+		if !converter.NeedsUpdate(gp.ObjectMeta, gp.Spec, p.ObjectMeta, p.Spec) {
+			clog.Debug("Profile is already up to date in Calico datastore")
+			return nil
+		}
+		if c.planner != nil {
+			c.planner.Record(dryrun.OpUpdate, key)
+			return nil
+		}
 		gp.Spec = p.Spec
+		converter.CopyManagedMetadata(&gp.ObjectMeta, p.ObjectMeta)
 		clog.Infof("Update Profile in Calico datastore with resource version %s", gp.ResourceVersion)
-		_, err = c.calicoClient.Profiles().Update(c.ctx, gp, options.SetOptions{})
+		start = time.Now()
+		_, err = c.calicoClient.Profiles().Update(ctx, gp, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "namespace", controller.DatastoreOpUpdate, start, err)
 		if err != nil {
 			clog.WithError(err).Warning("Failed to update profile")
 			return err
@@ -258,31 +527,3 @@ func (c *namespaceController) syncToDatastore(key string) error {
 		return nil
 	}
 }
-
-// handleErr handles errors which occur while processing a key received from the resource cache.
-// For a given error, we will re-queue the key in order to retry the datastore sync up to 5 times,
-// at which point the update is dropped.
-func (c *namespaceController) handleErr(err error, key string) {
-	workqueue := c.resourceCache.GetQueue()
-	if err == nil {
-		// Forget about the #AddRateLimited history of the key on every successful synchronization.
-		// This ensures that future processing of updates for this key is not delayed because of
-		// an outdated error history.
-		workqueue.Forget(key)
-		return
-	}
-
-	// This controller retries 5 times if something goes wrong. After that, it stops trying.
-	if workqueue.NumRequeues(key) < 5 {
-		// Re-enqueue the key rate limited. Based on the rate limiter on the
-		// queue and the re-enqueue history, the key will be processed later again.
-		log.WithError(err).Errorf("Error syncing Profile %v: %v", key, err)
-		workqueue.AddRateLimited(key)
-		return
-	}
-	workqueue.Forget(key)
-
-	// Report to an external entity that, even after several retries, we could not successfully process this key
-	uruntime.HandleError(err)
-	log.WithError(err).Errorf("Dropping Profile %q out of the queue: %v", key, err)
-}
@@ -29,6 +29,7 @@ import (
 	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 
 	"github.com/projectcalico/calico/felix/fv/containers"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/namespace"
 	"github.com/projectcalico/calico/kube-controllers/tests/testutils"
 	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
@@ -143,5 +144,54 @@ var _ = Describe("Calico namespace controller FV tests (etcd mode)", func() {
 				}, time.Second*15, 500*time.Millisecond).ShouldNot(BeEmpty())
 			})
 		})
+
+		It("should not create or manage profiles of namespaces that opt out", func() {
+			By("creating a namespace that opts out", func() {
+				ns := &v1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "jelly",
+						Annotations: map[string]string{namespace.SkipProfileAnnotation: "true"},
+					},
+				}
+				_, err := k8sClient.CoreV1().Namespaces().Create(context.Background(), ns, metav1.CreateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Consistently(func() error {
+					_, err := calicoClient.Profiles().Get(context.Background(), "kns.jelly", options.GetOptions{})
+					return err
+				}, time.Second*5, 500*time.Millisecond).Should(HaveOccurred())
+			})
+
+			By("opting out an existing namespace and modifying its profile", func() {
+				ns, err := k8sClient.CoreV1().Namespaces().Get(context.Background(), "peanutbutter", metav1.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				ns.Annotations = map[string]string{namespace.SkipProfileAnnotation: "true"}
+				_, err = k8sClient.CoreV1().Namespaces().Update(context.Background(), ns, metav1.UpdateOptions{})
+				Expect(err).NotTo(HaveOccurred())
+
+				// Give the controller time to see the annotation before changing the profile.
+				time.Sleep(2 * time.Second)
+				profile, err := calicoClient.Profiles().Get(context.Background(), profName, options.GetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				profile.Spec.LabelsToApply = map[string]string{}
+				_, err = calicoClient.Profiles().Update(context.Background(), profile, options.SetOptions{})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			By("checking that the controller leaves the profile alone", func() {
+				Consistently(func() map[string]string {
+					prof, _ := calicoClient.Profiles().Get(context.Background(), profName, options.GetOptions{})
+					return prof.Spec.LabelsToApply
+				}, time.Second*5, 500*time.Millisecond).Should(BeEmpty())
+			})
+
+			By("deleting the namespace and checking that its profile is kept", func() {
+				err := k8sClient.CoreV1().Namespaces().Delete(context.Background(), "peanutbutter", metav1.DeleteOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Consistently(func() error {
+					_, err := calicoClient.Profiles().Get(context.Background(), profName, options.GetOptions{})
+					return err
+				}, time.Second*10, 500*time.Millisecond).ShouldNot(HaveOccurred())
+			})
+		})
 	})
 })
@@ -0,0 +1,53 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespace
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ = Describe("Namespace opt-outs", func() {
+	optedOut := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "jelly",
+		Annotations: map[string]string{SkipProfileAnnotation: "true"},
+	}}
+	optedIn := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "jelly"}}
+
+	It("should only skip namespaces that opt out", func() {
+		o := newOptOuts()
+		o.Record(optedIn)
+		Expect(o.Skips("jelly")).To(BeFalse())
+		o.Record(optedOut)
+		Expect(o.Skips("jelly")).To(BeTrue())
+		o.Record(optedIn)
+		Expect(o.Skips("jelly")).To(BeFalse())
+	})
+
+	It("should keep skipping a namespace that was deleted while opted out", func() {
+		o := newOptOuts()
+		o.Record(optedOut)
+		o.Record(cache.DeletedFinalStateUnknown{Key: "jelly", Obj: optedOut})
+		Expect(o.Skips("jelly")).To(BeTrue())
+
+		// Until it's recreated without opting out.
+		o.Record(optedIn)
+		Expect(o.Skips("jelly")).To(BeFalse())
+	})
+})
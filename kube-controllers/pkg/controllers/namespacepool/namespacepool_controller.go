@@ -0,0 +1,293 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacepool
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	rcache "github.com/projectcalico/calico/kube-controllers/pkg/cache"
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// namespacePoolController implements the Controller interface for creating the IP pools requested
+// by namespaces with the PoolAnnotation, and deleting them when they are no longer requested.
+type namespacePoolController struct {
+	informer      cache.Controller
+	resourceCache rcache.ResourceCache[api.IPPool]
+	calicoClient  client.Interface
+	ctx           context.Context
+	cfg           config.NamespaceIPPoolControllerConfig
+
+	// Syncs keys from the resource cache, limiting retries of failed syncs to a fraction of all syncs.
+	worker *controller.Worker
+}
+
+// NewNamespacePoolController returns a controller which manages the IP pools of namespaces.
+func NewNamespacePoolController(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, cfg config.NamespaceIPPoolControllerConfig) controller.Controller {
+	// Function returns map of poolName:pool for the namespace pools in the datastore.
+	listFunc := func() (map[string]api.IPPool, error) {
+		start := time.Now()
+		pools, err := c.IPPools().List(ctx, options.ListOptions{})
+		controller.ObserveDatastoreOp(ctx, "namespacepool", controller.DatastoreOpList, start, err)
+		if err != nil {
+			return nil, err
+		}
+
+		m := make(map[string]api.IPPool)
+		for _, p := range pools.Items {
+			if !converter.IsManaged(p.ObjectMeta, SourceKind) {
+				continue
+			}
+			m[p.Name] = normalize(p)
+		}
+		log.Debugf("Found %d namespace IP pools in Calico datastore", len(m))
+		return m, nil
+	}
+
+	cacheArgs := rcache.ResourceCacheArgs[api.IPPool]{
+		ControllerName: "namespacepool",
+		Shards:         cfg.NumberOfWorkers,
+		MaxQueueDepth:  cfg.MaxQueueDepth,
+		CoalesceWindow: cfg.CoalesceWindow,
+		ListFunc:       listFunc,
+		LogTypeDesc:    "NamespaceIPPool",
+		ReconcilerConfig: rcache.ReconcilerConfig{
+			MaxReconcilerPeriod: cfg.MaxReconcilerPeriod,
+			ReconcilerJitter:    cfg.ReconcilerJitter,
+		},
+	}
+	ccache := rcache.NewResourceCache(cacheArgs)
+
+	// setPool updates the cache with the pool requested by the namespace, if any.
+	setPool := func(ns *v1.Namespace) {
+		key := PoolName(ns.Name)
+		p, ok, err := DesiredPool(ns)
+		if err != nil {
+			// Leave any existing pool in place until the request is fixed.
+			log.WithError(err).WithField("namespace", ns.Name).Warn("Invalid IP pool request on namespace")
+			return
+		}
+		if !ok {
+			if _, exists := ccache.Get(key); exists {
+				ccache.Delete(key)
+			}
+			return
+		}
+		ccache.Set(key, p)
+	}
+
+	listWatcher := cache.NewListWatchFromClient(k8sClientset.CoreV1().RESTClient(), "namespaces", "", fields.Everything())
+	_, informer := cache.NewIndexerInformer(listWatcher, &v1.Namespace{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			log.Debugf("Got ADD event for Namespace: %#v", obj)
+			setPool(obj.(*v1.Namespace))
+		},
+		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			log.Debugf("Got UPDATE event for Namespace: %#v", newObj)
+			if newObj.(*v1.Namespace).Status.Phase == v1.NamespaceTerminating {
+				// Keep the pool until the namespace is deleted, since its pods may still be
+				// using it.
+				return
+			}
+			setPool(newObj.(*v1.Namespace))
+		},
+		DeleteFunc: func(obj interface{}) {
+			log.Debugf("Got DELETE event for Namespace: %#v", obj)
+			name, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+			if err != nil {
+				log.WithError(err).Error("Failed to get name of deleted namespace")
+				return
+			}
+			if _, exists := ccache.Get(PoolName(name)); exists {
+				ccache.Delete(PoolName(name))
+			}
+		},
+	}, cache.Indexers{})
+
+	ctrl := &namespacePoolController{informer, ccache, c, ctx, cfg, nil}
+	ctrl.worker = controller.NewWorker("namespacepool", "namespace IP pool", ccache, cfg.SyncTimeout, cfg.RetryBudgetRatio, ctrl.syncToDatastore)
+	return ctrl
+}
+
+// Run starts the controller.
+func (c *namespacePoolController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+
+	// Let the workers stop when we are done
+	workqueue := c.resourceCache.GetQueue()
+	defer workqueue.ShutDown()
+
+	log.Info("Starting namespace IP pool controller")
+
+	// Wait till k8s cache is synced, so that the pools of all namespaces are in the cache before
+	// the first reconciliation deletes the ones that aren't.
+	go c.informer.Run(stopCh)
+	if !cache.WaitForNamedCacheSync("namespaces", stopCh, c.informer.HasSynced) {
+		log.Info("Failed to sync resources, received signal for controller to shut down.")
+		return
+	}
+
+	c.resourceCache.Run(c.cfg.ReconcilerPeriod.String())
+
+	for i := 0; i < c.cfg.NumberOfWorkers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	log.Info("Namespace IP pool controller is now running")
+
+	<-stopCh
+	log.Info("Stopping namespace IP pool controller")
+}
+
+// Resync triggers an immediate reconciliation of the Calico datastore.
+func (c *namespacePoolController) Resync() {
+	c.resourceCache.Resync()
+}
+
+// Dump returns a snapshot of the controller's cache and queue.
+func (c *namespacePoolController) Dump() rcache.Dump {
+	return c.resourceCache.Dump()
+}
+
+func (c *namespacePoolController) runWorker() {
+	for c.worker.ProcessNextItem(c.ctx) {
+	}
+}
+
+// syncToDatastore writes the namespace pool with the given name to the datastore, or deletes it
+// if it is no longer requested. Since the CIDR of a pool can't be changed, a pool whose CIDR no
+// longer satisfies the request is deleted and recreated.
+func (c *namespacePoolController) syncToDatastore(ctx context.Context, key string) error {
+	clog := log.WithField("key", key)
+
+	start := time.Now()
+	current, err := c.calicoClient.IPPools().Get(ctx, key, options.GetOptions{})
+	controller.ObserveDatastoreOp(ctx, "namespacepool", controller.DatastoreOpGet, start, err)
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+			clog.WithError(err).Warning("Failed to get IP pool from datastore")
+			return err
+		}
+		current = nil
+	}
+	if current != nil && !converter.IsManaged(current.ObjectMeta, SourceKind) {
+		// Never touch a pool that the controller didn't create.
+		clog.Warning("IP pool already exists and isn't managed by the namespace IP pool controller")
+		return errors.ErrorResourceAlreadyExists{Identifier: key}
+	}
+
+	p, exists := c.resourceCache.Get(key)
+	if !exists {
+		if current == nil {
+			return nil
+		}
+		return c.deletePool(ctx, key)
+	}
+
+	req, err := parseRequest(p.Annotations[RequestAnnotation])
+	if err != nil {
+		return err
+	}
+	if current != nil && !req.matches(current.Spec.CIDR) {
+		// The request has changed, so replace the pool. Its addresses stay in use by any
+		// pods that have them, but no more are assigned from it.
+		clog.WithField("cidr", current.Spec.CIDR).Info("IP pool no longer matches the namespace's request, replacing it")
+		if err := c.deletePool(ctx, key); err != nil {
+			return err
+		}
+		current = nil
+	}
+
+	if current == nil {
+		if p.Spec.CIDR == "" {
+			if p.Spec.CIDR, err = c.allocateCIDR(ctx, req.version, req.size); err != nil {
+				clog.WithError(err).Warning("Failed to choose a CIDR for IP pool")
+				return err
+			}
+		}
+
+		clog.WithField("cidr", p.Spec.CIDR).Info("Creating IP pool in Calico datastore")
+		start = time.Now()
+		_, err = c.calicoClient.IPPools().Create(ctx, &p, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "namespacepool", controller.DatastoreOpCreate, start, err)
+		if err != nil {
+			clog.WithError(err).Warning("Failed to create IP pool")
+			return err
+		}
+		return nil
+	}
+
+	p.Spec.CIDR = current.Spec.CIDR
+	if !converter.NeedsUpdate(current.ObjectMeta, current.Spec, p.ObjectMeta, p.Spec) && current.Annotations[RequestAnnotation] == req.String() {
+		clog.Debug("IP pool is already up to date in Calico datastore")
+		return nil
+	}
+
+	clog.Info("Updating IP pool in Calico datastore")
+	current.Spec = p.Spec
+	converter.CopyManagedMetadata(&current.ObjectMeta, p.ObjectMeta)
+	if current.Annotations == nil {
+		current.Annotations = map[string]string{}
+	}
+	current.Annotations[RequestAnnotation] = req.String()
+	start = time.Now()
+	_, err = c.calicoClient.IPPools().Update(ctx, current, options.SetOptions{})
+	controller.ObserveDatastoreOp(ctx, "namespacepool", controller.DatastoreOpUpdate, start, err)
+	if err != nil {
+		clog.WithError(err).Warning("Failed to update IP pool")
+		return err
+	}
+	return nil
+}
+
+// deletePool deletes the namespace pool with the given name from the datastore.
+func (c *namespacePoolController) deletePool(ctx context.Context, key string) error {
+	log.WithField("key", key).Info("Deleting IP pool from Calico datastore")
+	start := time.Now()
+	_, err := c.calicoClient.IPPools().Delete(ctx, key, options.DeleteOptions{})
+	controller.ObserveDatastoreOp(ctx, "namespacepool", controller.DatastoreOpDelete, start, err)
+	if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+		return err
+	}
+	return nil
+}
+
+// allocateCIDR chooses a free CIDR of the given IP version and size from the configured ranges,
+// avoiding all the IP pools in the datastore.
+func (c *namespacePoolController) allocateCIDR(ctx context.Context, version, size int) (string, error) {
+	start := time.Now()
+	pools, err := c.calicoClient.IPPools().List(ctx, options.ListOptions{})
+	controller.ObserveDatastoreOp(ctx, "namespacepool", controller.DatastoreOpList, start, err)
+	if err != nil {
+		return "", err
+	}
+	return allocateCIDR(version, size, c.cfg.Ranges, pools.Items)
+}
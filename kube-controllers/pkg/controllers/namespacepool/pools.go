@@ -0,0 +1,274 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacepool
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+const (
+	// PoolAnnotation requests a dedicated IP pool for a namespace. Its value is either the CIDR
+	// of the pool, such as "10.65.0.0/24", or just the prefix length, such as "/24", in which
+	// case a free CIDR of that size is chosen from the configured ranges. The prefix length may
+	// be preceded by the IP version, such as "v6/32"; without it, prefix lengths up to 32 are
+	// for IPv4 pools and longer ones for IPv6 pools.
+	PoolAnnotation = "projectcalico.org/ippool"
+
+	// NATOutgoingAnnotation controls whether traffic from the namespace's pool to destinations
+	// outside of the cluster's IP pools is NATed, when set to "true" or "false". Outbound NAT is
+	// enabled by default.
+	NATOutgoingAnnotation = "projectcalico.org/nat-outgoing"
+
+	// RequestAnnotation records the request that a namespace's pool was created for, so that
+	// the pool can be compared with the request without knowing the CIDR chosen for it.
+	RequestAnnotation = "projectcalico.org/ippool-request"
+
+	// NamePrefix is the prefix of the names of the namespace pools, which are followed by the
+	// name of the namespace.
+	NamePrefix = "kns-"
+
+	// SourceKind identifies the namespace pools in their ownership labels.
+	SourceKind = "Namespace"
+
+	// The pools only select nodes that no node can match, so that IPs are only assigned from
+	// them to pods that request the pool by name, using the cni.projectcalico.org/ipv4pools or
+	// cni.projectcalico.org/ipv6pools annotations.
+	nodeSelector = "!all()"
+
+	// The default block sizes, used unless the pool is smaller than a block.
+	defaultBlockSizeV4 = 26
+	defaultBlockSizeV6 = 122
+)
+
+// request is a parsed PoolAnnotation. Exactly one of cidr and size is set, and version is set
+// along with size.
+type request struct {
+	cidr    *cnet.IPNet
+	version int
+	size    int
+}
+
+// The usage in errors about invalid requests.
+const requestUsage = "must be a CIDR or a prefix length such as /24 or v6/64"
+
+// parseRequest parses the value of a PoolAnnotation.
+func parseRequest(value string) (request, error) {
+	value = strings.TrimSpace(value)
+	if family, prefixLen, ok := strings.Cut(value, "/"); ok && (family == "" || family == "v4" || family == "v6") {
+		size, err := strconv.Atoi(prefixLen)
+		if err != nil || size <= 0 || size > 128 {
+			return request{}, invalid(PoolAnnotation, value, requestUsage)
+		}
+		version := defaultVersion(size)
+		switch family {
+		case "v4":
+			version = 4
+		case "v6":
+			version = 6
+		}
+		if version == 4 && size > 32 {
+			return request{}, invalid(PoolAnnotation, value, "IPv4 prefix lengths must be at most 32")
+		}
+		return request{version: version, size: size}, nil
+	}
+	_, cidr, err := cnet.ParseCIDR(value)
+	if err != nil {
+		return request{}, invalid(PoolAnnotation, value, requestUsage)
+	}
+	return request{cidr: cidr}, nil
+}
+
+// String returns the normalized form of the request, as recorded in the RequestAnnotation. The
+// IP version is only included if it isn't implied by the prefix length.
+func (r request) String() string {
+	if r.cidr != nil {
+		return r.cidr.String()
+	}
+	if r.version != defaultVersion(r.size) {
+		return fmt.Sprintf("v%d/%d", r.version, r.size)
+	}
+	return fmt.Sprintf("/%d", r.size)
+}
+
+// matches returns true if the given CIDR satisfies the request.
+func (r request) matches(cidr string) bool {
+	_, n, err := cnet.ParseCIDR(cidr)
+	if err != nil {
+		return false
+	}
+	if r.cidr != nil {
+		return n.String() == r.cidr.String()
+	}
+	ones, _ := n.Mask.Size()
+	return n.Version() == r.version && ones == r.size
+}
+
+// PoolName returns the name of the pool of the given namespace.
+func PoolName(namespace string) string {
+	return NamePrefix + namespace
+}
+
+// DesiredPool returns the pool requested by the given namespace, and false if it doesn't request
+// one. If the request is just for a size, the CIDR of the returned pool is left empty, to be
+// chosen when the pool is created.
+func DesiredPool(ns *v1.Namespace) (api.IPPool, bool, error) {
+	value, ok := ns.Annotations[PoolAnnotation]
+	if !ok {
+		return api.IPPool{}, false, nil
+	}
+	req, err := parseRequest(value)
+	if err != nil {
+		return api.IPPool{}, false, err
+	}
+	natOutgoing := true
+	if v, ok := ns.Annotations[NATOutgoingAnnotation]; ok {
+		if natOutgoing, err = strconv.ParseBool(v); err != nil {
+			return api.IPPool{}, false, invalid(NATOutgoingAnnotation, v, "must be true or false")
+		}
+	}
+
+	p := api.IPPool{
+		TypeMeta: metav1.TypeMeta{Kind: api.KindIPPool, APIVersion: api.GroupVersionCurrent},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        PoolName(ns.Name),
+			Annotations: map[string]string{RequestAnnotation: req.String()},
+		},
+		Spec: api.IPPoolSpec{
+			IPIPMode:     api.IPIPModeNever,
+			VXLANMode:    api.VXLANModeNever,
+			NATOutgoing:  natOutgoing,
+			NodeSelector: nodeSelector,
+			AllowedUses:  []api.IPPoolAllowedUse{api.IPPoolAllowedUseWorkload},
+		},
+	}
+	converter.SetOwnership(&p.ObjectMeta, SourceKind, ns.UID)
+
+	if req.cidr != nil {
+		p.Spec.CIDR = req.cidr.String()
+		ones, _ := req.cidr.Mask.Size()
+		p.Spec.BlockSize = blockSize(req.cidr.Version(), ones)
+	} else {
+		p.Spec.BlockSize = blockSize(req.version, req.size)
+	}
+	return p, true, nil
+}
+
+// blockSize returns the block size for a pool of the given IP version and prefix length.
+func blockSize(version, prefixLen int) int {
+	size := defaultBlockSizeV4
+	if version == 6 {
+		size = defaultBlockSizeV6
+	}
+	if prefixLen > size {
+		return prefixLen
+	}
+	return size
+}
+
+// normalize returns the pool with just the metadata that is managed by the controller, so that
+// it can be compared with the pools in the cache. Pools requested by size have their CIDR
+// removed if it still satisfies the request, to match the desired pool.
+func normalize(p api.IPPool) api.IPPool {
+	meta := converter.ManagedMetadata(p.ObjectMeta)
+	if v, ok := p.Annotations[RequestAnnotation]; ok {
+		if meta.Annotations == nil {
+			meta.Annotations = map[string]string{}
+		}
+		meta.Annotations[RequestAnnotation] = v
+	}
+	spec := *p.Spec.DeepCopy()
+	if req, err := parseRequest(p.Annotations[RequestAnnotation]); err == nil && req.cidr == nil && req.matches(spec.CIDR) {
+		spec.CIDR = ""
+	}
+	return api.IPPool{TypeMeta: p.TypeMeta, ObjectMeta: meta, Spec: spec}
+}
+
+// allocateCIDR returns the first CIDR of the given IP version and prefix length in the given
+// ranges that doesn't overlap any of the given pools.
+func allocateCIDR(version, size int, ranges []string, pools []api.IPPool) (string, error) {
+	var used []cnet.IPNet
+	for _, p := range pools {
+		if _, n, err := cnet.ParseCIDR(p.Spec.CIDR); err == nil {
+			used = append(used, *n)
+		}
+	}
+
+	for _, r := range ranges {
+		_, rng, err := cnet.ParseCIDR(r)
+		if err != nil {
+			return "", invalid("NAMESPACE_IP_POOL_RANGES", r, "must be a CIDR")
+		}
+		ones, bits := rng.Mask.Size()
+		if rng.Version() != version || size < ones || size > bits {
+			continue
+		}
+
+		// Walk through the subnets of the requested size in order, skipping over any pools
+		// that are at least as large in one go.
+		step := big.NewInt(0).Lsh(big.NewInt(1), uint(bits-size))
+		end := big.NewInt(0).Add(cnet.IPToBigInt(cnet.IP{IP: rng.IP}), rng.NumAddrs())
+		for next := cnet.IPToBigInt(cnet.IP{IP: rng.IP}); next.Cmp(end) < 0; {
+			candidate := cnet.IPNet{IPNet: net.IPNet{IP: cnet.BigIntToIP(next, bits == 128).IP, Mask: net.CIDRMask(size, bits)}}
+			u, overlaps := overlapping(candidate, used)
+			if !overlaps {
+				return candidate.String(), nil
+			}
+			if uOnes, _ := u.Mask.Size(); uOnes <= size {
+				next = big.NewInt(0).Add(cnet.IPToBigInt(cnet.IP{IP: u.IP}), u.NumAddrs())
+			} else {
+				next = big.NewInt(0).Add(next, step)
+			}
+		}
+	}
+	return "", fmt.Errorf("no free IPv%d /%d CIDR in IP pool ranges %v", version, size, ranges)
+}
+
+// defaultVersion returns the IP version of pools requested with the given prefix length and no
+// explicit version: IPv4 for prefix lengths up to 32, and IPv6 for longer ones.
+func defaultVersion(size int) int {
+	if size > 32 {
+		return 6
+	}
+	return 4
+}
+
+// overlapping returns the first of the used CIDRs that overlaps n, if any.
+func overlapping(n cnet.IPNet, used []cnet.IPNet) (cnet.IPNet, bool) {
+	for _, u := range used {
+		if n.IsNetOverlap(u.IPNet) {
+			return u, true
+		}
+	}
+	return cnet.IPNet{}, false
+}
+
+func invalid(name, value, reason string) error {
+	return cerrors.ErrorValidation{
+		ErroredFields: []cerrors.ErroredField{{Name: name, Value: value, Reason: reason}},
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package namespacepool
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	validator "github.com/projectcalico/calico/libcalico-go/lib/validator/v3"
+)
+
+func namespace(annotation string) *v1.Namespace {
+	ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", UID: "uid-a"}}
+	if annotation != "" {
+		ns.Annotations = map[string]string{PoolAnnotation: annotation}
+	}
+	return ns
+}
+
+func pool(cidr string) api.IPPool {
+	return api.IPPool{Spec: api.IPPoolSpec{CIDR: cidr}}
+}
+
+var _ = Describe("Namespace IP pools", func() {
+	It("should not request a pool without the annotation", func() {
+		_, ok, err := DesiredPool(namespace(""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should request a dedicated pool with the given CIDR", func() {
+		p, ok, err := DesiredPool(namespace("10.65.0.1/24"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(p.Name).To(Equal("kns-tenant-a"))
+		Expect(p.Spec.CIDR).To(Equal("10.65.0.0/24"))
+		Expect(p.Spec.BlockSize).To(Equal(26))
+		Expect(p.Spec.NodeSelector).To(Equal("!all()"))
+		Expect(p.Annotations).To(HaveKeyWithValue(RequestAnnotation, "10.65.0.0/24"))
+		Expect(p.Annotations).To(HaveKeyWithValue(converter.SourceUIDAnnotation, "uid-a"))
+		Expect(converter.IsManaged(p.ObjectMeta, SourceKind)).To(BeTrue())
+		Expect(validator.Validate(&p)).To(Succeed())
+	})
+
+	It("should control outbound NAT with an annotation", func() {
+		ns := namespace("10.65.0.0/24")
+		p, _, err := DesiredPool(ns)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Spec.NATOutgoing).To(BeTrue())
+
+		ns.Annotations[NATOutgoingAnnotation] = "false"
+		p, _, err = DesiredPool(ns)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Spec.NATOutgoing).To(BeFalse())
+
+		ns.Annotations[NATOutgoingAnnotation] = "sometimes"
+		_, _, err = DesiredPool(ns)
+		Expect(controller.IsPermanentError(err)).To(BeTrue())
+	})
+
+	It("should use blocks no larger than the pool", func() {
+		p, _, err := DesiredPool(namespace("10.65.0.0/28"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Spec.BlockSize).To(Equal(28))
+		Expect(validator.Validate(&p)).To(Succeed())
+
+		p, _, err = DesiredPool(namespace("/120"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Spec.BlockSize).To(Equal(122))
+	})
+
+	It("should leave the CIDR of pools requested by size to be chosen", func() {
+		p, ok, err := DesiredPool(namespace("/24"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(p.Spec.CIDR).To(BeEmpty())
+		Expect(p.Annotations).To(HaveKeyWithValue(RequestAnnotation, "/24"))
+	})
+
+	It("should take the IP version of pools requested by size from the annotation", func() {
+		p, _, err := DesiredPool(namespace("v6/32"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Spec.BlockSize).To(Equal(122))
+		Expect(p.Annotations).To(HaveKeyWithValue(RequestAnnotation, "v6/32"))
+
+		// The version is only recorded if it isn't implied by the prefix length.
+		p, _, err = DesiredPool(namespace("v4/24"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Spec.BlockSize).To(Equal(26))
+		Expect(p.Annotations).To(HaveKeyWithValue(RequestAnnotation, "/24"))
+
+		p, _, err = DesiredPool(namespace("v6/64"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Annotations).To(HaveKeyWithValue(RequestAnnotation, "/64"))
+
+		// A pool of the other version doesn't satisfy the request.
+		stored := *p.DeepCopy()
+		stored.Spec.CIDR = "fd00:64::/32"
+		desired, _, err := DesiredPool(namespace("v6/32"))
+		Expect(err).NotTo(HaveOccurred())
+		stored.Annotations = desired.Annotations
+		Expect(normalize(stored).Spec.CIDR).To(BeEmpty())
+		stored.Spec.CIDR = "10.0.0.0/32"
+		Expect(normalize(stored).Spec.CIDR).To(Equal("10.0.0.0/32"))
+	})
+
+	It("should reject invalid requests as permanent errors", func() {
+		for _, v := range []string{"tenant", "/0", "/129", "/x", "10.65.0.0/33", "v4/33", "v6/129", "v5/24", "v6/"} {
+			_, _, err := DesiredPool(namespace(v))
+			Expect(err).To(HaveOccurred(), v)
+			Expect(controller.IsPermanentError(err)).To(BeTrue(), v)
+		}
+	})
+
+	It("should normalize pools from the datastore to match the desired pool", func() {
+		for _, v := range []string{"/24", "10.65.0.0/24"} {
+			desired, _, err := DesiredPool(namespace(v))
+			Expect(err).NotTo(HaveOccurred())
+
+			stored := *desired.DeepCopy()
+			stored.Spec.CIDR = "10.65.0.0/24"
+			stored.ResourceVersion = "123"
+			stored.Labels["user"] = "label"
+			Expect(normalize(stored)).To(Equal(desired), v)
+		}
+	})
+
+	It("should keep the CIDR of pools that no longer match a size request", func() {
+		desired, _, err := DesiredPool(namespace("/24"))
+		Expect(err).NotTo(HaveOccurred())
+		stored := *desired.DeepCopy()
+		stored.Spec.CIDR = "10.65.0.0/25"
+		Expect(normalize(stored).Spec.CIDR).To(Equal("10.65.0.0/25"))
+	})
+
+	It("should allocate the first free CIDR in the ranges", func() {
+		ranges := []string{"10.64.0.0/16", "fd00:64::/48"}
+		pools := []api.IPPool{pool("10.64.0.0/24"), pool("10.64.1.128/25"), pool("192.168.0.0/16")}
+
+		cidr, err := allocateCIDR(4, 24, ranges, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidr).To(Equal("10.64.2.0/24"))
+
+		cidr, err = allocateCIDR(4, 25, ranges, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidr).To(Equal("10.64.1.0/25"))
+
+		cidr, err = allocateCIDR(6, 64, ranges, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidr).To(Equal("fd00:64::/64"))
+
+		// Short IPv6 prefix lengths are allocated from the IPv6 ranges.
+		cidr, err = allocateCIDR(6, 24, []string{"10.64.0.0/8", "fd00::/16"}, pools)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidr).To(Equal("fd00::/24"))
+	})
+
+	It("should skip over pools larger than the requested size", func() {
+		cidr, err := allocateCIDR(4, 28, []string{"10.64.0.0/8"}, []api.IPPool{pool("10.0.0.0/9")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cidr).To(Equal("10.128.0.0/28"))
+	})
+
+	It("should fail when the ranges are full", func() {
+		_, err := allocateCIDR(4, 24, []string{"10.64.0.0/23"}, []api.IPPool{pool("10.64.0.0/24"), pool("10.64.1.0/24")})
+		Expect(err).To(HaveOccurred())
+
+		_, err = allocateCIDR(4, 24, nil, nil)
+		Expect(err).To(HaveOccurred())
+
+		_, err = allocateCIDR(4, 24, []string{"255.255.255.0/24"}, []api.IPPool{pool("255.255.255.0/24")})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -16,10 +16,12 @@ package networkpolicy
 
 import (
 	"context"
-	"reflect"
+	"fmt"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
 	rcache "github.com/projectcalico/calico/kube-controllers/pkg/cache"
@@ -29,54 +31,108 @@ import (
 	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 
 	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
-	kdd "github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
+	"github.com/projectcalico/calico/kube-controllers/pkg/dryrun"
+	"github.com/projectcalico/calico/kube-controllers/pkg/tenant"
+	"github.com/projectcalico/calico/kube-controllers/pkg/tracing"
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
 
+	v1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
 	uruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 )
 
+var syncsCounter *prometheus.CounterVec
+
+func init() {
+	syncsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "policy_controller_syncs_total",
+		Help: "Number of network policy writes to the Calico datastore, by tenant, operation and result",
+	}, []string{"tenant", "operation", "result"})
+	prometheus.MustRegister(syncsCounter)
+}
+
 // policyController implements the Controller interface for managing Kubernetes network policies
 // and syncing them to the Calico datastore as NetworkPolicies.
 type policyController struct {
 	informer      cache.Controller
-	resourceCache rcache.ResourceCache
+	resourceCache rcache.ResourceCache[api.NetworkPolicy]
 	calicoClient  client.Interface
 	ctx           context.Context
-	cfg           config.GenericControllerConfig
+	cfg           config.PolicyControllerConfig
+	converter     converter.Converter
+	planner       *dryrun.Planner
+
+	// Maps the namespace of each policy to the tenant used to label the sync metrics.
+	tenants *tenant.Resolver
+
+	// Namespace informer used to look up the tenant label, or nil if no tenant label is configured.
+	nsInformer cache.Controller
+
+	// Limits the rate of deletes from the Calico datastore.
+	deleteLimiter *controller.DeleteLimiter
+
+	// Syncs keys from the resource cache, limiting retries of failed syncs to a fraction of all syncs.
+	worker *controller.Worker
 }
 
 // NewPolicyController returns a controller which manages NetworkPolicy objects.
-func NewPolicyController(ctx context.Context, clientset *kubernetes.Clientset, c client.Interface, cfg config.GenericControllerConfig) controller.Controller {
-	policyConverter := converter.NewPolicyConverter()
+func NewPolicyController(ctx context.Context, clientset *kubernetes.Clientset, c client.Interface, cfg config.PolicyControllerConfig) controller.Controller {
+	policyConverter := converter.NewPolicyConverter(
+		converter.WithMaxNameLength(cfg.MaxNameLength),
+		converter.WithNamePrefix(cfg.NamePrefix),
+		converter.WithDNSEgressRules(cfg.AllowDNS),
+		converter.WithMinOrder(cfg.MinOrder),
+	)
+	registry := converter.NewRegistry()
+	registry.MustRegister(converter.NetworkPolicyGVK, policyConverter)
+
+	// Track which Kubernetes policy owns each generated name, so that we can detect two long
+	// policy names shortening to the same Calico name.
+	names := converter.NewNameRegistry()
+
+	// Policies in excluded namespaces are neither converted nor cleaned up.
+	nsFilter := controller.NewNamespaceFilter(cfg.ExcludeNamespaces)
+
+	// Only manage the selected policies. The selectors are validated when loading the config.
+	selectors, err := controller.ParseSelectors(cfg.LabelSelector, cfg.FieldSelector)
+	if err != nil {
+		log.WithError(err).Fatal("Invalid policy selector")
+	}
 
 	// Create a NetworkPolicy watcher.
-	listWatcher := cache.NewListWatchFromClient(clientset.NetworkingV1().RESTClient(), "networkpolicies", "", fields.Everything())
+	listWatcher := nsFilter.ListWatch(controller.PagedListWatch(selectors.ListWatch(clientset.NetworkingV1().RESTClient(), "networkpolicies"), cfg.ListPageSize))
 
 	// Function returns map of policyName:policy stored by policy controller
 	// in datastore.
-	listFunc := func() (map[string]interface{}, error) {
+	listFunc := func() (map[string]api.NetworkPolicy, error) {
 		// Get all policies from datastore
+		start := time.Now()
 		calicoPolicies, err := c.NetworkPolicies().List(ctx, options.ListOptions{})
+		controller.ObserveDatastoreOp(ctx, "policy", controller.DatastoreOpList, start, err)
 		if err != nil {
 			return nil, err
 		}
 
 		// Filter in only objects that are written by policy controller.
-		m := make(map[string]interface{})
+		m := make(map[string]api.NetworkPolicy)
 		for _, policy := range calicoPolicies.Items {
-			if strings.HasPrefix(policy.Name, kdd.K8sNetworkPolicyNamePrefix) {
-				// Update the network policy's ObjectMeta so that it simply contains the name and namespace.
-				// There is other metadata that we might receive (like resource version) that we don't want to
-				// compare in the cache.
-				policy.ObjectMeta = metav1.ObjectMeta{Name: policy.Name, Namespace: policy.Namespace}
+			if nsFilter.Excluded(policy.Namespace) {
+				continue
+			}
+			if converter.IsManagedOrLegacy(policy.ObjectMeta, "NetworkPolicy", cfg.NamePrefix) && strings.HasPrefix(policy.Name, cfg.NamePrefix) {
+				// Update the network policy's ObjectMeta so that it simply contains the name, namespace
+				// and ownership metadata. There is other metadata that we might receive (like resource
+				// version) that we don't want to compare in the cache.
+				policy.ObjectMeta = converter.ManagedMetadata(policy.ObjectMeta)
 				k := policyConverter.GetKey(policy)
 				m[k] = policy
 			}
@@ -86,55 +142,209 @@ func NewPolicyController(ctx context.Context, clientset *kubernetes.Clientset, c
 		return m, nil
 	}
 
-	cacheArgs := rcache.ResourceCacheArgs{
-		ListFunc:   listFunc,
-		ObjectType: reflect.TypeOf(api.NetworkPolicy{}),
+	// Functions used to spot check the cache, reading directly from the Kubernetes API and the
+	// Calico datastore.
+	sourceGetFunc := func(key string) (api.NetworkPolicy, bool, error) {
+		// Generated names may have been shortened, so look up the Kubernetes policy that the
+		// name was generated from.
+		source, ok := names.Source(key)
+		if !ok {
+			return api.NetworkPolicy{}, false, fmt.Errorf("no known source for policy %s", key)
+		}
+		ns, name, err := cache.SplitMetaNamespaceKey(source)
+		if err != nil {
+			return api.NetworkPolicy{}, false, err
+		}
+		np, err := clientset.NetworkingV1().NetworkPolicies(ns).Get(ctx, name, metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return api.NetworkPolicy{}, false, nil
+		} else if err != nil {
+			return api.NetworkPolicy{}, false, err
+		}
+		if !selectors.Matches(labels.Set(np.Labels), fields.Set{"metadata.name": np.Name, "metadata.namespace": np.Namespace}) {
+			return api.NetworkPolicy{}, false, nil
+		}
+		policies, err := converter.ConvertObjectAll[api.NetworkPolicy](registry, np)
+		if err != nil {
+			return api.NetworkPolicy{}, false, err
+		}
+		for _, policy := range policies {
+			if policyConverter.GetKey(policy) == key {
+				return policy, true, nil
+			}
+		}
+		return api.NetworkPolicy{}, false, nil
+	}
+	datastoreGetFunc := func(key string) (api.NetworkPolicy, bool, error) {
+		ns, name := policyConverter.DeleteArgsFromKey(key)
+		policy, err := c.NetworkPolicies().Get(ctx, ns, name, options.GetOptions{})
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+			return api.NetworkPolicy{}, false, nil
+		} else if err != nil {
+			return api.NetworkPolicy{}, false, err
+		}
+		if !converter.IsManagedOrLegacy(policy.ObjectMeta, "NetworkPolicy", cfg.NamePrefix) {
+			return api.NetworkPolicy{}, false, nil
+		}
+		policy.ObjectMeta = converter.ManagedMetadata(policy.ObjectMeta)
+		return *policy, true, nil
+	}
+
+	cacheArgs := rcache.ResourceCacheArgs[api.NetworkPolicy]{
+		ControllerName:   "policy",
+		Shards:           cfg.NumberOfWorkers,
+		MaxQueueDepth:    cfg.MaxQueueDepth,
+		CoalesceWindow:   cfg.CoalesceWindow,
+		ListFunc:         listFunc,
+		SourceGetFunc:    sourceGetFunc,
+		DatastoreGetFunc: datastoreGetFunc,
+
+		// Remove stale resources before writing their replacements, so that the two are never
+		// in effect at the same time.
+		PrioritizeDeletes: true,
+
+		ReconcilerConfig: rcache.ReconcilerConfig{
+			MaxReconcilerPeriod: cfg.MaxReconcilerPeriod,
+			ReconcilerJitter:    cfg.ReconcilerJitter,
+			ReconcileAtStartup:  true,
+			SpotCheckPeriod:     cfg.SpotCheckPeriod,
+			SpotCheckSampleSize: cfg.SpotCheckSampleSize,
+			ResyncWindow:        cfg.ResyncWindow,
+			ResyncThreshold:     cfg.ResyncThreshold,
+			MaxSnapshotAge:      cfg.MaxSnapshotAge,
+			MassDeleteThreshold: cfg.MassDeleteThreshold,
+
+			// Generated policies don't record the labels of the Kubernetes policy they were
+			// generated from, so when only some policies are selected the reconciler can't tell
+			// whether a policy that isn't in the cache belongs to another instance.
+			DisableMissingInCache: !selectors.Empty(),
+		},
+	}
+	if cfg.SnapshotDir != "" {
+		cacheArgs.SnapshotStore = rcache.NewFileSnapshotStore(filepath.Join(cfg.SnapshotDir, "policy.json"))
 	}
 	ccache := rcache.NewResourceCache(cacheArgs)
+	conversionErrors := controller.NewConversionErrors("NetworkPolicy")
+
+	// setPolicies converts the given Kubernetes policy and sets the Calico policies generated
+	// from it in the cache. Any policies previously generated from it that it no longer
+	// generates are removed.
+	setPolicies := func(spanCtx context.Context, obj interface{}) {
+		_, convertSpan := tracing.Start(spanCtx, "convert")
+		policies, err := converter.ConvertObjectAll[api.NetworkPolicy](registry, obj)
+		tracing.End(convertSpan, err)
+		if err != nil {
+			log.WithError(err).Errorf("Error while converting %#v to calico network policy.", obj)
+			conversionErrors.Record(obj, err)
+			return
+		}
+		conversionErrors.Clear(obj)
+
+		source := sourceKey(obj)
+		keys := map[string]bool{}
+		for _, policy := range policies {
+			k := policyConverter.GetKey(policy)
+			if err := names.Register(k, source); err != nil {
+				log.WithError(err).Error("Skipping network policy")
+				continue
+			}
+			keys[k] = true
+			_, cacheSpan := tracing.Start(spanCtx, "cache set")
+			ccache.Set(k, policy)
+			cacheSpan.End()
+		}
+		for _, k := range names.Names(source) {
+			if !keys[k] {
+				names.Release(k, source)
+				_, cacheSpan := tracing.Start(spanCtx, "cache delete")
+				ccache.Delete(k)
+				cacheSpan.End()
+			}
+		}
+	}
 
 	// Bind the Calico cache to kubernetes cache with the help of an informer. This way we make sure that
 	// whenever the kubernetes cache is updated, changes get reflected in the Calico cache as well.
 	_, informer := cache.NewIndexerInformer(listWatcher, &networkingv1.NetworkPolicy{}, 0, cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			log.Debugf("Got ADD event for network policy: %#v", obj)
-			policy, err := policyConverter.Convert(obj)
-			if err != nil {
-				log.WithError(err).Errorf("Error while converting %#v to calico network policy.", obj)
-				return
-			}
+			spanCtx, span := tracing.StartEvent("policy", "add", obj)
+			defer span.End()
 
-			// Add to cache.
-			k := policyConverter.GetKey(policy)
-			ccache.Set(k, policy)
+			log.Debugf("Got ADD event for network policy: %#v", obj)
+			setPolicies(spanCtx, obj)
 		},
 		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			spanCtx, span := tracing.StartEvent("policy", "update", newObj)
+			defer span.End()
+
 			log.Debugf("Got UPDATE event for NetworkPolicy.")
 			log.Debugf("Old object: \n%#v\n", oldObj)
 			log.Debugf("New object: \n%#v\n", newObj)
-			policy, err := policyConverter.Convert(newObj)
-			if err != nil {
-				log.WithError(err).Errorf("Error converting to Calico policy.")
-				return
-			}
-
-			// Add to cache.
-			k := policyConverter.GetKey(policy)
-			ccache.Set(k, policy)
+			setPolicies(spanCtx, newObj)
 		},
 		DeleteFunc: func(obj interface{}) {
+			spanCtx, span := tracing.StartEvent("policy", "delete", obj)
+			defer span.End()
+
 			log.Debugf("Got DELETE event for NetworkPolicy: %#v", obj)
-			policy, err := policyConverter.Convert(obj)
+			_, convertSpan := tracing.Start(spanCtx, "convert")
+			policies, err := converter.ConvertObjectAll[api.NetworkPolicy](registry, obj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error converting to Calico policy.")
+				conversionErrors.Record(obj, err)
 				return
 			}
-
-			calicoKey := policyConverter.GetKey(policy)
-			ccache.Delete(calicoKey)
+			conversionErrors.Clear(obj)
+
+			// Delete the policies generated from this policy, including any that are no longer
+			// generated from its final state.
+			source := sourceKey(obj)
+			for _, policy := range policies {
+				calicoKey := policyConverter.GetKey(policy)
+				if err := names.Register(calicoKey, source); err != nil {
+					// The generated name belongs to a different policy, leave it alone.
+					log.WithError(err).Error("Skipping network policy deletion")
+				}
+			}
+			for _, calicoKey := range names.Names(source) {
+				names.Release(calicoKey, source)
+				_, cacheSpan := tracing.Start(spanCtx, "cache delete")
+				ccache.Delete(calicoKey)
+				cacheSpan.End()
+			}
 		},
 	}, cache.Indexers{})
 
-	return &policyController{informer, ccache, c, ctx, cfg}
+	var planner *dryrun.Planner
+	if cfg.DryRun {
+		planner = dryrun.NewPlanner("policy", c.KubeControllersConfiguration())
+	}
+
+	// Only watch namespaces if we need their labels to identify the tenant.
+	var nsStore cache.Store
+	var nsInformer cache.Controller
+	if len(cfg.MetricsTenants) > 0 && cfg.MetricsTenantLabel != "" {
+		nsListWatcher := controller.PagedListWatch(cache.NewListWatchFromClient(clientset.CoreV1().RESTClient(), "namespaces", "", fields.Everything()), cfg.ListPageSize)
+		nsStore, nsInformer = cache.NewInformer(nsListWatcher, &v1.Namespace{}, 0, cache.ResourceEventHandlerFuncs{})
+	}
+	tenants := tenant.NewResolver(cfg.MetricsTenants, cfg.MetricsTenantLabel, nsStore)
+
+	deleteLimiter := controller.NewDeleteLimiter("policy", cfg.DeleteRateLimit, cfg.DeleteBurst)
+
+	ctrl := &policyController{informer, ccache, c, ctx, cfg, policyConverter, planner, tenants, nsInformer, deleteLimiter, nil}
+	ctrl.worker = controller.NewWorker("policy", "Policy", ccache, cfg.SyncTimeout, cfg.RetryBudgetRatio, ctrl.syncToDatastore)
+	return ctrl
+}
+
+// sourceKey returns the namespace/name key of the Kubernetes policy, used to detect collisions
+// between generated names.
+func sourceKey(obj interface{}) string {
+	k, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		log.WithError(err).Warn("Failed to generate key for network policy")
+	}
+	return k
 }
 
 // Run starts the controller.
@@ -148,11 +358,18 @@ func (c *policyController) Run(stopCh chan struct{}) {
 	// Start the Kubernetes informer, which will start syncing with the Kubernetes API.
 	log.Info("Starting NetworkPolicy controller")
 	go c.informer.Run(stopCh)
+	if c.nsInformer != nil {
+		go c.nsInformer.Run(stopCh)
+	}
 
 	// Wait until we are in sync with the Kubernetes API before starting the
 	// resource cache.
 	log.Debug("Waiting to sync with Kubernetes API (NetworkPolicy)")
-	if !cache.WaitForNamedCacheSync("network-policies", stopCh, c.informer.HasSynced) {
+	synced := []cache.InformerSynced{c.informer.HasSynced}
+	if c.nsInformer != nil {
+		synced = append(synced, c.nsInformer.HasSynced)
+	}
+	if !cache.WaitForNamedCacheSync("network-policies", stopCh, synced...) {
 		log.Info("Failed to sync resources, received signal for controller to shut down.")
 		return
 	}
@@ -162,6 +379,12 @@ func (c *policyController) Run(stopCh chan struct{}) {
 	// that are out of sync onto the resource cache event queue.
 	c.resourceCache.Run(c.cfg.ReconcilerPeriod.String())
 
+	// In dry-run mode, report the planned changes once per reconciliation.
+	if c.planner != nil {
+		log.Info("NetworkPolicy controller is running in dry-run mode")
+		go c.planner.Run(c.ctx, c.cfg.ReconcilerPeriod)
+	}
+
 	// Start a number of worker threads to read from the queue. Each worker
 	// will pull keys off the resource cache event queue and sync them to the
 	// Calico datastore.
@@ -174,57 +397,75 @@ func (c *policyController) Run(stopCh chan struct{}) {
 	log.Info("Stopping NetworkPolicy controller")
 }
 
-func (c *policyController) runWorker() {
-	for c.processNextItem() {
-	}
+// Resync triggers an immediate reconciliation of the Calico datastore.
+func (c *policyController) Resync() {
+	c.resourceCache.Resync()
 }
 
-// processNextItem waits for an event on the output queue from the resource cache and syncs
-// any received keys to the datastore.
-func (c *policyController) processNextItem() bool {
-	// Wait until there is a new item in the work queue.
-	workqueue := c.resourceCache.GetQueue()
-	key, quit := workqueue.Get()
-	if quit {
-		return false
-	}
+// ConfirmDeletes allows the next reconciliation to make deletes withheld by the mass delete
+// failsafe, and triggers it.
+func (c *policyController) ConfirmDeletes() {
+	c.resourceCache.ConfirmDeletes()
+}
 
-	// Sync the object to the Calico datastore.
-	err := c.syncToDatastore(key.(string))
-	c.handleErr(err, key.(string))
+// Dump returns a snapshot of the controller's cache and queue.
+func (c *policyController) Dump() rcache.Dump {
+	return c.resourceCache.Dump()
+}
 
-	// Indicate that we're done processing this key, allowing for safe parallel processing such that
-	// two objects with the same key are never processed in parallel.
-	workqueue.Done(key)
-	return true
+func (c *policyController) runWorker() {
+	for c.worker.ProcessNextItem(c.ctx) {
+	}
 }
 
 // syncToDatastore syncs the given update to the Calico datastore. The provided key can be used to
 // find the corresponding resource within the resource cache. If the resource for the provided key
 // exists in the cache, then the value should be written to the datastore. If it does not exist
 // in the cache, then it should be deleted from the datastore.
-func (c *policyController) syncToDatastore(key string) error {
+func (c *policyController) syncToDatastore(ctx context.Context, key string) error {
 	clog := log.WithField("key", key)
 
+	// In dry-run mode, the key is recorded again below if the datastore still differs.
+	if c.planner != nil {
+		c.planner.Resolve(key)
+	}
+
 	// Check if it exists in the controller's cache.
-	obj, exists := c.resourceCache.Get(key)
+	p, exists := c.resourceCache.Get(key)
 	if !exists {
 		// The object no longer exists - delete from the datastore.
+		ns, name := c.converter.DeleteArgsFromKey(key)
+		if c.planner != nil {
+			_, err := c.calicoClient.NetworkPolicies().Get(ctx, ns, name, options.GetOptions{})
+			if err == nil {
+				c.planner.Record(dryrun.OpDelete, key)
+			} else if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+				return err
+			}
+			return nil
+		}
+		if err := c.deleteLimiter.Reserve(key); err != nil {
+			return err
+		}
 		clog.Infof("Deleting NetworkPolicy from Calico datastore")
-		ns, name := converter.NewPolicyConverter().DeleteArgsFromKey(key)
-		_, err := c.calicoClient.NetworkPolicies().Delete(c.ctx, ns, name, options.DeleteOptions{})
+		start := time.Now()
+		_, err := c.calicoClient.NetworkPolicies().Delete(ctx, ns, name, options.DeleteOptions{})
+		controller.ObserveDatastoreOp(ctx, "policy", controller.DatastoreOpDelete, start, err)
 		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
 			// We hit an error other than "does not exist".
+			c.recordSync(ns, "delete", err)
 			return err
 		}
+		c.recordSync(ns, "delete", nil)
 		return nil
 	} else {
 		// The object exists - update the datastore to reflect.
 		clog.Infof("Create/Update NetworkPolicy in Calico datastore")
-		p := obj.(api.NetworkPolicy)
 
 		// Lookup to see if this object already exists in the datastore.
-		gp, err := c.calicoClient.NetworkPolicies().Get(c.ctx, p.Namespace, p.Name, options.GetOptions{})
+		start := time.Now()
+		gp, err := c.calicoClient.NetworkPolicies().Get(ctx, p.Namespace, p.Name, options.GetOptions{})
+		controller.ObserveDatastoreOp(ctx, "policy", controller.DatastoreOpGet, start, err)
 		if err != nil {
 			if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
 				clog.WithError(err).Warning("Failed to get network policy from datastore")
@@ -232,7 +473,14 @@ func (c *policyController) syncToDatastore(key string) error {
 			}
 
 			// Doesn't exist - create it.
-			_, err := c.calicoClient.NetworkPolicies().Create(c.ctx, &p, options.SetOptions{})
+			if c.planner != nil {
+				c.planner.Record(dryrun.OpCreate, key)
+				return nil
+			}
+			start = time.Now()
+			_, err := c.calicoClient.NetworkPolicies().Create(ctx, &p, options.SetOptions{})
+			controller.ObserveDatastoreOp(ctx, "policy", controller.DatastoreOpCreate, start, err)
+			c.recordSync(p.Namespace, "create", err)
 			if err != nil {
 				clog.WithError(err).Warning("Failed to create network policy")
 				return err
@@ -241,10 +489,22 @@ func (c *policyController) syncToDatastore(key string) error {
 			return nil
 		}
 
-		// The policy already exists, update it and write it back to the datastore.
+		// The policy already exists. Only update it if it has changed, to avoid churn for Felix.
+		if !converter.NeedsUpdate(gp.ObjectMeta, gp.Spec, p.ObjectMeta, p.Spec) {
+			clog.Debug("NetworkPolicy is already up to date in Calico datastore")
+			return nil
+		}
+		if c.planner != nil {
+			c.planner.Record(dryrun.OpUpdate, key)
+			return nil
+		}
 		gp.Spec = p.Spec
+		converter.CopyManagedMetadata(&gp.ObjectMeta, p.ObjectMeta)
 		clog.Infof("Update NetworkPolicy in Calico datastore with resource version %s", p.ResourceVersion)
-		_, err = c.calicoClient.NetworkPolicies().Update(c.ctx, gp, options.SetOptions{})
+		start = time.Now()
+		_, err = c.calicoClient.NetworkPolicies().Update(ctx, gp, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "policy", controller.DatastoreOpUpdate, start, err)
+		c.recordSync(p.Namespace, "update", err)
 		if err != nil {
 			clog.WithError(err).Warning("Failed to update network policy")
 			return err
@@ -254,30 +514,11 @@ func (c *policyController) syncToDatastore(key string) error {
 	}
 }
 
-// handleErr handles errors which occur while processing a key received from the resource cache.
-// For a given error, we will re-queue the key in order to retry the datastore sync up to 5 times,
-// at which point the update is dropped.
-func (c *policyController) handleErr(err error, key string) {
-	workqueue := c.resourceCache.GetQueue()
-	if err == nil {
-		// Forget about the #AddRateLimited history of the key on every successful synchronization.
-		// This ensures that future processing of updates for this key is not delayed because of
-		// an outdated error history.
-		workqueue.Forget(key)
-		return
+// recordSync updates the sync metrics for a write to the datastore of a policy in the given namespace.
+func (c *policyController) recordSync(namespace, operation string, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
 	}
-
-	// This controller retries 5 times if something goes wrong. After that, it stops trying.
-	if workqueue.NumRequeues(key) < 5 {
-		// Re-enqueue the key rate limited. Based on the rate limiter on the
-		// queue and the re-enqueue history, the key will be processed later again.
-		log.WithError(err).Errorf("Error syncing Policy %v: %v", key, err)
-		workqueue.AddRateLimited(key)
-		return
-	}
-	workqueue.Forget(key)
-
-	// Report to an external entity that, even after several retries, we could not successfully process this key
-	uruntime.HandleError(err)
-	log.WithError(err).Errorf("Dropping Policy %q out of the queue: %v", key, err)
+	syncsCounter.WithLabelValues(c.tenants.Tenant(namespace), operation, result).Inc()
 }
@@ -0,0 +1,249 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkset
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// networkSetController implements the Controller interface for projecting the ConfigMaps with the
+// configured label into NetworkSets, so that app teams can manage the address groups used in their
+// policies with the Kubernetes RBAC they already have for ConfigMaps.
+type networkSetController struct {
+	informer     cache.Controller
+	indexer      cache.Indexer
+	queue        workqueue.RateLimitingInterface
+	k8sClientset *kubernetes.Clientset
+	calicoClient client.Interface
+	ctx          context.Context
+	cfg          config.NetworkSetControllerConfig
+	recorder     record.EventRecorder
+}
+
+// NewNetworkSetController returns a controller which maintains a NetworkSet for each ConfigMap
+// with the configured label.
+func NewNetworkSetController(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, cfg config.NetworkSetControllerConfig) controller.Controller {
+	nsc := &networkSetController{
+		queue:        workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		k8sClientset: k8sClientset,
+		calicoClient: c,
+		ctx:          ctx,
+		cfg:          cfg,
+	}
+
+	enqueue := func(obj interface{}) {
+		if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+			nsc.queue.Add(key)
+		}
+	}
+	// Only watch the ConfigMaps that have the label, whatever its value.
+	listWatcher := cache.NewFilteredListWatchFromClient(k8sClientset.CoreV1().RESTClient(), "configmaps", "", func(o *metav1.ListOptions) {
+		o.LabelSelector = cfg.Label
+	})
+	nsc.indexer, nsc.informer = cache.NewIndexerInformer(listWatcher, &v1.ConfigMap{}, cfg.ReconcilerPeriod, cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			enqueue(newObj)
+		},
+		DeleteFunc: enqueue,
+	}, cache.Indexers{})
+
+	return nsc
+}
+
+// Run starts the controller.
+func (c *networkSetController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	log.WithField("label", c.cfg.Label).Info("Starting ConfigMap NetworkSet controller")
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.k8sClientset.CoreV1().Events("")})
+	defer broadcaster.Shutdown()
+	c.recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "calico-kube-controllers"})
+
+	go c.informer.Run(stopCh)
+	if !cache.WaitForNamedCacheSync("configmaps", stopCh, c.informer.HasSynced) {
+		log.Info("Failed to sync resources, received signal for controller to shut down.")
+		return
+	}
+
+	// Queue the NetworkSets of ConfigMaps that were deleted, or lost the label, while we weren't
+	// running, so that they are cleaned up.
+	if err := c.queueOrphans(); err != nil {
+		log.WithError(err).Warning("Failed to list NetworkSets generated from ConfigMaps")
+	}
+
+	for i := 0; i < c.cfg.NumberOfWorkers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	log.Info("ConfigMap NetworkSet controller is now running")
+
+	<-stopCh
+	log.Info("Stopping ConfigMap NetworkSet controller")
+}
+
+func (c *networkSetController) queueOrphans() error {
+	start := time.Now()
+	sets, err := c.calicoClient.NetworkSets().List(c.ctx, options.ListOptions{})
+	controller.ObserveDatastoreOp(c.ctx, "networkset", controller.DatastoreOpList, start, err)
+	if err != nil {
+		return err
+	}
+	for _, ns := range sets.Items {
+		if !converter.IsManaged(ns.ObjectMeta, SourceKind) {
+			continue
+		}
+		key := ns.Namespace + "/" + ns.Name
+		if _, exists, _ := c.indexer.GetByKey(key); !exists {
+			c.queue.Add(key)
+		}
+	}
+	return nil
+}
+
+func (c *networkSetController) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+// processNextItem waits for a ConfigMap on the queue and syncs its NetworkSet.
+func (c *networkSetController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	done := controller.WorkerWatchdog.Begin("networkset")
+	ctx, cancel := controller.WithSyncTimeout(c.ctx, c.cfg.SyncTimeout)
+	err := c.sync(ctx, key.(string))
+	cancel()
+	done()
+
+	if err == nil {
+		c.queue.Forget(key)
+		return true
+	}
+	controller.RecordSyncError("networkset", err)
+	if !controller.IsPermanentError(err) && c.queue.NumRequeues(key) < 5 {
+		log.WithError(err).Errorf("Error syncing NetworkSet %v: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	uruntime.HandleError(err)
+	log.WithError(err).Errorf("Dropping NetworkSet %q out of the queue: %v", key, err)
+	return true
+}
+
+// sync makes the NetworkSet with the given key match the ConfigMap with the same key, deleting it
+// if the ConfigMap no longer exists. NetworkSets that weren't generated from a ConfigMap are never
+// modified.
+func (c *networkSetController) sync(ctx context.Context, key string) error {
+	clog := log.WithField("networkSet", key)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	var desired *api.NetworkSet
+	obj, exists, err := c.indexer.GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if exists {
+		cm := obj.(*v1.ConfigMap)
+		if desired, err = DesiredSet(cm); err != nil {
+			clog.WithError(err).Warning("Invalid networks in ConfigMap")
+			c.recorder.Event(cm, v1.EventTypeWarning, "CalicoNetworkSetInvalid", err.Error())
+			return err
+		}
+	}
+
+	start := time.Now()
+	current, err := c.calicoClient.NetworkSets().Get(ctx, namespace, name, options.GetOptions{})
+	controller.ObserveDatastoreOp(ctx, "networkset", controller.DatastoreOpGet, start, err)
+	if err != nil {
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			return err
+		}
+		if desired == nil {
+			return nil
+		}
+		clog.Info("Creating NetworkSet from ConfigMap")
+		start = time.Now()
+		_, err = c.calicoClient.NetworkSets().Create(ctx, desired, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "networkset", controller.DatastoreOpCreate, start, err)
+		return err
+	}
+
+	if !converter.IsManaged(current.ObjectMeta, SourceKind) {
+		if desired != nil {
+			clog.Warning("NetworkSet already exists, and wasn't generated from the ConfigMap")
+			return cerrors.ErrorResourceAlreadyExists{Identifier: key}
+		}
+		return nil
+	}
+
+	if desired == nil {
+		clog.Info("Deleting NetworkSet of deleted ConfigMap")
+		start = time.Now()
+		_, err = c.calicoClient.NetworkSets().Delete(ctx, namespace, name, options.DeleteOptions{ResourceVersion: current.ResourceVersion})
+		controller.ObserveDatastoreOp(ctx, "networkset", controller.DatastoreOpDelete, start, err)
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); ok {
+			return nil
+		}
+		return err
+	}
+
+	if equality.Semantic.DeepEqual(current.Labels, desired.Labels) &&
+		!converter.NeedsUpdate(current.ObjectMeta, current.Spec, desired.ObjectMeta, desired.Spec) {
+		return nil
+	}
+	clog.Info("Updating NetworkSet from ConfigMap")
+	current.Labels = desired.Labels
+	converter.CopyManagedMetadata(&current.ObjectMeta, desired.ObjectMeta)
+	current.Spec = desired.Spec
+	start = time.Now()
+	_, err = c.calicoClient.NetworkSets().Update(ctx, current, options.SetOptions{})
+	controller.ObserveDatastoreOp(ctx, "networkset", controller.DatastoreOpUpdate, start, err)
+	return err
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkset
+
+import (
+	"sort"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	netsets "github.com/projectcalico/calico/kube-controllers/pkg/networkset"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+// SourceKind identifies the NetworkSets generated from ConfigMaps in their ownership labels.
+const SourceKind = "ConfigMap"
+
+// Nets returns the networks listed in the values of the ConfigMap's data, consolidated into the
+// fewest CIDRs that cover the same addresses. The IPs and CIDRs in each value may be separated by commas or whitespace, and
+// anything after a '#' on a line is a comment. Addresses become /32 or /128 networks.
+func Nets(cm *v1.ConfigMap) ([]string, error) {
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var nets []string
+	for _, k := range keys {
+		for _, line := range strings.Split(cm.Data[k], "\n") {
+			if i := strings.Index(line, "#"); i >= 0 {
+				line = line[:i]
+			}
+			for _, entry := range strings.FieldsFunc(line, func(r rune) bool {
+				return r == ',' || r == ' ' || r == '\t' || r == '\r'
+			}) {
+				if _, _, err := cnet.ParseCIDROrIP(entry); err != nil {
+					return nil, cerrors.ErrorValidation{
+						ErroredFields: []cerrors.ErroredField{{Name: "data." + k, Value: entry, Reason: "must be an IP address or CIDR"}},
+					}
+				}
+				nets = append(nets, entry)
+			}
+		}
+	}
+
+	return netsets.ConsolidateCIDRs(nets)
+}
+
+// DesiredSet returns the NetworkSet for the given ConfigMap. It has the same namespace, name and
+// labels as the ConfigMap, so that policies can select it by the labels the app team chose.
+func DesiredSet(cm *v1.ConfigMap) (*api.NetworkSet, error) {
+	nets, err := Nets(cm)
+	if err != nil {
+		return nil, err
+	}
+	ns := api.NewNetworkSet()
+	ns.Name = cm.Name
+	ns.Namespace = cm.Namespace
+	ns.Labels = map[string]string{}
+	for k, v := range cm.Labels {
+		ns.Labels[k] = v
+	}
+	ns.Spec.Nets = nets
+	converter.SetOwnership(&ns.ObjectMeta, SourceKind, cm.UID)
+	return ns, nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkset_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/networkset"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+)
+
+var _ = Describe("ConfigMap NetworkSets", func() {
+	configMap := func(data map[string]string) *v1.ConfigMap {
+		return &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "shop",
+				Name:      "partners",
+				UID:       "cm-uid",
+				Labels:    map[string]string{"projectcalico.org/network-set": "", "team": "payments"},
+			},
+			Data: data,
+		}
+	}
+
+	It("should merge the networks from all keys, ignoring comments", func() {
+		nets, err := networkset.Nets(configMap(map[string]string{
+			"acme":   "192.0.2.0/24, 198.51.100.7\n# the office\n10.1.2.3/8 # host bits set\n",
+			"globex": "2001:db8::1 198.51.100.7\t203.0.113.0/28",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nets).To(Equal([]string{"10.0.0.0/8", "192.0.2.0/24", "198.51.100.7/32", "203.0.113.0/28", "2001:db8::1/128"}))
+	})
+
+	It("should consolidate overlapping and adjacent networks", func() {
+		nets, err := networkset.Nets(configMap(map[string]string{
+			"acme":   "10.0.0.0/8, 10.1.0.0/16",
+			"globex": "192.0.2.0/25\n192.0.2.128/25",
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nets).To(Equal([]string{"10.0.0.0/8", "192.0.2.0/24"}))
+	})
+
+	It("should reject a ConfigMap with an invalid entry", func() {
+		_, err := networkset.Nets(configMap(map[string]string{"acme": "192.0.2.0/24, example.com"}))
+		Expect(err).To(BeAssignableToTypeOf(cerrors.ErrorValidation{}))
+		Expect(err.Error()).To(ContainSubstring("data.acme"))
+		Expect(err.Error()).To(ContainSubstring("example.com"))
+	})
+
+	It("should generate a NetworkSet with the ConfigMap's name and labels", func() {
+		cm := configMap(map[string]string{"acme": "192.0.2.0/24"})
+		ns, err := networkset.DesiredSet(cm)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ns.Namespace).To(Equal("shop"))
+		Expect(ns.Name).To(Equal("partners"))
+		Expect(ns.Labels).To(HaveKeyWithValue("team", "payments"))
+		Expect(ns.Spec.Nets).To(Equal([]string{"192.0.2.0/24"}))
+		Expect(converter.IsManaged(ns.ObjectMeta, networkset.SourceKind)).To(BeTrue())
+		Expect(ns.Annotations).To(HaveKeyWithValue(converter.SourceUIDAnnotation, "cm-uid"))
+
+		// The ConfigMap's labels must not be modified.
+		Expect(cm.Labels).To(HaveLen(2))
+	})
+
+	It("should generate an empty NetworkSet from an empty ConfigMap", func() {
+		ns, err := networkset.DesiredSet(configMap(nil))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ns.Spec.Nets).To(BeEmpty())
+	})
+})
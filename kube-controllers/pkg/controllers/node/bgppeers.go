@@ -0,0 +1,302 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+const (
+	// BGPPeersAnnotation lists the external BGP peers of a Kubernetes node, such as its
+	// top-of-rack switches, as a JSON list like [{"peerIP":"10.0.0.1","asNumber":65001}].
+	BGPPeersAnnotation = "projectcalico.org/bgp-peers"
+
+	// nodeBGPPeerSourceKind identifies the BGP peers created from node annotations in their
+	// ownership labels.
+	nodeBGPPeerSourceKind = "Node"
+
+	// nodeBGPPeerNamePrefix is the prefix of the names of the BGP peers created from node
+	// annotations, which are followed by the Calico node name and the peer IP.
+	nodeBGPPeerNamePrefix = "kn-"
+)
+
+// nodeBGPPeer is an entry in the BGPPeersAnnotation.
+type nodeBGPPeer struct {
+	PeerIP   string               `json:"peerIP"`
+	ASNumber numorstring.ASNumber `json:"asNumber"`
+}
+
+// parseBGPPeers parses the value of a BGPPeersAnnotation.
+func parseBGPPeers(value string) ([]nodeBGPPeer, error) {
+	var peers []nodeBGPPeer
+	if err := json.Unmarshal([]byte(value), &peers); err != nil {
+		return nil, err
+	}
+	for _, p := range peers {
+		if cnet.ParseIP(p.PeerIP) == nil {
+			return nil, fmt.Errorf("invalid peer IP %q", p.PeerIP)
+		}
+		if p.ASNumber == 0 {
+			return nil, fmt.Errorf("missing AS number for peer %s", p.PeerIP)
+		}
+	}
+	return peers, nil
+}
+
+// nodeBGPPeerName returns the name of the BGP peer for the given Calico node and peer IP. IPv6
+// addresses are hex encoded, since colons aren't allowed in names.
+func nodeBGPPeerName(node, peerIP string) string {
+	ip := cnet.ParseIP(peerIP)
+	name := ip.String()
+	if ip.Version() == 6 {
+		name = hex.EncodeToString(ip.To16())
+	}
+	return converter.ShortenName(nodeBGPPeerNamePrefix+node+"."+name, converter.DefaultMaxNameLength)
+}
+
+// desiredBGPPeer returns the BGP peer for the given Calico node and annotation entry.
+func desiredBGPPeer(node string, p nodeBGPPeer) *api.BGPPeer {
+	bp := api.NewBGPPeer()
+	bp.Name = nodeBGPPeerName(node, p.PeerIP)
+	bp.Spec = api.BGPPeerSpec{
+		Node:     node,
+		PeerIP:   cnet.ParseIP(p.PeerIP).String(),
+		ASNumber: p.ASNumber,
+	}
+	converter.SetOwnership(&bp.ObjectMeta, nodeBGPPeerSourceKind, "")
+	return bp
+}
+
+// bgpPeerController creates a BGP peer for each of the peers listed in the BGPPeersAnnotation of
+// the Kubernetes nodes, and deletes them again when they are removed from the annotation.
+type bgpPeerController struct {
+	sync.Mutex
+	client      client.Interface
+	nodeIndexer cache.Indexer
+	rl          workqueue.RateLimiter
+
+	// nodemapper maps a Kubernetes node name to the corresponding Calico node name.
+	nodemapper map[string]string
+	inSync     bool
+
+	kickChan chan interface{}
+}
+
+func NewBGPPeerController(c client.Interface, nodeIndexer cache.Indexer) *bgpPeerController {
+	return &bgpPeerController{
+		client:      c,
+		nodeIndexer: nodeIndexer,
+		rl:          workqueue.DefaultControllerRateLimiter(),
+		nodemapper:  map[string]string{},
+		kickChan:    make(chan interface{}, 1),
+	}
+}
+
+func (c *bgpPeerController) RegisterWith(f *DataFeed) {
+	// We want nodes, which are sent with key model.ResourceKey
+	f.RegisterForNotification(model.ResourceKey{}, c.onUpdate)
+	f.RegisterForSyncStatus(c.onStatusUpdate)
+}
+
+func (c *bgpPeerController) onStatusUpdate(s bapi.SyncStatus) {
+	if s == bapi.InSync {
+		c.Lock()
+		c.inSync = true
+		c.Unlock()
+		kick(c.kickChan)
+	}
+}
+
+// onUpdate maintains the mapping of Kubernetes nodes to Calico nodes, and triggers a sync when it
+// changes.
+func (c *bgpPeerController) onUpdate(update bapi.Update) {
+	key, ok := update.KVPair.Key.(model.ResourceKey)
+	if !ok || key.Kind != libapi.KindNode {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	changed := false
+	if n, ok := update.Value.(*libapi.Node); ok && n != nil {
+		kn, err := getK8sNodeName(*n)
+		if err != nil {
+			logrus.WithError(err).Debug("Unable to get corresponding k8s node name, skipping")
+			return
+		}
+		changed = c.nodemapper[kn] != n.Name
+		c.nodemapper[kn] = n.Name
+	} else {
+		for kn, cn := range c.nodemapper {
+			if cn == key.Name {
+				delete(c.nodemapper, kn)
+				changed = true
+			}
+		}
+	}
+	if changed && c.inSync {
+		kick(c.kickChan)
+	}
+}
+
+// OnKubernetesNodeUpdate is called by the Kubernetes informer callback when a node is added or
+// updated, with a nil old node when it is added. Nodes are updated often, so only changes to the
+// BGPPeersAnnotation trigger a sync.
+func (c *bgpPeerController) OnKubernetesNodeUpdate(oldObj, newObj interface{}) {
+	n, ok := newObj.(*v1.Node)
+	if !ok {
+		logrus.Warnf("Received update that is not a v1.Node: %+v", newObj)
+		return
+	}
+	if old, ok := oldObj.(*v1.Node); ok && old.Annotations[BGPPeersAnnotation] == n.Annotations[BGPPeersAnnotation] {
+		return
+	}
+	kick(c.kickChan)
+}
+
+// OnKubernetesNodeDeleted is called by the Kubernetes informer callback when a node is deleted.
+func (c *bgpPeerController) OnKubernetesNodeDeleted() {
+	kick(c.kickChan)
+}
+
+// Start starts the controller's sync loop, which retries failed syncs with backoff.
+func (c *bgpPeerController) Start(stopCh chan struct{}) {
+	go func() {
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-c.kickChan:
+				if err := c.sync(context.Background()); err != nil {
+					delay := c.rl.When("sync")
+					logrus.WithError(err).Warnf("Failed to sync BGP peers from node annotations, retrying in %v", delay)
+					time.AfterFunc(delay, func() { kick(c.kickChan) })
+					continue
+				}
+				c.rl.Forget("sync")
+			}
+		}
+	}()
+}
+
+// sync makes the BGP peers created by the controller match the annotations of the Kubernetes
+// nodes. The peers of a node with an invalid annotation are left as they are, so that a mistake
+// in the annotation doesn't tear down its existing peerings.
+func (c *bgpPeerController) sync(ctx context.Context) error {
+	c.Lock()
+	if !c.inSync {
+		// Until the syncer is in sync we don't know all of the Calico nodes, and would delete
+		// the peers of the ones we don't know about yet.
+		c.Unlock()
+		return nil
+	}
+	nodemapper := make(map[string]string, len(c.nodemapper))
+	for kn, cn := range c.nodemapper {
+		nodemapper[kn] = cn
+	}
+	c.Unlock()
+
+	desired := map[string]*api.BGPPeer{}
+	invalid := map[string]bool{}
+	for _, obj := range c.nodeIndexer.List() {
+		kn, ok := obj.(*v1.Node)
+		if !ok {
+			continue
+		}
+		value, ok := kn.Annotations[BGPPeersAnnotation]
+		if !ok {
+			continue
+		}
+		cn, ok := nodemapper[kn.Name]
+		if !ok {
+			logrus.WithField("node", kn.Name).Debug("No Calico node for Kubernetes node, skipping BGP peers")
+			continue
+		}
+		peers, err := parseBGPPeers(value)
+		if err != nil {
+			logrus.WithError(err).WithField("node", kn.Name).Warnf("Invalid %s annotation, leaving BGP peers unchanged", BGPPeersAnnotation)
+			invalid[cn] = true
+			continue
+		}
+		for _, p := range peers {
+			bp := desiredBGPPeer(cn, p)
+			desired[bp.Name] = bp
+		}
+	}
+
+	existing, err := c.client.BGPPeers().List(ctx, options.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range existing.Items {
+		bp := &existing.Items[i]
+		d, ok := desired[bp.Name]
+		delete(desired, bp.Name)
+		if !converter.IsManaged(bp.ObjectMeta, nodeBGPPeerSourceKind) {
+			if ok {
+				logrus.WithField("peer", bp.Name).Warn("BGP peer for node annotation already exists, and isn't managed by the controller")
+			}
+			continue
+		}
+		if !ok {
+			if invalid[bp.Spec.Node] {
+				continue
+			}
+			logrus.WithField("peer", bp.Name).Info("Deleting BGP peer for node annotation")
+			_, err := c.client.BGPPeers().Delete(ctx, bp.Name, options.DeleteOptions{ResourceVersion: bp.ResourceVersion})
+			if _, ok := err.(errors.ErrorResourceDoesNotExist); err != nil && !ok {
+				return err
+			}
+			continue
+		}
+		if !converter.NeedsUpdate(bp.ObjectMeta, bp.Spec, d.ObjectMeta, d.Spec) {
+			continue
+		}
+		logrus.WithField("peer", bp.Name).Info("Updating BGP peer for node annotation")
+		converter.CopyManagedMetadata(&bp.ObjectMeta, d.ObjectMeta)
+		bp.Spec = d.Spec
+		if _, err := c.client.BGPPeers().Update(ctx, bp, options.SetOptions{}); err != nil {
+			return err
+		}
+	}
+	for name, bp := range desired {
+		logrus.WithField("peer", name).Info("Creating BGP peer for node annotation")
+		if _, err := c.client.BGPPeers().Create(ctx, bp, options.SetOptions{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+var _ = Describe("Node BGP peer controller UTs", func() {
+	var (
+		cli     *FakeCalicoClient
+		indexer cache.Indexer
+		c       *bgpPeerController
+		ctx     = context.Background()
+	)
+
+	BeforeEach(func() {
+		cli = NewFakeCalicoClient()
+		indexer = cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		c = NewBGPPeerController(cli, indexer)
+		c.nodemapper["knode"] = "cnode"
+		c.inSync = true
+	})
+
+	setAnnotation := func(value string) {
+		n := &v1.Node{ObjectMeta: metav1.ObjectMeta{
+			Name:        "knode",
+			Annotations: map[string]string{BGPPeersAnnotation: value},
+		}}
+		Expect(indexer.Update(n)).To(Succeed())
+	}
+
+	peers := func() map[string]api.BGPPeerSpec {
+		l, err := cli.BGPPeers().List(ctx, options.ListOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		specs := map[string]api.BGPPeerSpec{}
+		for _, p := range l.Items {
+			specs[p.Name] = p.Spec
+		}
+		return specs
+	}
+
+	It("should create, update and delete BGP peers to match the annotation", func() {
+		setAnnotation(`[{"peerIP":"10.0.0.1","asNumber":65001},{"peerIP":"fd00::1","asNumber":"1.10"}]`)
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(peers()).To(Equal(map[string]api.BGPPeerSpec{
+			"kn-cnode.10.0.0.1":                         {Node: "cnode", PeerIP: "10.0.0.1", ASNumber: 65001},
+			"kn-cnode.fd000000000000000000000000000001": {Node: "cnode", PeerIP: "fd00::1", ASNumber: numorstring.ASNumber(65546)},
+		}))
+
+		setAnnotation(`[{"peerIP":"10.0.0.1","asNumber":65002}]`)
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(peers()).To(Equal(map[string]api.BGPPeerSpec{
+			"kn-cnode.10.0.0.1": {Node: "cnode", PeerIP: "10.0.0.1", ASNumber: 65002},
+		}))
+
+		Expect(indexer.Delete(&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "knode"}})).To(Succeed())
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(peers()).To(BeEmpty())
+	})
+
+	It("should leave the BGP peers of a node with an invalid annotation alone", func() {
+		setAnnotation(`[{"peerIP":"10.0.0.1","asNumber":65001}]`)
+		Expect(c.sync(ctx)).To(Succeed())
+
+		setAnnotation(`[{"peerIP":"10.0.0.300","asNumber":65001}]`)
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(peers()).To(HaveKey("kn-cnode.10.0.0.1"))
+	})
+
+	It("should leave BGP peers that it didn't create alone", func() {
+		p := api.NewBGPPeer()
+		p.Name = "user-peer"
+		p.Spec.PeerIP = "10.0.0.2"
+		_, err := cli.BGPPeers().Create(ctx, p, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		setAnnotation(`[]`)
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(peers()).To(HaveKey("user-peer"))
+	})
+
+	It("should not sync before the syncer is in sync", func() {
+		c.inSync = false
+		setAnnotation(`[{"peerIP":"10.0.0.1","asNumber":65001}]`)
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(peers()).To(BeEmpty())
+	})
+})
@@ -59,7 +59,9 @@ type NodeController struct {
 	dataFeed     *DataFeed
 
 	// Sub-controllers
-	ipamCtrl *ipamController
+	ipamCtrl    *ipamController
+	rrCtrl      *routeReflectorController
+	bgpPeerCtrl *bgpPeerController
 }
 
 // NewNodeController Constructor for NodeController
@@ -110,12 +112,34 @@ func NewNodeController(ctx context.Context,
 	autoHEPController := NewAutoHEPController(cfg, calicoClient)
 	autoHEPController.RegisterWith(nc.dataFeed)
 
+	if cfg.RouteReflectorLabel != "" {
+		// Create the route reflector controller and register it to receive data.
+		nc.rrCtrl = NewRouteReflectorController(cfg, calicoClient)
+		nc.rrCtrl.RegisterWith(nc.dataFeed)
+	}
+
+	if cfg.BGPPeerAnnotations {
+		// Create the BGP peer controller and register it to receive data. It also needs to
+		// be notified when Kubernetes node annotations change.
+		nc.bgpPeerCtrl = NewBGPPeerController(calicoClient, nodeInformer.GetIndexer())
+		nc.bgpPeerCtrl.RegisterWith(nc.dataFeed)
+		nodeDeletionFuncs = append(nodeDeletionFuncs, nc.bgpPeerCtrl.OnKubernetesNodeDeleted)
+		bgpPeerHandlers := cache.ResourceEventHandlerFuncs{
+			AddFunc:    func(obj interface{}) { nc.bgpPeerCtrl.OnKubernetesNodeUpdate(nil, obj) },
+			UpdateFunc: nc.bgpPeerCtrl.OnKubernetesNodeUpdate,
+		}
+		if _, err := nc.nodeInformer.AddEventHandler(bgpPeerHandlers); err != nil {
+			log.WithError(err).Error("failed to add BGP peer event handler for node")
+			return nil
+		}
+	}
+
 	if cfg.SyncLabels {
 		// Note that the configuration code has already handled disabling this if
 		// we are in KDD mode.
 
 		// Create Label-sync controller and register it to receive data.
-		nodeLabelCtrl := NewNodeLabelController(calicoClient)
+		nodeLabelCtrl := NewNodeLabelController(calicoClient, cfg.SyncLabelPrefixes)
 		nodeLabelCtrl.RegisterWith(nc.dataFeed)
 
 		// Hook the node label controller into the node informer so we are notified
@@ -173,6 +197,12 @@ func (c *NodeController) Run(stopCh chan struct{}) {
 
 	// We're in-sync. Start the sub-controllers.
 	c.ipamCtrl.Start(stopCh)
+	if c.rrCtrl != nil {
+		c.rrCtrl.Start(stopCh)
+	}
+	if c.bgpPeerCtrl != nil {
+		c.bgpPeerCtrl.Start(stopCh)
+	}
 
 	<-stopCh
 	log.Info("Stopping Node controller")
@@ -19,6 +19,8 @@ import (
 	"strings"
 	"sync"
 
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
 	apiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
 	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
@@ -39,15 +41,19 @@ func NewFakeCalicoClient() *FakeCalicoClient {
 		handlesReleased:    make(map[string]bool),
 	}
 	return &FakeCalicoClient{
-		nodeClient: &nc,
-		ipamClient: &ipamClient,
+		nodeClient:      &nc,
+		ipamClient:      &ipamClient,
+		bgpPeerClient:   &fakeBGPPeerClient{peers: make(map[string]*api.BGPPeer)},
+		bgpConfigClient: &fakeBGPConfigurationClient{configs: make(map[string]*api.BGPConfiguration)},
 	}
 }
 
 // FakeCalicoClient is a fake client for use in the IPAM tests.
 type FakeCalicoClient struct {
-	nodeClient clientv3.NodeInterface
-	ipamClient ipam.Interface
+	nodeClient      clientv3.NodeInterface
+	ipamClient      ipam.Interface
+	bgpPeerClient   clientv3.BGPPeerInterface
+	bgpConfigClient clientv3.BGPConfigurationInterface
 }
 
 func (f *FakeCalicoClient) Backend() bapi.Client {
@@ -106,7 +112,7 @@ func (f *FakeCalicoClient) BGPFilter() clientv3.BGPFilterInterface {
 
 // BGPPeers returns an interface for managing BGP peer resources.
 func (f *FakeCalicoClient) BGPPeers() clientv3.BGPPeerInterface {
-	panic("not implemented")
+	return f.bgpPeerClient
 }
 
 // IPAM returns an interface for managing IP address assignment and releasing.
@@ -116,7 +122,7 @@ func (f *FakeCalicoClient) IPAM() ipam.Interface {
 
 // BGPConfigurations returns an interface for managing the BGP configuration resources.
 func (f *FakeCalicoClient) BGPConfigurations() clientv3.BGPConfigurationInterface {
-	panic("not implemented")
+	return f.bgpConfigClient
 }
 
 // FelixConfigurations returns an interface for managing the Felix configuration resources.
@@ -179,7 +185,14 @@ func (f *fakeNodeClient) Create(ctx context.Context, res *apiv3.Node, opts optio
 }
 
 func (f *fakeNodeClient) Update(ctx context.Context, res *apiv3.Node, opts options.SetOptions) (*apiv3.Node, error) {
-	panic("not implemented") // TODO: Implement
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.nodes[res.Name]; !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: res.Name}
+	}
+	f.nodes[res.Name] = res
+	return res, nil
 }
 
 func (f *fakeNodeClient) Delete(ctx context.Context, name string, opts options.DeleteOptions) (*apiv3.Node, error) {
@@ -204,6 +217,123 @@ func (f *fakeNodeClient) Watch(ctx context.Context, opts options.ListOptions) (w
 	panic("not implemented") // TODO: Implement
 }
 
+// fakeBGPPeerClient implements the clientv3 BGPPeerInterface for testing purposes.
+type fakeBGPPeerClient struct {
+	sync.Mutex
+	peers map[string]*api.BGPPeer
+}
+
+func (f *fakeBGPPeerClient) Create(ctx context.Context, res *api.BGPPeer, opts options.SetOptions) (*api.BGPPeer, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.peers[res.Name]; ok {
+		return nil, cerrors.ErrorResourceAlreadyExists{Identifier: res.Name}
+	}
+	f.peers[res.Name] = res.DeepCopy()
+	return res, nil
+}
+
+func (f *fakeBGPPeerClient) Update(ctx context.Context, res *api.BGPPeer, opts options.SetOptions) (*api.BGPPeer, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.peers[res.Name]; !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: res.Name}
+	}
+	f.peers[res.Name] = res.DeepCopy()
+	return res, nil
+}
+
+func (f *fakeBGPPeerClient) Delete(ctx context.Context, name string, opts options.DeleteOptions) (*api.BGPPeer, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	p, ok := f.peers[name]
+	if !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: name}
+	}
+	delete(f.peers, name)
+	return p, nil
+}
+
+func (f *fakeBGPPeerClient) Get(ctx context.Context, name string, opts options.GetOptions) (*api.BGPPeer, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	p, ok := f.peers[name]
+	if !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: name}
+	}
+	return p.DeepCopy(), nil
+}
+
+func (f *fakeBGPPeerClient) List(ctx context.Context, opts options.ListOptions) (*api.BGPPeerList, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	l := &api.BGPPeerList{}
+	for _, p := range f.peers {
+		l.Items = append(l.Items, *p.DeepCopy())
+	}
+	return l, nil
+}
+
+func (f *fakeBGPPeerClient) Watch(ctx context.Context, opts options.ListOptions) (watch.Interface, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+// fakeBGPConfigurationClient implements the clientv3 BGPConfigurationInterface for testing purposes.
+type fakeBGPConfigurationClient struct {
+	sync.Mutex
+	configs map[string]*api.BGPConfiguration
+}
+
+func (f *fakeBGPConfigurationClient) Create(ctx context.Context, res *api.BGPConfiguration, opts options.SetOptions) (*api.BGPConfiguration, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.configs[res.Name]; ok {
+		return nil, cerrors.ErrorResourceAlreadyExists{Identifier: res.Name}
+	}
+	f.configs[res.Name] = res.DeepCopy()
+	return res, nil
+}
+
+func (f *fakeBGPConfigurationClient) Update(ctx context.Context, res *api.BGPConfiguration, opts options.SetOptions) (*api.BGPConfiguration, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.configs[res.Name]; !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: res.Name}
+	}
+	f.configs[res.Name] = res.DeepCopy()
+	return res, nil
+}
+
+func (f *fakeBGPConfigurationClient) Delete(ctx context.Context, name string, opts options.DeleteOptions) (*api.BGPConfiguration, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *fakeBGPConfigurationClient) Get(ctx context.Context, name string, opts options.GetOptions) (*api.BGPConfiguration, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	c, ok := f.configs[name]
+	if !ok {
+		return nil, cerrors.ErrorResourceDoesNotExist{Identifier: name}
+	}
+	return c.DeepCopy(), nil
+}
+
+func (f *fakeBGPConfigurationClient) List(ctx context.Context, opts options.ListOptions) (*api.BGPConfigurationList, error) {
+	panic("not implemented") // TODO: Implement
+}
+
+func (f *fakeBGPConfigurationClient) Watch(ctx context.Context, opts options.ListOptions) (watch.Interface, error) {
+	panic("not implemented") // TODO: Implement
+}
+
 // fakeIPAMClient implements ipam.Interface for testing purposes.
 type fakeIPAMClient struct {
 	sync.Mutex
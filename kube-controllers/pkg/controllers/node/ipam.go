@@ -65,6 +65,13 @@ var (
 	legacyAllocationsGauge *prometheus.GaugeVec
 	legacyBlocksGauge      *prometheus.GaugeVec
 	legacyBorrowedGauge    *prometheus.GaugeVec
+
+	// Utilization of each IP pool as a whole, for capacity planning.
+	poolBlocksGauge         *prometheus.GaugeVec
+	poolBlocksInUseGauge    *prometheus.GaugeVec
+	poolBlocksFreeGauge     *prometheus.GaugeVec
+	poolAddressesInUseGauge *prometheus.GaugeVec
+	poolAddressesFreeGauge  *prometheus.GaugeVec
 )
 
 const (
@@ -93,6 +100,28 @@ func init() {
 	}, []string{"ippool"})
 	prometheus.MustRegister(poolSizeGauge)
 
+	poolBlocksGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ipam_ippool_blocks",
+		Help: "Total number of blocks that the IP Pool can be divided into",
+	}, []string{"ippool"})
+	poolBlocksInUseGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ipam_ippool_blocks_in_use",
+		Help: "Number of blocks currently allocated from the IP Pool",
+	}, []string{"ippool"})
+	poolBlocksFreeGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ipam_ippool_blocks_free",
+		Help: "Number of blocks that can still be allocated from the IP Pool",
+	}, []string{"ippool"})
+	poolAddressesInUseGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ipam_ippool_addresses_in_use",
+		Help: "Number of addresses in the IP Pool currently allocated to a workload or tunnel endpoint",
+	}, []string{"ippool"})
+	poolAddressesFreeGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ipam_ippool_addresses_free",
+		Help: "Number of addresses in the IP Pool that aren't allocated, whether or not they are in an allocated block",
+	}, []string{"ippool"})
+	prometheus.MustRegister(poolBlocksGauge, poolBlocksInUseGauge, poolBlocksFreeGauge, poolAddressesInUseGauge, poolAddressesFreeGauge)
+
 	// Total IP allocations.
 	legacyAllocationsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "ipam_allocations_per_node",
@@ -151,6 +180,7 @@ func NewIPAMController(cfg config.NodeControllerConfig, c client.Interface, cs k
 		confirmedLeaks:              make(map[string]*allocation),
 		nodesByBlock:                make(map[string]string),
 		blocksByNode:                make(map[string]map[string]bool),
+		affinitiesByNode:            make(map[string]map[string]bool),
 		emptyBlocks:                 make(map[string]string),
 		poolManager:                 newPoolManager(),
 		datastoreReady:              true,
@@ -191,6 +221,7 @@ type ipamController struct {
 	handleTracker       *handleTracker
 	nodesByBlock        map[string]string
 	blocksByNode        map[string]map[string]bool
+	affinitiesByNode    map[string]map[string]bool
 	emptyBlocks         map[string]string
 	confirmedLeaks      map[string]*allocation
 	poolManager         *poolManager
@@ -209,6 +240,7 @@ func (c *ipamController) Start(stop chan struct{}) {
 
 func (c *ipamController) RegisterWith(f *DataFeed) {
 	f.RegisterForNotification(model.BlockKey{}, c.onUpdate)
+	f.RegisterForNotification(model.BlockAffinityKey{}, c.onUpdate)
 	f.RegisterForNotification(model.ResourceKey{}, c.onUpdate)
 	f.RegisterForSyncStatus(c.onStatusUpdate)
 }
@@ -224,7 +256,7 @@ func (c *ipamController) onUpdate(update bapi.Update) {
 		case libapiv3.KindNode, apiv3.KindIPPool, apiv3.KindClusterInformation:
 			c.syncerUpdates <- update.KVPair
 		}
-	case model.BlockKey:
+	case model.BlockKey, model.BlockAffinityKey:
 		c.syncerUpdates <- update.KVPair
 	default:
 		log.Warnf("Unexpected kind received over syncer: %s", update.KVPair.Key)
@@ -333,6 +365,9 @@ func (c *ipamController) handleUpdate(upd interface{}) {
 		case model.BlockKey:
 			c.handleBlockUpdate(upd)
 			return
+		case model.BlockAffinityKey:
+			c.handleAffinityUpdate(upd)
+			return
 		}
 	}
 	log.WithField("update", upd).Warn("Unexpected update received")
@@ -347,6 +382,25 @@ func (c *ipamController) handleBlockUpdate(kvp model.KVPair) {
 	}
 }
 
+// handleAffinityUpdate wraps up the logic to execute when receiving a block affinity update. Affinities
+// are tracked separately from blocks so that we can find nodes with affinities whose blocks don't exist,
+// for example because the node was deleted part way through claiming a block.
+func (c *ipamController) handleAffinityUpdate(kvp model.KVPair) {
+	key := kvp.Key.(model.BlockAffinityKey)
+	blockCIDR := key.CIDR.String()
+	if kvp.Value != nil {
+		if _, ok := c.affinitiesByNode[key.Host]; !ok {
+			c.affinitiesByNode[key.Host] = map[string]bool{}
+		}
+		c.affinitiesByNode[key.Host][blockCIDR] = true
+	} else {
+		delete(c.affinitiesByNode[key.Host], blockCIDR)
+		if len(c.affinitiesByNode[key.Host]) == 0 {
+			delete(c.affinitiesByNode, key.Host)
+		}
+	}
+}
+
 // handleNodeUpdate wraps up the logic to execute when receiving a node update.
 func (c *ipamController) handleNodeUpdate(kvp model.KVPair) {
 	if kvp.Value != nil {
@@ -617,6 +671,8 @@ func (c *ipamController) updateMetrics() {
 		updatePoolGaugeWithNodeValues(gcCandidateGauges, poolName, gcCandidatesByNode)
 	}
 
+	c.updatePoolUtilizationMetrics()
+
 	// Update legacy gauges
 	legacyAllocationsGauge.Reset()
 	for node, allocations := range c.allocationsByNode {
@@ -712,7 +768,7 @@ func (c *ipamController) checkEmptyBlocks() error {
 // - It has been a leak candidate for >= the grace period.
 // - It is a leak candidate and it's node has been deleted.
 //
-// A node's affinities should be released when:
+// A node's affinities, including any affinities whose blocks don't exist, should be released when:
 // - The node no longer exists in the Kubernetes API, AND
 // - There are no longer any IP allocations on the node, OR
 // - The remaining IP allocations on the node are all determined to be leaked IP addresses.
@@ -726,6 +782,11 @@ func (c *ipamController) checkAllocations() ([]string, error) {
 		// if they have no allocations.
 		nodesAndAllocations[node] = nil
 	}
+	for node := range c.affinitiesByNode {
+		// For each node with a block affinity, add an entry. This makes sure we consider
+		// affinities that were left behind without a block.
+		nodesAndAllocations[node] = nil
+	}
 	for node, allocations := range c.allocationsByNode {
 		// For each allocation, add an entry. This make sure we consider them even
 		// if the node has no affine blocks.
@@ -1270,6 +1331,50 @@ func publishPoolSizeMetric(pool *apiv3.IPPool) {
 
 func clearPoolSizeMetric(poolName string) {
 	poolSizeGauge.Delete(prometheus.Labels{"ippool": poolName})
+	for _, g := range []*prometheus.GaugeVec{poolBlocksGauge, poolBlocksInUseGauge, poolBlocksFreeGauge, poolAddressesInUseGauge, poolAddressesFreeGauge} {
+		g.Delete(prometheus.Labels{"ippool": poolName})
+	}
+}
+
+// updatePoolUtilizationMetrics publishes the number of blocks and addresses used and free in each
+// IP pool. Blocks that don't belong to any pool aren't counted.
+func (c *ipamController) updatePoolUtilizationMetrics() {
+	for poolName, pool := range c.poolManager.allPools {
+		_, poolNet, err := cnet.ParseCIDR(pool.Spec.CIDR)
+		if err != nil {
+			log.WithError(err).Warnf("Unable to parse CIDR for IP Pool %s", poolName)
+			continue
+		}
+		ones, bits := poolNet.Mask.Size()
+		poolSize := math.Pow(2, float64(bits-ones))
+		totalBlocks := 1.0
+		if pool.Spec.BlockSize > ones {
+			totalBlocks = math.Pow(2, float64(pool.Spec.BlockSize-ones))
+		}
+
+		// Count every allocated address, including those without a handle, which aren't tracked
+		// as allocations.
+		blocksInUse, addressesInUse := 0, 0
+		for blockCIDR := range c.poolManager.blocksByPool[poolName] {
+			kvp, ok := c.allBlocks[blockCIDR]
+			if !ok {
+				continue
+			}
+			blocksInUse++
+			for _, idx := range kvp.Value.(*model.AllocationBlock).Allocations {
+				if idx != nil {
+					addressesInUse++
+				}
+			}
+		}
+
+		labels := prometheus.Labels{"ippool": poolName}
+		poolBlocksGauge.With(labels).Set(totalBlocks)
+		poolBlocksInUseGauge.With(labels).Set(float64(blocksInUse))
+		poolBlocksFreeGauge.With(labels).Set(math.Max(totalBlocks-float64(blocksInUse), 0))
+		poolAddressesInUseGauge.With(labels).Set(float64(addressesInUse))
+		poolAddressesFreeGauge.With(labels).Set(math.Max(poolSize-float64(addressesInUse), 0))
+	}
 }
 
 // When we stop tracking a node, clear counters to prevent accumulation of stale metrics.
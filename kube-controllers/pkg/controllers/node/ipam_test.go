@@ -24,6 +24,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -554,6 +556,76 @@ var _ = Describe("IPAM controller UTs", func() {
 		}, 1*time.Second, 100*time.Millisecond).Should(Equal(map[string]map[string]bool{"ippool-2": {}}))
 	})
 
+	It("should publish IP pool utilization metrics", func() {
+		// Start the controller.
+		c.Start(stopChan)
+
+		// Add a pool with room for 4 blocks, and a block with 2 of its 4 addresses allocated.
+		pool := apiv3.IPPool{}
+		pool.Name = "utilization-pool"
+		pool.Spec.CIDR = "192.168.0.0/28"
+		pool.Spec.BlockSize = 30
+		c.onUpdate(bapi.Update{
+			KVPair:     model.KVPair{Key: model.ResourceKey{Name: pool.Name, Kind: apiv3.KindIPPool}, Value: &pool},
+			UpdateType: bapi.UpdateTypeKVNew,
+		})
+		idx := 0
+		handle := "utilization-handle"
+		cidr := net.MustParseCIDR("192.168.0.0/30")
+		b := model.AllocationBlock{
+			CIDR:        cidr,
+			Allocations: []*int{&idx, &idx, nil, nil},
+			Unallocated: []int{2, 3},
+			Attributes:  []model.AllocationAttribute{{AttrPrimary: &handle}},
+		}
+		c.onUpdate(bapi.Update{
+			KVPair:     model.KVPair{Key: model.BlockKey{CIDR: cidr}, Value: &b},
+			UpdateType: bapi.UpdateTypeKVNew,
+		})
+		Eventually(func() map[string]bool {
+			done := c.pause()
+			defer done()
+			return c.poolManager.blocksByPool[pool.Name]
+		}, 1*time.Second, 100*time.Millisecond).Should(HaveLen(1))
+
+		value := func(g *prometheus.GaugeVec) float64 {
+			m := &dto.Metric{}
+			Expect(g.WithLabelValues(pool.Name).Write(m)).To(Succeed())
+			return m.GetGauge().GetValue()
+		}
+		done := c.pause()
+		c.updateMetrics()
+		done()
+		Expect(value(poolBlocksGauge)).To(Equal(4.0))
+		Expect(value(poolBlocksInUseGauge)).To(Equal(1.0))
+		Expect(value(poolBlocksFreeGauge)).To(Equal(3.0))
+		Expect(value(poolAddressesInUseGauge)).To(Equal(2.0))
+		Expect(value(poolAddressesFreeGauge)).To(Equal(14.0))
+
+		// Deleting the pool removes its metrics.
+		c.onUpdate(bapi.Update{
+			KVPair:     model.KVPair{Key: model.ResourceKey{Name: pool.Name, Kind: apiv3.KindIPPool}},
+			UpdateType: bapi.UpdateTypeKVDeleted,
+		})
+		Eventually(func() bool {
+			mfs, err := prometheus.DefaultGatherer.Gather()
+			Expect(err).NotTo(HaveOccurred())
+			for _, mf := range mfs {
+				if mf.GetName() != "ipam_ippool_blocks" {
+					continue
+				}
+				for _, m := range mf.GetMetric() {
+					for _, l := range m.GetLabel() {
+						if l.GetName() == "ippool" && l.GetValue() == pool.Name {
+							return true
+						}
+					}
+				}
+			}
+			return false
+		}, 1*time.Second, 100*time.Millisecond).Should(BeFalse())
+	})
+
 	It("should handle node deletion properly", func() {
 		// Start the controller.
 		c.Start(stopChan)
@@ -613,6 +685,44 @@ var _ = Describe("IPAM controller UTs", func() {
 		}, assertionTimeout, 100*time.Millisecond).Should(BeTrue())
 	})
 
+	It("should release affinities without blocks for deleted nodes", func() {
+		// Start the controller.
+		c.Start(stopChan)
+
+		// Add a block affinity for a node that never existed, with no corresponding block.
+		cidr := net.MustParseCIDR("10.0.0.0/30")
+		kvp := model.KVPair{
+			Key:   model.BlockAffinityKey{CIDR: cidr, Host: "cnode"},
+			Value: &model.BlockAffinity{State: model.StatePending},
+		}
+		c.onUpdate(bapi.Update{KVPair: kvp, UpdateType: bapi.UpdateTypeKVNew})
+
+		// Wait for internal caches to update.
+		Eventually(func() bool {
+			done := c.pause()
+			defer done()
+			return c.affinitiesByNode["cnode"][cidr.String()]
+		}, 1*time.Second, 100*time.Millisecond).Should(BeTrue())
+
+		// Mark the syncer as InSync so that the GC will be enabled, and trigger a node deletion.
+		c.onStatusUpdate(bapi.InSync)
+		c.OnKubernetesNodeDeleted()
+
+		// Confirm the affinity was released.
+		fakeClient := cli.IPAM().(*fakeIPAMClient)
+		Eventually(func() bool {
+			return fakeClient.affinityReleased("cnode")
+		}, assertionTimeout, 100*time.Millisecond).Should(BeTrue())
+
+		// Deleting the affinity removes it from the cache.
+		c.onUpdate(bapi.Update{KVPair: model.KVPair{Key: kvp.Key}, UpdateType: bapi.UpdateTypeKVDeleted})
+		Eventually(func() int {
+			done := c.pause()
+			defer done()
+			return len(c.affinitiesByNode)
+		}, 1*time.Second, 100*time.Millisecond).Should(Equal(0))
+	})
+
 	It("should handle clusterinformation updates and maintain its clusterinformation datastoreReady cache", func() {
 		// Start the controller.
 		c.Start(stopChan)
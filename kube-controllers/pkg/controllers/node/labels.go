@@ -16,6 +16,7 @@ package node
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,10 +30,11 @@ import (
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
 )
 
-func NewNodeLabelController(c client.Interface) *nodeLabelController {
+func NewNodeLabelController(c client.Interface, prefixes []string) *nodeLabelController {
 	return &nodeLabelController{
 		nodemapper: map[string]string{},
 		client:     c,
+		prefixes:   prefixes,
 	}
 }
 
@@ -48,6 +50,9 @@ type nodeLabelController struct {
 
 	// For interacting with the Calico API to update nodes.
 	client client.Interface
+
+	// The prefixes of the keys of the labels to sync, or empty to sync all labels.
+	prefixes []string
 }
 
 func (c *nodeLabelController) RegisterWith(f *DataFeed) {
@@ -174,7 +179,8 @@ func (c *nodeLabelController) syncNodeLabels(node *v1.Node) {
 		// We've synced labels before. Determine diffs to apply.
 		// For each k/v in node.Labels, if it isn't present or the value
 		// differs, add it to the node.
-		for k, v := range node.Labels {
+		labels := c.labelsToSync(node.Labels)
+		for k, v := range labels {
 			if v2, ok := calNode.Labels[k]; !ok || v != v2 {
 				logrus.Debugf("Adding node label %s=%s", k, v)
 				calNode.Labels[k] = v
@@ -185,7 +191,7 @@ func (c *nodeLabelController) syncNodeLabels(node *v1.Node) {
 		// For each k/v that used to be in the k8s node labels, but is no longer,
 		// remove it from the Calico node.
 		for k, v := range oldLabels {
-			if _, ok := node.Labels[k]; !ok {
+			if _, ok := labels[k]; !ok {
 				// The old label is no longer present. Remove it.
 				logrus.Debugf("Deleting node label %s=%s", k, v)
 				delete(calNode.Labels, k)
@@ -194,7 +200,7 @@ func (c *nodeLabelController) syncNodeLabels(node *v1.Node) {
 		}
 
 		// Set the annotation to the correct values.
-		bytes, err := json.Marshal(node.Labels)
+		bytes, err := json.Marshal(labels)
 		if err != nil {
 			logrus.WithError(err).Errorf("Error marshalling node labels")
 			return
@@ -214,3 +220,21 @@ func (c *nodeLabelController) syncNodeLabels(node *v1.Node) {
 	}
 	logrus.Errorf("Too many retries when updating node")
 }
+
+// labelsToSync returns the labels of a Kubernetes node whose keys start with one of the configured
+// prefixes, or all of the labels if no prefixes are configured.
+func (c *nodeLabelController) labelsToSync(labels map[string]string) map[string]string {
+	if len(c.prefixes) == 0 {
+		return labels
+	}
+	filtered := map[string]string{}
+	for k, v := range labels {
+		for _, p := range c.prefixes {
+			if strings.HasPrefix(k, p) {
+				filtered[k] = v
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+var _ = Describe("Node label controller UTs", func() {
+	var cli *FakeCalicoClient
+
+	BeforeEach(func() {
+		cli = NewFakeCalicoClient()
+		cn := libapiv3.NewNode()
+		cn.Name = "cnode"
+		cn.Labels = map[string]string{"user": "label"}
+		_, err := cli.Nodes().Create(context.Background(), cn, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	kubernetesNode := func(labels map[string]string) *v1.Node {
+		return &v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "knode", Labels: labels}}
+	}
+
+	calicoNode := func() *libapiv3.Node {
+		n, err := cli.Nodes().Get(context.Background(), "cnode", options.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		return n
+	}
+
+	It("should only sync labels with the configured prefixes", func() {
+		c := NewNodeLabelController(cli, []string{"topology.kubernetes.io/", "example.com/rack"})
+		c.nodemapper["knode"] = "cnode"
+
+		c.syncNodeLabels(kubernetesNode(map[string]string{
+			"topology.kubernetes.io/zone": "zone-a",
+			"example.com/rack":            "r1",
+			"kubernetes.io/hostname":      "knode",
+		}))
+		Expect(calicoNode().Labels).To(Equal(map[string]string{
+			"user":                        "label",
+			"topology.kubernetes.io/zone": "zone-a",
+			"example.com/rack":            "r1",
+		}))
+
+		// Only the synced labels are recorded, so removing them from the Kubernetes node removes
+		// them from the Calico node, leaving the other labels in place.
+		synced := map[string]string{}
+		Expect(json.Unmarshal([]byte(calicoNode().Annotations[nodeLabelAnnotation]), &synced)).To(Succeed())
+		Expect(synced).To(HaveLen(2))
+
+		c.syncNodeLabels(kubernetesNode(map[string]string{"example.com/rack": "r2"}))
+		Expect(calicoNode().Labels).To(Equal(map[string]string{
+			"user":             "label",
+			"example.com/rack": "r2",
+		}))
+	})
+
+	It("should sync all labels when no prefixes are configured", func() {
+		c := NewNodeLabelController(cli, nil)
+		c.nodemapper["knode"] = "cnode"
+
+		c.syncNodeLabels(kubernetesNode(map[string]string{"kubernetes.io/hostname": "knode"}))
+		Expect(calicoNode().Labels).To(Equal(map[string]string{
+			"user":                   "label",
+			"kubernetes.io/hostname": "knode",
+		}))
+	})
+})
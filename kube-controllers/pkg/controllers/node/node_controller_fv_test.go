@@ -556,6 +556,42 @@ var _ = Describe("Calico node controller FV tests (etcd mode)", func() {
 			}, time.Second*15, 500*time.Millisecond).ShouldNot(BeNil())
 		})
 
+		It("should clean up weps whose node no longer exists", func() {
+			// Create a WEP on a node that exists neither in Calico nor in Kubernetes.
+			wep := libapi.WorkloadEndpoint{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "orphanednode-k8s-mypod-eth0",
+					Namespace: "default",
+				},
+				Spec: libapi.WorkloadEndpointSpec{
+					InterfaceName: "eth0",
+					Pod:           "mypod",
+					Endpoint:      "eth0",
+					IPNetworks:    []string{"192.168.0.1/32"},
+					Node:          "orphanednode",
+					Orchestrator:  "k8s",
+				},
+			}
+			_, err := calicoClient.WorkloadEndpoints().Create(context.Background(), &wep, options.SetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Trigger a sync by creating and deleting a Kubernetes node.
+			kn := &v1.Node{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: kNodeName,
+				},
+			}
+			_, err = k8sClient.CoreV1().Nodes().Create(context.Background(), kn, metav1.CreateOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.CoreV1().Nodes().Delete(context.Background(), kNodeName, metav1.DeleteOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() error {
+				_, err := calicoClient.WorkloadEndpoints().Get(context.Background(), "default", wep.Name, options.GetOptions{})
+				return err
+			}, time.Second*15, 500*time.Millisecond).Should(BeAssignableToTypeOf(errors.ErrorResourceDoesNotExist{}))
+		})
+
 		It("should clean up weps, IPAM allocations, etc. when deleting a node", func() {
 			// Create the node in the Kubernetes API.
 			kn := &v1.Node{
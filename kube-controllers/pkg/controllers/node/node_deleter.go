@@ -17,6 +17,7 @@ import (
 	"context"
 	"time"
 
+	apiv3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -28,8 +29,8 @@ import (
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
 )
 
-// NewNodeDeletionController creates a new controller responsible for garbage collection Calico node objects
-// in etcd mode when their corresponding Kubernetes node is deleted.
+// NewNodeDeletionController creates a new controller responsible for garbage collection Calico node objects,
+// and the workload endpoints on them, in etcd mode when their corresponding Kubernetes node is deleted.
 func NewNodeDeletionController(client client.Interface, cs *kubernetes.Clientset) *nodeDeleter {
 	return &nodeDeleter{
 		clientset: cs,
@@ -81,6 +82,10 @@ func (c *nodeDeleter) deleteStaleNodes() error {
 	for _, node := range kNodes.Items {
 		kNodeIdx[node.Name] = true
 	}
+	cNodeIdx := make(map[string]bool)
+	for _, node := range cNodes.Items {
+		cNodeIdx[node.Name] = true
+	}
 
 	for _, node := range cNodes.Items {
 		k8sNodeName, err := getK8sNodeName(node)
@@ -112,5 +117,70 @@ func (c *nodeDeleter) deleteStaleNodes() error {
 			c.rl.Forget(rlKey)
 		}
 	}
-	return nil
+	return c.deleteOrphanedWorkloadEndpoints(cNodeIdx, kNodeIdx)
+}
+
+// deleteOrphanedWorkloadEndpoints deletes Kubernetes workload endpoints whose node exists neither in the
+// Calico datastore nor in Kubernetes. Deleting a Calico node deletes its workload endpoints, but if the
+// Calico node was removed some other way, its workload endpoints are left behind, since kubelet never
+// gets a chance to clean them up.
+func (c *nodeDeleter) deleteOrphanedWorkloadEndpoints(cNodeIdx, kNodeIdx map[string]bool) error {
+	time.Sleep(c.rl.When(RateLimitCalicoList))
+	weps, err := c.client.WorkloadEndpoints().List(context.TODO(), options.ListOptions{})
+	if err != nil {
+		log.WithError(err).Error("Error listing Calico workload endpoints")
+		return err
+	}
+	c.rl.Forget(RateLimitCalicoList)
+
+	// Whether each node referenced by an orphaned workload endpoint has been confirmed as missing.
+	confirmed := make(map[string]bool)
+	var storedErr error
+	for _, wep := range weps.Items {
+		node := wep.Spec.Node
+		if wep.Spec.Orchestrator != apiv3.OrchestratorKubernetes || node == "" || cNodeIdx[node] || kNodeIdx[node] {
+			continue
+		}
+		logc := log.WithFields(log.Fields{"node": node, "workloadEndpoint": wep.Namespace + "/" + wep.Name})
+
+		missing, ok := confirmed[node]
+		if !ok {
+			// Re-confirm that the node is actually missing, in case it was created since the List() calls.
+			rlKey := rateLimiterItemKey{Type: RateLimitCalicoDelete, Name: node}
+			time.Sleep(c.rl.When(rlKey))
+			missing, err = c.nodeIsMissing(node)
+			if err != nil {
+				logc.WithError(err).Warn("Error checking whether workload endpoint's node exists")
+				storedErr = err
+				continue
+			}
+			c.rl.Forget(rlKey)
+			confirmed[node] = missing
+		}
+		if !missing {
+			continue
+		}
+
+		logc.Info("Deleting workload endpoint for deleted node")
+		_, err = c.client.WorkloadEndpoints().Delete(context.TODO(), wep.Namespace, wep.Name, options.DeleteOptions{})
+		if _, doesNotExist := err.(cerrors.ErrorResourceDoesNotExist); err != nil && !doesNotExist {
+			// Store the error, but carry on with the other workload endpoints.
+			logc.WithError(err).Error("Error deleting workload endpoint")
+			storedErr = err
+		}
+	}
+	return storedErr
+}
+
+// nodeIsMissing returns true if the named node exists neither in the Calico datastore nor in Kubernetes.
+func (c *nodeDeleter) nodeIsMissing(name string) (bool, error) {
+	_, err := c.client.Nodes().Get(context.TODO(), name, options.GetOptions{})
+	if _, doesNotExist := err.(cerrors.ErrorResourceDoesNotExist); !doesNotExist {
+		return false, err
+	}
+	_, err = c.clientset.CoreV1().Nodes().Get(context.TODO(), name, metav1.GetOptions{})
+	if !errors.IsNotFound(err) {
+		return false, err
+	}
+	return true, nil
 }
@@ -0,0 +1,324 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+const (
+	// routeReflectorSourceKind identifies the BGP peers created for route reflectors in their
+	// ownership labels.
+	routeReflectorSourceKind = "RouteReflector"
+
+	// The BGP peers that make up the route reflector topology: every other node peers with the
+	// route reflectors, and the route reflectors peer with each other.
+	routeReflectorClientPeerName = "krr-peer-with-route-reflectors"
+	routeReflectorMeshPeerName   = "krr-route-reflector-mesh"
+
+	// meshDisabledAnnotation is set on the default BGPConfiguration when the controller disables
+	// the node-to-node mesh, so that it only ever re-enables a mesh that it disabled.
+	meshDisabledAnnotation = "projectcalico.org/mesh-disabled-by"
+	meshDisabledValue      = "calico-kube-controllers"
+
+	defaultBGPConfigurationName = "default"
+)
+
+// routeReflectorNode is the state of a Calico node that the route reflector controller cares about.
+type routeReflectorNode struct {
+	reflector bool
+	hasBGP    bool
+	clusterID string
+}
+
+// routeReflectorController configures the nodes with the configured label as BGP route reflectors,
+// and maintains the BGP topology to match: while there are route reflectors, all other nodes peer
+// with them instead of with each other in a full mesh.
+type routeReflectorController struct {
+	sync.Mutex
+	client    client.Interface
+	label     string
+	clusterID string
+	rl        workqueue.RateLimiter
+
+	// The nodes, by Calico node name, learned from the syncer.
+	nodes  map[string]routeReflectorNode
+	inSync bool
+
+	kickChan chan interface{}
+}
+
+func NewRouteReflectorController(cfg config.NodeControllerConfig, c client.Interface) *routeReflectorController {
+	return &routeReflectorController{
+		client:    c,
+		label:     cfg.RouteReflectorLabel,
+		clusterID: cfg.RouteReflectorClusterID,
+		rl:        workqueue.DefaultControllerRateLimiter(),
+		nodes:     map[string]routeReflectorNode{},
+		kickChan:  make(chan interface{}, 1),
+	}
+}
+
+func (c *routeReflectorController) RegisterWith(f *DataFeed) {
+	// We want nodes, which are sent with key model.ResourceKey
+	f.RegisterForNotification(model.ResourceKey{}, c.onUpdate)
+	f.RegisterForSyncStatus(c.onStatusUpdate)
+}
+
+func (c *routeReflectorController) onStatusUpdate(s bapi.SyncStatus) {
+	if s == bapi.InSync {
+		c.Lock()
+		c.inSync = true
+		c.Unlock()
+		kick(c.kickChan)
+	}
+}
+
+// onUpdate tracks the state of each node, and triggers a sync when it changes. Nodes are updated
+// often, so other changes are ignored.
+func (c *routeReflectorController) onUpdate(update bapi.Update) {
+	key, ok := update.KVPair.Key.(model.ResourceKey)
+	if !ok || key.Kind != libapi.KindNode {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	old, existed := c.nodes[key.Name]
+	if n, ok := update.Value.(*libapi.Node); ok && n != nil {
+		state := routeReflectorNode{}
+		_, state.reflector = n.Labels[c.label]
+		if n.Spec.BGP != nil {
+			state.hasBGP = true
+			state.clusterID = n.Spec.BGP.RouteReflectorClusterID
+		}
+		c.nodes[key.Name] = state
+		if existed && old == state {
+			return
+		}
+	} else {
+		if !existed {
+			return
+		}
+		delete(c.nodes, key.Name)
+	}
+	if c.inSync {
+		kick(c.kickChan)
+	}
+}
+
+// Start starts the controller's sync loop, which retries failed syncs with backoff.
+func (c *routeReflectorController) Start(stopCh chan struct{}) {
+	go func() {
+		for {
+			select {
+			case <-stopCh:
+				return
+			case <-c.kickChan:
+				if err := c.sync(context.Background()); err != nil {
+					delay := c.rl.When("sync")
+					logrus.WithError(err).Warnf("Failed to sync route reflectors, retrying in %v", delay)
+					time.AfterFunc(delay, func() { kick(c.kickChan) })
+					continue
+				}
+				c.rl.Forget("sync")
+			}
+		}
+	}()
+}
+
+// sync configures the route reflector cluster ID of each node, then the BGP topology. The mesh is
+// only disabled once the route reflectors and the peerings with them are in place, and re-enabled
+// before the peerings are removed.
+func (c *routeReflectorController) sync(ctx context.Context) error {
+	c.Lock()
+	nodes := make(map[string]routeReflectorNode, len(c.nodes))
+	for name, n := range c.nodes {
+		nodes[name] = n
+	}
+	c.Unlock()
+
+	reflectors := 0
+	for name, n := range nodes {
+		want := ""
+		if n.reflector {
+			if !n.hasBGP {
+				logrus.WithField("node", name).Warn("Node is labelled as a route reflector, but doesn't run BGP")
+				continue
+			}
+			want = c.clusterID
+			reflectors++
+		}
+		if n.clusterID == want || (want == "" && n.clusterID != c.clusterID) {
+			// Up to date, or a route reflector that was configured by hand.
+			continue
+		}
+		if err := c.setClusterID(ctx, name, want); err != nil {
+			return err
+		}
+	}
+
+	if reflectors > 0 {
+		if err := c.setPeer(ctx, routeReflectorClientPeerName, fmt.Sprintf("!has(%s)", c.label)); err != nil {
+			return err
+		}
+		if err := c.setPeer(ctx, routeReflectorMeshPeerName, fmt.Sprintf("has(%s)", c.label)); err != nil {
+			return err
+		}
+		return c.setMesh(ctx, false)
+	}
+	if err := c.setMesh(ctx, true); err != nil {
+		return err
+	}
+	for _, name := range []string{routeReflectorClientPeerName, routeReflectorMeshPeerName} {
+		if err := c.deletePeer(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setClusterID sets the route reflector cluster ID of the node, making it a route reflector, or
+// clears it if the ID is empty.
+func (c *routeReflectorController) setClusterID(ctx context.Context, name, clusterID string) error {
+	n, err := c.client.Nodes().Get(ctx, name, options.GetOptions{})
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+			return nil
+		}
+		return err
+	}
+	if n.Spec.BGP == nil {
+		return nil
+	}
+	logrus.WithFields(logrus.Fields{"node": name, "clusterID": clusterID}).Info("Updating route reflector cluster ID of node")
+	n.Spec.BGP.RouteReflectorClusterID = clusterID
+	_, err = c.client.Nodes().Update(ctx, n, options.SetOptions{})
+	return err
+}
+
+// setPeer makes sure that the named BGP peer, which peers the nodes matching the given selector
+// with the route reflectors, exists. BGP peers that weren't created by the controller are left
+// alone, but are reported as an error so that the mesh isn't disabled in favour of peerings that
+// may not be in place.
+func (c *routeReflectorController) setPeer(ctx context.Context, name, nodeSelector string) error {
+	spec := api.BGPPeerSpec{
+		NodeSelector: nodeSelector,
+		PeerSelector: fmt.Sprintf("has(%s)", c.label),
+	}
+	p, err := c.client.BGPPeers().Get(ctx, name, options.GetOptions{})
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+			return err
+		}
+		p = api.NewBGPPeer()
+		p.Name = name
+		p.Spec = spec
+		converter.SetOwnership(&p.ObjectMeta, routeReflectorSourceKind, "")
+		logrus.WithField("peer", name).Info("Creating BGP peer for route reflectors")
+		_, err = c.client.BGPPeers().Create(ctx, p, options.SetOptions{})
+		return err
+	}
+	if !converter.IsManaged(p.ObjectMeta, routeReflectorSourceKind) {
+		return fmt.Errorf("BGP peer %s for route reflectors already exists, and isn't managed by the controller", name)
+	}
+	if p.Spec.NodeSelector == spec.NodeSelector && p.Spec.PeerSelector == spec.PeerSelector {
+		return nil
+	}
+	logrus.WithField("peer", name).Info("Updating BGP peer for route reflectors")
+	p.Spec.NodeSelector = spec.NodeSelector
+	p.Spec.PeerSelector = spec.PeerSelector
+	_, err = c.client.BGPPeers().Update(ctx, p, options.SetOptions{})
+	return err
+}
+
+// deletePeer deletes the named BGP peer, if it was created by the controller.
+func (c *routeReflectorController) deletePeer(ctx context.Context, name string) error {
+	p, err := c.client.BGPPeers().Get(ctx, name, options.GetOptions{})
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+			return nil
+		}
+		return err
+	}
+	if !converter.IsManaged(p.ObjectMeta, routeReflectorSourceKind) {
+		return nil
+	}
+	logrus.WithField("peer", name).Info("Deleting BGP peer for route reflectors")
+	_, err = c.client.BGPPeers().Delete(ctx, name, options.DeleteOptions{ResourceVersion: p.ResourceVersion})
+	if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+		return nil
+	}
+	return err
+}
+
+// setMesh enables or disables the node-to-node mesh in the default BGPConfiguration. A mesh is
+// only enabled again if the controller disabled it, so a mesh disabled by the user stays disabled.
+func (c *routeReflectorController) setMesh(ctx context.Context, enabled bool) error {
+	bc, err := c.client.BGPConfigurations().Get(ctx, defaultBGPConfigurationName, options.GetOptions{})
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+			return err
+		}
+		if enabled {
+			// The mesh is enabled by default.
+			return nil
+		}
+		bc = api.NewBGPConfiguration()
+		bc.Name = defaultBGPConfigurationName
+		bc.Annotations = map[string]string{meshDisabledAnnotation: meshDisabledValue}
+		bc.Spec.NodeToNodeMeshEnabled = &enabled
+		logrus.Info("Disabling node-to-node BGP mesh in favour of route reflectors")
+		_, err = c.client.BGPConfigurations().Create(ctx, bc, options.SetOptions{})
+		return err
+	}
+
+	meshEnabled := bc.Spec.NodeToNodeMeshEnabled == nil || *bc.Spec.NodeToNodeMeshEnabled
+	if enabled {
+		if meshEnabled || bc.Annotations[meshDisabledAnnotation] != meshDisabledValue {
+			return nil
+		}
+		logrus.Info("Enabling node-to-node BGP mesh, since there are no route reflectors")
+		delete(bc.Annotations, meshDisabledAnnotation)
+	} else {
+		if !meshEnabled {
+			return nil
+		}
+		logrus.Info("Disabling node-to-node BGP mesh in favour of route reflectors")
+		if bc.Annotations == nil {
+			bc.Annotations = map[string]string{}
+		}
+		bc.Annotations[meshDisabledAnnotation] = meshDisabledValue
+	}
+	bc.Spec.NodeToNodeMeshEnabled = &enabled
+	_, err = c.client.BGPConfigurations().Update(ctx, bc, options.SetOptions{})
+	return err
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package node
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	libapiv3 "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+var _ = Describe("Route reflector controller UTs", func() {
+	const label = "example.com/route-reflector"
+	var (
+		cli *FakeCalicoClient
+		c   *routeReflectorController
+		ctx = context.Background()
+	)
+
+	BeforeEach(func() {
+		cli = NewFakeCalicoClient()
+		c = NewRouteReflectorController(config.NodeControllerConfig{
+			RouteReflectorLabel:     label,
+			RouteReflectorClusterID: "244.0.0.1",
+		}, cli)
+		for _, name := range []string{"rr", "worker"} {
+			n := libapiv3.NewNode()
+			n.Name = name
+			n.Spec.BGP = &libapiv3.NodeBGPSpec{IPv4Address: "192.168.0.1/24"}
+			_, err := cli.Nodes().Create(ctx, n, options.SetOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			c.nodes[name] = routeReflectorNode{hasBGP: true}
+		}
+	})
+
+	clusterID := func(name string) string {
+		n, err := cli.Nodes().Get(ctx, name, options.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		return n.Spec.BGP.RouteReflectorClusterID
+	}
+
+	peerExists := func(name string) bool {
+		_, err := cli.BGPPeers().Get(ctx, name, options.GetOptions{})
+		return err == nil
+	}
+
+	bgpConfig := func() *api.BGPConfiguration {
+		bc, err := cli.BGPConfigurations().Get(ctx, defaultBGPConfigurationName, options.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		return bc
+	}
+
+	It("should configure route reflectors and remove them again", func() {
+		c.nodes["rr"] = routeReflectorNode{reflector: true, hasBGP: true}
+		Expect(c.sync(ctx)).To(Succeed())
+
+		Expect(clusterID("rr")).To(Equal("244.0.0.1"))
+		Expect(clusterID("worker")).To(Equal(""))
+		Expect(peerExists(routeReflectorClientPeerName)).To(BeTrue())
+		Expect(peerExists(routeReflectorMeshPeerName)).To(BeTrue())
+		Expect(*bgpConfig().Spec.NodeToNodeMeshEnabled).To(BeFalse())
+		Expect(bgpConfig().Annotations).To(HaveKeyWithValue(meshDisabledAnnotation, meshDisabledValue))
+
+		c.nodes["rr"] = routeReflectorNode{hasBGP: true, clusterID: "244.0.0.1"}
+		Expect(c.sync(ctx)).To(Succeed())
+
+		Expect(clusterID("rr")).To(Equal(""))
+		Expect(peerExists(routeReflectorClientPeerName)).To(BeFalse())
+		Expect(peerExists(routeReflectorMeshPeerName)).To(BeFalse())
+		Expect(*bgpConfig().Spec.NodeToNodeMeshEnabled).To(BeTrue())
+		Expect(bgpConfig().Annotations).NotTo(HaveKey(meshDisabledAnnotation))
+	})
+
+	It("should leave route reflectors configured by hand alone", func() {
+		c.nodes["worker"] = routeReflectorNode{hasBGP: true, clusterID: "10.0.0.1"}
+		n, err := cli.Nodes().Get(ctx, "worker", options.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		n.Spec.BGP.RouteReflectorClusterID = "10.0.0.1"
+		_, err = cli.Nodes().Update(ctx, n, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(clusterID("worker")).To(Equal("10.0.0.1"))
+	})
+
+	It("should not enable a mesh that was disabled by the user", func() {
+		disabled := false
+		bc := api.NewBGPConfiguration()
+		bc.Name = defaultBGPConfigurationName
+		bc.Spec.NodeToNodeMeshEnabled = &disabled
+		_, err := cli.BGPConfigurations().Create(ctx, bc, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		c.nodes["rr"] = routeReflectorNode{reflector: true, hasBGP: true}
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(bgpConfig().Annotations).NotTo(HaveKey(meshDisabledAnnotation))
+
+		c.nodes["rr"] = routeReflectorNode{hasBGP: true, clusterID: "244.0.0.1"}
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(*bgpConfig().Spec.NodeToNodeMeshEnabled).To(BeFalse())
+	})
+
+	It("should leave BGP peers that it didn't create alone", func() {
+		p := api.NewBGPPeer()
+		p.Name = routeReflectorMeshPeerName
+		p.Spec.NodeSelector = "all()"
+		_, err := cli.BGPPeers().Create(ctx, p, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(c.sync(ctx)).To(Succeed())
+		Expect(peerExists(routeReflectorMeshPeerName)).To(BeTrue())
+	})
+	It("should not disable the mesh if a BGP peer that it didn't create is in the way", func() {
+		p := api.NewBGPPeer()
+		p.Name = routeReflectorClientPeerName
+		p.Spec.NodeSelector = "all()"
+		_, err := cli.BGPPeers().Create(ctx, p, options.SetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+
+		c.nodes["rr"] = routeReflectorNode{reflector: true, hasBGP: true}
+		Expect(c.sync(ctx)).NotTo(Succeed())
+
+		p, err = cli.BGPPeers().Get(ctx, routeReflectorClientPeerName, options.GetOptions{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Spec.NodeSelector).To(Equal("all()"))
+		_, err = cli.BGPConfigurations().Get(ctx, defaultBGPConfigurationName, options.GetOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+})
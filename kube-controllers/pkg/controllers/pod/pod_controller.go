@@ -26,10 +26,13 @@ import (
 	"github.com/projectcalico/calico/kube-controllers/pkg/config"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
 	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	"github.com/projectcalico/calico/kube-controllers/pkg/dryrun"
+	"github.com/projectcalico/calico/kube-controllers/pkg/tracing"
 
 	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 
 	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
@@ -49,11 +52,15 @@ type WorkloadEndpointCache struct {
 // and syncing them to the Calico datastore as WorkloadEndpoints.
 type podController struct {
 	informer              cache.Controller
-	resourceCache         rcache.ResourceCache
+	resourceCache         rcache.ResourceCache[converter.WorkloadEndpointData]
 	calicoClient          client.Interface
 	workloadEndpointCache *WorkloadEndpointCache
 	ctx                   context.Context
 	cfg                   config.GenericControllerConfig
+	planner               *dryrun.Planner
+
+	// Syncs keys from the resource cache, limiting retries of failed syncs to a fraction of all syncs.
+	worker *controller.Worker
 }
 
 // NewPodController returns a controller which manages Pod objects.
@@ -61,7 +68,7 @@ func NewPodController(ctx context.Context, k8sClientset *kubernetes.Clientset, c
 	podConverter := converter.NewPodConverter()
 
 	// Function returns map of key->WorkloadEndpointData from the Calico datastore.
-	listFunc := func() (map[string]interface{}, error) {
+	listFunc := func() (map[string]converter.WorkloadEndpointData, error) {
 		// Get all workloadEndpoints for kubernetes orchestrator from the Calico datastore
 		workloadEndpoints, err := c.WorkloadEndpoints().List(ctx, options.ListOptions{})
 		if err != nil {
@@ -69,7 +76,7 @@ func NewPodController(ctx context.Context, k8sClientset *kubernetes.Clientset, c
 		}
 
 		// Iterate through and collect data from workload endpoints that we care about.
-		m := make(map[string]interface{})
+		m := make(map[string]converter.WorkloadEndpointData)
 		for _, wep := range workloadEndpoints.Items {
 			// We only care about Kubernetes workload endpoints.
 			if wep.Spec.Orchestrator == api.OrchestratorKubernetes {
@@ -84,9 +91,12 @@ func NewPodController(ctx context.Context, k8sClientset *kubernetes.Clientset, c
 		return m, nil
 	}
 
-	cacheArgs := rcache.ResourceCacheArgs{
-		ListFunc:   listFunc,
-		ObjectType: reflect.TypeOf(converter.WorkloadEndpointData{}),
+	cacheArgs := rcache.ResourceCacheArgs[converter.WorkloadEndpointData]{
+		ControllerName: "workloadendpoint",
+		Shards:         cfg.NumberOfWorkers,
+		MaxQueueDepth:  cfg.MaxQueueDepth,
+		CoalesceWindow: cfg.CoalesceWindow,
+		ListFunc:       listFunc,
 
 		// We don't handle the cases where data is missing in the cache
 		// or in the datastore, so disable those events in the reconciler. They
@@ -94,16 +104,22 @@ func NewPodController(ctx context.Context, k8sClientset *kubernetes.Clientset, c
 		ReconcilerConfig: rcache.ReconcilerConfig{
 			DisableMissingInCache:     true,
 			DisableMissingInDatastore: true,
+			MaxReconcilerPeriod:       cfg.MaxReconcilerPeriod,
+			ReconcilerJitter:          cfg.ReconcilerJitter,
 		},
 	}
 
 	resourceCache := rcache.NewResourceCache(cacheArgs)
 	workloadEndpointCache := WorkloadEndpointCache{m: make(map[string]libapi.WorkloadEndpoint)}
+	conversionErrors := controller.NewConversionErrors("Pod")
 
 	// Bind the Calico cache to kubernetes cache with the help of an informer. This way we make sure that
 	// whenever the kubernetes cache is updated, changes get reflected in the Calico cache as well.
 	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
+			spanCtx, span := tracing.StartEvent("workloadendpoint", "add", obj)
+			defer span.End()
+
 			key, err := cache.MetaNamespaceKeyFunc(obj)
 			if err != nil {
 				log.WithError(err).Error("Failed to generate key")
@@ -125,20 +141,29 @@ func NewPodController(ctx context.Context, k8sClientset *kubernetes.Clientset, c
 				return
 			}
 
+			_, convertSpan := tracing.Start(spanCtx, "convert")
 			wepDataList, err := podConverter.Convert(obj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error while converting %v to wep.", key)
+				conversionErrors.Record(obj, err)
 				return
 			}
+			conversionErrors.Clear(obj)
 
 			// Prime the cache - we only need to make changes to the datastore when the controller
 			// receives an update, because initial state is written to the datastore by the CNI plugin.
+			_, cacheSpan := tracing.Start(spanCtx, "cache prime")
 			for _, wepData := range wepDataList {
 				k := podConverter.GetKey(wepData)
 				resourceCache.Prime(k, wepData)
 			}
+			cacheSpan.End()
 		},
 		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			spanCtx, span := tracing.StartEvent("workloadendpoint", "update", newObj)
+			defer span.End()
+
 			key, err := cache.MetaNamespaceKeyFunc(newObj)
 			if err != nil {
 				log.WithError(err).Error("Failed to generate key")
@@ -158,20 +183,29 @@ func NewPodController(ctx context.Context, k8sClientset *kubernetes.Clientset, c
 				return
 			}
 
+			_, convertSpan := tracing.Start(spanCtx, "convert")
 			wepDataList, err := podConverter.Convert(newObj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error while converting %v to wep.", key)
+				conversionErrors.Record(newObj, err)
 				return
 			}
+			conversionErrors.Clear(newObj)
 
 			// Update the cache.
+			_, cacheSpan := tracing.Start(spanCtx, "cache set")
 			for _, wepData := range wepDataList {
 				k := podConverter.GetKey(wepData)
 				resourceCache.Set(k, wepData)
 			}
+			cacheSpan.End()
 
 		},
 		DeleteFunc: func(obj interface{}) {
+			spanCtx, span := tracing.StartEvent("workloadendpoint", "delete", obj)
+			defer span.End()
+
 			key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 			if err != nil {
 				log.WithError(err).Error("Failed to generate key")
@@ -194,13 +228,18 @@ func NewPodController(ctx context.Context, k8sClientset *kubernetes.Clientset, c
 			}
 
 			// Convert to workload endpoint(s).
+			_, convertSpan := tracing.Start(spanCtx, "convert")
 			wepDataList, err := podConverter.Convert(obj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error while converting %v to wep.", key)
+				conversionErrors.Record(obj, err)
 				return
 			}
+			conversionErrors.Clear(obj)
 
 			// Clean up after the deleted workload endpoint.
+			_, cacheSpan := tracing.Start(spanCtx, "cache clean")
 			for _, wepData := range wepDataList {
 				k := podConverter.GetKey(wepData)
 				resourceCache.Clean(k)
@@ -208,6 +247,7 @@ func NewPodController(ctx context.Context, k8sClientset *kubernetes.Clientset, c
 				delete(workloadEndpointCache.m, k)
 				workloadEndpointCache.Unlock()
 			}
+			cacheSpan.End()
 
 		},
 	}); err != nil {
@@ -215,7 +255,14 @@ func NewPodController(ctx context.Context, k8sClientset *kubernetes.Clientset, c
 		return nil
 	}
 
-	return &podController{informer, resourceCache, c, &workloadEndpointCache, ctx, cfg}
+	var planner *dryrun.Planner
+	if cfg.DryRun {
+		planner = dryrun.NewPlanner("workloadendpoint", c.KubeControllersConfiguration())
+	}
+
+	ctrl := &podController{informer, resourceCache, c, &workloadEndpointCache, ctx, cfg, planner, nil}
+	ctrl.worker = controller.NewWorker("workloadendpoint", "pod", resourceCache, cfg.SyncTimeout, cfg.RetryBudgetRatio, ctrl.syncToCalico)
+	return ctrl
 }
 
 // Run starts the controller.
@@ -246,6 +293,12 @@ func (c *podController) Run(stopCh chan struct{}) {
 	// Start Calico cache.
 	c.resourceCache.Run(c.cfg.ReconcilerPeriod.String())
 
+	// In dry-run mode, report the planned changes once per reconciliation.
+	if c.planner != nil {
+		log.Info("Pod/WorkloadEndpoint controller is running in dry-run mode")
+		go c.planner.Run(c.ctx, c.cfg.ReconcilerPeriod)
+	}
+
 	// Start a number of worker threads to read from the queue.
 	for i := 0; i < c.cfg.NumberOfWorkers; i++ {
 		go c.runWorker()
@@ -256,36 +309,31 @@ func (c *podController) Run(stopCh chan struct{}) {
 	log.Info("Stopping Pod controller")
 }
 
-func (c *podController) runWorker() {
-	for c.processNextItem() {
-	}
+// Resync triggers an immediate reconciliation of the Calico datastore.
+func (c *podController) Resync() {
+	c.resourceCache.Resync()
 }
 
-// processNextItem waits for an event on the output queue from the resource cache and syncs
-// any received keys to the datastore.
-func (c *podController) processNextItem() bool {
-	// Wait until there is a new item in the work queue.
-	workqueue := c.resourceCache.GetQueue()
-	key, quit := workqueue.Get()
-	if quit {
-		return false
-	}
-
-	// Sync the object to the Calico datastore.
-	err := c.syncToCalico(key.(string))
-	c.handleErr(err, key.(string))
+// Dump returns a snapshot of the controller's cache and queue.
+func (c *podController) Dump() rcache.Dump {
+	return c.resourceCache.Dump()
+}
 
-	// Indicate that we're done processing this key, allowing for safe parallel processing such that
-	// two objects with the same key are never processed in parallel.
-	workqueue.Done(key)
-	return true
+func (c *podController) runWorker() {
+	for c.worker.ProcessNextItem(c.ctx) {
+	}
 }
 
 // syncToDatastore syncs the given update to the Calico datastore. The provided key can be used to
 // find the corresponding resource within the resource cache. If the resource for the provided key
 // exists in the cache, then the value should be written to the datastore. If it does not exist
 // in the cache, then it should be deleted from the datastore.
-func (c *podController) syncToCalico(key string) error {
+func (c *podController) syncToCalico(ctx context.Context, key string) error {
+	// In dry-run mode, the key is recorded again below if the datastore still differs.
+	if c.planner != nil {
+		c.planner.Resolve(key)
+	}
+
 	// Check if the wep data exists in our cache.  If it doesn't, then we don't need to do anything,
 	// since CNI handles deletion of workload endpoints.
 	if wepData, exists := c.resourceCache.Get(key); exists {
@@ -318,12 +366,18 @@ func (c *podController) syncToCalico(key string) error {
 
 		// Compare to see if the workload endpoint data has changed.
 		old := converter.BuildWorkloadEndpointData(wep)
-		new := wepData.(converter.WorkloadEndpointData)
+		new := wepData
 		if !reflect.DeepEqual(old, new) {
 			// The relevant wep data has changed - update the wep and write it to the datastore.
+			if c.planner != nil {
+				c.planner.Record(dryrun.OpUpdate, key)
+				return nil
+			}
 			log.Infof("Writing endpoint %s with updated data %#v to Calico datastore", key, new)
 			converter.MergeWorkloadEndpointData(&wep, new)
-			_, err := c.calicoClient.WorkloadEndpoints().Update(c.ctx, &wep, options.SetOptions{})
+			start := time.Now()
+			_, err := c.calicoClient.WorkloadEndpoints().Update(ctx, &wep, options.SetOptions{})
+			controller.ObserveDatastoreOp(ctx, "workloadendpoint", controller.DatastoreOpUpdate, start, err)
 			if err != nil {
 				if _, ok := err.(errors.ErrorResourceUpdateConflict); !ok {
 					// Not an update conflict - return the error right away.
@@ -333,7 +387,7 @@ func (c *podController) syncToCalico(key string) error {
 
 				// We hit an update conflict, re-query the WorkloadEndpoint before we try again.
 				clog.Warn("Update conflict, re-querying workload endpoint")
-				qwep, gErr := c.calicoClient.WorkloadEndpoints().Get(c.ctx, wep.Namespace, wep.Name, options.GetOptions{})
+				qwep, gErr := c.calicoClient.WorkloadEndpoints().Get(ctx, wep.Namespace, wep.Name, options.GetOptions{})
 				if gErr != nil {
 					log.WithError(err).Errorf("failed to query workload endpoint %s", key)
 					return gErr
@@ -346,7 +400,7 @@ func (c *podController) syncToCalico(key string) error {
 			}
 
 			// Update endpoint cache as well with the modified workload endpoint.
-			updatedWep, err := c.calicoClient.WorkloadEndpoints().Get(c.ctx, wep.Namespace, wep.Name, options.GetOptions{})
+			updatedWep, err := c.calicoClient.WorkloadEndpoints().Get(ctx, wep.Namespace, wep.Name, options.GetOptions{})
 			if err != nil {
 				log.WithError(err).Errorf("failed to query workload endpoint %s", key)
 				return err
@@ -364,7 +418,9 @@ func (c *podController) syncToCalico(key string) error {
 // worker's workload endpoint cache.
 func (c *podController) populateWorkloadEndpointCache() error {
 	// List all workload endpoints for kubernetes orchestrator
+	start := time.Now()
 	workloadEndpointList, err := c.calicoClient.WorkloadEndpoints().List(c.ctx, options.ListOptions{})
+	controller.ObserveDatastoreOp(c.ctx, "workloadendpoint", controller.DatastoreOpList, start, err)
 	if err != nil {
 		return err
 	}
@@ -383,35 +439,6 @@ func (c *podController) populateWorkloadEndpointCache() error {
 	return nil
 }
 
-// handleErr handles errors which occur while processing a key received from the resource cache.
-// For a given error, we will re-queue the key in order to retry the datastore sync up to 5 times,
-// at which point the update is dropped.
-func (c *podController) handleErr(err error, key string) {
-	workqueue := c.resourceCache.GetQueue()
-	if err == nil {
-		// Forget about the #AddRateLimited history of the key on every successful synchronization.
-		// This ensures that future processing of updates for this key is not delayed because of
-		// an outdated error history.
-		log.WithField("key", key).Debug("Error for key is no more, drop from retry queue")
-		workqueue.Forget(key)
-		return
-	}
-
-	// This controller retries 5 times if something goes wrong. After that, it stops trying.
-	if workqueue.NumRequeues(key) < 5 {
-		log.WithError(err).Errorf("Error syncing pod, will retry: %v: %v", key, err)
-		// Re-enqueue the key rate limited. Based on the rate limiter on the
-		// queue and the re-enqueue history, the key will be processed later again.
-		workqueue.AddRateLimited(key)
-		return
-	}
-	workqueue.Forget(key)
-
-	// Report to an external entity that, even after several retries, we could not successfully process this key
-	uruntime.HandleError(err)
-	log.WithError(err).Errorf("Dropping pod %q out of the retry queue: %v", key, err)
-}
-
 func isReadyCalicoPod(pod *v1.Pod) bool {
 	if isHostNetworked(pod) {
 		log.WithField("pod", pod.Name).Debug("Pod is host networked.")
@@ -434,6 +461,8 @@ func isHostNetworked(pod *v1.Pod) bool {
 	return pod.Spec.HostNetwork
 }
 
+// hasIPAddress returns true if the pod has been assigned an address of either IP family,
+// either in its status or, before the status is updated, by the CNI plugin's annotations.
 func hasIPAddress(pod *v1.Pod) bool {
-	return pod.Status.PodIP != ""
+	return len(pod.Status.PodIPs) > 0 || conversion.NewConverter().HasIPAddress(pod)
 }
@@ -0,0 +1,166 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poolassignment
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/namespacepool"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+const (
+	// PoolsAnnotation assigns a namespace to a comma separated list of existing IP pools, by
+	// name. Pods in the namespace are assigned addresses from those pools only. A namespace with
+	// a pool of its own, requested with the namespacepool.PoolAnnotation, is also assigned to that.
+	PoolsAnnotation = "projectcalico.org/ippools"
+
+	// AssignedAnnotation records the values of the CNI annotations written by the controller, so
+	// that it can tell them apart from ones set by users, which it leaves alone.
+	AssignedAnnotation = "projectcalico.org/assigned-ippools"
+
+	// The annotations that the Calico CNI plugin reads the pools to assign a namespace's pods
+	// addresses from.
+	IPv4PoolsAnnotation = "cni.projectcalico.org/ipv4pools"
+	IPv6PoolsAnnotation = "cni.projectcalico.org/ipv6pools"
+)
+
+// Assignment is the pools that a namespace is assigned to.
+type Assignment struct {
+	// The names of the available pools, by IP version.
+	IPv4Pools []string
+	IPv6Pools []string
+
+	// The names of requested pools that don't exist or are disabled, and so aren't assigned.
+	Unavailable []string
+}
+
+// requested returns the names of the pools that the namespace requests, and false if it doesn't
+// request any.
+func requested(ns *v1.Namespace) ([]string, bool) {
+	var names []string
+	for _, name := range strings.Split(ns.Annotations[PoolsAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if _, ok := ns.Annotations[namespacepool.PoolAnnotation]; ok {
+		names = append(names, namespacepool.PoolName(ns.Name))
+	}
+	return names, len(names) > 0
+}
+
+// Assign returns the pools that the given namespace is assigned to, given all the IP pools in the
+// datastore by name.
+func Assign(ns *v1.Namespace, pools map[string]api.IPPool) Assignment {
+	var a Assignment
+	names, _ := requested(ns)
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		p, ok := pools[name]
+		if !ok || p.Spec.Disabled {
+			a.Unavailable = append(a.Unavailable, name)
+			continue
+		}
+		_, cidr, err := cnet.ParseCIDR(p.Spec.CIDR)
+		if err != nil {
+			a.Unavailable = append(a.Unavailable, name)
+			continue
+		}
+		if cidr.Version() == 6 {
+			a.IPv6Pools = append(a.IPv6Pools, name)
+		} else {
+			a.IPv4Pools = append(a.IPv4Pools, name)
+		}
+	}
+	return a
+}
+
+// annotations returns the values of the CNI annotations for the assignment, with an empty value
+// for an annotation that shouldn't be set.
+func (a Assignment) annotations() map[string]string {
+	return map[string]string{
+		IPv4PoolsAnnotation: poolList(a.IPv4Pools),
+		IPv6PoolsAnnotation: poolList(a.IPv6Pools),
+	}
+}
+
+func poolList(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	b, _ := json.Marshal(names)
+	return string(b)
+}
+
+// Patch returns the changes to the namespace's annotations needed to apply the assignment, with
+// nil values for annotations to remove, or an empty map if it is already applied. CNI annotations
+// that were set by users rather than the controller are left alone, and returned in conflicts.
+func Patch(ns *v1.Namespace, a Assignment) (patch map[string]*string, conflicts []string) {
+	recorded := map[string]string{}
+	if v, ok := ns.Annotations[AssignedAnnotation]; ok {
+		// An unreadable record is treated as empty, so the CNI annotations become user owned.
+		_ = json.Unmarshal([]byte(v), &recorded)
+	}
+
+	patch = map[string]*string{}
+	assigned := map[string]string{}
+	for k, want := range a.annotations() {
+		current, exists := ns.Annotations[k]
+		if exists && current != recorded[k] {
+			if want != "" && current != want {
+				conflicts = append(conflicts, k)
+			}
+			continue
+		}
+		if want == "" {
+			if exists {
+				patch[k] = nil
+			}
+			continue
+		}
+		assigned[k] = want
+		if current != want || !exists {
+			patch[k] = stringPtr(want)
+		}
+	}
+	sort.Strings(conflicts)
+
+	if len(assigned) == 0 {
+		if _, ok := ns.Annotations[AssignedAnnotation]; ok {
+			patch[AssignedAnnotation] = nil
+		}
+	} else {
+		b, _ := json.Marshal(assigned)
+		if ns.Annotations[AssignedAnnotation] != string(b) {
+			patch[AssignedAnnotation] = stringPtr(string(b))
+		}
+	}
+	return patch, conflicts
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
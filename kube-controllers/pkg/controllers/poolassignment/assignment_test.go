@@ -0,0 +1,138 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poolassignment_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/namespacepool"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/poolassignment"
+)
+
+func namespace(annotations map[string]string) *v1.Namespace {
+	return &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "secure", Annotations: annotations}}
+}
+
+func pools(ps ...api.IPPool) map[string]api.IPPool {
+	m := map[string]api.IPPool{}
+	for _, p := range ps {
+		m[p.Name] = p
+	}
+	return m
+}
+
+func pool(name, cidr string, disabled bool) api.IPPool {
+	return api.IPPool{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: api.IPPoolSpec{CIDR: cidr, Disabled: disabled}}
+}
+
+func ptr(s string) *string {
+	return &s
+}
+
+var _ = Describe("IP pool assignment", func() {
+	all := pools(
+		pool("routable", "10.10.0.0/16", false),
+		pool("routable-v6", "fd00:10::/48", false),
+		pool("firewalled", "10.20.0.0/16", false),
+		pool("retired", "10.30.0.0/16", true),
+		pool("kns-secure", "10.64.0.0/24", false),
+	)
+
+	It("should split the requested pools by IP version", func() {
+		ns := namespace(map[string]string{poolassignment.PoolsAnnotation: "routable, routable-v6,firewalled,routable"})
+		a := poolassignment.Assign(ns, all)
+		Expect(a.IPv4Pools).To(Equal([]string{"routable", "firewalled"}))
+		Expect(a.IPv6Pools).To(Equal([]string{"routable-v6"}))
+		Expect(a.Unavailable).To(BeEmpty())
+	})
+
+	It("should not assign missing or disabled pools", func() {
+		ns := namespace(map[string]string{poolassignment.PoolsAnnotation: "missing,retired,routable"})
+		a := poolassignment.Assign(ns, all)
+		Expect(a.IPv4Pools).To(Equal([]string{"routable"}))
+		Expect(a.Unavailable).To(Equal([]string{"missing", "retired"}))
+	})
+
+	It("should assign a namespace to its own pool", func() {
+		ns := namespace(map[string]string{namespacepool.PoolAnnotation: "/24"})
+		a := poolassignment.Assign(ns, all)
+		Expect(a.IPv4Pools).To(Equal([]string{"kns-secure"}))
+	})
+
+	It("should set the CNI annotations and record them", func() {
+		ns := namespace(map[string]string{poolassignment.PoolsAnnotation: "routable,routable-v6"})
+		patch, conflicts := poolassignment.Patch(ns, poolassignment.Assign(ns, all))
+		Expect(conflicts).To(BeEmpty())
+		Expect(patch).To(Equal(map[string]*string{
+			poolassignment.IPv4PoolsAnnotation: ptr(`["routable"]`),
+			poolassignment.IPv6PoolsAnnotation: ptr(`["routable-v6"]`),
+			poolassignment.AssignedAnnotation:  ptr(`{"cni.projectcalico.org/ipv4pools":"[\"routable\"]","cni.projectcalico.org/ipv6pools":"[\"routable-v6\"]"}`),
+		}))
+
+		// Once applied, there is nothing more to do.
+		for k, v := range patch {
+			ns.Annotations[k] = *v
+		}
+		patch, _ = poolassignment.Patch(ns, poolassignment.Assign(ns, all))
+		Expect(patch).To(BeEmpty())
+
+		// Removing the request removes the annotations again.
+		delete(ns.Annotations, poolassignment.PoolsAnnotation)
+		patch, _ = poolassignment.Patch(ns, poolassignment.Assign(ns, all))
+		Expect(patch).To(Equal(map[string]*string{
+			poolassignment.IPv4PoolsAnnotation: nil,
+			poolassignment.IPv6PoolsAnnotation: nil,
+			poolassignment.AssignedAnnotation:  nil,
+		}))
+	})
+
+	It("should update the annotations when the assignment changes", func() {
+		ns := namespace(map[string]string{
+			poolassignment.PoolsAnnotation:     "firewalled",
+			poolassignment.IPv4PoolsAnnotation: `["routable"]`,
+			poolassignment.AssignedAnnotation:  `{"cni.projectcalico.org/ipv4pools":"[\"routable\"]"}`,
+		})
+		patch, conflicts := poolassignment.Patch(ns, poolassignment.Assign(ns, all))
+		Expect(conflicts).To(BeEmpty())
+		Expect(patch).To(Equal(map[string]*string{
+			poolassignment.IPv4PoolsAnnotation: ptr(`["firewalled"]`),
+			poolassignment.AssignedAnnotation:  ptr(`{"cni.projectcalico.org/ipv4pools":"[\"firewalled\"]"}`),
+		}))
+	})
+
+	It("should leave annotations set by users alone", func() {
+		ns := namespace(map[string]string{
+			poolassignment.PoolsAnnotation:     "routable,routable-v6",
+			poolassignment.IPv4PoolsAnnotation: `["10.99.0.0/16"]`,
+		})
+		patch, conflicts := poolassignment.Patch(ns, poolassignment.Assign(ns, all))
+		Expect(conflicts).To(Equal([]string{poolassignment.IPv4PoolsAnnotation}))
+		Expect(patch).To(Equal(map[string]*string{
+			poolassignment.IPv6PoolsAnnotation: ptr(`["routable-v6"]`),
+			poolassignment.AssignedAnnotation:  ptr(`{"cni.projectcalico.org/ipv6pools":"[\"routable-v6\"]"}`),
+		}))
+
+		// Without a request, user annotations aren't removed.
+		ns = namespace(map[string]string{poolassignment.IPv4PoolsAnnotation: `["10.99.0.0/16"]`})
+		patch, conflicts = poolassignment.Patch(ns, poolassignment.Assign(ns, all))
+		Expect(conflicts).To(BeEmpty())
+		Expect(patch).To(BeEmpty())
+	})
+})
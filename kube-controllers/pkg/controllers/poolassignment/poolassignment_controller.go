@@ -0,0 +1,183 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package poolassignment
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// poolAssignmentController implements the Controller interface for keeping the CNI annotations of
+// namespaces in line with the IP pools they are assigned to. The namespaces are resynced every
+// reconciler period, to pick up changes to the pools.
+type poolAssignmentController struct {
+	informer     cache.Controller
+	indexer      cache.Indexer
+	queue        workqueue.RateLimitingInterface
+	k8sClientset kubernetes.Interface
+	calicoClient client.Interface
+	ctx          context.Context
+	cfg          config.GenericControllerConfig
+}
+
+// NewPoolAssignmentController returns a controller which assigns namespaces to IP pools.
+func NewPoolAssignmentController(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, cfg config.GenericControllerConfig) controller.Controller {
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+
+	// Only namespaces that request pools, or were assigned to some, need syncing.
+	enqueue := func(obj interface{}) {
+		ns, ok := obj.(*v1.Namespace)
+		if !ok {
+			return
+		}
+		if _, ok := requested(ns); !ok {
+			if _, ok := ns.Annotations[AssignedAnnotation]; !ok {
+				return
+			}
+		}
+		queue.Add(ns.Name)
+	}
+
+	listWatcher := cache.NewListWatchFromClient(k8sClientset.CoreV1().RESTClient(), "namespaces", "", fields.Everything())
+	indexer, informer := cache.NewIndexerInformer(listWatcher, &v1.Namespace{}, cfg.ReconcilerPeriod, cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueue,
+		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			enqueue(newObj)
+		},
+	}, cache.Indexers{})
+
+	return &poolAssignmentController{informer, indexer, queue, k8sClientset, c, ctx, cfg}
+}
+
+// Run starts the controller.
+func (c *poolAssignmentController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	log.Info("Starting IP pool assignment controller")
+
+	go c.informer.Run(stopCh)
+	if !cache.WaitForNamedCacheSync("namespaces", stopCh, c.informer.HasSynced) {
+		log.Info("Failed to sync resources, received signal for controller to shut down.")
+		return
+	}
+
+	for i := 0; i < c.cfg.NumberOfWorkers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	log.Info("IP pool assignment controller is now running")
+
+	<-stopCh
+	log.Info("Stopping IP pool assignment controller")
+}
+
+func (c *poolAssignmentController) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+// processNextItem waits for a namespace on the queue and syncs its assignment.
+func (c *poolAssignmentController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	done := controller.WorkerWatchdog.Begin("poolassignment")
+	ctx, cancel := controller.WithSyncTimeout(c.ctx, c.cfg.SyncTimeout)
+	err := c.sync(ctx, key.(string))
+	cancel()
+	done()
+
+	if err == nil {
+		c.queue.Forget(key)
+		return true
+	}
+	controller.RecordSyncError("poolassignment", err)
+	if c.queue.NumRequeues(key) < 5 {
+		log.WithError(err).Errorf("Error syncing IP pool assignment of namespace %v: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	uruntime.HandleError(err)
+	log.WithError(err).Errorf("Dropping IP pool assignment of namespace %q out of the queue: %v", key, err)
+	return true
+}
+
+// sync updates the CNI annotations of the given namespace to match the pools it is assigned to.
+func (c *poolAssignmentController) sync(ctx context.Context, name string) error {
+	clog := log.WithField("namespace", name)
+	obj, exists, err := c.indexer.GetByKey(name)
+	if err != nil || !exists {
+		return err
+	}
+	ns := obj.(*v1.Namespace)
+
+	pools := map[string]api.IPPool{}
+	if _, ok := requested(ns); ok {
+		start := time.Now()
+		list, err := c.calicoClient.IPPools().List(ctx, options.ListOptions{})
+		controller.ObserveDatastoreOp(ctx, "poolassignment", controller.DatastoreOpList, start, err)
+		if err != nil {
+			return err
+		}
+		for _, p := range list.Items {
+			pools[p.Name] = p
+		}
+	}
+
+	a := Assign(ns, pools)
+	if len(a.Unavailable) > 0 {
+		clog.WithField("pools", a.Unavailable).Warning("Namespace is assigned to IP pools that don't exist or are disabled")
+	}
+	patch, conflicts := Patch(ns, a)
+	if len(conflicts) > 0 {
+		clog.WithField("annotations", conflicts).Warning("Not overwriting IP pool annotations set on namespace by user")
+	}
+	if len(patch) == 0 {
+		clog.Debug("IP pool assignment of namespace is up to date")
+		return nil
+	}
+
+	b, err := json.Marshal(map[string]interface{}{"metadata": map[string]interface{}{"annotations": patch}})
+	if err != nil {
+		return err
+	}
+	clog.WithFields(log.Fields{"ipv4Pools": a.IPv4Pools, "ipv6Pools": a.IPv6Pools}).Info("Updating IP pool assignment of namespace")
+	_, err = c.k8sClientset.CoreV1().Namespaces().Patch(ctx, name, types.MergePatchType, b, metav1.PatchOptions{})
+	return err
+}
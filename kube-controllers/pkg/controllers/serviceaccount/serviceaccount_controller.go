@@ -16,8 +16,10 @@ package serviceaccount
 
 import (
 	"context"
-	"reflect"
+	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -27,12 +29,15 @@ import (
 	"github.com/projectcalico/calico/kube-controllers/pkg/config"
 	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
 	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	"github.com/projectcalico/calico/kube-controllers/pkg/dryrun"
+	"github.com/projectcalico/calico/kube-controllers/pkg/tracing"
 	kdd "github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
 	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
 	"github.com/projectcalico/calico/libcalico-go/lib/errors"
 	"github.com/projectcalico/calico/libcalico-go/lib/options"
 
 	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	uruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -44,36 +49,52 @@ import (
 // and syncing them to the Calico datastore as Profiles.
 type serviceAccountController struct {
 	informer      cache.Controller
-	resourceCache rcache.ResourceCache
+	resourceCache rcache.ResourceCache[api.Profile]
 	calicoClient  client.Interface
 	ctx           context.Context
 	cfg           config.GenericControllerConfig
+	planner       *dryrun.Planner
+	deleteLimiter *controller.DeleteLimiter
+
+	// Syncs keys from the resource cache, limiting retries of failed syncs to a fraction of all syncs.
+	worker *controller.Worker
 }
 
 // NewServiceAccountController returns a controller which manages ServiceAccount objects.
 func NewServiceAccountController(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, cfg config.GenericControllerConfig) controller.Controller {
 	serviceAccountConverter := converter.NewServiceAccountConverter()
+	registry := converter.NewRegistry()
+	registry.MustRegister(converter.ServiceAccountGVK, serviceAccountConverter)
+	nsFilter := controller.NewNamespaceFilter(cfg.ExcludeNamespaces)
 
 	// Function returns map of profile_name:object stored by policy controller
 	// in the Calico datastore. Identifies controller written objects by
-	// their naming convention.
-	listFunc := func() (map[string]interface{}, error) {
+	// their ownership labels.
+	listFunc := func() (map[string]api.Profile, error) {
 		log.Debugf("Listing profiles from Calico datastore: to check for ServiceAccount")
-		filteredProfiles := make(map[string]interface{})
+		filteredProfiles := make(map[string]api.Profile)
 
 		// Get all profile objects from Calico datastore.
+		start := time.Now()
 		profileList, err := c.Profiles().List(ctx, options.ListOptions{})
+		controller.ObserveDatastoreOp(ctx, "serviceaccount", controller.DatastoreOpList, start, err)
 		if err != nil {
 			return nil, err
 		}
 
 		// Filter out only objects that are written by policy controller.
 		for _, profile := range profileList.Items {
-			if strings.HasPrefix(profile.Name, kdd.ServiceAccountProfileNamePrefix) {
-				// Update the profile's ObjectMeta so that it simply contains the name.
+			// Namespace names can't contain dots, so the namespace is everything up to the first
+			// dot. Leave the profiles of excluded namespaces alone.
+			ns, _, _ := strings.Cut(strings.TrimPrefix(profile.Name, kdd.ServiceAccountProfileNamePrefix), ".")
+			if nsFilter.Excluded(ns) {
+				continue
+			}
+			if converter.IsManagedOrLegacy(profile.ObjectMeta, "ServiceAccount", kdd.ServiceAccountProfileNamePrefix) {
+				// Update the profile's ObjectMeta so that it simply contains the name and ownership metadata.
 				// There is other metadata that we might receive (like resource version) that we don't want to
 				// compare in the cache.
-				profile.ObjectMeta = metav1.ObjectMeta{Name: profile.Name}
+				profile.ObjectMeta = converter.ManagedMetadata(profile.ObjectMeta)
 				key := serviceAccountConverter.GetKey(profile)
 				filteredProfiles[key] = profile
 			}
@@ -82,63 +103,156 @@ func NewServiceAccountController(ctx context.Context, k8sClientset *kubernetes.C
 		return filteredProfiles, nil
 	}
 
+	// Functions used to spot check the cache, reading directly from the Kubernetes API and the
+	// Calico datastore.
+	sourceGetFunc := func(key string) (api.Profile, bool, error) {
+		// Namespace names can't contain dots, so the first dot separates the namespace from
+		// the service account name.
+		parts := strings.SplitN(strings.TrimPrefix(key, kdd.ServiceAccountProfileNamePrefix), ".", 2)
+		if len(parts) != 2 {
+			return api.Profile{}, false, fmt.Errorf("invalid service account profile name %q", key)
+		}
+		sa, err := k8sClientset.CoreV1().ServiceAccounts(parts[0]).Get(ctx, parts[1], metav1.GetOptions{})
+		if kerrors.IsNotFound(err) {
+			return api.Profile{}, false, nil
+		} else if err != nil {
+			return api.Profile{}, false, err
+		}
+		profile, err := converter.ConvertObject[api.Profile](registry, sa)
+		return profile, err == nil, err
+	}
+	datastoreGetFunc := func(key string) (api.Profile, bool, error) {
+		profile, err := c.Profiles().Get(ctx, key, options.GetOptions{})
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+			return api.Profile{}, false, nil
+		} else if err != nil {
+			return api.Profile{}, false, err
+		}
+		if !converter.IsManagedOrLegacy(profile.ObjectMeta, "ServiceAccount", kdd.ServiceAccountProfileNamePrefix) {
+			return api.Profile{}, false, nil
+		}
+		profile.ObjectMeta = converter.ManagedMetadata(profile.ObjectMeta)
+		return *profile, true, nil
+	}
+
 	// Create a Cache to store Profiles in.
-	cacheArgs := rcache.ResourceCacheArgs{
-		ListFunc:    listFunc,
-		ObjectType:  reflect.TypeOf(api.Profile{}),
-		LogTypeDesc: "ServiceAccount",
+	cacheArgs := rcache.ResourceCacheArgs[api.Profile]{
+		ControllerName:   "serviceaccount",
+		Shards:           cfg.NumberOfWorkers,
+		MaxQueueDepth:    cfg.MaxQueueDepth,
+		CoalesceWindow:   cfg.CoalesceWindow,
+		ListFunc:         listFunc,
+		LogTypeDesc:      "ServiceAccount",
+		SourceGetFunc:    sourceGetFunc,
+		DatastoreGetFunc: datastoreGetFunc,
+
+		// Remove stale resources before writing their replacements, so that the two are never
+		// in effect at the same time.
+		PrioritizeDeletes: true,
+
+		ReconcilerConfig: rcache.ReconcilerConfig{
+			MaxReconcilerPeriod: cfg.MaxReconcilerPeriod,
+			ReconcilerJitter:    cfg.ReconcilerJitter,
+			ReconcileAtStartup:  true,
+			SpotCheckPeriod:     cfg.SpotCheckPeriod,
+			SpotCheckSampleSize: cfg.SpotCheckSampleSize,
+			ResyncWindow:        cfg.ResyncWindow,
+			ResyncThreshold:     cfg.ResyncThreshold,
+			MaxSnapshotAge:      cfg.MaxSnapshotAge,
+			MassDeleteThreshold: cfg.MassDeleteThreshold,
+		},
+	}
+	if cfg.SnapshotDir != "" {
+		cacheArgs.SnapshotStore = rcache.NewFileSnapshotStore(filepath.Join(cfg.SnapshotDir, "serviceaccount.json"))
 	}
 	ccache := rcache.NewResourceCache(cacheArgs)
+	conversionErrors := controller.NewConversionErrors("ServiceAccount")
 
 	// Create a ServiceAccount watcher.
-	listWatcher := cache.NewListWatchFromClient(k8sClientset.CoreV1().RESTClient(), "serviceaccounts", "", fields.Everything())
+	listWatcher := nsFilter.ListWatch(controller.PagedListWatch(cache.NewListWatchFromClient(k8sClientset.CoreV1().RESTClient(), "serviceaccounts", "", fields.Everything()), cfg.ListPageSize))
 
 	// Bind the calico cache to kubernetes cache with the help of an informer. This way we make sure that
 	// whenever the kubernetes cache is updated, changes get reflected in the Calico cache as well.
 	_, informer := cache.NewIndexerInformer(listWatcher, &v1.ServiceAccount{}, 0, cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
+			spanCtx, span := tracing.StartEvent("serviceaccount", "add", obj)
+			defer span.End()
+
 			log.Debugf("Got ADD event for ServiceAccount: %#v", obj)
-			profile, err := serviceAccountConverter.Convert(obj)
+			_, convertSpan := tracing.Start(spanCtx, "convert")
+			profile, err := converter.ConvertObject[api.Profile](registry, obj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error while converting %#v to Calico profile.", obj)
+				conversionErrors.Record(obj, err)
 				return
 			}
+			conversionErrors.Clear(obj)
 
 			// Add to cache.
 			k := serviceAccountConverter.GetKey(profile)
+			_, cacheSpan := tracing.Start(spanCtx, "cache set")
 			ccache.Set(k, profile)
+			cacheSpan.End()
 		},
 		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			spanCtx, span := tracing.StartEvent("serviceaccount", "update", newObj)
+			defer span.End()
+
 			log.Debugf("Got UPDATE event for ServiceAccount")
 			log.Debugf("Old object: \n%#v\n", oldObj)
 			log.Debugf("New object: \n%#v\n", newObj)
 
 			// Convert the ServiceAccount into a Profile.
-			profile, err := serviceAccountConverter.Convert(newObj)
+			_, convertSpan := tracing.Start(spanCtx, "convert")
+			profile, err := converter.ConvertObject[api.Profile](registry, newObj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error while converting %#v to Calico profile.", newObj)
+				conversionErrors.Record(newObj, err)
 				return
 			}
+			conversionErrors.Clear(newObj)
 
 			// Update in the cache.
 			k := serviceAccountConverter.GetKey(profile)
+			_, cacheSpan := tracing.Start(spanCtx, "cache set")
 			ccache.Set(k, profile)
+			cacheSpan.End()
 		},
 		DeleteFunc: func(obj interface{}) {
+			spanCtx, span := tracing.StartEvent("serviceaccount", "delete", obj)
+			defer span.End()
+
 			// Convert the ServiceAccount into a Profile.
 			log.Debugf("Got DELETE event for ServiceAccount: %#v", obj)
-			profile, err := serviceAccountConverter.Convert(obj)
+			_, convertSpan := tracing.Start(spanCtx, "convert")
+			profile, err := converter.ConvertObject[api.Profile](registry, obj)
+			tracing.End(convertSpan, err)
 			if err != nil {
 				log.WithError(err).Errorf("Error converting %#v to Calico profile.", obj)
+				conversionErrors.Record(obj, err)
 				return
 			}
+			conversionErrors.Clear(obj)
 
 			k := serviceAccountConverter.GetKey(profile)
+			_, cacheSpan := tracing.Start(spanCtx, "cache delete")
 			ccache.Delete(k)
+			cacheSpan.End()
 		},
 	}, cache.Indexers{})
 
-	return &serviceAccountController{informer, ccache, c, ctx, cfg}
+	var planner *dryrun.Planner
+	if cfg.DryRun {
+		planner = dryrun.NewPlanner("serviceaccount", c.KubeControllersConfiguration())
+	}
+
+	deleteLimiter := controller.NewDeleteLimiter("serviceaccount", cfg.DeleteRateLimit, cfg.DeleteBurst)
+
+	ctrl := &serviceAccountController{informer, ccache, c, ctx, cfg, planner, deleteLimiter, nil}
+	ctrl.worker = controller.NewWorker("serviceaccount", "Profile", ccache, cfg.SyncTimeout, cfg.RetryBudgetRatio, ctrl.syncToDatastore)
+	return ctrl
 }
 
 // Run starts the controller.
@@ -163,6 +277,12 @@ func (c *serviceAccountController) Run(stopCh chan struct{}) {
 	// Start Calico cache.
 	c.resourceCache.Run(c.cfg.ReconcilerPeriod.String())
 
+	// In dry-run mode, report the planned changes once per reconciliation.
+	if c.planner != nil {
+		log.Info("ServiceAccount/Profile controller is running in dry-run mode")
+		go c.planner.Run(c.ctx, c.cfg.ReconcilerPeriod)
+	}
+
 	// Start a number of worker threads to read from the queue.
 	for i := 0; i < c.cfg.NumberOfWorkers; i++ {
 		go c.runWorker()
@@ -173,45 +293,60 @@ func (c *serviceAccountController) Run(stopCh chan struct{}) {
 	log.Info("Stopping ServiceAccount/Profile controller")
 }
 
-func (c *serviceAccountController) runWorker() {
-	for c.processNextItem() {
-	}
+// Resync triggers an immediate reconciliation of the Calico datastore.
+func (c *serviceAccountController) Resync() {
+	c.resourceCache.Resync()
 }
 
-// processNextItem waits for an event on the output queue from the resource cache and syncs
-// any received keys to the datastore.
-func (c *serviceAccountController) processNextItem() bool {
-	// Wait until there is a new item in the work queue.
-	workqueue := c.resourceCache.GetQueue()
-	key, quit := workqueue.Get()
-	if quit {
-		return false
-	}
+// ConfirmDeletes allows the next reconciliation to make deletes withheld by the mass delete
+// failsafe, and triggers it.
+func (c *serviceAccountController) ConfirmDeletes() {
+	c.resourceCache.ConfirmDeletes()
+}
 
-	// Sync the object to the Calico datastore.
-	err := c.syncToDatastore(key.(string))
-	c.handleErr(err, key.(string))
+// Dump returns a snapshot of the controller's cache and queue.
+func (c *serviceAccountController) Dump() rcache.Dump {
+	return c.resourceCache.Dump()
+}
 
-	// Indicate that we're done processing this key, allowing for safe parallel processing such that
-	// two objects with the same key are never processed in parallel.
-	workqueue.Done(key)
-	return true
+func (c *serviceAccountController) runWorker() {
+	for c.worker.ProcessNextItem(c.ctx) {
+	}
 }
 
 // syncToDatastore syncs the given update to the Calico datastore. The provided key can be used to
 // find the corresponding resource within the resource cache. If the resource for the provided key
 // exists in the cache, then the value should be written to the datastore. If it does not exist
 // in the cache, then it should be deleted from the datastore.
-func (c *serviceAccountController) syncToDatastore(key string) error {
+func (c *serviceAccountController) syncToDatastore(ctx context.Context, key string) error {
 	clog := log.WithField("key", key)
 
+	// In dry-run mode, the key is recorded again below if the datastore still differs.
+	if c.planner != nil {
+		c.planner.Resolve(key)
+	}
+
 	// Check if it exists in the controller's cache.
-	obj, exists := c.resourceCache.Get(key)
+	p, exists := c.resourceCache.Get(key)
 	if !exists {
 		// The object no longer exists - delete from the datastore.
-		clog.Infof("Deleting ServiceAccount Profile from Calico datastore")
 		_, name := converter.NewServiceAccountConverter().DeleteArgsFromKey(key)
-		_, err := c.calicoClient.Profiles().Delete(c.ctx, name, options.DeleteOptions{})
+		if c.planner != nil {
+			_, err := c.calicoClient.Profiles().Get(ctx, name, options.GetOptions{})
+			if err == nil {
+				c.planner.Record(dryrun.OpDelete, key)
+			} else if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+				return err
+			}
+			return nil
+		}
+		if err := c.deleteLimiter.Reserve(key); err != nil {
+			return err
+		}
+		clog.Infof("Deleting ServiceAccount Profile from Calico datastore")
+		start := time.Now()
+		_, err := c.calicoClient.Profiles().Delete(ctx, name, options.DeleteOptions{})
+		controller.ObserveDatastoreOp(ctx, "serviceaccount", controller.DatastoreOpDelete, start, err)
 		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
 			// We hit an error other than "does not exist".
 			return err
@@ -220,10 +355,11 @@ func (c *serviceAccountController) syncToDatastore(key string) error {
 	} else {
 		// The object exists - update the datastore to reflect.
 		clog.Info("Create/Update ServiceAccount Profile in Calico datastore")
-		p := obj.(api.Profile)
 
 		// Lookup to see if this object already exists in the datastore.
-		gp, err := c.calicoClient.Profiles().Get(c.ctx, p.Name, options.GetOptions{})
+		start := time.Now()
+		gp, err := c.calicoClient.Profiles().Get(ctx, p.Name, options.GetOptions{})
+		controller.ObserveDatastoreOp(ctx, "serviceaccount", controller.DatastoreOpGet, start, err)
 		if err != nil {
 			if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
 				clog.WithError(err).Warning("Unexpected error for ServiceAccount profile from datastore")
@@ -231,7 +367,13 @@ func (c *serviceAccountController) syncToDatastore(key string) error {
 			}
 
 			// Doesn't exist - create it.
-			_, err := c.calicoClient.Profiles().Create(c.ctx, &p, options.SetOptions{})
+			if c.planner != nil {
+				c.planner.Record(dryrun.OpCreate, key)
+				return nil
+			}
+			start = time.Now()
+			_, err := c.calicoClient.Profiles().Create(ctx, &p, options.SetOptions{})
+			controller.ObserveDatastoreOp(ctx, "serviceaccount", controller.DatastoreOpCreate, start, err)
 			if err != nil {
 				clog.WithError(err).Warning("Failed to create ServiceAccount profile")
 				return err
@@ -240,10 +382,21 @@ func (c *serviceAccountController) syncToDatastore(key string) error {
 			return nil
 		}
 
-		// The profile already exists, update it and write it back to the datastore.
+		// The profile already exists. Only update it if it has changed, to avoid churn for Felix.
+		if !converter.NeedsUpdate(gp.ObjectMeta, gp.Spec, p.ObjectMeta, p.Spec) {
+			clog.Debug("Profile is already up to date in Calico datastore")
+			return nil
+		}
+		if c.planner != nil {
+			c.planner.Record(dryrun.OpUpdate, key)
+			return nil
+		}
 		gp.Spec = p.Spec
+		converter.CopyManagedMetadata(&gp.ObjectMeta, p.ObjectMeta)
 		clog.Infof("Update ServiceAccount Profile in Calico datastore with resource version %s", gp.ResourceVersion)
-		_, err = c.calicoClient.Profiles().Update(c.ctx, gp, options.SetOptions{})
+		start = time.Now()
+		_, err = c.calicoClient.Profiles().Update(ctx, gp, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "serviceaccount", controller.DatastoreOpUpdate, start, err)
 		if err != nil {
 			clog.WithError(err).Warning("Failed to update profile")
 			return err
@@ -252,31 +405,3 @@ func (c *serviceAccountController) syncToDatastore(key string) error {
 		return nil
 	}
 }
-
-// handleErr handles errors which occur while processing a key received from the resource cache.
-// For a given error, we will re-queue the key in order to retry the datastore sync up to 5 times,
-// at which point the update is dropped.
-func (c *serviceAccountController) handleErr(err error, key string) {
-	workqueue := c.resourceCache.GetQueue()
-	if err == nil {
-		// Forget about the #AddRateLimited history of the key on every successful synchronization.
-		// This ensures that future processing of updates for this key is not delayed because of
-		// an outdated error history.
-		workqueue.Forget(key)
-		return
-	}
-
-	// This controller retries 5 times if something goes wrong. After that, it stops trying.
-	if workqueue.NumRequeues(key) < 5 {
-		// Re-enqueue the key rate limited. Based on the rate limiter on the
-		// queue and the re-enqueue history, the key will be processed later again.
-		log.WithError(err).Errorf("Error syncing Profile %v: %v", key, err)
-		workqueue.AddRateLimited(key)
-		return
-	}
-	workqueue.Forget(key)
-
-	// Report to an external entity that, even after several retries, we could not successfully process this key
-	uruntime.HandleError(err)
-	log.WithError(err).Errorf("Dropping Profile %q out of the queue: %v", key, err)
-}
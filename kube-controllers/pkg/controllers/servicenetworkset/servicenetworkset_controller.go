@@ -0,0 +1,295 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicenetworkset
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// serviceIndex indexes EndpointSlices by the key of the Service they belong to.
+const serviceIndex = "service"
+
+// serviceNetworkSetController implements the Controller interface for maintaining a NetworkSet
+// of the endpoint addresses of each annotated Service. Unlike the Service's selector, the
+// NetworkSet can be used in policies for host endpoints, and copied to other clusters.
+type serviceNetworkSetController struct {
+	serviceInformer cache.Controller
+	serviceIndexer  cache.Indexer
+	sliceInformer   cache.Controller
+	sliceIndexer    cache.Indexer
+	queue           workqueue.RateLimitingInterface
+	calicoClient    client.Interface
+	ctx             context.Context
+	cfg             config.GenericControllerConfig
+}
+
+// NewServiceNetworkSetController returns a controller which maintains a NetworkSet for each
+// Service with the endpoint NetworkSet annotation.
+func NewServiceNetworkSetController(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, cfg config.GenericControllerConfig) controller.Controller {
+	snc := &serviceNetworkSetController{
+		queue:        workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+		calicoClient: c,
+		ctx:          ctx,
+		cfg:          cfg,
+	}
+
+	// Queue Services that have, or had, the annotation.
+	enqueueService := func(obj interface{}) {
+		if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+			snc.queue.Add(key)
+		}
+	}
+	serviceListWatcher := cache.NewListWatchFromClient(k8sClientset.CoreV1().RESTClient(), "services", "", fields.Everything())
+	snc.serviceIndexer, snc.serviceInformer = cache.NewIndexerInformer(serviceListWatcher, &v1.Service{}, cfg.ReconcilerPeriod, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if Enabled(obj.(*v1.Service)) {
+				enqueueService(obj)
+			}
+		},
+		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			if Enabled(oldObj.(*v1.Service)) || Enabled(newObj.(*v1.Service)) {
+				enqueueService(newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if svc, ok := obj.(*v1.Service); !ok || Enabled(svc) {
+				enqueueService(obj)
+			}
+		},
+	}, cache.Indexers{})
+
+	// Queue the Service of each EndpointSlice that changes, if it has the annotation.
+	enqueueSlice := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		slice, ok := obj.(*discovery.EndpointSlice)
+		if !ok {
+			return
+		}
+		key := slice.Namespace + "/" + slice.Labels[discovery.LabelServiceName]
+		if svc, exists, _ := snc.serviceIndexer.GetByKey(key); exists && Enabled(svc.(*v1.Service)) {
+			snc.queue.Add(key)
+		}
+	}
+	sliceListWatcher := cache.NewFilteredListWatchFromClient(k8sClientset.DiscoveryV1().RESTClient(), "endpointslices", "", func(o *metav1.ListOptions) {
+		o.LabelSelector = discovery.LabelServiceName
+	})
+	snc.sliceIndexer, snc.sliceInformer = cache.NewIndexerInformer(sliceListWatcher, &discovery.EndpointSlice{}, cfg.ReconcilerPeriod, cache.ResourceEventHandlerFuncs{
+		AddFunc: enqueueSlice,
+		UpdateFunc: func(oldObj interface{}, newObj interface{}) {
+			enqueueSlice(newObj)
+		},
+		DeleteFunc: enqueueSlice,
+	}, cache.Indexers{
+		serviceIndex: func(obj interface{}) ([]string, error) {
+			slice := obj.(*discovery.EndpointSlice)
+			return []string{slice.Namespace + "/" + slice.Labels[discovery.LabelServiceName]}, nil
+		},
+	})
+
+	return snc
+}
+
+// Run starts the controller.
+func (c *serviceNetworkSetController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	log.Info("Starting Service NetworkSet controller")
+
+	go c.serviceInformer.Run(stopCh)
+	go c.sliceInformer.Run(stopCh)
+	if !cache.WaitForNamedCacheSync("services", stopCh, c.serviceInformer.HasSynced, c.sliceInformer.HasSynced) {
+		log.Info("Failed to sync resources, received signal for controller to shut down.")
+		return
+	}
+
+	// Queue the NetworkSets of Services that were deleted, or lost the annotation, while we
+	// weren't running, so that they are cleaned up.
+	if err := c.queueOrphans(); err != nil {
+		log.WithError(err).Warning("Failed to list NetworkSets generated from Services")
+	}
+
+	for i := 0; i < c.cfg.NumberOfWorkers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	log.Info("Service NetworkSet controller is now running")
+
+	<-stopCh
+	log.Info("Stopping Service NetworkSet controller")
+}
+
+func (c *serviceNetworkSetController) queueOrphans() error {
+	start := time.Now()
+	sets, err := c.calicoClient.NetworkSets().List(c.ctx, options.ListOptions{})
+	controller.ObserveDatastoreOp(c.ctx, "servicenetworkset", controller.DatastoreOpList, start, err)
+	if err != nil {
+		return err
+	}
+	for _, ns := range sets.Items {
+		if !converter.IsManaged(ns.ObjectMeta, SourceKind) {
+			continue
+		}
+		key := ns.Namespace + "/" + ns.Labels[ServiceNameLabel]
+		if svc, exists, _ := c.serviceIndexer.GetByKey(key); !exists || !Enabled(svc.(*v1.Service)) {
+			c.queue.Add(key)
+		}
+	}
+	return nil
+}
+
+func (c *serviceNetworkSetController) runWorker() {
+	for c.processNextItem() {
+	}
+}
+
+// processNextItem waits for a Service on the queue and syncs its NetworkSet.
+func (c *serviceNetworkSetController) processNextItem() bool {
+	key, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	done := controller.WorkerWatchdog.Begin("servicenetworkset")
+	ctx, cancel := controller.WithSyncTimeout(c.ctx, c.cfg.SyncTimeout)
+	err := c.sync(ctx, key.(string))
+	cancel()
+	done()
+
+	if err == nil {
+		c.queue.Forget(key)
+		return true
+	}
+	controller.RecordSyncError("servicenetworkset", err)
+	if !controller.IsPermanentError(err) && c.queue.NumRequeues(key) < 5 {
+		log.WithError(err).Errorf("Error syncing NetworkSet of Service %v: %v", key, err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	uruntime.HandleError(err)
+	log.WithError(err).Errorf("Dropping Service %q out of the queue: %v", key, err)
+	return true
+}
+
+// sync makes the NetworkSet of the Service with the given key match the Service's endpoints,
+// deleting it if the Service no longer exists or no longer has the annotation. NetworkSets that
+// weren't generated from a Service are never modified.
+func (c *serviceNetworkSetController) sync(ctx context.Context, key string) error {
+	clog := log.WithField("service", key)
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	var desired *api.NetworkSet
+	obj, exists, err := c.serviceIndexer.GetByKey(key)
+	if err != nil {
+		return err
+	}
+	if exists && Enabled(obj.(*v1.Service)) {
+		objs, err := c.sliceIndexer.ByIndex(serviceIndex, key)
+		if err != nil {
+			return err
+		}
+		slices := make([]*discovery.EndpointSlice, 0, len(objs))
+		for _, o := range objs {
+			slices = append(slices, o.(*discovery.EndpointSlice))
+		}
+		desired, err = DesiredSet(obj.(*v1.Service), slices)
+		if err != nil {
+			return err
+		}
+	}
+
+	setName := SetName(name)
+	start := time.Now()
+	current, err := c.calicoClient.NetworkSets().Get(ctx, namespace, setName, options.GetOptions{})
+	controller.ObserveDatastoreOp(ctx, "servicenetworkset", controller.DatastoreOpGet, start, err)
+	if err != nil {
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			return err
+		}
+		if desired == nil {
+			return nil
+		}
+		clog.WithField("networks", len(desired.Spec.Nets)).Info("Creating NetworkSet for Service")
+		start = time.Now()
+		_, err = c.calicoClient.NetworkSets().Create(ctx, desired, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "servicenetworkset", controller.DatastoreOpCreate, start, err)
+		return err
+	}
+
+	if !converter.IsManaged(current.ObjectMeta, SourceKind) {
+		if desired != nil {
+			clog.WithField("networkSet", setName).Warning("NetworkSet already exists, and wasn't generated from the Service")
+			return cerrors.ErrorResourceAlreadyExists{Identifier: namespace + "/" + setName}
+		}
+		return nil
+	}
+
+	if desired == nil {
+		clog.Info("Deleting NetworkSet of Service")
+		start = time.Now()
+		_, err = c.calicoClient.NetworkSets().Delete(ctx, namespace, setName, options.DeleteOptions{ResourceVersion: current.ResourceVersion})
+		controller.ObserveDatastoreOp(ctx, "servicenetworkset", controller.DatastoreOpDelete, start, err)
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); ok {
+			return nil
+		}
+		return err
+	}
+
+	if current.Labels[ServiceNameLabel] == desired.Labels[ServiceNameLabel] &&
+		current.Annotations[PortsAnnotation] == desired.Annotations[PortsAnnotation] &&
+		!converter.NeedsUpdate(current.ObjectMeta, current.Spec, desired.ObjectMeta, desired.Spec) {
+		return nil
+	}
+	clog.WithField("networks", len(desired.Spec.Nets)).Info("Updating NetworkSet for Service")
+	current.Labels[ServiceNameLabel] = desired.Labels[ServiceNameLabel]
+	if current.Annotations == nil {
+		current.Annotations = map[string]string{}
+	}
+	current.Annotations[PortsAnnotation] = desired.Annotations[PortsAnnotation]
+	converter.CopyManagedMetadata(&current.ObjectMeta, desired.ObjectMeta)
+	current.Spec = desired.Spec
+	start = time.Now()
+	_, err = c.calicoClient.NetworkSets().Update(ctx, current, options.SetOptions{})
+	controller.ObserveDatastoreOp(ctx, "servicenetworkset", controller.DatastoreOpUpdate, start, err)
+	return err
+}
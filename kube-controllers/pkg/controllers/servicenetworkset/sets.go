@@ -0,0 +1,122 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicenetworkset
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	netsets "github.com/projectcalico/calico/kube-controllers/pkg/networkset"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+const (
+	// EnabledAnnotation is set to "true" on the Services to maintain NetworkSets for.
+	EnabledAnnotation = "projectcalico.org/endpoint-network-set"
+
+	// ServiceNameLabel is set on each generated NetworkSet to the name of its Service, so that
+	// policies can select it with projectcalico.org/service-name == '<name>'.
+	ServiceNameLabel = "projectcalico.org/service-name"
+
+	// PortsAnnotation records the ports of the Service's endpoints, as protocol:port, for use
+	// when writing the policy rules that select the NetworkSet.
+	PortsAnnotation = "projectcalico.org/service-ports"
+
+	// NamePrefix is the prefix of the names of the generated NetworkSets.
+	NamePrefix = "ksvc-"
+
+	// SourceKind identifies the NetworkSets generated from Services in their ownership labels.
+	SourceKind = "Service"
+)
+
+// Enabled returns true if the Service has asked for a NetworkSet of its endpoints.
+func Enabled(svc *v1.Service) bool {
+	return svc != nil && svc.Annotations[EnabledAnnotation] == "true"
+}
+
+// SetName returns the name of the NetworkSet for the Service with the given name. Service names
+// are at most 63 characters, so it never needs shortening.
+func SetName(service string) string {
+	return NamePrefix + service
+}
+
+// DesiredSet returns the NetworkSet for the given Service, with the addresses of the ready
+// endpoints in its EndpointSlices. Terminating and unready endpoints are left out, as the
+// Service wouldn't send traffic to them either. The addresses are consolidated into the fewest
+// CIDRs that cover them.
+func DesiredSet(svc *v1.Service, slices []*discovery.EndpointSlice) (*api.NetworkSet, error) {
+	var nets []string
+	ports := map[string]bool{}
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			for _, addr := range ep.Addresses {
+				if ip := net.ParseIP(addr); ip != nil {
+					nets = append(nets, hostNet(ip))
+				}
+			}
+		}
+		for _, p := range slice.Ports {
+			if p.Port == nil {
+				continue
+			}
+			protocol := v1.ProtocolTCP
+			if p.Protocol != nil {
+				protocol = *p.Protocol
+			}
+			ports[fmt.Sprintf("%s:%d", protocol, *p.Port)] = true
+		}
+	}
+
+	consolidated, err := netsets.ConsolidateCIDRs(nets)
+	if err != nil {
+		return nil, err
+	}
+
+	ns := api.NewNetworkSet()
+	ns.Name = SetName(svc.Name)
+	ns.Namespace = svc.Namespace
+	ns.Labels = map[string]string{ServiceNameLabel: svc.Name}
+	ns.Annotations = map[string]string{PortsAnnotation: strings.Join(sortedKeys(ports), ",")}
+	ns.Spec.Nets = consolidated
+	converter.SetOwnership(&ns.ObjectMeta, SourceKind, svc.UID)
+	return ns, nil
+}
+
+func hostNet(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	n := cnet.IPNet{IPNet: net.IPNet{IP: ip, Mask: net.CIDRMask(8*len(ip), 8*len(ip))}}
+	return n.String()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicenetworkset_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/servicenetworkset"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+)
+
+var _ = Describe("Service NetworkSets", func() {
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "shop",
+			Name:        "payments",
+			UID:         "svc-uid",
+			Annotations: map[string]string{servicenetworkset.EnabledAnnotation: "true"},
+		},
+	}
+	ready, notReady := true, false
+	tcp, udp := v1.ProtocolTCP, v1.ProtocolUDP
+	port := func(p int32) *int32 { return &p }
+
+	It("should only be enabled by the annotation", func() {
+		Expect(servicenetworkset.Enabled(svc)).To(BeTrue())
+		Expect(servicenetworkset.Enabled(&v1.Service{})).To(BeFalse())
+		Expect(servicenetworkset.Enabled(nil)).To(BeFalse())
+	})
+
+	It("should collect the ready addresses and ports of all slices", func() {
+		slices := []*discovery.EndpointSlice{
+			{
+				AddressType: discovery.AddressTypeIPv4,
+				Endpoints: []discovery.Endpoint{
+					{Addresses: []string{"10.0.0.2"}, Conditions: discovery.EndpointConditions{Ready: &ready}},
+					{Addresses: []string{"10.0.0.1"}},
+					{Addresses: []string{"10.0.0.3"}, Conditions: discovery.EndpointConditions{Ready: &notReady}},
+				},
+				Ports: []discovery.EndpointPort{{Port: port(8080), Protocol: &tcp}, {Port: port(53)}},
+			},
+			{
+				AddressType: discovery.AddressTypeIPv6,
+				Endpoints: []discovery.Endpoint{
+					{Addresses: []string{"fd00::1"}, Conditions: discovery.EndpointConditions{Ready: &ready}},
+				},
+				Ports: []discovery.EndpointPort{{Port: port(53), Protocol: &udp}, {Port: port(8080), Protocol: &tcp}},
+			},
+		}
+		ns, err := servicenetworkset.DesiredSet(svc, slices)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ns.Namespace).To(Equal("shop"))
+		Expect(ns.Name).To(Equal("ksvc-payments"))
+		Expect(ns.Labels).To(HaveKeyWithValue(servicenetworkset.ServiceNameLabel, "payments"))
+		Expect(ns.Spec.Nets).To(Equal([]string{"10.0.0.1/32", "10.0.0.2/32", "fd00::1/128"}))
+		Expect(ns.Annotations).To(HaveKeyWithValue(servicenetworkset.PortsAnnotation, "TCP:53,TCP:8080,UDP:53"))
+		Expect(converter.IsManaged(ns.ObjectMeta, servicenetworkset.SourceKind)).To(BeTrue())
+		Expect(ns.Annotations).To(HaveKeyWithValue(converter.SourceUIDAnnotation, "svc-uid"))
+	})
+
+	It("should consolidate adjacent addresses", func() {
+		slices := []*discovery.EndpointSlice{{
+			AddressType: discovery.AddressTypeIPv4,
+			Endpoints: []discovery.Endpoint{
+				{Addresses: []string{"10.0.0.3"}},
+				{Addresses: []string{"10.0.0.2"}},
+			},
+		}}
+		ns, err := servicenetworkset.DesiredSet(svc, slices)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ns.Spec.Nets).To(Equal([]string{"10.0.0.2/31"}))
+	})
+
+	It("should generate an empty NetworkSet for a Service without endpoints", func() {
+		ns, err := servicenetworkset.DesiredSet(svc, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ns.Spec.Nets).To(BeEmpty())
+		Expect(ns.Annotations).To(HaveKeyWithValue(servicenetworkset.PortsAnnotation, ""))
+	})
+})
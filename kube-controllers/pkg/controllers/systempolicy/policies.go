@@ -0,0 +1,175 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systempolicy
+
+import (
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+)
+
+const (
+	// NamePrefix is the prefix of the names of the system policies.
+	NamePrefix = "ksys-"
+
+	// SourceKind identifies the system policies in their ownership labels. Any
+	// GlobalNetworkPolicy with these labels is managed by the controller, and is deleted if it
+	// is no longer part of the curated set.
+	SourceKind = "SystemPolicy"
+
+	// VersionAnnotation records the version of the curated set that a system policy belongs to.
+	VersionAnnotation = "projectcalico.org/system-policy-version"
+
+	// Version is the version of the curated set of system policies. It must be incremented
+	// whenever the policies change.
+	Version = "2"
+
+	// The system policies are ordered ahead of typical user policies, so that a default-deny
+	// policy doesn't prevent the critical components from working. Users can still override
+	// them with policies that have a lower order.
+	policyOrder = 100.0
+
+	// The failsafe policy is ordered ahead of any other untracked policy, so that none of them can
+	// deny the traffic that it allows.
+	failsafeOrder = 0.0
+
+	// FailsafeName is the name of the failsafe policy.
+	FailsafeName = NamePrefix + "failsafe"
+
+	// The failsafe policy selects all host endpoints, which, unlike workload endpoints, have no
+	// namespace.
+	failsafeSelector = "!has(projectcalico.org/namespace)"
+)
+
+var (
+	tcp = numorstring.ProtocolFromString(numorstring.ProtocolTCP)
+	udp = numorstring.ProtocolFromString(numorstring.ProtocolUDP)
+
+	// Access to the Kubernetes API through the kubernetes service.
+	allowAPIServer = api.Rule{
+		Action:      api.Allow,
+		Protocol:    &tcp,
+		Destination: api.EntityRule{Services: &api.ServiceMatch{Name: "kubernetes", Namespace: "default"}},
+	}
+
+	// Access to DNS servers, in or out of cluster.
+	allowDNS = []api.Rule{
+		{Action: api.Allow, Protocol: &udp, Destination: api.EntityRule{Ports: ports(53)}},
+		{Action: api.Allow, Protocol: &tcp, Destination: api.EntityRule{Ports: ports(53)}},
+	}
+)
+
+// Policies returns the curated set of system policies. Each policy selects a single critical
+// system component, and allows the traffic that the component needs to work. Since the
+// policies only apply to the selected components, they don't change the behaviour of any other
+// workloads.
+func Policies() []api.GlobalNetworkPolicy {
+	return []api.GlobalNetworkPolicy{
+		policy("kube-dns", "projectcalico.org/namespace == 'kube-system' && k8s-app == 'kube-dns'",
+			[]api.Rule{
+				// DNS, metrics and health checks.
+				{Action: api.Allow, Protocol: &udp, Destination: api.EntityRule{Ports: ports(53)}},
+				{Action: api.Allow, Protocol: &tcp, Destination: api.EntityRule{Ports: ports(53, 8080, 8181, 9153)}},
+			},
+			append([]api.Rule{allowAPIServer}, allowDNS...),
+		),
+		policy("metrics-server", "projectcalico.org/namespace == 'kube-system' && k8s-app == 'metrics-server'",
+			[]api.Rule{
+				// Requests from the API server, and health checks.
+				{Action: api.Allow, Protocol: &tcp, Destination: api.EntityRule{Ports: ports(443, 4443, 10250)}},
+			},
+			append([]api.Rule{
+				allowAPIServer,
+				// Scraping metrics from the kubelets.
+				{Action: api.Allow, Protocol: &tcp, Destination: api.EntityRule{Ports: ports(10250)}},
+			}, allowDNS...),
+		),
+	}
+}
+
+// FailsafePolicy returns the policy that allows the given ports to and from all host endpoints,
+// so that protecting the hosts can't lock the control plane out of them. Like Felix's failsafe
+// ports, it only applies to host endpoints, but it can also be seen and audited in the datastore.
+//
+// The policy is untracked, so that it doesn't count as a policy applying to the host endpoints:
+// traffic that it doesn't allow carries on to their normal policies and, for host endpoints
+// with no normal policies, such as the automatic ones, to their profiles. A Pass rule would not
+// do, as it would skip every later policy as well. Untracked traffic has no connection state,
+// so each direction also allows the replies to the other direction's ports.
+func FailsafePolicy(inbound, outbound []api.ProtoPort) api.GlobalNetworkPolicy {
+	p := policy("failsafe", failsafeSelector,
+		append(failsafeRules(inbound, false), failsafeRules(outbound, true)...),
+		append(failsafeRules(outbound, false), failsafeRules(inbound, true)...),
+	)
+	order := failsafeOrder
+	p.Spec.Order = &order
+	p.Spec.DoNotTrack = true
+	p.Spec.ApplyOnForward = true
+	return p
+}
+
+// failsafeRules returns a rule allowing each protocol to the given ports, or from them if reply
+// is set, keeping the protocols in the order they first appear.
+func failsafeRules(protoPorts []api.ProtoPort, reply bool) []api.Rule {
+	var protocols []string
+	byProtocol := map[string][]uint16{}
+	for _, pp := range protoPorts {
+		if _, ok := byProtocol[pp.Protocol]; !ok {
+			protocols = append(protocols, pp.Protocol)
+		}
+		byProtocol[pp.Protocol] = append(byProtocol[pp.Protocol], pp.Port)
+	}
+	var rules []api.Rule
+	for _, protocol := range protocols {
+		proto := numorstring.ProtocolFromString(protocol)
+		rule := api.Rule{Action: api.Allow, Protocol: &proto}
+		if reply {
+			rule.Source = api.EntityRule{Ports: ports(byProtocol[protocol]...)}
+		} else {
+			rule.Destination = api.EntityRule{Ports: ports(byProtocol[protocol]...)}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func policy(name, selector string, ingress, egress []api.Rule) api.GlobalNetworkPolicy {
+	order := policyOrder
+	p := api.GlobalNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        NamePrefix + name,
+			Annotations: map[string]string{VersionAnnotation: Version},
+		},
+		Spec: api.GlobalNetworkPolicySpec{
+			Order:    &order,
+			Selector: selector,
+			Types:    []api.PolicyType{api.PolicyTypeIngress, api.PolicyTypeEgress},
+			Ingress:  ingress,
+			Egress:   egress,
+		},
+	}
+	converter.SetOwnership(&p.ObjectMeta, SourceKind, "")
+	return p
+}
+
+func ports(ps ...uint16) []numorstring.Port {
+	var out []numorstring.Port
+	for _, p := range ps {
+		out = append(out, numorstring.SinglePort(p))
+	}
+	return out
+}
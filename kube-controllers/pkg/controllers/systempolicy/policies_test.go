@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systempolicy_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/systempolicy"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	validator "github.com/projectcalico/calico/libcalico-go/lib/validator/v3"
+)
+
+var _ = Describe("System policies", func() {
+	It("should only contain valid, versioned policies", func() {
+		names := map[string]bool{}
+		for _, p := range systempolicy.Policies() {
+			Expect(p.Name).To(HavePrefix(systempolicy.NamePrefix))
+			Expect(names).NotTo(HaveKey(p.Name))
+			names[p.Name] = true
+
+			Expect(p.Annotations).To(HaveKeyWithValue(systempolicy.VersionAnnotation, systempolicy.Version))
+			Expect(converter.IsManaged(p.ObjectMeta, systempolicy.SourceKind)).To(BeTrue())
+			Expect(validator.Validate(&p)).To(Succeed())
+		}
+		Expect(names).NotTo(BeEmpty())
+	})
+
+	It("should only select system components", func() {
+		for _, p := range systempolicy.Policies() {
+			Expect(strings.Contains(p.Spec.Selector, "projectcalico.org/namespace == 'kube-system'")).To(BeTrue(), p.Name)
+		}
+	})
+
+	It("should allow the failsafe ports and their replies to and from host endpoints", func() {
+		p := systempolicy.FailsafePolicy(
+			[]api.ProtoPort{{Protocol: "TCP", Port: 22}, {Protocol: "UDP", Port: 68}, {Protocol: "TCP", Port: 6443}},
+			[]api.ProtoPort{{Protocol: "UDP", Port: 53}},
+		)
+		Expect(p.Name).To(Equal(systempolicy.FailsafeName))
+		Expect(p.Annotations).To(HaveKeyWithValue(systempolicy.VersionAnnotation, systempolicy.Version))
+		Expect(converter.IsManaged(p.ObjectMeta, systempolicy.SourceKind)).To(BeTrue())
+		Expect(validator.Validate(&p)).To(Succeed())
+		Expect(p.Spec.Selector).To(Equal("!has(projectcalico.org/namespace)"))
+		for _, sp := range systempolicy.Policies() {
+			Expect(*p.Spec.Order).To(BeNumerically("<", *sp.Spec.Order))
+		}
+
+		Expect(p.Spec.Ingress).To(HaveLen(3))
+		Expect(p.Spec.Ingress[0].Protocol.String()).To(Equal("TCP"))
+		Expect(p.Spec.Ingress[0].Destination.Ports).To(Equal([]numorstring.Port{numorstring.SinglePort(22), numorstring.SinglePort(6443)}))
+		Expect(p.Spec.Ingress[1].Protocol.String()).To(Equal("UDP"))
+		Expect(p.Spec.Ingress[1].Destination.Ports).To(Equal([]numorstring.Port{numorstring.SinglePort(68)}))
+		Expect(p.Spec.Ingress[2].Protocol.String()).To(Equal("UDP"))
+		Expect(p.Spec.Ingress[2].Source.Ports).To(Equal([]numorstring.Port{numorstring.SinglePort(53)}))
+		Expect(p.Spec.Ingress[2].Destination.Ports).To(BeEmpty())
+
+		Expect(p.Spec.Egress).To(HaveLen(3))
+		Expect(p.Spec.Egress[0].Protocol.String()).To(Equal("UDP"))
+		Expect(p.Spec.Egress[0].Destination.Ports).To(Equal([]numorstring.Port{numorstring.SinglePort(53)}))
+		Expect(p.Spec.Egress[1].Protocol.String()).To(Equal("TCP"))
+		Expect(p.Spec.Egress[1].Source.Ports).To(Equal([]numorstring.Port{numorstring.SinglePort(22), numorstring.SinglePort(6443)}))
+		Expect(p.Spec.Egress[2].Protocol.String()).To(Equal("UDP"))
+		Expect(p.Spec.Egress[2].Source.Ports).To(Equal([]numorstring.Port{numorstring.SinglePort(68)}))
+		for _, r := range append(p.Spec.Ingress, p.Spec.Egress...) {
+			Expect(r.Action).To(Equal(api.Allow))
+		}
+	})
+
+	// Felix only applies a host endpoint's profiles when no normal policy applies to it, and an
+	// untracked policy hands anything it doesn't allow on to the normal policies, so a host
+	// endpoint that only has a profile, such as an automatic one with the default-allow profile,
+	// must keep it in effect.
+	It("should leave the profiles of a host endpoint without policies in effect", func() {
+		p := systempolicy.FailsafePolicy([]api.ProtoPort{{Protocol: "TCP", Port: 22}}, nil)
+		Expect(p.Spec.DoNotTrack).To(BeTrue())
+		Expect(p.Spec.PreDNAT).To(BeFalse())
+		Expect(p.Spec.ApplyOnForward).To(BeTrue())
+		for _, r := range append(p.Spec.Ingress, p.Spec.Egress...) {
+			Expect(r.Action).To(Equal(api.Allow))
+		}
+	})
+
+	It("should allow nothing if there are no failsafe ports", func() {
+		p := systempolicy.FailsafePolicy(nil, nil)
+		Expect(validator.Validate(&p)).To(Succeed())
+		Expect(p.Spec.DoNotTrack).To(BeTrue())
+		Expect(p.Spec.Ingress).To(BeEmpty())
+		Expect(p.Spec.Egress).To(BeEmpty())
+	})
+
+	It("should return a new copy each time", func() {
+		a := systempolicy.Policies()
+		a[0].Spec.Selector = "all()"
+		Expect(systempolicy.Policies()[0].Spec.Selector).NotTo(Equal("all()"))
+	})
+})
@@ -0,0 +1,207 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systempolicy
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	rcache "github.com/projectcalico/calico/kube-controllers/pkg/cache"
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// systemPolicyController implements the Controller interface for installing the curated set of
+// system policies, and keeping them up to date.
+type systemPolicyController struct {
+	resourceCache rcache.ResourceCache[api.GlobalNetworkPolicy]
+	calicoClient  client.Interface
+	ctx           context.Context
+	cfg           config.SystemPolicyControllerConfig
+
+	// Syncs keys from the resource cache, limiting retries of failed syncs to a fraction of all syncs.
+	worker *controller.Worker
+}
+
+// NewSystemPolicyController returns a controller which manages the system policies.
+func NewSystemPolicyController(ctx context.Context, c client.Interface, cfg config.SystemPolicyControllerConfig) controller.Controller {
+	// Function returns map of policyName:policy for the system policies in the datastore.
+	listFunc := func() (map[string]api.GlobalNetworkPolicy, error) {
+		start := time.Now()
+		policies, err := c.GlobalNetworkPolicies().List(ctx, options.ListOptions{})
+		controller.ObserveDatastoreOp(ctx, "systempolicy", controller.DatastoreOpList, start, err)
+		if err != nil {
+			return nil, err
+		}
+
+		m := make(map[string]api.GlobalNetworkPolicy)
+		for _, p := range policies.Items {
+			if !converter.IsManagedOrLegacy(p.ObjectMeta, SourceKind, NamePrefix) {
+				continue
+			}
+			m[p.Name] = normalize(p)
+		}
+		log.Debugf("Found %d system policies in Calico datastore", len(m))
+		return m, nil
+	}
+
+	cacheArgs := rcache.ResourceCacheArgs[api.GlobalNetworkPolicy]{
+		ControllerName: "systempolicy",
+		Shards:         cfg.NumberOfWorkers,
+		MaxQueueDepth:  cfg.MaxQueueDepth,
+		CoalesceWindow: cfg.CoalesceWindow,
+		ListFunc:       listFunc,
+		ReconcilerConfig: rcache.ReconcilerConfig{
+			MaxReconcilerPeriod: cfg.MaxReconcilerPeriod,
+			ReconcilerJitter:    cfg.ReconcilerJitter,
+		},
+	}
+	ccache := rcache.NewResourceCache(cacheArgs)
+
+	// The desired state never changes while we're running, so just load it into the cache.
+	if cfg.Curated {
+		for _, p := range Policies() {
+			ccache.Set(p.Name, p)
+		}
+	}
+	if cfg.Failsafe {
+		ccache.Set(FailsafeName, FailsafePolicy(cfg.FailsafeInbound, cfg.FailsafeOutbound))
+	}
+
+	ctrl := &systemPolicyController{ccache, c, ctx, cfg, nil}
+	ctrl.worker = controller.NewWorker("systempolicy", "system policy", ccache, cfg.SyncTimeout, cfg.RetryBudgetRatio, ctrl.syncToDatastore)
+	return ctrl
+}
+
+// normalize returns the policy with just the metadata that is managed by the controller, so
+// that it can be compared with the policies in the cache.
+func normalize(p api.GlobalNetworkPolicy) api.GlobalNetworkPolicy {
+	meta := converter.ManagedMetadata(p.ObjectMeta)
+	if v, ok := p.Annotations[VersionAnnotation]; ok {
+		if meta.Annotations == nil {
+			meta.Annotations = map[string]string{}
+		}
+		meta.Annotations[VersionAnnotation] = v
+	}
+	return api.GlobalNetworkPolicy{ObjectMeta: meta, Spec: p.Spec}
+}
+
+// Run starts the controller.
+func (c *systemPolicyController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+
+	// Let the workers stop when we are done
+	workqueue := c.resourceCache.GetQueue()
+	defer workqueue.ShutDown()
+
+	log.WithField("version", Version).Info("Starting system policy controller")
+
+	// Start the resource cache - this will trigger the queueing of any system policies that
+	// are missing or out of date.
+	c.resourceCache.Run(c.cfg.ReconcilerPeriod.String())
+
+	for i := 0; i < c.cfg.NumberOfWorkers; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+	log.Info("System policy controller is now running")
+
+	<-stopCh
+	log.Info("Stopping system policy controller")
+}
+
+// Resync triggers an immediate reconciliation of the Calico datastore.
+func (c *systemPolicyController) Resync() {
+	c.resourceCache.Resync()
+}
+
+// Dump returns a snapshot of the controller's cache and queue.
+func (c *systemPolicyController) Dump() rcache.Dump {
+	return c.resourceCache.Dump()
+}
+
+func (c *systemPolicyController) runWorker() {
+	for c.worker.ProcessNextItem(c.ctx) {
+	}
+}
+
+// syncToDatastore writes the system policy with the given name to the datastore, or deletes it
+// if it is no longer part of the curated set.
+func (c *systemPolicyController) syncToDatastore(ctx context.Context, key string) error {
+	clog := log.WithField("key", key)
+
+	p, exists := c.resourceCache.Get(key)
+	if !exists {
+		clog.Info("Deleting system policy from Calico datastore")
+		start := time.Now()
+		_, err := c.calicoClient.GlobalNetworkPolicies().Delete(ctx, key, options.DeleteOptions{})
+		controller.ObserveDatastoreOp(ctx, "systempolicy", controller.DatastoreOpDelete, start, err)
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+			return err
+		}
+		return nil
+	}
+
+	start := time.Now()
+	gp, err := c.calicoClient.GlobalNetworkPolicies().Get(ctx, key, options.GetOptions{})
+	controller.ObserveDatastoreOp(ctx, "systempolicy", controller.DatastoreOpGet, start, err)
+	if err != nil {
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); !ok {
+			clog.WithError(err).Warning("Failed to get system policy from datastore")
+			return err
+		}
+
+		clog.Info("Creating system policy in Calico datastore")
+		start = time.Now()
+		_, err = c.calicoClient.GlobalNetworkPolicies().Create(ctx, &p, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "systempolicy", controller.DatastoreOpCreate, start, err)
+		if err != nil {
+			clog.WithError(err).Warning("Failed to create system policy")
+			return err
+		}
+		return nil
+	}
+
+	if !converter.NeedsUpdate(gp.ObjectMeta, gp.Spec, p.ObjectMeta, p.Spec) && gp.Annotations[VersionAnnotation] == Version {
+		clog.Debug("System policy is already up to date in Calico datastore")
+		return nil
+	}
+
+	clog.Info("Updating system policy in Calico datastore")
+	gp.Spec = p.Spec
+	converter.CopyManagedMetadata(&gp.ObjectMeta, p.ObjectMeta)
+	if gp.Annotations == nil {
+		gp.Annotations = map[string]string{}
+	}
+	gp.Annotations[VersionAnnotation] = Version
+	start = time.Now()
+	_, err = c.calicoClient.GlobalNetworkPolicies().Update(ctx, gp, options.SetOptions{})
+	controller.ObserveDatastoreOp(ctx, "systempolicy", controller.DatastoreOpUpdate, start, err)
+	if err != nil {
+		clog.WithError(err).Warning("Failed to update system policy")
+		return err
+	}
+	return nil
+}
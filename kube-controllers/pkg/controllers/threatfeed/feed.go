@@ -0,0 +1,160 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package threatfeed
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	"github.com/projectcalico/calico/kube-controllers/pkg/networkset"
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+const (
+	// FeedLabel is set on each feed's GlobalNetworkSet to the name of the feed, so that policies
+	// can select the sets of particular feeds, or all of them with has(projectcalico.org/threat-feed).
+	FeedLabel = "projectcalico.org/threat-feed"
+
+	// Annotations recording where a set's networks were fetched from, the ETag the server returned
+	// with them, and the SHA-256 checksum of the fetched content. The ETag is sent with the next
+	// request so that an unchanged feed isn't downloaded again, and the checksum avoids parsing and
+	// rewriting a feed whose server doesn't support ETags.
+	SourceURLAnnotation = "projectcalico.org/threat-feed-url"
+	ETagAnnotation      = "projectcalico.org/threat-feed-etag"
+	ChecksumAnnotation  = "projectcalico.org/threat-feed-sha256"
+
+	// NamePrefix is the prefix of the names of the feeds' GlobalNetworkSets, which are followed by
+	// the name of the feed.
+	NamePrefix = "kfeed-"
+
+	// SourceKind identifies the feeds' GlobalNetworkSets in their ownership labels.
+	SourceKind = "ThreatFeed"
+
+	// maxFeedSize is the largest feed that is downloaded, to bound the memory used.
+	maxFeedSize = 16 << 20
+)
+
+// SetName returns the name of the GlobalNetworkSet of the given feed.
+func SetName(feed string) string {
+	return NamePrefix + feed
+}
+
+// Parse returns the networks listed in a feed, consolidated into the fewest CIDRs that cover the
+// same addresses, and the number of lines that couldn't be parsed. Each line holds an IP address
+// or CIDR, optionally followed by whitespace or a comment starting with '#' or ';', which is the
+// format of most published deny-lists.
+func Parse(content []byte) ([]string, int, error) {
+	var nets []string
+	invalid := 0
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexAny(line, "#;"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if _, _, err := cnet.ParseCIDROrIP(fields[0]); err != nil {
+			invalid++
+			continue
+		}
+		nets = append(nets, fields[0])
+	}
+	// Feeds often list overlapping or adjacent networks, so consolidate them to keep the set,
+	// and Felix's IP sets, small.
+	consolidated, err := networkset.ConsolidateCIDRs(nets)
+	if err != nil {
+		return nil, invalid, err
+	}
+	return consolidated, invalid, nil
+}
+
+// DesiredSet returns the GlobalNetworkSet for the given feed and its networks.
+func DesiredSet(feed, url string, nets []string, etag, checksum string) *api.GlobalNetworkSet {
+	gns := api.NewGlobalNetworkSet()
+	gns.Name = SetName(feed)
+	gns.Labels = map[string]string{FeedLabel: feed}
+	gns.Annotations = map[string]string{
+		SourceURLAnnotation: url,
+		ChecksumAnnotation:  checksum,
+	}
+	if etag != "" {
+		gns.Annotations[ETagAnnotation] = etag
+	}
+	gns.Spec.Nets = nets
+	converter.SetOwnership(&gns.ObjectMeta, SourceKind, "")
+	return gns
+}
+
+// fetchResult is the outcome of fetching a feed.
+type fetchResult struct {
+	// Whether the server reported that the feed hasn't changed since the given ETag.
+	notModified bool
+
+	content  []byte
+	etag     string
+	checksum string
+}
+
+// fetch downloads the feed at the given URL, unless the server reports that it hasn't changed
+// since the given ETag.
+func fetch(ctx context.Context, client *http.Client, url, etag string) (fetchResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fetchResult{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return fetchResult{notModified: true, etag: etag}, nil
+	case http.StatusOK:
+	default:
+		return fetchResult{}, fmt.Errorf("unexpected status fetching %s: %s", url, resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedSize+1))
+	if err != nil {
+		return fetchResult{}, err
+	}
+	if len(content) > maxFeedSize {
+		return fetchResult{}, fmt.Errorf("feed %s is larger than %d bytes", url, maxFeedSize)
+	}
+	sum := sha256.Sum256(content)
+	return fetchResult{
+		content:  content,
+		etag:     resp.Header.Get("ETag"),
+		checksum: hex.EncodeToString(sum[:]),
+	}, nil
+}
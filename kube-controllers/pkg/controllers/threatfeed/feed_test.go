@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package threatfeed
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+)
+
+var _ = Describe("Threat feeds", func() {
+	It("should parse IPs and CIDRs, ignoring comments and invalid lines", func() {
+		nets, invalid, err := Parse([]byte(`; Spamhaus DROP List
+1.10.16.0/20 ; SBL256894
+# a comment
+192.0.2.7
+192.0.2.7/32
+10.1.2.3/8	# host bits set
+2001:db8::1
+
+<html>
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nets).To(Equal([]string{"1.10.16.0/20", "10.0.0.0/8", "192.0.2.7/32", "2001:db8::1/128"}))
+		Expect(invalid).To(Equal(1))
+	})
+
+	It("should consolidate overlapping and adjacent networks", func() {
+		nets, invalid, err := Parse([]byte(`10.0.0.0/8
+10.1.0.0/16
+192.0.2.0/25
+192.0.2.128/25
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nets).To(Equal([]string{"10.0.0.0/8", "192.0.2.0/24"}))
+		Expect(invalid).To(BeZero())
+	})
+
+	It("should build a managed GlobalNetworkSet", func() {
+		gns := DesiredSet("drop", "https://example.com/drop.txt", []string{"10.0.0.0/8"}, `"v1"`, "abc")
+		Expect(gns.Name).To(Equal("kfeed-drop"))
+		Expect(gns.Labels).To(HaveKeyWithValue(FeedLabel, "drop"))
+		Expect(gns.Annotations).To(Equal(map[string]string{
+			SourceURLAnnotation: "https://example.com/drop.txt",
+			ETagAnnotation:      `"v1"`,
+			ChecksumAnnotation:  "abc",
+		}))
+		Expect(converter.IsManaged(gns.ObjectMeta, SourceKind)).To(BeTrue())
+		Expect(gns.Spec.Nets).To(Equal([]string{"10.0.0.0/8"}))
+	})
+
+	Describe("fetching", func() {
+		var (
+			server   *httptest.Server
+			status   int
+			requests []string
+		)
+
+		BeforeEach(func() {
+			status = http.StatusOK
+			requests = nil
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Header.Get("If-None-Match"))
+				if r.Header.Get("If-None-Match") == `"v1"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte("10.0.0.0/8\n"))
+			}))
+		})
+
+		AfterEach(func() {
+			server.Close()
+		})
+
+		It("should use the ETag to skip unchanged feeds", func() {
+			res, err := fetch(context.Background(), server.Client(), server.URL, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.notModified).To(BeFalse())
+			Expect(string(res.content)).To(Equal("10.0.0.0/8\n"))
+			Expect(res.etag).To(Equal(`"v1"`))
+			Expect(res.checksum).To(HaveLen(64))
+
+			res, err = fetch(context.Background(), server.Client(), server.URL, res.etag)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.notModified).To(BeTrue())
+			Expect(requests).To(Equal([]string{"", `"v1"`}))
+		})
+
+		It("should fail on error responses", func() {
+			status = http.StatusNotFound
+			_, err := fetch(context.Background(), server.Client(), server.URL, "")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,211 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package threatfeed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/config"
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+var (
+	networksGauge      *prometheus.GaugeVec
+	fetchErrorsCounter *prometheus.CounterVec
+)
+
+func init() {
+	networksGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "threat_feed_networks",
+		Help: "Number of networks in the GlobalNetworkSet of each threat feed",
+	}, []string{"feed"})
+	fetchErrorsCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "threat_feed_fetch_errors_total",
+		Help: "Number of failed attempts to fetch and apply each threat feed",
+	}, []string{"feed"})
+	prometheus.MustRegister(networksGauge, fetchErrorsCounter)
+}
+
+// threatFeedController periodically fetches lists of IPs and CIDRs, such as published deny-lists,
+// from the configured URLs, and maintains a GlobalNetworkSet with the networks of each. If a feed
+// can't be fetched, its set is left as it is, so that a deny-list isn't emptied by an outage.
+type threatFeedController struct {
+	ctx          context.Context
+	calicoClient client.Interface
+	cfg          config.ThreatFeedControllerConfig
+	httpClient   *http.Client
+}
+
+// NewThreatFeedController returns a controller which maintains a GlobalNetworkSet for each of the
+// configured feeds.
+func NewThreatFeedController(ctx context.Context, c client.Interface, cfg config.ThreatFeedControllerConfig) controller.Controller {
+	return &threatFeedController{
+		ctx:          ctx,
+		calicoClient: c,
+		cfg:          cfg,
+		httpClient:   &http.Client{Timeout: time.Minute},
+	}
+}
+
+// Run starts the controller.
+func (c *threatFeedController) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+
+	log.WithField("feeds", len(c.cfg.Feeds)).Info("Starting threat feed controller")
+	if err := c.deleteUnconfigured(); err != nil {
+		log.WithError(err).Warning("Failed to delete the GlobalNetworkSets of removed threat feeds")
+	}
+	c.syncAll()
+
+	ticker := time.NewTicker(c.cfg.ReconcilerPeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			log.Info("Stopping threat feed controller")
+			return
+		case <-ticker.C:
+			c.syncAll()
+		}
+	}
+}
+
+func (c *threatFeedController) syncAll() {
+	for _, feed := range c.cfg.Feeds {
+		ctx, cancel := controller.WithSyncTimeout(c.ctx, c.cfg.SyncTimeout)
+		err := c.syncFeed(ctx, feed)
+		cancel()
+		if err != nil {
+			log.WithError(err).WithField("feed", feed.Name).Warning("Failed to sync threat feed, keeping its current networks")
+			fetchErrorsCounter.WithLabelValues(feed.Name).Inc()
+		}
+	}
+}
+
+// syncFeed fetches the given feed, and writes its networks to its GlobalNetworkSet if they have
+// changed.
+func (c *threatFeedController) syncFeed(ctx context.Context, feed config.ThreatFeed) error {
+	clog := log.WithFields(log.Fields{"feed": feed.Name, "url": feed.URL})
+	name := SetName(feed.Name)
+
+	start := time.Now()
+	current, err := c.calicoClient.GlobalNetworkSets().Get(ctx, name, options.GetOptions{})
+	controller.ObserveDatastoreOp(ctx, "threatfeed", controller.DatastoreOpGet, start, err)
+	if err != nil {
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			return err
+		}
+		current = nil
+	}
+	var etag, checksum string
+	if current != nil {
+		if !converter.IsManaged(current.ObjectMeta, SourceKind) {
+			return fmt.Errorf("GlobalNetworkSet %s already exists, and isn't managed by the controller", name)
+		}
+		networksGauge.WithLabelValues(feed.Name).Set(float64(len(current.Spec.Nets)))
+		if current.Annotations[SourceURLAnnotation] == feed.URL {
+			etag = current.Annotations[ETagAnnotation]
+			checksum = current.Annotations[ChecksumAnnotation]
+		}
+	}
+
+	res, err := fetch(ctx, c.httpClient, feed.URL, etag)
+	if err != nil {
+		return err
+	}
+	if res.notModified || (current != nil && res.checksum == checksum && res.etag == etag) {
+		clog.Debug("Threat feed hasn't changed")
+		return nil
+	}
+
+	nets, invalid, err := Parse(res.content)
+	if err != nil {
+		return err
+	}
+	if invalid > 0 {
+		clog.WithField("invalidLines", invalid).Warning("Ignoring lines of threat feed that aren't IPs or CIDRs")
+		if len(nets) == 0 {
+			// Most likely an error page, rather than an empty feed.
+			return fmt.Errorf("threat feed %s has no valid networks", feed.URL)
+		}
+	}
+	desired := DesiredSet(feed.Name, feed.URL, nets, res.etag, res.checksum)
+
+	start = time.Now()
+	if current == nil {
+		clog.WithField("networks", len(nets)).Info("Creating GlobalNetworkSet for threat feed")
+		_, err = c.calicoClient.GlobalNetworkSets().Create(ctx, desired, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "threatfeed", controller.DatastoreOpCreate, start, err)
+	} else {
+		clog.WithField("networks", len(nets)).Info("Updating GlobalNetworkSet for threat feed")
+		for k, v := range desired.Labels {
+			current.Labels[k] = v
+		}
+		if current.Annotations == nil {
+			current.Annotations = map[string]string{}
+		}
+		delete(current.Annotations, ETagAnnotation)
+		for k, v := range desired.Annotations {
+			current.Annotations[k] = v
+		}
+		current.Spec = desired.Spec
+		_, err = c.calicoClient.GlobalNetworkSets().Update(ctx, current, options.SetOptions{})
+		controller.ObserveDatastoreOp(ctx, "threatfeed", controller.DatastoreOpUpdate, start, err)
+	}
+	if err != nil {
+		return err
+	}
+	networksGauge.WithLabelValues(feed.Name).Set(float64(len(nets)))
+	return nil
+}
+
+// deleteUnconfigured deletes the GlobalNetworkSets of feeds that are no longer configured.
+func (c *threatFeedController) deleteUnconfigured() error {
+	configured := map[string]bool{}
+	for _, feed := range c.cfg.Feeds {
+		configured[SetName(feed.Name)] = true
+	}
+
+	start := time.Now()
+	sets, err := c.calicoClient.GlobalNetworkSets().List(c.ctx, options.ListOptions{})
+	controller.ObserveDatastoreOp(c.ctx, "threatfeed", controller.DatastoreOpList, start, err)
+	if err != nil {
+		return err
+	}
+	for _, gns := range sets.Items {
+		if configured[gns.Name] || !converter.IsManaged(gns.ObjectMeta, SourceKind) {
+			continue
+		}
+		log.WithField("name", gns.Name).Info("Deleting GlobalNetworkSet of removed threat feed")
+		start = time.Now()
+		_, err := c.calicoClient.GlobalNetworkSets().Delete(c.ctx, gns.Name, options.DeleteOptions{ResourceVersion: gns.ResourceVersion})
+		controller.ObserveDatastoreOp(c.ctx, "threatfeed", controller.DatastoreOpDelete, start, err)
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); err != nil && !ok {
+			return err
+		}
+	}
+	return nil
+}
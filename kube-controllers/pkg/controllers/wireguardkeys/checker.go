@@ -0,0 +1,192 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguardkeys
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	uruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/controller"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+var (
+	problemsGauge  *prometheus.GaugeVec
+	clearedCounter prometheus.Counter
+)
+
+func init() {
+	problemsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "wireguard_key_problems",
+		Help: "Number of node WireGuard public keys that are missing, stale, invalid or duplicated",
+	}, []string{"problem"})
+	clearedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "wireguard_keys_cleared_total",
+		Help: "Number of stale or invalid node WireGuard public keys that have been cleared",
+	})
+	prometheus.MustRegister(problemsGauge, clearedCounter)
+}
+
+// checker periodically looks for problems with the WireGuard public keys of the nodes. A broken
+// key silently blackholes the encrypted traffic to its node, so each problem is logged and
+// reported as an Event on the node.
+type checker struct {
+	ctx          context.Context
+	k8sClientset *kubernetes.Clientset
+	calicoClient client.Interface
+	period       time.Duration
+	clear        bool
+	recorder     record.EventRecorder
+
+	// Problems found by the previous check. A problem is only reported once it is seen in two
+	// consecutive checks, since keys are briefly missing or stale while WireGuard is enabled or
+	// disabled.
+	previous map[string]bool
+
+	// Problems that have already been reported.
+	reported map[string]bool
+}
+
+// NewWireguardKeyChecker returns a controller which looks for problems with the nodes' WireGuard
+// public keys once every period. If clear is true, stale and invalid keys are removed.
+func NewWireguardKeyChecker(ctx context.Context, k8sClientset *kubernetes.Clientset, c client.Interface, period time.Duration, clear bool) controller.Controller {
+	return &checker{
+		ctx:          ctx,
+		k8sClientset: k8sClientset,
+		calicoClient: c,
+		period:       period,
+		clear:        clear,
+		previous:     map[string]bool{},
+		reported:     map[string]bool{},
+	}
+}
+
+// Run starts the checker.
+func (c *checker) Run(stopCh chan struct{}) {
+	defer uruntime.HandleCrash()
+
+	log.Info("Starting WireGuard key checker")
+
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.k8sClientset.CoreV1().Events("")})
+	defer broadcaster.Shutdown()
+	c.recorder = broadcaster.NewRecorder(scheme.Scheme, v1.EventSource{Component: "calico-kube-controllers"})
+
+	ticker := time.NewTicker(c.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			log.Info("Stopping WireGuard key checker")
+			return
+		case <-ticker.C:
+			if err := c.check(); err != nil {
+				log.WithError(err).Warning("Failed to check WireGuard keys")
+			}
+		}
+	}
+}
+
+func (c *checker) check() error {
+	s, err := c.snapshot()
+	if err != nil {
+		return err
+	}
+
+	counts := map[Problem]int{}
+	current := map[string]bool{}
+	for _, p := range Detect(s) {
+		id := p.ID()
+		current[id] = true
+		if !c.previous[id] {
+			continue
+		}
+		counts[p.Problem]++
+		if !c.reported[id] {
+			log.WithFields(log.Fields{"problem": p.Problem, "node": p.Node, "ipVersion": p.IPVersion}).Warning(p.Message())
+			if obj := p.Object(); obj != nil {
+				c.recorder.Event(obj, v1.EventTypeWarning, "CalicoWireguardKeyProblem", p.Message())
+			}
+			c.reported[id] = true
+		}
+		if c.clear && p.Clearable() {
+			c.clearKey(p)
+		}
+	}
+	for id := range c.reported {
+		if !current[id] {
+			log.WithField("problem", id).Info("WireGuard key problem has been resolved")
+			delete(c.reported, id)
+		}
+	}
+	c.previous = current
+
+	for _, problem := range AllProblems {
+		problemsGauge.WithLabelValues(string(problem)).Set(float64(counts[problem]))
+	}
+	return nil
+}
+
+// clearKey removes the node's key, if it hasn't changed since the problem was detected. Any errors
+// are logged, and the key is cleared on the next check if the problem remains.
+func (c *checker) clearKey(p KeyProblem) {
+	logc := log.WithFields(log.Fields{"problem": p.Problem, "node": p.Node, "ipVersion": p.IPVersion})
+	n, err := c.calicoClient.Nodes().Get(c.ctx, p.Node, options.GetOptions{})
+	if err != nil {
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); !ok {
+			logc.WithError(err).Warning("Failed to clear WireGuard key of node")
+		}
+		return
+	}
+	if publicKey(*n, p.IPVersion) != p.Key {
+		return
+	}
+	logc.Info("Clearing WireGuard key of node")
+	setPublicKey(n, p.IPVersion, "")
+	if _, err := c.calicoClient.Nodes().Update(c.ctx, n, options.SetOptions{}); err != nil {
+		logc.WithError(err).Warning("Failed to clear WireGuard key of node")
+		return
+	}
+	clearedCounter.Inc()
+}
+
+// snapshot lists the resources needed to detect key problems.
+func (c *checker) snapshot() (Snapshot, error) {
+	var s Snapshot
+
+	nodes, err := c.calicoClient.Nodes().List(c.ctx, options.ListOptions{})
+	if err != nil {
+		return s, err
+	}
+	s.Nodes = nodes.Items
+
+	fcs, err := c.calicoClient.FelixConfigurations().List(c.ctx, options.ListOptions{})
+	if err != nil {
+		return s, err
+	}
+	s.FelixConfigurations = fcs.Items
+	return s, nil
+}
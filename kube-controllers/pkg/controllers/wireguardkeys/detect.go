@@ -0,0 +1,221 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguardkeys
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	v1 "k8s.io/api/core/v1"
+
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+)
+
+// Problem identifies what is wrong with a node's WireGuard public key.
+type Problem string
+
+const (
+	// WireGuard is enabled for the node, but it hasn't published a public key, so other nodes
+	// can't encrypt traffic to it.
+	ProblemMissing Problem = "missing"
+
+	// WireGuard is disabled for the node, but it still has a public key, so other nodes encrypt
+	// traffic to it that it can't decrypt.
+	ProblemStale Problem = "stale"
+
+	// The node's public key isn't a valid WireGuard key.
+	ProblemInvalid Problem = "invalid"
+
+	// More than one node has the same public key, so other nodes can only reach one of them.
+	ProblemDuplicate Problem = "duplicate"
+)
+
+// AllProblems lists every kind of key problem.
+var AllProblems = []Problem{ProblemMissing, ProblemStale, ProblemInvalid, ProblemDuplicate}
+
+// Snapshot is the set of resources that key problems are detected in.
+type Snapshot struct {
+	Nodes               []libapi.Node
+	FelixConfigurations []api.FelixConfiguration
+}
+
+// KeyProblem describes a problem with the IPv4 or IPv6 public key of a node.
+type KeyProblem struct {
+	Problem   Problem
+	Node      string
+	K8sNode   string
+	IPVersion int
+
+	// The node's public key, if it has one.
+	Key string
+
+	// For duplicates, the other nodes with the same key, sorted by name.
+	Others []string
+}
+
+// ID returns a unique identifier for the problem.
+func (p KeyProblem) ID() string {
+	return fmt.Sprintf("%s/%s/v%d", p.Problem, p.Node, p.IPVersion)
+}
+
+// Clearable returns true if the key should be removed from the node. Felix publishes the key of
+// a node that is running WireGuard again, so only keys that can't be used are cleared.
+func (p KeyProblem) Clearable() bool {
+	return p.Problem == ProblemStale || p.Problem == ProblemInvalid
+}
+
+// Message returns a description of the problem, suitable for logs and Events.
+func (p KeyProblem) Message() string {
+	switch p.Problem {
+	case ProblemMissing:
+		return fmt.Sprintf("IPv%d WireGuard is enabled for node %s, but it has no public key", p.IPVersion, p.Node)
+	case ProblemStale:
+		return fmt.Sprintf("IPv%d WireGuard is disabled for node %s, but it still has a public key", p.IPVersion, p.Node)
+	case ProblemInvalid:
+		return fmt.Sprintf("Node %s has an invalid IPv%d WireGuard public key %q", p.Node, p.IPVersion, p.Key)
+	default:
+		return fmt.Sprintf("Node %s has the same IPv%d WireGuard public key as %s", p.Node, p.IPVersion, strings.Join(p.Others, ", "))
+	}
+}
+
+// Object returns the Kubernetes node that any Event for the problem should be attached to, or nil
+// if the node isn't a Kubernetes node.
+func (p KeyProblem) Object() *v1.ObjectReference {
+	if p.K8sNode == "" {
+		return nil
+	}
+	return &v1.ObjectReference{APIVersion: "v1", Kind: "Node", Name: p.K8sNode}
+}
+
+// Detect returns the key problems of the nodes in the given snapshot, sorted by ID. Whether
+// WireGuard is enabled for a node comes from its own FelixConfiguration, or the default one. Felix
+// can also be configured with environment variables, so nodes whose FelixConfiguration doesn't
+// set whether WireGuard is enabled are only checked for invalid and duplicate keys.
+func Detect(s Snapshot) []KeyProblem {
+	configs := map[string]api.FelixConfigurationSpec{}
+	for _, fc := range s.FelixConfigurations {
+		configs[fc.Name] = fc.Spec
+	}
+
+	var problems []KeyProblem
+	for _, version := range []int{4, 6} {
+		owners := map[string][]string{}
+		for _, n := range s.Nodes {
+			key := publicKey(n, version)
+			p := KeyProblem{Node: n.Name, K8sNode: k8sNodeName(n), IPVersion: version, Key: key}
+			enabled := wireguardEnabled(configs, n.Name, version)
+			switch {
+			case key == "" && enabled != nil && *enabled:
+				p.Problem = ProblemMissing
+			case key == "":
+				continue
+			case enabled != nil && !*enabled:
+				p.Problem = ProblemStale
+			case !validKey(key):
+				p.Problem = ProblemInvalid
+			default:
+				owners[key] = append(owners[key], n.Name)
+				continue
+			}
+			problems = append(problems, p)
+		}
+
+		for key, nodes := range owners {
+			if len(nodes) < 2 {
+				continue
+			}
+			sort.Strings(nodes)
+			for _, n := range s.Nodes {
+				if publicKey(n, version) != key {
+					continue
+				}
+				var others []string
+				for _, o := range nodes {
+					if o != n.Name {
+						others = append(others, o)
+					}
+				}
+				problems = append(problems, KeyProblem{
+					Problem:   ProblemDuplicate,
+					Node:      n.Name,
+					K8sNode:   k8sNodeName(n),
+					IPVersion: version,
+					Key:       key,
+					Others:    others,
+				})
+			}
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].ID() < problems[j].ID()
+	})
+	return problems
+}
+
+// wireguardEnabled returns whether WireGuard is enabled for the given node and IP version by its
+// FelixConfiguration, or nil if that isn't configured.
+func wireguardEnabled(configs map[string]api.FelixConfigurationSpec, node string, version int) *bool {
+	for _, name := range []string{"node." + node, "default"} {
+		spec, ok := configs[name]
+		if !ok {
+			continue
+		}
+		enabled := spec.WireguardEnabled
+		if version == 6 {
+			enabled = spec.WireguardEnabledV6
+		}
+		if enabled != nil {
+			return enabled
+		}
+	}
+	return nil
+}
+
+// publicKey returns the node's public key for the given IP version.
+func publicKey(n libapi.Node, version int) string {
+	if version == 6 {
+		return n.Status.WireguardPublicKeyV6
+	}
+	return n.Status.WireguardPublicKey
+}
+
+// setPublicKey sets the node's public key for the given IP version.
+func setPublicKey(n *libapi.Node, version int, key string) {
+	if version == 6 {
+		n.Status.WireguardPublicKeyV6 = key
+	} else {
+		n.Status.WireguardPublicKey = key
+	}
+}
+
+// validKey returns true if the key is a base64 encoded 32 byte WireGuard key.
+func validKey(key string) bool {
+	b, err := base64.StdEncoding.DecodeString(key)
+	return err == nil && len(b) == 32
+}
+
+// k8sNodeName returns the name of the Kubernetes node of the given Calico node, if any.
+func k8sNodeName(n libapi.Node) string {
+	for _, ref := range n.Spec.OrchRefs {
+		if ref.Orchestrator == api.OrchestratorKubernetes {
+			return ref.NodeName
+		}
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wireguardkeys_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/controllers/wireguardkeys"
+	libapi "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+)
+
+const (
+	keyA = "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+	keyB = "BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBA="
+)
+
+func node(name, key, keyV6 string) libapi.Node {
+	return libapi.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       libapi.NodeSpec{OrchRefs: []libapi.OrchRef{{Orchestrator: "k8s", NodeName: name}}},
+		Status:     libapi.NodeStatus{WireguardPublicKey: key, WireguardPublicKeyV6: keyV6},
+	}
+}
+
+func felixConfig(name string, enabled, enabledV6 *bool) api.FelixConfiguration {
+	return api.FelixConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       api.FelixConfigurationSpec{WireguardEnabled: enabled, WireguardEnabledV6: enabledV6},
+	}
+}
+
+func problems(ps []wireguardkeys.KeyProblem) []string {
+	ids := []string{}
+	for _, p := range ps {
+		ids = append(ids, p.ID())
+	}
+	return ids
+}
+
+var _ = Describe("WireGuard key problem detection", func() {
+	enabled, disabled := true, false
+
+	It("should find nothing if every node has its own valid key", func() {
+		Expect(wireguardkeys.Detect(wireguardkeys.Snapshot{
+			Nodes:               []libapi.Node{node("a", keyA, ""), node("b", keyB, "")},
+			FelixConfigurations: []api.FelixConfiguration{felixConfig("default", &enabled, nil)},
+		})).To(BeEmpty())
+	})
+
+	It("should find missing and stale keys according to the nodes' configuration", func() {
+		ps := wireguardkeys.Detect(wireguardkeys.Snapshot{
+			Nodes: []libapi.Node{node("a", "", keyA), node("b", keyB, "")},
+			FelixConfigurations: []api.FelixConfiguration{
+				felixConfig("default", &enabled, &disabled),
+				felixConfig("node.b", &disabled, nil),
+			},
+		})
+		Expect(problems(ps)).To(Equal([]string{"missing/a/v4", "stale/a/v6", "stale/b/v4"}))
+		Expect(ps[0].Clearable()).To(BeFalse())
+		Expect(ps[1].Clearable()).To(BeTrue())
+		Expect(ps[2].Object().Name).To(Equal("b"))
+	})
+
+	It("should only check for missing and stale keys if WireGuard is configured", func() {
+		Expect(wireguardkeys.Detect(wireguardkeys.Snapshot{
+			Nodes: []libapi.Node{node("a", "", ""), node("b", keyB, keyA)},
+		})).To(BeEmpty())
+	})
+
+	It("should find invalid and duplicate keys", func() {
+		ps := wireguardkeys.Detect(wireguardkeys.Snapshot{
+			Nodes: []libapi.Node{node("a", keyA, ""), node("b", keyA, ""), node("c", "not-a-key", "")},
+		})
+		Expect(problems(ps)).To(Equal([]string{"duplicate/a/v4", "duplicate/b/v4", "invalid/c/v4"}))
+		Expect(ps[0].Others).To(Equal([]string{"b"}))
+		Expect(ps[0].Message()).To(Equal("Node a has the same IPv4 WireGuard public key as b"))
+		Expect(ps[0].Clearable()).To(BeFalse())
+		Expect(ps[2].Clearable()).To(BeTrue())
+	})
+})
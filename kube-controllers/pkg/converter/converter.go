@@ -14,6 +14,8 @@
 
 package converter
 
+import "fmt"
+
 // Converter Responsible for conversion of given kubernetes object to equivalent calico object
 type Converter interface {
 	// Converts kubernetes object to calico representation of it.
@@ -26,3 +28,54 @@ type Converter interface {
 	// for the given key as generated by GetKey.
 	DeleteArgsFromKey(key string) (string, string)
 }
+
+// MultiConverter is implemented by converters that may generate more than one calico object
+// from a single kubernetes object, for example to split a policy across several resources.
+type MultiConverter interface {
+	Converter
+
+	// Converts kubernetes object to all of its calico representations.
+	ConvertAll(k8sObj interface{}) ([]interface{}, error)
+}
+
+// ConvertTo converts the given kubernetes object with the given converter, returning an error
+// rather than panicking if the converter doesn't return a T.
+func ConvertTo[T any](c Converter, k8sObj interface{}) (T, error) {
+	var zero T
+	obj, err := c.Convert(k8sObj)
+	if err != nil {
+		return zero, err
+	}
+	t, ok := obj.(T)
+	if !ok {
+		return zero, fmt.Errorf("converter returned a %T rather than a %T", obj, zero)
+	}
+	return t, nil
+}
+
+// ConvertAll converts the given kubernetes object with the given converter, returning all of
+// the calico objects generated from it.
+func ConvertAll[T any](c Converter, k8sObj interface{}) ([]T, error) {
+	mc, ok := c.(MultiConverter)
+	if !ok {
+		t, err := ConvertTo[T](c, k8sObj)
+		if err != nil {
+			return nil, err
+		}
+		return []T{t}, nil
+	}
+	objs, err := mc.ConvertAll(k8sObj)
+	if err != nil {
+		return nil, err
+	}
+	ts := make([]T, 0, len(objs))
+	for _, obj := range objs {
+		t, ok := obj.(T)
+		if !ok {
+			var zero T
+			return nil, fmt.Errorf("converter returned a %T rather than a %T", obj, zero)
+		}
+		ts = append(ts, t)
+	}
+	return ts, nil
+}
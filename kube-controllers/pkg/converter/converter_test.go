@@ -0,0 +1,71 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+
+	k8sapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// splitConverter generates one object per character of its string input.
+type splitConverter struct {
+	converter.Converter
+}
+
+func (c splitConverter) ConvertAll(k8sObj interface{}) ([]interface{}, error) {
+	s, ok := k8sObj.(string)
+	if !ok {
+		return nil, errors.New("not a string")
+	}
+	var objs []interface{}
+	for _, r := range s {
+		objs = append(objs, string(r))
+	}
+	return objs, nil
+}
+
+var _ = Describe("ConvertAll", func() {
+	It("should return the single object from a Converter", func() {
+		c := converter.NewServiceAccountConverter()
+		_, err := converter.ConvertAll[api.Profile](c, "not a service account")
+		Expect(err).To(HaveOccurred())
+
+		objs, err := converter.ConvertAll[api.Profile](c, &k8sapi.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "sa", Namespace: "default", UID: "aa844ac0-87c8-440a-b270-307cdba8fd25"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(HaveLen(1))
+	})
+
+	It("should return all of the objects from a MultiConverter", func() {
+		objs, err := converter.ConvertAll[string](splitConverter{}, "abc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objs).To(Equal([]string{"a", "b", "c"}))
+	})
+
+	It("should return an error if the converter returns the wrong type", func() {
+		_, err := converter.ConvertTo[api.NetworkPolicy](converter.NewServiceAccountConverter(), &k8sapi.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "sa", Namespace: "default", UID: "aa844ac0-87c8-440a-b270-307cdba8fd25"}})
+		Expect(err).To(HaveOccurred())
+		_, err = converter.ConvertAll[int](splitConverter{}, "abc")
+		Expect(err).To(HaveOccurred())
+	})
+})
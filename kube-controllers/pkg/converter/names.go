@@ -0,0 +1,158 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
+)
+
+const (
+	// DefaultMaxNameLength is the maximum length of a Calico resource name.
+	DefaultMaxNameLength = 253
+
+	// MinMaxNameLength is the smallest maximum name length that may be configured. It leaves
+	// room for the generated name prefix, some of the original name and the hash suffix.
+	MinMaxNameLength = 32
+
+	// SourceNameAnnotation is set on generated resources whose names have been shortened,
+	// and records the name of the Kubernetes resource they were generated from.
+	SourceNameAnnotation = "projectcalico.org/source-name"
+
+	// MaxNamePrefixLength is the maximum length of a configured name prefix. It leaves room for
+	// some of the original name when generated names are shortened.
+	MaxNamePrefixLength = 16
+
+	// nameHashLength is the number of hex characters of the hash appended to shortened names.
+	nameHashLength = 10
+)
+
+var namePrefixRegex = regexp.MustCompile(`^[a-z0-9][-a-z0-9.]*$`)
+
+// ValidateNamePrefix returns an error if the given prefix can't be used to generate valid
+// resource names, or if it overlaps the default prefix or any of the given prefixes used by
+// other instances. Two prefixes overlap if either is a prefix of the other, since each instance
+// would then treat the other's resources as its own and delete them.
+//
+// Note that changing the prefix of an existing instance orphans the resources it generated with
+// the previous prefix; they are no longer managed and must be removed by hand.
+func ValidateNamePrefix(prefix string, others ...string) error {
+	if len(prefix) > MaxNamePrefixLength {
+		return fmt.Errorf("name prefix %q is longer than %d characters", prefix, MaxNamePrefixLength)
+	}
+	if !namePrefixRegex.MatchString(prefix) {
+		return fmt.Errorf("name prefix %q must start with a lower case alphanumeric character and contain only lower case alphanumeric characters, '-' or '.'", prefix)
+	}
+	if prefix != conversion.K8sNetworkPolicyNamePrefix && prefixesOverlap(prefix, conversion.K8sNetworkPolicyNamePrefix) {
+		return fmt.Errorf("name prefix %q overlaps the default prefix %q", prefix, conversion.K8sNetworkPolicyNamePrefix)
+	}
+	for _, other := range others {
+		if prefixesOverlap(prefix, other) {
+			return fmt.Errorf("name prefix %q overlaps the prefix %q of another instance", prefix, other)
+		}
+	}
+	return nil
+}
+
+func prefixesOverlap(a, b string) bool {
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// ShortenName returns the given name unchanged if it is no longer than maxLen. Otherwise, it
+// returns the name truncated and suffixed with a hash of the full name, such that the result
+// is exactly maxLen characters or fewer. The result is deterministic for a given name.
+func ShortenName(name string, maxLen int) string {
+	if len(name) <= maxLen {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:nameHashLength]
+
+	// Trim any separators from the end of the truncated name so that the result is still a
+	// valid DNS subdomain.
+	truncated := strings.TrimRight(name[:maxLen-nameHashLength-1], "-.")
+	return truncated + "-" + hash
+}
+
+// NameRegistry tracks which source resource each generated name belongs to, so that two
+// different sources that shorten to the same name can be detected rather than silently
+// overwriting each other.
+type NameRegistry struct {
+	sync.Mutex
+	owners map[string]string
+
+	// names indexes the generated names by source, since one source may generate several.
+	names map[string]map[string]struct{}
+}
+
+func NewNameRegistry() *NameRegistry {
+	return &NameRegistry{owners: map[string]string{}, names: map[string]map[string]struct{}{}}
+}
+
+// Register records that the generated name belongs to the given source. It returns an error
+// if the name is already owned by a different source.
+func (r *NameRegistry) Register(name, source string) error {
+	r.Lock()
+	defer r.Unlock()
+	if owner, ok := r.owners[name]; ok && owner != source {
+		return fmt.Errorf("generated name %q for %q collides with the name generated for %q", name, source, owner)
+	}
+	r.owners[name] = source
+	if r.names[source] == nil {
+		r.names[source] = map[string]struct{}{}
+	}
+	r.names[source][name] = struct{}{}
+	return nil
+}
+
+// Source returns the source that the generated name belongs to, if any.
+func (r *NameRegistry) Source(name string) (string, bool) {
+	r.Lock()
+	defer r.Unlock()
+	source, ok := r.owners[name]
+	return source, ok
+}
+
+// Release removes the generated name from the registry if it is owned by the given source.
+func (r *NameRegistry) Release(name, source string) {
+	r.Lock()
+	defer r.Unlock()
+	if owner, ok := r.owners[name]; ok && owner == source {
+		delete(r.owners, name)
+		delete(r.names[source], name)
+		if len(r.names[source]) == 0 {
+			delete(r.names, source)
+		}
+	}
+}
+
+// Names returns the generated names that belong to the given source, sorted.
+func (r *NameRegistry) Names(source string) []string {
+	r.Lock()
+	defer r.Unlock()
+	names := make([]string, 0, len(r.names[source]))
+	for name := range r.names[source] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
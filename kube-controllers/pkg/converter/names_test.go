@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Name shortening tests", func() {
+	It("should not change names that fit", func() {
+		Expect(converter.ShortenName("knp.default.short", 253)).To(Equal("knp.default.short"))
+	})
+
+	It("should deterministically shorten long names", func() {
+		long := "knp.default." + strings.Repeat("a", 300)
+		short := converter.ShortenName(long, 253)
+		Expect(short).To(HaveLen(253))
+		Expect(short).To(HavePrefix("knp.default.aaaa"))
+		Expect(converter.ShortenName(long, 253)).To(Equal(short))
+	})
+
+	It("should generate different names for names that share a prefix", func() {
+		a := converter.ShortenName("knp.default."+strings.Repeat("a", 300)+"x", 64)
+		b := converter.ShortenName("knp.default."+strings.Repeat("a", 300)+"y", 64)
+		Expect(a).NotTo(Equal(b))
+	})
+
+	It("should not leave separators before the hash", func() {
+		short := converter.ShortenName("knp.default."+strings.Repeat("a", 39)+"."+strings.Repeat("b", 20), 64)
+		Expect(short).NotTo(ContainSubstring(".-"))
+	})
+
+	It("should detect collisions between sources", func() {
+		r := converter.NewNameRegistry()
+		Expect(r.Register("ns/knp.default.foo", "ns/foo")).To(Succeed())
+		Expect(r.Register("ns/knp.default.foo", "ns/foo")).To(Succeed())
+		Expect(r.Register("ns/knp.default.foo", "ns/bar")).NotTo(Succeed())
+
+		// Releasing from a non-owner has no effect.
+		r.Release("ns/knp.default.foo", "ns/bar")
+		Expect(r.Register("ns/knp.default.foo", "ns/bar")).NotTo(Succeed())
+
+		source, ok := r.Source("ns/knp.default.foo")
+		Expect(ok).To(BeTrue())
+		Expect(source).To(Equal("ns/foo"))
+
+		r.Release("ns/knp.default.foo", "ns/foo")
+		_, ok = r.Source("ns/knp.default.foo")
+		Expect(ok).To(BeFalse())
+		Expect(r.Register("ns/knp.default.foo", "ns/bar")).To(Succeed())
+	})
+
+	It("should track all of the names generated from a source", func() {
+		r := converter.NewNameRegistry()
+		Expect(r.Register("ns/knp.default.foo-2", "ns/foo")).To(Succeed())
+		Expect(r.Register("ns/knp.default.foo-1", "ns/foo")).To(Succeed())
+		Expect(r.Register("ns/knp.default.bar", "ns/bar")).To(Succeed())
+		Expect(r.Names("ns/foo")).To(Equal([]string{"ns/knp.default.foo-1", "ns/knp.default.foo-2"}))
+
+		r.Release("ns/knp.default.foo-2", "ns/foo")
+		Expect(r.Names("ns/foo")).To(Equal([]string{"ns/knp.default.foo-1"}))
+		r.Release("ns/knp.default.foo-1", "ns/foo")
+		Expect(r.Names("ns/foo")).To(BeEmpty())
+		Expect(r.Names("ns/bar")).To(Equal([]string{"ns/knp.default.bar"}))
+	})
+
+	It("should shorten long policy names and annotate the source name", func() {
+		name := strings.Repeat("p", 250)
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		}
+		pol, err := converter.NewPolicyConverter().Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		p := pol.(api.NetworkPolicy)
+		Expect(len(p.Name)).To(BeNumerically("<=", converter.DefaultMaxNameLength))
+		Expect(p.Name).To(HavePrefix("knp.default.ppp"))
+		Expect(p.Annotations).To(Equal(map[string]string{converter.SourceNameAnnotation: name}))
+	})
+
+	It("should use the configured policy name prefix", func() {
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "allow", Namespace: "default"},
+		}
+		pol, err := converter.NewPolicyConverter(converter.WithNamePrefix("mig.")).Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pol.(api.NetworkPolicy).Name).To(Equal("mig.allow"))
+	})
+
+	It("should validate name prefixes", func() {
+		Expect(converter.ValidateNamePrefix("knp.default.")).To(Succeed())
+		Expect(converter.ValidateNamePrefix("mig-")).To(Succeed())
+		Expect(converter.ValidateNamePrefix("")).NotTo(Succeed())
+		Expect(converter.ValidateNamePrefix(".knp.")).NotTo(Succeed())
+		Expect(converter.ValidateNamePrefix("KNP.")).NotTo(Succeed())
+		Expect(converter.ValidateNamePrefix(strings.Repeat("a", converter.MaxNamePrefixLength+1))).NotTo(Succeed())
+	})
+
+	It("should reject name prefixes that overlap the default or another instance's prefix", func() {
+		Expect(converter.ValidateNamePrefix("knp.")).NotTo(Succeed())
+		Expect(converter.ValidateNamePrefix("knp.default.mig.")).NotTo(Succeed())
+		Expect(converter.ValidateNamePrefix("mig.", "mig.")).NotTo(Succeed())
+		Expect(converter.ValidateNamePrefix("mig.", "mig.a.")).NotTo(Succeed())
+		Expect(converter.ValidateNamePrefix("mig.a.", "mig.")).NotTo(Succeed())
+		Expect(converter.ValidateNamePrefix("mig.a.", "mig.b.")).To(Succeed())
+	})
+})
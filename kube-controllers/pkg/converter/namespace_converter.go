@@ -16,22 +16,74 @@ package converter
 
 import (
 	"fmt"
+	"strings"
 
 	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	log "github.com/sirupsen/logrus"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/cache"
 )
 
+// DefaultDenyAnnotation on a namespace overrides whether its profile is default-deny. When "true",
+// the profile doesn't allow any traffic, so traffic to and from the namespace's pods must be
+// allowed by policy. When "false", the profile allows all traffic.
+const DefaultDenyAnnotation = "projectcalico.org/default-deny"
+
 type namespaceConverter struct {
+	labelDenyList []string
+
+	// Whether profiles are default-deny, unless overridden by DefaultDenyAnnotation.
+	defaultDeny bool
+
+	// Annotations to copy to profiles as labels, and the prefix to add to their keys.
+	annotationLabels      []string
+	annotationLabelPrefix string
+}
+
+// NamespaceConverterOption configures optional behaviour of the namespace converter.
+type NamespaceConverterOption func(*namespaceConverter)
+
+// WithLabelDenyList prevents the given namespace labels from being copied to the generated
+// profiles. Each entry is either a label key, or a prefix of label keys ending in "*". The label
+// holding the namespace name is always copied.
+func WithLabelDenyList(keys []string) NamespaceConverterOption {
+	return func(nc *namespaceConverter) {
+		nc.labelDenyList = keys
+	}
+}
+
+// WithAnnotationLabels copies the given namespace annotations to the generated profiles as labels,
+// so that policies can select namespaces by them. Each entry is either an annotation key, or a
+// prefix of annotation keys ending in "*". The label keys are the annotation keys with the given
+// prefix added. Annotations that don't make a valid label, for example because their value is too
+// long, aren't copied, and labels of the namespace take precedence over copied annotations.
+func WithAnnotationLabels(keys []string, prefix string) NamespaceConverterOption {
+	return func(nc *namespaceConverter) {
+		nc.annotationLabels = keys
+		nc.annotationLabelPrefix = prefix
+	}
+}
+
+// WithDefaultDeny makes the generated profiles default-deny, rather than allowing all traffic,
+// for namespaces that don't set DefaultDenyAnnotation.
+func WithDefaultDeny(defaultDeny bool) NamespaceConverterOption {
+	return func(nc *namespaceConverter) {
+		nc.defaultDeny = defaultDeny
+	}
 }
 
 // NewNamespaceConverter Constructor for namespaceConverter
-func NewNamespaceConverter() Converter {
-	return &namespaceConverter{}
+func NewNamespaceConverter(opts ...NamespaceConverterOption) Converter {
+	nc := &namespaceConverter{}
+	for _, o := range opts {
+		o(nc)
+	}
+	return nc
 }
 func (nc *namespaceConverter) Convert(k8sObj interface{}) (interface{}, error) {
 	c := conversion.NewConverter()
@@ -51,14 +103,82 @@ func (nc *namespaceConverter) Convert(k8sObj interface{}) (interface{}, error) {
 		return nil, err
 	}
 	profile := kvp.Value.(*api.Profile)
+	if nc.isDefaultDeny(namespace) {
+		profile.Spec.Ingress = nil
+		profile.Spec.Egress = nil
+	}
+	nc.addAnnotationLabels(namespace, profile.Spec.LabelsToApply)
+	for k := range profile.Spec.LabelsToApply {
+		if nc.denied(strings.TrimPrefix(k, conversion.NamespaceLabelPrefix)) {
+			delete(profile.Spec.LabelsToApply, k)
+		}
+	}
 
 	// Isolate the metadata fields that we care about. ResourceVersion, CreationTimeStamp, etc are
 	// not relevant so we ignore them. This prevents unnecessary updates.
 	profile.ObjectMeta = metav1.ObjectMeta{Name: profile.Name}
+	SetOwnership(&profile.ObjectMeta, "Namespace", namespace.UID)
 
 	return *profile, nil
 }
 
+// isDefaultDeny returns true if the profile of the given namespace should be default-deny.
+func (nc *namespaceConverter) isDefaultDeny(namespace *v1.Namespace) bool {
+	switch v := namespace.Annotations[DefaultDenyAnnotation]; v {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "":
+	default:
+		log.WithFields(log.Fields{"namespace": namespace.Name, "value": v}).Warnf("Ignoring invalid %s annotation", DefaultDenyAnnotation)
+	}
+	return nc.defaultDeny
+}
+
+// addAnnotationLabels adds the namespace annotations that are copied to profiles to the given
+// labels to apply.
+func (nc *namespaceConverter) addAnnotationLabels(namespace *v1.Namespace, labels map[string]string) {
+	for k, v := range namespace.Annotations {
+		if !matchesKey(k, nc.annotationLabels) {
+			continue
+		}
+		key := nc.annotationLabelPrefix + k
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			log.WithFields(log.Fields{"namespace": namespace.Name, "annotation": k, "errors": errs}).Debug("Annotation doesn't make a valid label key, not copying it to the profile")
+			continue
+		}
+		if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+			log.WithFields(log.Fields{"namespace": namespace.Name, "annotation": k, "errors": errs}).Debug("Annotation doesn't make a valid label value, not copying it to the profile")
+			continue
+		}
+		if _, ok := labels[conversion.NamespaceLabelPrefix+key]; ok {
+			continue
+		}
+		labels[conversion.NamespaceLabelPrefix+key] = v
+	}
+}
+
+// denied returns true if the namespace label with the given key must not be copied to profiles.
+func (nc *namespaceConverter) denied(key string) bool {
+	return key != conversion.NameLabel && matchesKey(key, nc.labelDenyList)
+}
+
+// matchesKey returns true if the given key is in the given list of keys and key prefixes ending
+// in "*".
+func matchesKey(key string, keys []string) bool {
+	for _, k := range keys {
+		if prefix, ok := strings.CutSuffix(k, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == k {
+			return true
+		}
+	}
+	return false
+}
+
 // GetKey returns name of the Profile as its key.  For Profiles
 // backed by Kubernetes namespaces and managed by this controller, the name
 // is of format `kns.name`.
@@ -74,6 +74,91 @@ var _ = Describe("Namespace conversion tests", func() {
 		})
 	})
 
+	It("should not copy labels on the deny list", func() {
+		ns := k8sapi.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "default",
+				Labels: map[string]string{
+					"internal.example.com/id":    "1234",
+					"internal.example.com/owner": "team-a",
+					"cost-center":                "42",
+					"cost-center-name":           "platform",
+					"roger":                      "rabbit",
+				},
+				UID: "aa844ac0-87c8-440a-b270-307cdba8fd25",
+			},
+		}
+
+		c := converter.NewNamespaceConverter(converter.WithLabelDenyList([]string{"internal.example.com/*", "cost-center", "projectcalico.org/*"}))
+		p, err := c.Convert(&ns)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.(api.Profile).Spec.LabelsToApply).To(Equal(map[string]string{
+			"pcns.cost-center-name":       "platform",
+			"pcns.roger":                  "rabbit",
+			"pcns.projectcalico.org/name": "default",
+		}))
+	})
+
+	It("should copy selected annotations to labels", func() {
+		ns := k8sapi.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "default",
+				Labels: map[string]string{
+					"annotation.shadowed": "label",
+				},
+				Annotations: map[string]string{
+					"example.com/team":       "payments",
+					"owner.example.com/name": "alice",
+					"owner.example.com/bio":  "not a valid label value",
+					"shadowed":               "annotation",
+					"unselected":             "value",
+				},
+				UID: "aa844ac0-87c8-440a-b270-307cdba8fd25",
+			},
+		}
+
+		c := converter.NewNamespaceConverter(converter.WithAnnotationLabels([]string{"example.com/team", "owner.example.com/*", "shadowed"}, "annotation."))
+		p, err := c.Convert(&ns)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.(api.Profile).Spec.LabelsToApply).To(Equal(map[string]string{
+			"pcns.annotation.example.com/team":       "payments",
+			"pcns.annotation.owner.example.com/name": "alice",
+			"pcns.annotation.shadowed":               "label",
+			"pcns.projectcalico.org/name":            "default",
+		}))
+	})
+
+	It("should generate default-deny profiles when configured or annotated", func() {
+		namespace := func(annotation string) *k8sapi.Namespace {
+			ns := &k8sapi.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "aa844ac0-87c8-440a-b270-307cdba8fd25"}}
+			if annotation != "" {
+				ns.Annotations = map[string]string{converter.DefaultDenyAnnotation: annotation}
+			}
+			return ns
+		}
+		defaultDeny := func(c converter.Converter, ns *k8sapi.Namespace) bool {
+			p, err := c.Convert(ns)
+			Expect(err).NotTo(HaveOccurred())
+			spec := p.(api.Profile).Spec
+			if len(spec.Ingress) == 0 && len(spec.Egress) == 0 {
+				return true
+			}
+			Expect(spec.Ingress).To(Equal([]api.Rule{{Action: api.Allow}}))
+			Expect(spec.Egress).To(Equal([]api.Rule{{Action: api.Allow}}))
+			return false
+		}
+
+		allow := converter.NewNamespaceConverter()
+		Expect(defaultDeny(allow, namespace(""))).To(BeFalse())
+		Expect(defaultDeny(allow, namespace("true"))).To(BeTrue())
+		Expect(defaultDeny(allow, namespace("bogus"))).To(BeFalse())
+
+		deny := converter.NewNamespaceConverter(converter.WithDefaultDeny(true))
+		Expect(defaultDeny(deny, namespace(""))).To(BeTrue())
+		Expect(defaultDeny(deny, namespace("false"))).To(BeFalse())
+		Expect(defaultDeny(deny, namespace("bogus"))).To(BeTrue())
+	})
+
 	It("should parse a Namespace to a Profile with no labels", func() {
 		ns := k8sapi.Namespace{
 			ObjectMeta: metav1.ObjectMeta{
@@ -17,24 +17,105 @@ package converter
 import (
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
 
 	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
 	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/selector"
 
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/cache"
 )
 
+// ServiceAccountSelectorAnnotation on a Kubernetes NetworkPolicy restricts the pods that the
+// generated policy applies to, to those whose service account matches the given Calico selector.
+// This allows identity-based policies to be written using the Kubernetes API.
+const ServiceAccountSelectorAnnotation = "projectcalico.org/service-account-selector"
+
+// OrderAnnotation on a Kubernetes NetworkPolicy sets the order of the generated policy, in place
+// of the default order of Kubernetes policies, so that it can be evaluated before or after
+// Calico policies. Orders below the converter's minimum order are raised to it.
+const OrderAnnotation = "projectcalico.org/order"
+
+// DefaultPolicyOrder is the order of policies generated from Kubernetes NetworkPolicies without
+// the OrderAnnotation.
+const DefaultPolicyOrder = 1000.0
+
+var (
+	tcp = numorstring.ProtocolFromString(numorstring.ProtocolTCP)
+	udp = numorstring.ProtocolFromString(numorstring.ProtocolUDP)
+
+	// DNSEgressRules allow DNS requests to kube-dns, over both UDP and TCP.
+	DNSEgressRules = []api.Rule{dnsEgressRule(&tcp), dnsEgressRule(&udp)}
+)
+
+func dnsEgressRule(protocol *numorstring.Protocol) api.Rule {
+	return api.Rule{
+		Action:   api.Allow,
+		Protocol: protocol,
+		Destination: api.EntityRule{
+			NamespaceSelector: fmt.Sprintf("%s == 'kube-system'", conversion.NameLabel),
+			Selector:          "k8s-app == 'kube-dns'",
+			Ports:             []numorstring.Port{numorstring.SinglePort(53)},
+		},
+	}
+}
+
 type policyConverter struct {
+	maxNameLength int
+	namePrefix    string
+	allowDNS      bool
+	minOrder      float64
+}
+
+// PolicyConverterOption configures optional behaviour of the policy converter.
+type PolicyConverterOption func(*policyConverter)
+
+// WithMaxNameLength sets the maximum length of generated policy names. Longer names are
+// shortened using ShortenName, and the original name is recorded in the SourceNameAnnotation.
+func WithMaxNameLength(n int) PolicyConverterOption {
+	return func(p *policyConverter) {
+		p.maxNameLength = n
+	}
+}
+
+// WithNamePrefix sets the prefix of generated policy names, in place of the default
+// K8sNetworkPolicyNamePrefix.
+func WithNamePrefix(prefix string) PolicyConverterOption {
+	return func(p *policyConverter) {
+		p.namePrefix = prefix
+	}
+}
+
+// WithDNSEgressRules appends DNSEgressRules to the egress rules of every policy that applies to
+// egress, so that policies that isolate egress don't break DNS.
+func WithDNSEgressRules(allowDNS bool) PolicyConverterOption {
+	return func(p *policyConverter) {
+		p.allowDNS = allowDNS
+	}
+}
+
+// WithMinOrder sets the lowest order that the OrderAnnotation may give a policy, in place of the
+// default of DefaultPolicyOrder, which only allows policies to be ordered after the default.
+func WithMinOrder(order float64) PolicyConverterOption {
+	return func(p *policyConverter) {
+		p.minOrder = order
+	}
 }
 
 // NewPolicyConverter Constructor for policyConverter
-func NewPolicyConverter() Converter {
-	return &policyConverter{}
+func NewPolicyConverter(opts ...PolicyConverterOption) Converter {
+	p := &policyConverter{maxNameLength: DefaultMaxNameLength, namePrefix: conversion.K8sNetworkPolicyNamePrefix, minOrder: DefaultPolicyOrder}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
 }
 
 // Convert takes a Kubernetes NetworkPolicy and returns a Calico api.NetworkPolicy representation.
@@ -52,8 +133,11 @@ func (p *policyConverter) Convert(k8sObj interface{}) (interface{}, error) {
 		}
 	}
 
+	if err := validateSelectors(np); err != nil {
+		return nil, fmt.Errorf("NetworkPolicy %s/%s: %w", np.Namespace, np.Name, err)
+	}
 	c := conversion.NewConverter()
-	kvp, err := c.K8sNetworkPolicyToCalico(np)
+	kvp, err := c.K8sNetworkPolicyToCalico(normalizeSelectors(np))
 	// Silently ignore rule conversion errors. We don't expect any conversion errors
 	// since the data given to us here is validated by the Kubernetes API. The conversion
 	// code ignores any rules that it cannot parse, and we will pass the valid ones to Felix.
@@ -62,14 +146,58 @@ func (p *policyConverter) Convert(k8sObj interface{}) (interface{}, error) {
 		return nil, err
 	}
 	cnp := kvp.Value.(*api.NetworkPolicy)
+	if sel, ok := np.Annotations[ServiceAccountSelectorAnnotation]; ok {
+		if _, perr := selector.Parse(sel); perr != nil {
+			return nil, fmt.Errorf("invalid %s annotation on NetworkPolicy %s/%s: %w", ServiceAccountSelectorAnnotation, np.Namespace, np.Name, perr)
+		}
+		cnp.Spec.ServiceAccountSelector = sel
+	}
+	if v, ok := np.Annotations[OrderAnnotation]; ok {
+		order, perr := strconv.ParseFloat(v, 64)
+		if perr != nil || math.IsNaN(order) || math.IsInf(order, 0) {
+			return nil, fmt.Errorf("invalid %s annotation on NetworkPolicy %s/%s: %q is not a number", OrderAnnotation, np.Namespace, np.Name, v)
+		}
+		// Don't let the annotation order a policy before the configured minimum, since anyone
+		// who can write a NetworkPolicy could otherwise pre-empt the policies of cluster admins.
+		order = math.Max(order, p.minOrder)
+		cnp.Spec.Order = &order
+	}
+	if p.allowDNS && appliesToEgress(cnp) {
+		cnp.Spec.Egress = append(cnp.Spec.Egress, DNSEgressRules...)
+	}
+	cnp.Spec.Ingress = normalizeRules(cnp.Spec.Ingress)
+	cnp.Spec.Egress = normalizeRules(cnp.Spec.Egress)
 
 	// Isolate the metadata fields that we care about. ResourceVersion, CreationTimeStamp, etc are
 	// not relevant so we ignore them. This prevents unnecessary updates.
 	cnp.ObjectMeta = metav1.ObjectMeta{Name: cnp.Name, Namespace: cnp.Namespace}
+	if p.namePrefix != conversion.K8sNetworkPolicyNamePrefix {
+		cnp.Name = p.namePrefix + strings.TrimPrefix(cnp.Name, conversion.K8sNetworkPolicyNamePrefix)
+	}
+	SetOwnership(&cnp.ObjectMeta, "NetworkPolicy", np.UID)
+
+	// Shorten the name if it is too long, recording the original name so that the mapping
+	// back to the Kubernetes policy remains traceable.
+	if name := ShortenName(cnp.Name, p.maxNameLength); name != cnp.Name {
+		cnp.Name = name
+		if cnp.Annotations == nil {
+			cnp.Annotations = map[string]string{}
+		}
+		cnp.Annotations[SourceNameAnnotation] = np.Name
+	}
 
 	return *cnp, err
 }
 
+func appliesToEgress(policy *api.NetworkPolicy) bool {
+	for _, t := range policy.Spec.Types {
+		if t == api.PolicyTypeEgress {
+			return true
+		}
+	}
+	return false
+}
+
 // GetKey returns the 'namespace/name' for the given Calico NetworkPolicy as its key.
 func (p *policyConverter) GetKey(obj interface{}) string {
 	policy := obj.(api.NetworkPolicy)
@@ -16,6 +16,7 @@ package converter_test
 
 import (
 	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/syncersv1/updateprocessors"
 
 	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
 	"github.com/projectcalico/api/pkg/lib/numorstring"
@@ -23,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/client-go/tools/cache"
 
@@ -587,4 +589,397 @@ var _ = Describe("Kubernetes 1.7 NetworkPolicy conversion tests", func() {
 			Expect(pol.(api.NetworkPolicy).Spec.Types[0]).To(Equal(api.PolicyTypeIngress))
 		})
 	})
+
+	It("should allow DNS in policies that apply to egress when configured", func() {
+		c := converter.NewPolicyConverter(converter.WithDNSEgressRules(true))
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "deny-all",
+				Namespace: "default",
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			},
+		}
+
+		pol, err := c.Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pol.(api.NetworkPolicy).Spec.Egress).To(Equal(converter.DNSEgressRules))
+		Expect(pol.(api.NetworkPolicy).Spec.Ingress).To(BeEmpty())
+		Expect(converter.DNSEgressRules[0].Destination.NamespaceSelector).To(Equal("projectcalico.org/name == 'kube-system'"))
+		Expect(converter.DNSEgressRules[0].Destination.Ports).To(Equal([]numorstring.Port{numorstring.SinglePort(53)}))
+
+		By("not adding rules to policies that only apply to ingress", func() {
+			np.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+			pol, err := c.Convert(&np)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pol.(api.NetworkPolicy).Spec.Egress).To(BeEmpty())
+		})
+
+		By("not adding rules unless configured", func() {
+			np.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}
+			pol, err := npConverter.Convert(&np)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pol.(api.NetworkPolicy).Spec.Egress).To(BeEmpty())
+		})
+	})
+
+	It("should add a service account selector from the annotation", func() {
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "identity",
+				Namespace:   "default",
+				Annotations: map[string]string{converter.ServiceAccountSelectorAnnotation: "role == 'frontend'"},
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+
+		pol, err := npConverter.Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pol.(api.NetworkPolicy).Spec.ServiceAccountSelector).To(Equal("role == 'frontend'"))
+
+		By("rejecting an invalid selector", func() {
+			np.Annotations[converter.ServiceAccountSelectorAnnotation] = "role == "
+			_, err := npConverter.Convert(&np)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	It("should produce identical rules for equivalent NetworkPolicies", func() {
+		port80 := intstr.FromInt(80)
+		port81 := intstr.FromInt(81)
+		http := intstr.FromString("http")
+		tcp := corev1.ProtocolTCP
+		frontend := networkingv1.NetworkPolicyPeer{PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "frontend"}}}
+		ipBlock := networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.2.0.0/16", "10.1.0.0/16"}}}
+		ipBlockReordered := networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16", "10.2.0.0/16"}}}
+
+		np1 := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default"},
+			Spec: networkingv1.NetworkPolicySpec{
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{
+						Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port80}, {Protocol: &tcp, Port: &http}},
+						From:  []networkingv1.NetworkPolicyPeer{frontend, ipBlock},
+					},
+					{
+						Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port81}},
+						From:  []networkingv1.NetworkPolicyPeer{frontend},
+					},
+				},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+		np2 := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default"},
+			Spec: networkingv1.NetworkPolicySpec{
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{
+						Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &port81}, {Protocol: &tcp, Port: &port80}, {Protocol: &tcp, Port: &port80}},
+						From:  []networkingv1.NetworkPolicyPeer{frontend},
+					},
+					{
+						Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &http}},
+						From:  []networkingv1.NetworkPolicyPeer{frontend},
+					},
+					{
+						Ports: []networkingv1.NetworkPolicyPort{{Protocol: &tcp, Port: &http}, {Protocol: &tcp, Port: &port80}},
+						From:  []networkingv1.NetworkPolicyPeer{ipBlockReordered},
+					},
+				},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+
+		pol1, err := npConverter.Convert(&np1)
+		Expect(err).NotTo(HaveOccurred())
+		pol2, err := npConverter.Convert(&np2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pol1).To(Equal(pol2))
+
+		By("merging the ports of otherwise identical rules", func() {
+			var frontendPorts []numorstring.Port
+			for _, r := range pol1.(api.NetworkPolicy).Spec.Ingress {
+				if r.Source.Selector != "" && len(r.Source.Nets) == 0 {
+					frontendPorts = r.Destination.Ports
+				}
+			}
+			Expect(frontendPorts).To(Equal([]numorstring.Port{{MinPort: 80, MaxPort: 81}, numorstring.NamedPort("http")}))
+		})
+
+		By("sorting excluded CIDRs", func() {
+			for _, r := range pol1.(api.NetworkPolicy).Spec.Ingress {
+				if len(r.Source.Nets) > 0 {
+					Expect(r.Source.NotNets).To(Equal([]string{"10.1.0.0/16", "10.2.0.0/16"}))
+				}
+			}
+		})
+	})
+
+	It("should produce the same selectors regardless of match expression order", func() {
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy", Namespace: "default"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"web", "api"}},
+						{Key: "app", Operator: metav1.LabelSelectorOpExists},
+					},
+				},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From: []networkingv1.NetworkPolicyPeer{{
+						NamespaceSelector: &metav1.LabelSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "env", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"prod", "dev", "prod"}},
+							},
+						},
+					}},
+				}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+		reordered := np.DeepCopy()
+		reordered.Spec.PodSelector.MatchExpressions = []metav1.LabelSelectorRequirement{
+			{Key: "app", Operator: metav1.LabelSelectorOpExists},
+			{Key: "tier", Operator: metav1.LabelSelectorOpIn, Values: []string{"api", "web"}},
+		}
+		reordered.Spec.Ingress[0].From[0].NamespaceSelector.MatchExpressions[0].Values = []string{"dev", "prod"}
+
+		pol, err := npConverter.Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pol.(api.NetworkPolicy).Spec.Selector).To(Equal("projectcalico.org/orchestrator == 'k8s' && has(app) && tier in { 'api', 'web' }"))
+		Expect(pol.(api.NetworkPolicy).Spec.Ingress[0].Source.NamespaceSelector).To(Equal("env not in { 'dev', 'prod' }"))
+
+		polReordered, err := npConverter.Convert(reordered)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(polReordered).To(Equal(pol))
+
+		By("not modifying the Kubernetes policy", func() {
+			Expect(np.Spec.PodSelector.MatchExpressions[0].Key).To(Equal("tier"))
+			Expect(np.Spec.PodSelector.MatchExpressions[0].Values).To(Equal([]string{"web", "api"}))
+		})
+	})
+
+	It("should convert IPv6 and dual-stack ipBlocks", func() {
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "dual-stack", Namespace: "default"},
+			Spec: networkingv1.NetworkPolicySpec{
+				Egress: []networkingv1.NetworkPolicyEgressRule{{
+					To: []networkingv1.NetworkPolicyPeer{
+						{IPBlock: &networkingv1.IPBlock{CIDR: "2001:DB8::1/32", Except: []string{"2001:db8:ffff::/48", "2001:db8:1::/48"}}},
+						{IPBlock: &networkingv1.IPBlock{CIDR: "10.0.0.0/8"}},
+					},
+				}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			},
+		}
+
+		pol, err := npConverter.Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pol.(api.NetworkPolicy).Spec.Egress).To(Equal([]api.Rule{
+			{
+				Action:      "Allow",
+				Destination: api.EntityRule{Nets: []string{"10.0.0.0/8"}},
+			},
+			{
+				Action: "Allow",
+				Destination: api.EntityRule{
+					Nets:    []string{"2001:db8::/32"},
+					NotNets: []string{"2001:db8:1::/48", "2001:db8:ffff::/48"},
+				},
+			},
+		}))
+	})
+
+	It("should set the order from the annotation", func() {
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "ordered",
+				Namespace:   "default",
+				Annotations: map[string]string{converter.OrderAnnotation: "1100.5"},
+			},
+			Spec: networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+
+		pol, err := npConverter.Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*pol.(api.NetworkPolicy).Spec.Order).To(Equal(1100.5))
+
+		By("raising orders below the minimum", func() {
+			np.Annotations[converter.OrderAnnotation] = "100.5"
+			pol, err := npConverter.Convert(&np)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*pol.(api.NetworkPolicy).Spec.Order).To(Equal(converter.DefaultPolicyOrder))
+
+			pol, err = converter.NewPolicyConverter(converter.WithMinOrder(100)).Convert(&np)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*pol.(api.NetworkPolicy).Spec.Order).To(Equal(100.5))
+
+			np.Annotations[converter.OrderAnnotation] = "-5"
+			pol, err = converter.NewPolicyConverter(converter.WithMinOrder(100)).Convert(&np)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*pol.(api.NetworkPolicy).Spec.Order).To(Equal(100.0))
+		})
+
+		By("defaulting the order without the annotation", func() {
+			delete(np.Annotations, converter.OrderAnnotation)
+			pol, err := npConverter.Convert(&np)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(*pol.(api.NetworkPolicy).Spec.Order).To(Equal(1000.0))
+		})
+
+		By("rejecting an invalid order", func() {
+			for _, v := range []string{"high", "NaN", "+Inf"} {
+				np.Annotations[converter.OrderAnnotation] = v
+				_, err := npConverter.Convert(&np)
+				Expect(err).To(HaveOccurred())
+			}
+		})
+	})
+
+	It("should convert all match expression operators", func() {
+		exprs := []metav1.LabelSelectorRequirement{
+			{Key: "a", Operator: metav1.LabelSelectorOpIn, Values: []string{"x", "y"}},
+			{Key: "b", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"z"}},
+			{Key: "c", Operator: metav1.LabelSelectorOpExists},
+			{Key: "d", Operator: metav1.LabelSelectorOpDoesNotExist},
+		}
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "expressions", Namespace: "default"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchExpressions: exprs},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From: []networkingv1.NetworkPolicyPeer{{
+						NamespaceSelector: &metav1.LabelSelector{MatchExpressions: exprs},
+					}},
+				}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+
+		pol, err := npConverter.Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		const expected = "a in { 'x', 'y' } && b not in { 'z' } && has(c) && ! has(d)"
+		Expect(pol.(api.NetworkPolicy).Spec.Selector).To(Equal("projectcalico.org/orchestrator == 'k8s' && " + expected))
+		Expect(pol.(api.NetworkPolicy).Spec.Ingress[0].Source.NamespaceSelector).To(Equal(expected))
+
+		By("rejecting expressions that can't be converted", func() {
+			for _, e := range []metav1.LabelSelectorRequirement{
+				{Key: "a", Operator: metav1.LabelSelectorOpIn},
+				{Key: "a", Operator: metav1.LabelSelectorOpNotIn, Values: []string{}},
+				{Key: "a", Operator: metav1.LabelSelectorOpExists, Values: []string{"x"}},
+				{Key: "a", Operator: metav1.LabelSelectorOpDoesNotExist, Values: []string{"x"}},
+				{Key: "a", Operator: "Gt", Values: []string{"1"}},
+				{Key: "a", Operator: metav1.LabelSelectorOpIn, Values: []string{"it's"}},
+			} {
+				bad := np.DeepCopy()
+				bad.Spec.Ingress[0].From[0].NamespaceSelector.MatchExpressions = []metav1.LabelSelectorRequirement{e}
+				_, err := npConverter.Convert(bad)
+				Expect(err).To(HaveOccurred(), "expression %v", e)
+
+				bad = np.DeepCopy()
+				bad.Spec.PodSelector.MatchExpressions = []metav1.LabelSelectorRequirement{e}
+				_, err = npConverter.Convert(bad)
+				Expect(err).To(HaveOccurred(), "expression %v", e)
+			}
+		})
+	})
+
+	It("should select namespaces by their profile labels in namespaceSelector peers", func() {
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "namespaces", Namespace: "default"},
+			Spec: networkingv1.NetworkPolicySpec{
+				Ingress: []networkingv1.NetworkPolicyIngressRule{{
+					From: []networkingv1.NetworkPolicyPeer{
+						{NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}}},
+						{NamespaceSelector: &metav1.LabelSelector{}},
+					},
+				}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+
+		pol, err := npConverter.Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		var selectors []string
+		for _, r := range pol.(api.NetworkPolicy).Spec.Ingress {
+			selectors = append(selectors, updateprocessors.GetEntityRuleSelector(&r.Source, "default", "Source"))
+		}
+		Expect(selectors).To(ConsistOf(
+			"(pcns.env == \"prod\") && (projectcalico.org/orchestrator == 'k8s')",
+			"(has(projectcalico.org/namespace)) && (projectcalico.org/orchestrator == 'k8s')",
+		))
+	})
+
+	It("should AND the podSelector and namespaceSelector of a single peer", func() {
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cross-namespace", Namespace: "default"},
+			Spec: networkingv1.NetworkPolicySpec{
+				Egress: []networkingv1.NetworkPolicyEgressRule{{
+					To: []networkingv1.NetworkPolicyPeer{{
+						NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+						PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "db"}},
+					}},
+				}},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeEgress},
+			},
+		}
+
+		pol, err := npConverter.Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pol.(api.NetworkPolicy).Spec.Egress).To(HaveLen(1))
+		dst := pol.(api.NetworkPolicy).Spec.Egress[0].Destination
+		Expect(dst.Selector).To(Equal("projectcalico.org/orchestrator == 'k8s' && app == 'db'"))
+		Expect(dst.NamespaceSelector).To(Equal("team == 'payments'"))
+		Expect(updateprocessors.GetEntityRuleSelector(&dst, "default", "Destination")).To(Equal(
+			"(pcns.team == \"payments\") && (projectcalico.org/orchestrator == 'k8s' && app == 'db')"))
+	})
+
+	It("should merge contiguous ports into ranges", func() {
+		tcp := corev1.ProtocolTCP
+		udp := corev1.ProtocolUDP
+		var tcpPorts, udpPorts []networkingv1.NetworkPolicyPort
+		for p := 8000; p < 8100; p++ {
+			port := intstr.FromInt(p)
+			tcpPorts = append(tcpPorts, networkingv1.NetworkPolicyPort{Protocol: &tcp, Port: &port})
+		}
+		for _, p := range []int{53, 5353, 54, 9000} {
+			port := intstr.FromInt(p)
+			udpPorts = append(udpPorts, networkingv1.NetworkPolicyPort{Protocol: &udp, Port: &port})
+		}
+		port9000, endPort := intstr.FromInt(9000), int32(9100)
+		np := networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "ports", Namespace: "default"},
+			Spec: networkingv1.NetworkPolicySpec{
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{Ports: append(tcpPorts[50:], udpPorts...)},
+					{Ports: tcpPorts[:50]},
+					{Ports: []networkingv1.NetworkPolicyPort{{Protocol: &udp, Port: &port9000, EndPort: &endPort}}},
+				},
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			},
+		}
+
+		pol, err := npConverter.Convert(&np)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pol.(api.NetworkPolicy).Spec.Ingress).To(HaveLen(2))
+		for _, r := range pol.(api.NetworkPolicy).Spec.Ingress {
+			switch r.Protocol.StrVal {
+			case "TCP":
+				Expect(r.Destination.Ports).To(Equal([]numorstring.Port{{MinPort: 8000, MaxPort: 8099}}))
+			case "UDP":
+				Expect(r.Destination.Ports).To(Equal([]numorstring.Port{
+					{MinPort: 53, MaxPort: 54},
+					numorstring.SinglePort(5353),
+					{MinPort: 9000, MaxPort: 9100},
+				}))
+			default:
+				Fail("unexpected protocol " + r.Protocol.String())
+			}
+		}
+	})
 })
@@ -0,0 +1,110 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// ManagedByLabel is set on every resource created by the controllers, so that they can be
+	// identified without relying on naming conventions.
+	ManagedByLabel = "projectcalico.org/managed-by"
+	ManagedByValue = "calico-kube-controllers"
+
+	// SourceKindLabel records the kind of resource that a managed resource was generated from,
+	// so that controllers writing the same kind of Calico resource don't claim each other's.
+	SourceKindLabel = "projectcalico.org/source-kind"
+
+	// SourceUIDAnnotation records the UID of the resource that a managed resource was generated
+	// from, if any.
+	SourceUIDAnnotation = "projectcalico.org/source-uid"
+)
+
+// SetOwnership marks the resource as managed by the controllers, and generated from a resource
+// of the given kind and UID. The UID may be empty if there is no single source resource.
+func SetOwnership(meta *metav1.ObjectMeta, kind string, uid types.UID) {
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	meta.Labels[ManagedByLabel] = ManagedByValue
+	meta.Labels[SourceKindLabel] = kind
+	if uid != "" {
+		if meta.Annotations == nil {
+			meta.Annotations = map[string]string{}
+		}
+		meta.Annotations[SourceUIDAnnotation] = string(uid)
+	}
+}
+
+// IsManaged returns true if the resource is managed by the controllers, and was generated from a
+// resource of the given kind.
+func IsManaged(meta metav1.ObjectMeta, kind string) bool {
+	return meta.Labels[ManagedByLabel] == ManagedByValue && meta.Labels[SourceKindLabel] == kind
+}
+
+// IsManagedOrLegacy returns true if the resource is managed by the controllers, and was generated
+// from a resource of the given kind, or if it has no ownership labels and its name has the given
+// prefix, like the resources written before the labels were introduced. Those are then adopted,
+// or cleaned up, along with the labelled ones.
+func IsManagedOrLegacy(meta metav1.ObjectMeta, kind, legacyPrefix string) bool {
+	if _, ok := meta.Labels[ManagedByLabel]; ok {
+		return IsManaged(meta, kind)
+	}
+	return legacyPrefix != "" && strings.HasPrefix(meta.Name, legacyPrefix)
+}
+
+// ManagedMetadata returns the name and namespace of the resource, along with just the labels and
+// annotations that are managed by the controllers. Other metadata, like the resource version,
+// is dropped so that resources read from the datastore can be compared with converted ones.
+func ManagedMetadata(meta metav1.ObjectMeta) metav1.ObjectMeta {
+	m := metav1.ObjectMeta{Name: meta.Name, Namespace: meta.Namespace}
+	CopyManagedMetadata(&m, meta)
+	return m
+}
+
+// NeedsUpdate returns true if a resource read from the datastore, with the given metadata and
+// spec, differs from the desired resource. Only the name, namespace and managed labels and
+// annotations are compared, so server-set fields don't trigger a write, and nil and empty slices
+// and maps are treated as equal.
+func NeedsUpdate(current metav1.ObjectMeta, currentSpec interface{}, desired metav1.ObjectMeta, desiredSpec interface{}) bool {
+	return !equality.Semantic.DeepEqual(currentSpec, desiredSpec) ||
+		!equality.Semantic.DeepEqual(ManagedMetadata(current), ManagedMetadata(desired))
+}
+
+// CopyManagedMetadata copies the labels and annotations that are managed by the controllers from
+// src to dst, leaving any other labels and annotations on dst in place.
+func CopyManagedMetadata(dst *metav1.ObjectMeta, src metav1.ObjectMeta) {
+	for _, k := range []string{ManagedByLabel, SourceKindLabel} {
+		if v, ok := src.Labels[k]; ok {
+			if dst.Labels == nil {
+				dst.Labels = map[string]string{}
+			}
+			dst.Labels[k] = v
+		}
+	}
+	for _, k := range []string{SourceUIDAnnotation, SourceNameAnnotation} {
+		if v, ok := src.Annotations[k]; ok {
+			if dst.Annotations == nil {
+				dst.Annotations = map[string]string{}
+			}
+			dst.Annotations[k] = v
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Ownership metadata tests", func() {
+	It("should label converted resources with their owner", func() {
+		ns := v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "30316465-6365-4463-ad63-3564622d3638"}}
+		p, err := converter.NewNamespaceConverter().Convert(&ns)
+		Expect(err).NotTo(HaveOccurred())
+		profile := p.(api.Profile)
+		Expect(converter.IsManaged(profile.ObjectMeta, "Namespace")).To(BeTrue())
+		Expect(converter.IsManaged(profile.ObjectMeta, "ServiceAccount")).To(BeFalse())
+		Expect(profile.Annotations).To(HaveKeyWithValue(converter.SourceUIDAnnotation, "30316465-6365-4463-ad63-3564622d3638"))
+	})
+
+	It("should not treat similarly named resources as managed", func() {
+		meta := metav1.ObjectMeta{Name: "kns.default"}
+		Expect(converter.IsManaged(meta, "Namespace")).To(BeFalse())
+	})
+
+	It("should treat unlabelled resources with the legacy name prefix as managed", func() {
+		Expect(converter.IsManagedOrLegacy(metav1.ObjectMeta{Name: "knp.default.allow"}, "NetworkPolicy", "knp.default.")).To(BeTrue())
+		Expect(converter.IsManagedOrLegacy(metav1.ObjectMeta{Name: "allow"}, "NetworkPolicy", "knp.default.")).To(BeFalse())
+		Expect(converter.IsManagedOrLegacy(metav1.ObjectMeta{Name: "knp.default.allow"}, "NetworkPolicy", "")).To(BeFalse())
+
+		// Labelled resources are only managed if the labels say so, whatever their name.
+		labelled := metav1.ObjectMeta{Name: "knp.default.allow"}
+		converter.SetOwnership(&labelled, "SystemPolicy", "")
+		Expect(converter.IsManagedOrLegacy(labelled, "NetworkPolicy", "knp.default.")).To(BeFalse())
+		Expect(converter.IsManagedOrLegacy(labelled, "SystemPolicy", "knp.default.")).To(BeTrue())
+	})
+
+	It("should only keep the managed metadata", func() {
+		meta := metav1.ObjectMeta{
+			Name:            "knp.default.foo",
+			Namespace:       "default",
+			ResourceVersion: "1234",
+			Labels:          map[string]string{"user": "label"},
+			Annotations:     map[string]string{"user": "annotation"},
+		}
+		Expect(converter.ManagedMetadata(meta)).To(Equal(metav1.ObjectMeta{Name: "knp.default.foo", Namespace: "default"}))
+
+		converter.SetOwnership(&meta, "NetworkPolicy", "np-uid")
+		Expect(converter.ManagedMetadata(meta)).To(Equal(metav1.ObjectMeta{
+			Name:      "knp.default.foo",
+			Namespace: "default",
+			Labels: map[string]string{
+				converter.ManagedByLabel:  converter.ManagedByValue,
+				converter.SourceKindLabel: "NetworkPolicy",
+			},
+			Annotations: map[string]string{converter.SourceUIDAnnotation: "np-uid"},
+		}))
+	})
+
+	It("should copy managed metadata without removing other metadata", func() {
+		dst := metav1.ObjectMeta{
+			Labels:      map[string]string{"user": "label"},
+			Annotations: map[string]string{"user": "annotation"},
+		}
+		var src metav1.ObjectMeta
+		converter.SetOwnership(&src, "NetworkPolicy", "np-uid")
+		converter.CopyManagedMetadata(&dst, src)
+		Expect(dst.Labels).To(HaveKeyWithValue("user", "label"))
+		Expect(dst.Labels).To(HaveKeyWithValue(converter.ManagedByLabel, converter.ManagedByValue))
+		Expect(dst.Annotations).To(HaveKeyWithValue("user", "annotation"))
+		Expect(dst.Annotations).To(HaveKeyWithValue(converter.SourceUIDAnnotation, "np-uid"))
+	})
+
+	It("should only need an update if the spec or managed metadata differ", func() {
+		desired := metav1.ObjectMeta{Name: "kns.default"}
+		converter.SetOwnership(&desired, "Namespace", "ns-uid")
+		desiredSpec := api.ProfileSpec{LabelsToApply: map[string]string{"pcns.name": "default"}}
+
+		// Server-set fields, unmanaged labels and empty rather than nil slices don't need an update.
+		current := *desired.DeepCopy()
+		current.ResourceVersion = "1234"
+		current.CreationTimestamp = metav1.Now()
+		current.Labels["user"] = "label"
+		currentSpec := *desiredSpec.DeepCopy()
+		currentSpec.Ingress = []api.Rule{}
+		Expect(converter.NeedsUpdate(current, currentSpec, desired, desiredSpec)).To(BeFalse())
+
+		currentSpec.LabelsToApply["pcns.name"] = "other"
+		Expect(converter.NeedsUpdate(current, currentSpec, desired, desiredSpec)).To(BeTrue())
+
+		currentSpec = *desiredSpec.DeepCopy()
+		current.Annotations[converter.SourceUIDAnnotation] = "old-uid"
+		Expect(converter.NeedsUpdate(current, currentSpec, desired, desiredSpec)).To(BeTrue())
+	})
+})
@@ -23,6 +23,7 @@ import (
 
 	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
 	api "github.com/projectcalico/calico/libcalico-go/lib/apis/v3"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/k8s/conversion"
 )
 
 var _ = Describe("PodConverter", func() {
@@ -113,6 +114,79 @@ var _ = Describe("PodConverter", func() {
 		})
 	})
 
+	// convertIPs converts the given Pod as Convert does, and returns the addresses of its endpoint,
+	// which WorkloadEndpointData doesn't carry.
+	convertIPs := func(pod *v1.Pod) []string {
+		kvps, err := conversion.NewConverter().PodToWorkloadEndpoints(pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(kvps).To(HaveLen(1))
+		return kvps[0].Value.(*api.WorkloadEndpoint).Spec.IPNetworks
+	}
+
+	It("should convert a dual-stack Pod", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "podA",
+				Namespace: "default",
+				Labels:    map[string]string{"app": "web"},
+			},
+			Spec: v1.PodSpec{
+				NodeName: "nodeA",
+			},
+			Status: v1.PodStatus{
+				PodIP:  "fd00::10",
+				PodIPs: []v1.PodIP{{IP: "fd00::10"}, {IP: "10.0.0.10"}},
+			},
+		}
+
+		wepDatas, err := c.Convert(&pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wepDatas).To(HaveLen(1))
+		Expect(wepDatas[0].PodName).To(Equal("podA"))
+		Expect(wepDatas[0].Labels).To(HaveKeyWithValue("app", "web"))
+		Expect(convertIPs(&pod)).To(Equal([]string{"fd00::10/128", "10.0.0.10/32"}))
+	})
+
+	It("should convert a Pod with only PodIPs set", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "podA",
+				Namespace: "default",
+			},
+			Spec: v1.PodSpec{
+				NodeName: "nodeA",
+			},
+			Status: v1.PodStatus{
+				PodIPs: []v1.PodIP{{IP: "10.0.0.10"}, {IP: "fd00::10"}},
+			},
+		}
+
+		wepDatas, err := c.Convert(&pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wepDatas).To(HaveLen(1))
+		Expect(wepDatas[0].PodName).To(Equal("podA"))
+		Expect(convertIPs(&pod)).To(Equal([]string{"10.0.0.10/32", "fd00::10/128"}))
+	})
+
+	It("should convert a Pod with only the Calico IP annotation", func() {
+		pod := v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "podA",
+				Namespace:   "default",
+				Annotations: map[string]string{conversion.AnnotationPodIP: "fd00::10/128"},
+			},
+			Spec: v1.PodSpec{
+				NodeName: "nodeA",
+			},
+		}
+
+		wepDatas, err := c.Convert(&pod)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(wepDatas).To(HaveLen(1))
+		Expect(wepDatas[0].PodName).To(Equal("podA"))
+		Expect(convertIPs(&pod)).To(Equal([]string{"fd00::10/128"}))
+	})
+
 	It("should handle cache.DeletedFinalStateUnknown conversion", func() {
 		pod := cache.DeletedFinalStateUnknown{
 			Key: "cache.DeletedFinalStateUnknown",
@@ -0,0 +1,144 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+)
+
+var (
+	// NetworkPolicyGVK is the kind of Kubernetes object converted by the policy converter.
+	NetworkPolicyGVK = networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy")
+
+	// NamespaceGVK is the kind of Kubernetes object converted by the namespace converter.
+	NamespaceGVK = corev1.SchemeGroupVersion.WithKind("Namespace")
+
+	// ServiceAccountGVK is the kind of Kubernetes object converted by the service account
+	// converter.
+	ServiceAccountGVK = corev1.SchemeGroupVersion.WithKind("ServiceAccount")
+)
+
+// Registry maps the kinds of source objects to the converters for them, so that new source
+// types can be added without controllers needing to know which converter handles which type.
+type Registry struct {
+	lock       sync.RWMutex
+	converters map[schema.GroupVersionKind]Converter
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{converters: map[schema.GroupVersionKind]Converter{}}
+}
+
+// Register adds the converter for the given kind of object. It is an error to register more
+// than one converter for a kind.
+func (r *Registry) Register(gvk schema.GroupVersionKind, c Converter) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.converters[gvk]; ok {
+		return fmt.Errorf("a converter is already registered for %s", gvk)
+	}
+	r.converters[gvk] = c
+	return nil
+}
+
+// MustRegister is like Register but panics on error.
+func (r *Registry) MustRegister(gvk schema.GroupVersionKind, c Converter) {
+	if err := r.Register(gvk, c); err != nil {
+		panic(err)
+	}
+}
+
+// Get returns the converter for the given kind of object.
+func (r *Registry) Get(gvk schema.GroupVersionKind) (Converter, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	c, ok := r.converters[gvk]
+	return c, ok
+}
+
+// ForObject returns the converter for the given Kubernetes object, which may be wrapped in a
+// tombstone.
+func (r *Registry) ForObject(obj interface{}) (Converter, error) {
+	gvk, err := GVKForObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := r.Get(gvk)
+	if !ok {
+		return nil, fmt.Errorf("no converter is registered for %s", gvk)
+	}
+	return c, nil
+}
+
+// ConvertObject converts the given Kubernetes object, which may be wrapped in a tombstone, with
+// the converter registered for its kind.
+func ConvertObject[T any](r *Registry, obj interface{}) (T, error) {
+	c, err := r.ForObject(obj)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return ConvertTo[T](c, obj)
+}
+
+// ConvertObjectAll is like ConvertObject, but returns all of the calico objects generated from the
+// Kubernetes object.
+func ConvertObjectAll[T any](r *Registry, obj interface{}) ([]T, error) {
+	c, err := r.ForObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertAll[T](c, obj)
+}
+
+// Kinds returns the kinds of object that have converters registered, sorted.
+func (r *Registry) Kinds() []schema.GroupVersionKind {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	kinds := make([]schema.GroupVersionKind, 0, len(r.converters))
+	for gvk := range r.converters {
+		kinds = append(kinds, gvk)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i].String() < kinds[j].String() })
+	return kinds
+}
+
+// GVKForObject returns the kind of the given Kubernetes object, which may be wrapped in a
+// tombstone. Objects from informers don't have their TypeMeta filled in, so the kind is looked
+// up in the client-go scheme.
+func GVKForObject(obj interface{}) (schema.GroupVersionKind, error) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	o, ok := obj.(runtime.Object)
+	if !ok {
+		return schema.GroupVersionKind{}, fmt.Errorf("%T is not a Kubernetes object", obj)
+	}
+	gvks, _, err := scheme.Scheme.ObjectKinds(o)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return gvks[0], nil
+}
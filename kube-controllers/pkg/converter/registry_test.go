@@ -0,0 +1,89 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/converter"
+)
+
+var _ = Describe("Registry", func() {
+	var r *converter.Registry
+	policyConverter := converter.NewPolicyConverter()
+	namespaceConverter := converter.NewNamespaceConverter()
+
+	BeforeEach(func() {
+		r = converter.NewRegistry()
+		Expect(r.Register(converter.NetworkPolicyGVK, policyConverter)).To(Succeed())
+		Expect(r.Register(converter.NamespaceGVK, namespaceConverter)).To(Succeed())
+	})
+
+	It("should look up converters by kind", func() {
+		c, ok := r.Get(converter.NetworkPolicyGVK)
+		Expect(ok).To(BeTrue())
+		Expect(c).To(BeIdenticalTo(policyConverter))
+
+		_, ok = r.Get(converter.ServiceAccountGVK)
+		Expect(ok).To(BeFalse())
+
+		Expect(r.Kinds()).To(Equal([]schema.GroupVersionKind{converter.NamespaceGVK, converter.NetworkPolicyGVK}))
+	})
+
+	It("should reject a second converter for a kind", func() {
+		Expect(r.Register(converter.NamespaceGVK, namespaceConverter)).NotTo(Succeed())
+		Expect(func() { r.MustRegister(converter.NamespaceGVK, namespaceConverter) }).To(Panic())
+	})
+
+	It("should look up converters for objects", func() {
+		np := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "np", Namespace: "default"}}
+		c, err := r.ForObject(np)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).To(BeIdenticalTo(policyConverter))
+
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+		c, err = r.ForObject(cache.DeletedFinalStateUnknown{Key: "default", Obj: ns})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).To(BeIdenticalTo(namespaceConverter))
+
+		_, err = r.ForObject(&corev1.ServiceAccount{})
+		Expect(err).To(HaveOccurred())
+		_, err = r.ForObject("not an object")
+		Expect(err).To(HaveOccurred())
+	})
+	It("should convert objects with the converter for their kind", func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default", UID: "aa844ac0-87c8-440a-b270-307cdba8fd25"}}
+		profile, err := converter.ConvertObject[api.Profile](r, ns)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(profile.Name).To(Equal("kns.default"))
+
+		np := &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: "np", Namespace: "default", UID: "30316465-6365-4463-ad63-3564622d3638"}}
+		policies, err := converter.ConvertObjectAll[api.NetworkPolicy](r, cache.DeletedFinalStateUnknown{Key: "default/np", Obj: np})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(policies).To(HaveLen(1))
+		Expect(policies[0].Name).To(Equal("knp.default.np"))
+
+		_, err = converter.ConvertObject[api.Profile](r, &corev1.ServiceAccount{})
+		Expect(err).To(HaveOccurred())
+	})
+})
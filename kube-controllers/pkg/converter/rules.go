@@ -0,0 +1,280 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package converter
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+	"github.com/projectcalico/api/pkg/lib/numorstring"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// validateSelectors returns an error if any of the label selectors of the given NetworkPolicy
+// has a match expression that can't be converted to an equivalent Calico selector.
+func validateSelectors(np *networkingv1.NetworkPolicy) error {
+	if err := validateLabelSelector(&np.Spec.PodSelector); err != nil {
+		return fmt.Errorf("invalid podSelector: %w", err)
+	}
+	for i, r := range np.Spec.Ingress {
+		if err := validatePeers(r.From); err != nil {
+			return fmt.Errorf("invalid ingress rule %d: %w", i, err)
+		}
+	}
+	for i, r := range np.Spec.Egress {
+		if err := validatePeers(r.To); err != nil {
+			return fmt.Errorf("invalid egress rule %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func validatePeers(peers []networkingv1.NetworkPolicyPeer) error {
+	for _, p := range peers {
+		if err := validateLabelSelector(p.PodSelector); err != nil {
+			return fmt.Errorf("invalid podSelector: %w", err)
+		}
+		if err := validateLabelSelector(p.NamespaceSelector); err != nil {
+			return fmt.Errorf("invalid namespaceSelector: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateLabelSelector checks that each match expression uses a supported operator with values
+// that suit it. Unsupported expressions would otherwise be dropped, widening the selector.
+func validateLabelSelector(s *metav1.LabelSelector) error {
+	if s == nil {
+		return nil
+	}
+	for _, e := range s.MatchExpressions {
+		switch e.Operator {
+		case metav1.LabelSelectorOpIn, metav1.LabelSelectorOpNotIn:
+			if len(e.Values) == 0 {
+				return fmt.Errorf("operator %s on key %q requires values", e.Operator, e.Key)
+			}
+		case metav1.LabelSelectorOpExists, metav1.LabelSelectorOpDoesNotExist:
+			if len(e.Values) > 0 {
+				return fmt.Errorf("operator %s on key %q doesn't take values", e.Operator, e.Key)
+			}
+		default:
+			return fmt.Errorf("unsupported operator %q on key %q", e.Operator, e.Key)
+		}
+		for _, v := range e.Values {
+			if strings.ContainsAny(v, `'"`) {
+				return fmt.Errorf("value %q of key %q can't be quoted in a Calico selector", v, e.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// normalizeSelectors returns a copy of the given NetworkPolicy with the match expressions of
+// all of its label selectors, and their values, sorted and deduplicated. The converted
+// selectors then don't depend on the order in which the expressions were written.
+func normalizeSelectors(np *networkingv1.NetworkPolicy) *networkingv1.NetworkPolicy {
+	np = np.DeepCopy()
+	normalizeLabelSelector(&np.Spec.PodSelector)
+	for i := range np.Spec.Ingress {
+		normalizePeers(np.Spec.Ingress[i].From)
+	}
+	for i := range np.Spec.Egress {
+		normalizePeers(np.Spec.Egress[i].To)
+	}
+	return np
+}
+
+func normalizePeers(peers []networkingv1.NetworkPolicyPeer) {
+	for _, p := range peers {
+		normalizeLabelSelector(p.PodSelector)
+		normalizeLabelSelector(p.NamespaceSelector)
+	}
+}
+
+func normalizeLabelSelector(s *metav1.LabelSelector) {
+	if s == nil || len(s.MatchExpressions) == 0 {
+		return
+	}
+	exprs := make([]metav1.LabelSelectorRequirement, 0, len(s.MatchExpressions))
+	for _, e := range s.MatchExpressions {
+		e.Values = sortedUnique(e.Values)
+		exprs = append(exprs, e)
+	}
+	sort.SliceStable(exprs, func(i, j int) bool {
+		return lessRequirement(exprs[i], exprs[j])
+	})
+	s.MatchExpressions = exprs[:1]
+	for _, e := range exprs[1:] {
+		if lessRequirement(s.MatchExpressions[len(s.MatchExpressions)-1], e) {
+			s.MatchExpressions = append(s.MatchExpressions, e)
+		}
+	}
+}
+
+// lessRequirement orders label selector requirements by key, operator and then values.
+func lessRequirement(a, b metav1.LabelSelectorRequirement) bool {
+	if a.Key != b.Key {
+		return a.Key < b.Key
+	}
+	if a.Operator != b.Operator {
+		return a.Operator < b.Operator
+	}
+	for i := 0; i < len(a.Values) && i < len(b.Values); i++ {
+		if a.Values[i] != b.Values[i] {
+			return a.Values[i] < b.Values[i]
+		}
+	}
+	return len(a.Values) < len(b.Values)
+}
+
+// normalizeRules puts the given rules into a canonical form, so that semantically identical
+// Kubernetes policies always produce identical Calico policies, and so avoid needless writes
+// to the datastore. CIDRs and ports within each rule are sorted and deduplicated. If all of
+// the rules are Allow rules, their order is insignificant: rules that only differ in their
+// destination ports are merged, and the result is sorted.
+func normalizeRules(rules []api.Rule) []api.Rule {
+	if len(rules) == 0 {
+		return rules
+	}
+	out := make([]api.Rule, 0, len(rules))
+	for _, r := range rules {
+		r.Source = normalizeEntityRule(r.Source)
+		r.Destination = normalizeEntityRule(r.Destination)
+		out = append(out, r)
+	}
+	for _, r := range out {
+		if r.Action != api.Allow {
+			return out
+		}
+	}
+
+	merged := make([]api.Rule, 0, len(out))
+	for _, r := range out {
+		i := indexOfRuleExceptPorts(merged, r)
+		if i < 0 {
+			merged = append(merged, r)
+			continue
+		}
+		merged[i].Destination.Ports = unionPorts(merged[i].Destination.Ports, r.Destination.Ports)
+	}
+
+	type keyedRule struct {
+		key  string
+		rule api.Rule
+	}
+	keyed := make([]keyedRule, 0, len(merged))
+	for _, r := range merged {
+		b, err := json.Marshal(r)
+		if err != nil {
+			// Rules always marshal, but if one didn't there is no canonical order to impose.
+			return merged
+		}
+		keyed = append(keyed, keyedRule{key: string(b), rule: r})
+	}
+	sort.SliceStable(keyed, func(i, j int) bool { return keyed[i].key < keyed[j].key })
+	for i := range keyed {
+		merged[i] = keyed[i].rule
+	}
+	return merged
+}
+
+// indexOfRuleExceptPorts returns the index of the rule in rules that is identical to r apart
+// from its destination ports, or -1 if there is none.
+func indexOfRuleExceptPorts(rules []api.Rule, r api.Rule) int {
+	r.Destination.Ports = nil
+	for i, o := range rules {
+		o.Destination.Ports = nil
+		if reflect.DeepEqual(o, r) {
+			return i
+		}
+	}
+	return -1
+}
+
+// unionPorts returns the normalized union of two port lists, where an empty list matches all
+// ports.
+func unionPorts(a, b []numorstring.Port) []numorstring.Port {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	return normalizePorts(append(append([]numorstring.Port{}, a...), b...))
+}
+
+func normalizeEntityRule(e api.EntityRule) api.EntityRule {
+	e.Nets = sortedUnique(e.Nets)
+	e.NotNets = sortedUnique(e.NotNets)
+	e.Ports = normalizePorts(e.Ports)
+	e.NotPorts = normalizePorts(e.NotPorts)
+	return e
+}
+
+// normalizePorts returns the minimal set of port ranges covering the given ports, sorted, with
+// numeric ports before named ports.
+func normalizePorts(ports []numorstring.Port) []numorstring.Port {
+	if len(ports) <= 1 {
+		return ports
+	}
+	var numeric []numorstring.Port
+	var names []string
+	for _, p := range ports {
+		if p.PortName != "" {
+			names = append(names, p.PortName)
+		} else {
+			numeric = append(numeric, p)
+		}
+	}
+	sort.Slice(numeric, func(i, j int) bool {
+		if numeric[i].MinPort != numeric[j].MinPort {
+			return numeric[i].MinPort < numeric[j].MinPort
+		}
+		return numeric[i].MaxPort < numeric[j].MaxPort
+	})
+
+	out := make([]numorstring.Port, 0, len(ports))
+	for _, p := range numeric {
+		if n := len(out); n > 0 && int(p.MinPort) <= int(out[n-1].MaxPort)+1 {
+			if p.MaxPort > out[n-1].MaxPort {
+				out[n-1].MaxPort = p.MaxPort
+			}
+			continue
+		}
+		out = append(out, numorstring.Port{MinPort: p.MinPort, MaxPort: p.MaxPort})
+	}
+	for _, name := range sortedUnique(names) {
+		out = append(out, numorstring.NamedPort(name))
+	}
+	return out
+}
+
+func sortedUnique(s []string) []string {
+	if len(s) <= 1 {
+		return s
+	}
+	sorted := append([]string{}, s...)
+	sort.Strings(sorted)
+	out := sorted[:1]
+	for _, v := range sorted[1:] {
+		if v != out[len(out)-1] {
+			out = append(out, v)
+		}
+	}
+	return out
+}
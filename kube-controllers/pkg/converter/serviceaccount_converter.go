@@ -56,6 +56,7 @@ func (nc *serviceAccountConverter) Convert(k8sObj interface{}) (interface{}, err
 	// Isolate the metadata fields that we care about. ResourceVersion, CreationTimeStamp, etc are
 	// not relevant so we ignore them. This prevents unnecessary updates.
 	profile.ObjectMeta = metav1.ObjectMeta{Name: profile.Name}
+	SetOwnership(&profile.ObjectMeta, "ServiceAccount", serviceAccount.UID)
 
 	return *profile, nil
 }
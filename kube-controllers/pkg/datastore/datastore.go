@@ -0,0 +1,340 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package datastore manages the connection of kube-controllers to the Calico datastore.
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/model"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/ipam"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+// Backoff between attempts to reach the datastore at startup.
+var (
+	minStartupBackoff = time.Second
+	maxStartupBackoff = 30 * time.Second
+)
+
+var reconnectCounter prometheus.Counter
+
+func init() {
+	reconnectCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "datastore_reconnects_total",
+		Help: "Number of times the Calico datastore client has been rebuilt after the datastore was unreachable",
+	})
+	prometheus.MustRegister(reconnectCounter)
+}
+
+// WaitForDatastore blocks until the Calico datastore is reachable and initialized, retrying with
+// exponential backoff. The given function, if not nil, is called with the error from each failed
+// attempt. An error is returned if the datastore isn't reachable within the timeout, or 0 to wait
+// indefinitely, or the context is done.
+func WaitForDatastore(ctx context.Context, c client.Interface, timeout time.Duration, onError func(error)) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	backoff := minStartupBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.EnsureInitialized(ctx, "", "k8s")
+		if err == nil {
+			return nil
+		}
+		log.WithError(err).WithFields(log.Fields{"attempt": attempt, "retry": backoff}).Warn("Calico datastore is not ready")
+		if onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("calico datastore not ready after %d attempts: %w", attempt, err)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > maxStartupBackoff {
+			backoff = maxStartupBackoff
+		}
+	}
+}
+
+// ReconnectingClient is a Calico client that can replace its underlying connection to the
+// datastore, so that kube-controllers recovers from a connection that's stuck in a bad state
+// without restarting. Each call is made with the current connection, so callers that fetch a
+// resource client, such as Nodes(), for each operation transparently use the new connection.
+// The old connection is closed, which ends any watches using it, so that they are restarted on
+// the new connection.
+type ReconnectingClient struct {
+	newClient func() (client.Interface, error)
+
+	mu     sync.RWMutex
+	client client.Interface
+}
+
+// NewReconnectingClient returns a ReconnectingClient that uses the given function to build its
+// connection to the datastore, both initially and on each reconnect.
+func NewReconnectingClient(newClient func() (client.Interface, error)) (*ReconnectingClient, error) {
+	c, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	return &ReconnectingClient{newClient: newClient, client: c}, nil
+}
+
+// Reconnect replaces the connection to the datastore with a new one, and closes the old one.
+func (r *ReconnectingClient) Reconnect() error {
+	c, err := r.newClient()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	old := r.client
+	r.client = c
+	r.mu.Unlock()
+	reconnectCounter.Inc()
+
+	if closer, ok := backend(old).(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.WithError(err).Warn("Failed to close old Calico datastore client")
+		}
+	}
+	return nil
+}
+
+// Run checks that the datastore is reachable every interval, and rebuilds the connection if it
+// has been continuously unreachable for reconnectAfter. It returns when the context is done. The
+// check only reads the ClusterInformation, so that it never writes to the datastore.
+func (r *ReconnectingClient) Run(ctx context.Context, interval, reconnectAfter time.Duration) {
+	var failingSince time.Time
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, interval)
+		_, err := r.ClusterInformation().Get(checkCtx, "default", options.GetOptions{})
+		cancel()
+		if _, ok := err.(errors.ErrorResourceDoesNotExist); ok {
+			// The datastore answered.
+			err = nil
+		}
+		if err == nil {
+			if !failingSince.IsZero() {
+				log.Info("Calico datastore is reachable again")
+			}
+			failingSince = time.Time{}
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		now := time.Now()
+		if failingSince.IsZero() {
+			failingSince = now
+		}
+		if now.Sub(failingSince) < reconnectAfter {
+			continue
+		}
+		log.WithError(err).WithField("since", failingSince).Warn("Calico datastore unreachable, reconnecting")
+		if err := r.Reconnect(); err != nil {
+			log.WithError(err).Error("Failed to rebuild Calico datastore client")
+			continue
+		}
+		// Give the new connection as long as the old one before trying again.
+		failingSince = now
+	}
+}
+
+// current returns the current connection to the datastore.
+func (r *ReconnectingClient) current() client.Interface {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.client
+}
+
+// Backend returns a backend client for the syncers that are built directly on it. Each call is
+// made with the backend of the current connection, so that the syncers' retries reconnect too.
+func (r *ReconnectingClient) Backend() bapi.Client {
+	return reconnectingBackend{r}
+}
+
+// backend returns the backend client of the given connection.
+func backend(c client.Interface) bapi.Client {
+	type accessor interface {
+		Backend() bapi.Client
+	}
+	if a, ok := c.(accessor); ok {
+		return a.Backend()
+	}
+	return nil
+}
+
+// reconnectingBackend implements the backend client using the current connection.
+type reconnectingBackend struct {
+	r *ReconnectingClient
+}
+
+func (b reconnectingBackend) current() bapi.Client {
+	return backend(b.r.current())
+}
+
+func (b reconnectingBackend) Create(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	return b.current().Create(ctx, object)
+}
+
+func (b reconnectingBackend) Update(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	return b.current().Update(ctx, object)
+}
+
+func (b reconnectingBackend) Apply(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	return b.current().Apply(ctx, object)
+}
+
+func (b reconnectingBackend) Delete(ctx context.Context, key model.Key, revision string) (*model.KVPair, error) {
+	return b.current().Delete(ctx, key, revision)
+}
+
+func (b reconnectingBackend) DeleteKVP(ctx context.Context, object *model.KVPair) (*model.KVPair, error) {
+	return b.current().DeleteKVP(ctx, object)
+}
+
+func (b reconnectingBackend) Get(ctx context.Context, key model.Key, revision string) (*model.KVPair, error) {
+	return b.current().Get(ctx, key, revision)
+}
+
+func (b reconnectingBackend) List(ctx context.Context, list model.ListInterface, revision string) (*model.KVPairList, error) {
+	return b.current().List(ctx, list, revision)
+}
+
+func (b reconnectingBackend) Watch(ctx context.Context, list model.ListInterface, revision string) (bapi.WatchInterface, error) {
+	return b.current().Watch(ctx, list, revision)
+}
+
+func (b reconnectingBackend) EnsureInitialized() error {
+	return b.current().EnsureInitialized()
+}
+
+func (b reconnectingBackend) Clean() error {
+	return b.current().Clean()
+}
+
+// The remaining methods implement client.Interface using the current connection.
+
+func (r *ReconnectingClient) EnsureInitialized(ctx context.Context, calicoVersion, clusterType string) error {
+	return r.current().EnsureInitialized(ctx, calicoVersion, clusterType)
+}
+
+func (r *ReconnectingClient) Nodes() client.NodeInterface {
+	return r.current().Nodes()
+}
+
+func (r *ReconnectingClient) GlobalNetworkPolicies() client.GlobalNetworkPolicyInterface {
+	return r.current().GlobalNetworkPolicies()
+}
+
+func (r *ReconnectingClient) NetworkPolicies() client.NetworkPolicyInterface {
+	return r.current().NetworkPolicies()
+}
+
+func (r *ReconnectingClient) IPPools() client.IPPoolInterface {
+	return r.current().IPPools()
+}
+
+func (r *ReconnectingClient) IPReservations() client.IPReservationInterface {
+	return r.current().IPReservations()
+}
+
+func (r *ReconnectingClient) Profiles() client.ProfileInterface {
+	return r.current().Profiles()
+}
+
+func (r *ReconnectingClient) GlobalNetworkSets() client.GlobalNetworkSetInterface {
+	return r.current().GlobalNetworkSets()
+}
+
+func (r *ReconnectingClient) NetworkSets() client.NetworkSetInterface {
+	return r.current().NetworkSets()
+}
+
+func (r *ReconnectingClient) HostEndpoints() client.HostEndpointInterface {
+	return r.current().HostEndpoints()
+}
+
+func (r *ReconnectingClient) WorkloadEndpoints() client.WorkloadEndpointInterface {
+	return r.current().WorkloadEndpoints()
+}
+
+func (r *ReconnectingClient) BGPPeers() client.BGPPeerInterface {
+	return r.current().BGPPeers()
+}
+
+func (r *ReconnectingClient) BGPFilter() client.BGPFilterInterface {
+	return r.current().BGPFilter()
+}
+
+func (r *ReconnectingClient) IPAM() ipam.Interface {
+	return r.current().IPAM()
+}
+
+func (r *ReconnectingClient) BGPConfigurations() client.BGPConfigurationInterface {
+	return r.current().BGPConfigurations()
+}
+
+func (r *ReconnectingClient) FelixConfigurations() client.FelixConfigurationInterface {
+	return r.current().FelixConfigurations()
+}
+
+func (r *ReconnectingClient) ClusterInformation() client.ClusterInformationInterface {
+	return r.current().ClusterInformation()
+}
+
+func (r *ReconnectingClient) KubeControllersConfiguration() client.KubeControllersConfigurationInterface {
+	return r.current().KubeControllersConfiguration()
+}
+
+func (r *ReconnectingClient) CalicoNodeStatus() client.CalicoNodeStatusInterface {
+	return r.current().CalicoNodeStatus()
+}
+
+func (r *ReconnectingClient) IPAMConfig() client.IPAMConfigInterface {
+	return r.current().IPAMConfig()
+}
+
+func (r *ReconnectingClient) BlockAffinities() client.BlockAffinityInterface {
+	return r.current().BlockAffinities()
+}
+
+var (
+	_ client.Interface = &ReconnectingClient{}
+	_ bapi.Client      = reconnectingBackend{}
+)
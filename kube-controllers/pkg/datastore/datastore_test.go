@@ -0,0 +1,218 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package datastore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	api "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	bapi "github.com/projectcalico/calico/libcalico-go/lib/backend/api"
+	client "github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	cerrors "github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+	"github.com/projectcalico/calico/libcalico-go/lib/watch"
+)
+
+// fakeClient is a Calico client whose datastore is unreachable while down is set.
+type fakeClient struct {
+	client.Interface
+
+	mu     sync.Mutex
+	down   bool
+	calls  int
+	writes int
+	closed bool
+}
+
+func (f *fakeClient) EnsureInitialized(ctx context.Context, calicoVersion, clusterType string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	f.writes++
+	if f.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (f *fakeClient) ClusterInformation() client.ClusterInformationInterface {
+	return fakeClusterInformation{f}
+}
+
+func (f *fakeClient) Backend() bapi.Client {
+	return fakeBackend{f: f}
+}
+
+func (f *fakeClient) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed
+}
+
+type fakeClusterInformation struct {
+	f *fakeClient
+}
+
+func (c fakeClusterInformation) Create(ctx context.Context, res *api.ClusterInformation, opts options.SetOptions) (*api.ClusterInformation, error) {
+	panic("not implemented")
+}
+
+func (c fakeClusterInformation) Update(ctx context.Context, res *api.ClusterInformation, opts options.SetOptions) (*api.ClusterInformation, error) {
+	panic("not implemented")
+}
+
+func (c fakeClusterInformation) Delete(ctx context.Context, name string, opts options.DeleteOptions) (*api.ClusterInformation, error) {
+	panic("not implemented")
+}
+
+func (c fakeClusterInformation) Get(ctx context.Context, name string, opts options.GetOptions) (*api.ClusterInformation, error) {
+	c.f.mu.Lock()
+	defer c.f.mu.Unlock()
+	c.f.calls++
+	if c.f.down {
+		return nil, errors.New("connection refused")
+	}
+	return nil, cerrors.ErrorResourceDoesNotExist{}
+}
+
+func (c fakeClusterInformation) List(ctx context.Context, opts options.ListOptions) (*api.ClusterInformationList, error) {
+	panic("not implemented")
+}
+
+func (c fakeClusterInformation) Watch(ctx context.Context, opts options.ListOptions) (watch.Interface, error) {
+	panic("not implemented")
+}
+
+// fakeBackend is the backend of a fakeClient, which records when it is closed.
+type fakeBackend struct {
+	bapi.Client
+	f *fakeClient
+}
+
+func (b fakeBackend) EnsureInitialized() error {
+	return b.f.EnsureInitialized(context.Background(), "", "")
+}
+
+func (b fakeBackend) Close() error {
+	b.f.mu.Lock()
+	defer b.f.mu.Unlock()
+	b.f.closed = true
+	return nil
+}
+
+func (f *fakeClient) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+var _ = Describe("WaitForDatastore", func() {
+	BeforeEach(func() {
+		minStartupBackoff = 10 * time.Millisecond
+		maxStartupBackoff = 20 * time.Millisecond
+	})
+
+	It("should retry until the datastore is reachable", func() {
+		f := &fakeClient{down: true}
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			f.setDown(false)
+		}()
+		var errs int
+		Expect(WaitForDatastore(context.Background(), f, 0, func(error) { errs++ })).To(Succeed())
+		// The backoff is capped, so the datastore is retried regularly while it's down.
+		Expect(errs).To(BeNumerically(">=", 4))
+	})
+
+	It("should give up after the timeout", func() {
+		f := &fakeClient{down: true}
+		err := WaitForDatastore(context.Background(), f, 50*time.Millisecond, nil)
+		Expect(err).To(MatchError(ContainSubstring("connection refused")))
+	})
+})
+
+var _ = Describe("ReconnectingClient", func() {
+	var mu sync.Mutex
+	var clients []*fakeClient
+	var r *ReconnectingClient
+
+	latest := func() *fakeClient {
+		mu.Lock()
+		defer mu.Unlock()
+		return clients[len(clients)-1]
+	}
+	numClients := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(clients)
+	}
+
+	BeforeEach(func() {
+		clients = nil
+		var err error
+		r, err = NewReconnectingClient(func() (client.Interface, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			c := &fakeClient{}
+			clients = append(clients, c)
+			return c, nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should use the new connection after reconnecting", func() {
+		b := r.Backend()
+		Expect(r.Reconnect()).To(Succeed())
+		Expect(r.EnsureInitialized(context.Background(), "", "k8s")).To(Succeed())
+		Expect(clients).To(HaveLen(2))
+		Expect(clients[0].calls).To(Equal(0))
+		Expect(clients[1].calls).To(Equal(1))
+
+		// Backends fetched before reconnecting also use the new connection.
+		Expect(b.EnsureInitialized()).To(Succeed())
+		Expect(clients[0].calls).To(Equal(0))
+		Expect(clients[1].calls).To(Equal(2))
+	})
+
+	It("should close the old connection after reconnecting", func() {
+		Expect(r.Reconnect()).To(Succeed())
+		Expect(clients[0].isClosed()).To(BeTrue())
+		Expect(clients[1].isClosed()).To(BeFalse())
+	})
+
+	It("should reconnect once the datastore has been unreachable for long enough", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go r.Run(ctx, 10*time.Millisecond, 50*time.Millisecond)
+
+		Consistently(numClients, 100*time.Millisecond).Should(Equal(1))
+		latest().setDown(true)
+		Eventually(numClients).Should(Equal(2))
+
+		// The new connection works, so there are no more reconnects.
+		Consistently(numClients, 100*time.Millisecond).Should(Equal(2))
+
+		// The datastore is only ever read to check that it's reachable.
+		Expect(clients[0].writes).To(BeZero())
+		Expect(clients[1].writes).To(BeZero())
+	})
+})
@@ -0,0 +1,209 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dryrun
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+// Op is a datastore operation that a controller would have performed.
+type Op string
+
+const (
+	OpCreate Op = "create"
+	OpUpdate Op = "update"
+	OpDelete Op = "delete"
+)
+
+const (
+	// MaxSampleKeys is the maximum number of keys reported for each operation in a plan.
+	MaxSampleKeys = 10
+
+	// Number of times to retry writing the plan to the KubeControllersConfiguration on conflict.
+	maxUpdateRetries = 5
+)
+
+// Planner accumulates the datastore operations that a controller running in dry-run mode
+// would have performed, and periodically publishes a summary of them in the status of the
+// default KubeControllersConfiguration.
+var pendingChangesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "dry_run_pending_changes",
+	Help: "Number of datastore writes that a controller in dry-run mode would make",
+}, []string{"controller", "op"})
+
+func init() {
+	prometheus.MustRegister(pendingChangesGauge)
+}
+
+type Planner struct {
+	controller string
+	client     clientv3.KubeControllersConfigurationInterface
+
+	lock    sync.Mutex
+	creates map[string]struct{}
+	updates map[string]struct{}
+	deletes map[string]struct{}
+}
+
+// NewPlanner returns a Planner for the named controller.
+func NewPlanner(controller string, client clientv3.KubeControllersConfigurationInterface) *Planner {
+	return &Planner{
+		controller: controller,
+		client:     client,
+		creates:    map[string]struct{}{},
+		updates:    map[string]struct{}{},
+		deletes:    map[string]struct{}{},
+	}
+}
+
+// Record notes that the controller would have performed the given operation on the resource
+// with the given key.
+func (p *Planner) Record(op Op, key string) {
+	log.WithFields(log.Fields{"controller": p.controller, "op": op, "key": key}).Info("Dry-run: skipping datastore write")
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.resolve(key)
+	switch op {
+	case OpCreate:
+		p.creates[key] = struct{}{}
+	case OpUpdate:
+		p.updates[key] = struct{}{}
+	case OpDelete:
+		p.deletes[key] = struct{}{}
+	}
+}
+
+// Resolve records that the datastore is in sync for the given key, so it is no longer reported
+// as a pending change. Controllers resolve each key before syncing it, and record it again if
+// it still differs.
+func (p *Planner) Resolve(key string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.resolve(key)
+}
+
+func (p *Planner) resolve(key string) {
+	delete(p.creates, key)
+	delete(p.updates, key)
+	delete(p.deletes, key)
+}
+
+// Plan returns the changes that are currently pending. Each key is reported until it is
+// resolved, so that the plan reflects the outstanding differences between the desired state
+// and the datastore rather than only the keys synced recently.
+func (p *Planner) Plan() v3.ReconcilePlan {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	plan := v3.ReconcilePlan{
+		Controller:    p.controller,
+		Time:          metav1.Now(),
+		Creates:       len(p.creates),
+		Updates:       len(p.updates),
+		Deletes:       len(p.deletes),
+		SampleCreates: sampleKeys(p.creates),
+		SampleUpdates: sampleKeys(p.updates),
+		SampleDeletes: sampleKeys(p.deletes),
+	}
+	pendingChangesGauge.WithLabelValues(p.controller, string(OpCreate)).Set(float64(plan.Creates))
+	pendingChangesGauge.WithLabelValues(p.controller, string(OpUpdate)).Set(float64(plan.Updates))
+	pendingChangesGauge.WithLabelValues(p.controller, string(OpDelete)).Set(float64(plan.Deletes))
+	return plan
+}
+
+// Run publishes the plan once every period, until the context is cancelled. A period of zero,
+// as when periodic reconciliation is disabled, disables publishing.
+func (p *Planner) Run(ctx context.Context, period time.Duration) {
+	if period <= 0 {
+		log.WithField("controller", p.controller).Info("Reconciler period is disabled, not publishing dry-run plans")
+		return
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.publish(ctx, p.Plan()); err != nil {
+				log.WithError(err).WithField("controller", p.controller).Warn("Failed to write dry-run plan to KubeControllersConfiguration status")
+			}
+		}
+	}
+}
+
+// publish writes the given plan into the status of the default KubeControllersConfiguration,
+// replacing any previous plan from the same controller.
+func (p *Planner) publish(ctx context.Context, plan v3.ReconcilePlan) error {
+	var err error
+	for i := 0; i < maxUpdateRetries; i++ {
+		var kcc *v3.KubeControllersConfiguration
+		kcc, err = p.client.Get(ctx, "default", options.GetOptions{})
+		if err != nil {
+			return err
+		}
+		kcc.Status.DryRunPlans = SetPlan(kcc.Status.DryRunPlans, plan)
+		_, err = p.client.Update(ctx, kcc, options.SetOptions{})
+		if _, ok := err.(errors.ErrorResourceUpdateConflict); !ok {
+			return err
+		}
+		log.WithField("controller", p.controller).Debug("Conflict writing dry-run plan, retrying")
+	}
+	return err
+}
+
+// SetPlan returns the given plans with the plan for plan.Controller replaced by (or extended
+// with) the given plan. Plans are kept sorted by controller name.
+func SetPlan(plans []v3.ReconcilePlan, plan v3.ReconcilePlan) []v3.ReconcilePlan {
+	out := []v3.ReconcilePlan{plan}
+	for _, existing := range plans {
+		if existing.Controller != plan.Controller {
+			out = append(out, existing)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Controller < out[j].Controller
+	})
+	return out
+}
+
+func sampleKeys(keys map[string]struct{}) []string {
+	if len(keys) == 0 {
+		return nil
+	}
+	sample := make([]string, 0, len(keys))
+	for k := range keys {
+		sample = append(sample, k)
+	}
+	sort.Strings(sample)
+	if len(sample) > MaxSampleKeys {
+		sample = sample[:MaxSampleKeys]
+	}
+	return sample
+}
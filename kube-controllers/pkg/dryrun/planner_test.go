@@ -0,0 +1,115 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dryrun_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/dryrun"
+)
+
+var _ = Describe("Planner", func() {
+	var p *dryrun.Planner
+
+	BeforeEach(func() {
+		p = dryrun.NewPlanner("namespace", nil)
+	})
+
+	It("should not publish plans if the period is disabled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			p.Run(ctx, 0)
+		}()
+		Eventually(done, time.Second).Should(BeClosed())
+	})
+
+	It("should count each key once per plan", func() {
+		p.Record(dryrun.OpCreate, "kns.a")
+		p.Record(dryrun.OpCreate, "kns.a")
+		p.Record(dryrun.OpUpdate, "kns.b")
+		p.Record(dryrun.OpDelete, "kns.c")
+		p.Record(dryrun.OpDelete, "kns.d")
+
+		plan := p.Plan()
+		Expect(plan.Controller).To(Equal("namespace"))
+		Expect(plan.Creates).To(Equal(1))
+		Expect(plan.Updates).To(Equal(1))
+		Expect(plan.Deletes).To(Equal(2))
+		Expect(plan.SampleCreates).To(Equal([]string{"kns.a"}))
+		Expect(plan.SampleUpdates).To(Equal([]string{"kns.b"}))
+		Expect(plan.SampleDeletes).To(Equal([]string{"kns.c", "kns.d"}))
+	})
+
+	It("should report each key until it is resolved", func() {
+		p.Record(dryrun.OpCreate, "kns.a")
+		p.Record(dryrun.OpCreate, "kns.b")
+		Expect(p.Plan().Creates).To(Equal(2))
+		Expect(p.Plan().Creates).To(Equal(2))
+
+		p.Resolve("kns.a")
+		plan := p.Plan()
+		Expect(plan.Creates).To(Equal(1))
+		Expect(plan.SampleCreates).To(Equal([]string{"kns.b"}))
+	})
+
+	It("should only report the latest operation for a key", func() {
+		p.Record(dryrun.OpCreate, "kns.a")
+		p.Record(dryrun.OpUpdate, "kns.a")
+
+		plan := p.Plan()
+		Expect(plan.Creates).To(BeZero())
+		Expect(plan.SampleCreates).To(BeNil())
+		Expect(plan.Updates).To(Equal(1))
+	})
+
+	It("should limit the number of sample keys", func() {
+		for i := 0; i < 2*dryrun.MaxSampleKeys; i++ {
+			p.Record(dryrun.OpUpdate, fmt.Sprintf("kns.%02d", i))
+		}
+
+		plan := p.Plan()
+		Expect(plan.Updates).To(Equal(2 * dryrun.MaxSampleKeys))
+		Expect(plan.SampleUpdates).To(HaveLen(dryrun.MaxSampleKeys))
+		Expect(plan.SampleUpdates[0]).To(Equal("kns.00"))
+	})
+})
+
+var _ = Describe("SetPlan", func() {
+	It("should replace the plan for the same controller and keep plans sorted", func() {
+		plans := []v3.ReconcilePlan{
+			{Controller: "serviceaccount", Creates: 1},
+			{Controller: "policy", Creates: 2},
+		}
+
+		plans = dryrun.SetPlan(plans, v3.ReconcilePlan{Controller: "policy", Creates: 3})
+		plans = dryrun.SetPlan(plans, v3.ReconcilePlan{Controller: "namespace", Deletes: 4})
+
+		Expect(plans).To(Equal([]v3.ReconcilePlan{
+			{Controller: "namespace", Deletes: 4},
+			{Controller: "policy", Creates: 3},
+			{Controller: "serviceaccount", Creates: 1},
+		}))
+	})
+})
@@ -0,0 +1,88 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package heartbeat periodically records in the Calico datastore that kube-controllers is running.
+package heartbeat
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+// Number of times to retry writing the heartbeat to the KubeControllersConfiguration on conflict.
+const maxUpdateRetries = 5
+
+// Heartbeat periodically writes the identity of this kube-controllers instance and the current
+// time into the status of the default KubeControllersConfiguration, so that other components,
+// such as calicoctl or an operator, can tell whether kube-controllers is running and which
+// instance is active.
+type Heartbeat struct {
+	identity string
+	version  string
+	client   clientv3.Interface
+}
+
+// New returns a Heartbeat for the kube-controllers instance with the given identity and version.
+func New(identity, version string, client clientv3.Interface) *Heartbeat {
+	return &Heartbeat{identity: identity, version: version, client: client}
+}
+
+// Run writes a heartbeat immediately and then once every period, until the context is cancelled.
+func (h *Heartbeat) Run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		if err := h.Beat(ctx); err != nil {
+			log.WithError(err).Warn("Failed to write heartbeat to KubeControllersConfiguration status")
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Beat writes a single heartbeat into the status of the default KubeControllersConfiguration.
+func (h *Heartbeat) Beat(ctx context.Context) error {
+	kccClient := h.client.KubeControllersConfiguration()
+	var err error
+	for i := 0; i < maxUpdateRetries; i++ {
+		var kcc *v3.KubeControllersConfiguration
+		kcc, err = kccClient.Get(ctx, "default", options.GetOptions{})
+		if err != nil {
+			return err
+		}
+		kcc.Status.Heartbeat = &v3.KubeControllersHeartbeat{
+			Identity: h.identity,
+			Version:  h.version,
+			Time:     metav1.Now(),
+		}
+		_, err = kccClient.Update(ctx, kcc, options.SetOptions{})
+		if _, ok := err.(errors.ErrorResourceUpdateConflict); !ok {
+			return err
+		}
+		log.Debug("Conflict writing heartbeat, retrying")
+	}
+	return err
+}
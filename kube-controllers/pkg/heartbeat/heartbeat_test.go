@@ -0,0 +1,118 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package heartbeat_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v3 "github.com/projectcalico/api/pkg/apis/projectcalico/v3"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/heartbeat"
+	"github.com/projectcalico/calico/libcalico-go/lib/clientv3"
+	"github.com/projectcalico/calico/libcalico-go/lib/errors"
+	"github.com/projectcalico/calico/libcalico-go/lib/options"
+)
+
+// fakeKCCClient stores a single KubeControllersConfiguration, and fails the given number of
+// updates with a conflict.
+type fakeKCCClient struct {
+	clientv3.KubeControllersConfigurationInterface
+
+	mu        sync.Mutex
+	kcc       *v3.KubeControllersConfiguration
+	conflicts int
+	updates   int
+}
+
+func (f *fakeKCCClient) Get(ctx context.Context, name string, opts options.GetOptions) (*v3.KubeControllersConfiguration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.kcc.DeepCopy(), nil
+}
+
+func (f *fakeKCCClient) Update(ctx context.Context, res *v3.KubeControllersConfiguration, opts options.SetOptions) (*v3.KubeControllersConfiguration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.conflicts > 0 {
+		f.conflicts--
+		return nil, errors.ErrorResourceUpdateConflict{Identifier: res.Name}
+	}
+	f.updates++
+	f.kcc = res.DeepCopy()
+	return res, nil
+}
+
+// fakeClient is a Calico client that only supports KubeControllersConfigurations.
+type fakeClient struct {
+	clientv3.Interface
+	kcc *fakeKCCClient
+}
+
+func (f *fakeClient) KubeControllersConfiguration() clientv3.KubeControllersConfigurationInterface {
+	return f.kcc
+}
+
+func (f *fakeKCCClient) heartbeat() *v3.KubeControllersHeartbeat {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.kcc.Status.Heartbeat
+}
+
+var _ = Describe("Heartbeat", func() {
+	var client *fakeKCCClient
+
+	BeforeEach(func() {
+		kcc := v3.NewKubeControllersConfiguration()
+		kcc.Name = "default"
+		kcc.Status.EnvironmentVars = map[string]string{"LOG_LEVEL": "info"}
+		client = &fakeKCCClient{kcc: kcc}
+	})
+
+	It("should write the identity and time, keeping the rest of the status", func() {
+		before := time.Now().Add(-time.Second)
+		Expect(heartbeat.New("kube-controllers-abc", "v3.99", &fakeClient{kcc: client}).Beat(context.Background())).To(Succeed())
+
+		hb := client.heartbeat()
+		Expect(hb).NotTo(BeNil())
+		Expect(hb.Identity).To(Equal("kube-controllers-abc"))
+		Expect(hb.Version).To(Equal("v3.99"))
+		Expect(hb.Time.Time).To(BeTemporally(">", before))
+		Expect(client.kcc.Status.EnvironmentVars).To(HaveKeyWithValue("LOG_LEVEL", "info"))
+	})
+
+	It("should retry on conflict", func() {
+		client.conflicts = 2
+		Expect(heartbeat.New("kube-controllers-abc", "", &fakeClient{kcc: client}).Beat(context.Background())).To(Succeed())
+		Expect(client.heartbeat()).NotTo(BeNil())
+	})
+
+	It("should write a heartbeat every period", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go heartbeat.New("kube-controllers-abc", "", &fakeClient{kcc: client}).Run(ctx, 20*time.Millisecond)
+
+		updates := func() int {
+			client.mu.Lock()
+			defer client.mu.Unlock()
+			return client.updates
+		}
+		Eventually(updates).Should(BeNumerically(">=", 3))
+	})
+})
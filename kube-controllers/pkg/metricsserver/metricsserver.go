@@ -0,0 +1,132 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metricsserver serves the Prometheus metrics of kube-controllers.
+package metricsserver
+
+import (
+	cryptotls "crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/crypto/pkg/tls"
+)
+
+// Config configures the metrics server.
+type Config struct {
+	// Host is the address to listen on, or empty to listen on all addresses.
+	Host string
+	Port int
+
+	// CertFile and KeyFile enable TLS when set. The certificate is reloaded when the file changes,
+	// so that certificates mounted from a Secret can be rotated without a restart.
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile (optional) requires clients to present a certificate signed by one of the CAs
+	// in the file. Only used with TLS.
+	ClientCAFile string
+}
+
+// New returns an HTTP server for the given handler, configured for TLS if required.
+func New(cfg Config, handler http.Handler) (*http.Server, error) {
+	srv := &http.Server{
+		Addr:              net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if cfg.CertFile == "" && cfg.KeyFile == "" {
+		if cfg.ClientCAFile != "" {
+			return nil, fmt.Errorf("a client CA requires a TLS certificate and key")
+		}
+		return srv, nil
+	}
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return nil, fmt.Errorf("both a TLS certificate and key are required")
+	}
+
+	loader := &keyPairLoader{certFile: cfg.CertFile, keyFile: cfg.KeyFile}
+	if _, err := loader.GetCertificate(nil); err != nil {
+		return nil, err
+	}
+	srv.TLSConfig = tls.NewTLSConfig()
+	srv.TLSConfig.GetCertificate = loader.GetCertificate
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", cfg.ClientCAFile)
+		}
+		srv.TLSConfig.ClientCAs = pool
+		srv.TLSConfig.ClientAuth = cryptotls.RequireAndVerifyClientCert
+	}
+	return srv, nil
+}
+
+// ListenAndServe serves the server returned by New, over TLS if it's configured.
+func ListenAndServe(srv *http.Server) error {
+	if srv.TLSConfig != nil {
+		return srv.ListenAndServeTLS("", "")
+	}
+	return srv.ListenAndServe()
+}
+
+// keyPairLoader loads a TLS certificate and key from files, reloading them when the certificate
+// file changes.
+type keyPairLoader struct {
+	certFile string
+	keyFile  string
+
+	lock    sync.Mutex
+	cert    *cryptotls.Certificate
+	modTime time.Time
+}
+
+func (l *keyPairLoader) GetCertificate(*cryptotls.ClientHelloInfo) (*cryptotls.Certificate, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	info, err := os.Stat(l.certFile)
+	if err == nil && l.cert != nil && info.ModTime().Equal(l.modTime) {
+		return l.cert, nil
+	}
+	if err == nil {
+		var cert cryptotls.Certificate
+		cert, err = cryptotls.LoadX509KeyPair(l.certFile, l.keyFile)
+		if err == nil {
+			log.WithField("file", l.certFile).Info("Loaded metrics server certificate")
+			l.cert = &cert
+			l.modTime = info.ModTime()
+			return l.cert, nil
+		}
+	}
+	if l.cert != nil {
+		// Keep serving the previous certificate, the files may be part way through an update.
+		log.WithError(err).Warn("Failed to reload metrics server certificate")
+		return l.cert, nil
+	}
+	return nil, fmt.Errorf("failed to load metrics server certificate: %w", err)
+}
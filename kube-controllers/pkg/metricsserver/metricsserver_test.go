@@ -0,0 +1,180 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metricsserver_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/metricsserver"
+)
+
+// writeCert writes a self-signed certificate and key for 127.0.0.1 to the given directory,
+// returning the paths of the files and the parsed certificate.
+func writeCert(dir, name string, isClient bool) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if isClient {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	Expect(err).NotTo(HaveOccurred())
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	Expect(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600)).To(Succeed())
+	Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)).To(Succeed())
+	return certFile, keyFile, cert
+}
+
+var _ = Describe("Metrics server", func() {
+	var dir string
+	var servers []*http.Server
+	var serving sync.WaitGroup
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("metrics"))
+	})
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "metricsserver")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		for _, srv := range servers {
+			srv.Close()
+		}
+		servers = nil
+		serving.Wait()
+		os.RemoveAll(dir)
+	})
+
+	// serve serves the server on a random local port, returning the URL of the metrics.
+	serve := func(srv *http.Server) string {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		// Capture the TLS config before starting the server, since Serve may modify it.
+		tlsConfig := srv.TLSConfig
+		if tlsConfig != nil {
+			l = tls.NewListener(l, tlsConfig)
+		}
+		serving.Add(1)
+		go func() {
+			defer GinkgoRecover()
+			defer serving.Done()
+			Expect(srv.Serve(l)).To(Equal(http.ErrServerClosed))
+		}()
+		servers = append(servers, srv)
+		if tlsConfig != nil {
+			return "https://" + l.Addr().String() + "/metrics"
+		}
+		return "http://" + l.Addr().String() + "/metrics"
+	}
+
+	It("should serve plain HTTP on the configured address by default", func() {
+		srv, err := metricsserver.New(metricsserver.Config{Host: "127.0.0.1", Port: 9094}, handler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(srv.Addr).To(Equal("127.0.0.1:9094"))
+		Expect(srv.TLSConfig).To(BeNil())
+
+		resp, err := http.Get(serve(srv))
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("should serve over TLS when given a certificate", func() {
+		certFile, keyFile, cert := writeCert(dir, "server", false)
+		srv, err := metricsserver.New(metricsserver.Config{CertFile: certFile, KeyFile: keyFile}, handler)
+		Expect(err).NotTo(HaveOccurred())
+		url := serve(srv)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(cert)
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+		resp, err := client.Get(url)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("should require a client certificate signed by the client CA if configured", func() {
+		certFile, keyFile, cert := writeCert(dir, "server", false)
+		clientCertFile, clientKeyFile, _ := writeCert(dir, "client", true)
+		srv, err := metricsserver.New(metricsserver.Config{
+			CertFile:     certFile,
+			KeyFile:      keyFile,
+			ClientCAFile: clientCertFile,
+		}, handler)
+		Expect(err).NotTo(HaveOccurred())
+		url := serve(srv)
+
+		roots := x509.NewCertPool()
+		roots.AddCert(cert)
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+		_, err = client.Get(url)
+		Expect(err).To(HaveOccurred())
+
+		clientCert, err := tls.LoadX509KeyPair(clientCertFile, clientKeyFile)
+		Expect(err).NotTo(HaveOccurred())
+		client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			Certificates: []tls.Certificate{clientCert},
+		}}}
+		resp, err := client.Get(url)
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("should reject incomplete TLS configuration", func() {
+		certFile, _, _ := writeCert(dir, "server", false)
+		_, err := metricsserver.New(metricsserver.Config{CertFile: certFile}, handler)
+		Expect(err).To(HaveOccurred())
+		_, err = metricsserver.New(metricsserver.Config{ClientCAFile: certFile}, handler)
+		Expect(err).To(HaveOccurred())
+	})
+})
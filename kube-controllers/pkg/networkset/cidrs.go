@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkset
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"sort"
+
+	cnet "github.com/projectcalico/calico/libcalico-go/lib/net"
+)
+
+// ipRange is an inclusive range of addresses of a single IP version.
+type ipRange struct {
+	start *big.Int
+	end   *big.Int
+}
+
+// ConsolidateCIDRs merges one or more lists of CIDRs (or bare IPs) into a single, minimal
+// list of CIDRs covering exactly the same addresses. Duplicate entries are removed, CIDRs
+// contained in other CIDRs are dropped, and overlapping or adjacent ranges are collapsed
+// into the smallest set of CIDRs that covers them.
+//
+// The result is sorted, with IPv4 CIDRs before IPv6 CIDRs, so that the output is stable
+// for a given set of addresses regardless of the order of the input. This keeps the
+// number of entries written to GlobalNetworkSets (and hence Felix's IP sets) down, and
+// avoids churn when feeds return the same data in a different order.
+func ConsolidateCIDRs(cidrLists ...[]string) ([]string, error) {
+	var v4, v6 []ipRange
+	for _, cidrs := range cidrLists {
+		for _, c := range cidrs {
+			_, ipNet, err := cnet.ParseCIDROrIP(c)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q: %w", c, err)
+			}
+			r := rangeFromIPNet(ipNet.IPNet)
+			if ipNet.Version() == 4 {
+				v4 = append(v4, r)
+			} else {
+				v6 = append(v6, r)
+			}
+		}
+	}
+
+	out := []string{}
+	out = append(out, rangesToCIDRs(mergeRanges(v4), 32)...)
+	out = append(out, rangesToCIDRs(mergeRanges(v6), 128)...)
+	return out, nil
+}
+
+// rangeFromIPNet returns the inclusive range of addresses covered by the given network.
+func rangeFromIPNet(n net.IPNet) ipRange {
+	ip := n.IP.To4()
+	if ip == nil {
+		ip = n.IP.To16()
+	}
+	start := new(big.Int).SetBytes(ip.Mask(n.Mask))
+	ones, bits := n.Mask.Size()
+	size := new(big.Int).Lsh(big.NewInt(1), uint(bits-ones))
+	end := new(big.Int).Add(start, size)
+	end.Sub(end, big.NewInt(1))
+	return ipRange{start: start, end: end}
+}
+
+// mergeRanges sorts the given ranges and merges any that overlap or are adjacent.
+func mergeRanges(ranges []ipRange) []ipRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].start.Cmp(ranges[j].start) < 0
+	})
+
+	merged := []ipRange{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		next := new(big.Int).Add(last.end, big.NewInt(1))
+		if r.start.Cmp(next) <= 0 {
+			// Overlapping or adjacent, extend the previous range if required.
+			if r.end.Cmp(last.end) > 0 {
+				last.end = r.end
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// rangesToCIDRs converts each range into the minimal list of CIDRs covering it.
+func rangesToCIDRs(ranges []ipRange, bits int) []string {
+	var cidrs []string
+	one := big.NewInt(1)
+	for _, r := range ranges {
+		start := new(big.Int).Set(r.start)
+		for start.Cmp(r.end) <= 0 {
+			// Find the largest block that is aligned on start and does not extend past the
+			// end of the range.
+			hostBits := 0
+			for hostBits < bits {
+				size := new(big.Int).Lsh(one, uint(hostBits+1))
+				aligned := new(big.Int).Mod(start, size).Sign() == 0
+				last := new(big.Int).Add(start, size)
+				last.Sub(last, one)
+				if !aligned || last.Cmp(r.end) > 0 {
+					break
+				}
+				hostBits++
+			}
+			cidrs = append(cidrs, fmt.Sprintf("%s/%d", cnet.BigIntToIP(start, bits == 128), bits-hostBits))
+			start.Add(start, new(big.Int).Lsh(one, uint(hostBits)))
+		}
+	}
+	return cidrs
+}
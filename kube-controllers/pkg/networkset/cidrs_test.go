@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package networkset_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/networkset"
+)
+
+var _ = Describe("CIDR consolidation", func() {
+	DescribeTable("consolidating CIDRs",
+		func(input [][]string, expected []string) {
+			out, err := networkset.ConsolidateCIDRs(input...)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(out).To(Equal(expected))
+		},
+		Entry("empty input", nil, []string{}),
+		Entry("duplicates across feeds",
+			[][]string{{"10.0.0.0/24", "192.168.1.1"}, {"10.0.0.0/24", "192.168.1.1/32"}},
+			[]string{"10.0.0.0/24", "192.168.1.1/32"}),
+		Entry("contained CIDRs",
+			[][]string{{"10.0.0.0/8", "10.1.0.0/16", "10.1.2.3"}},
+			[]string{"10.0.0.0/8"}),
+		Entry("adjacent CIDRs",
+			[][]string{{"10.0.1.0/24", "10.0.0.0/24"}},
+			[]string{"10.0.0.0/23"}),
+		Entry("adjacent but unaligned CIDRs",
+			[][]string{{"10.0.1.0/24", "10.0.2.0/24"}},
+			[]string{"10.0.1.0/24", "10.0.2.0/24"}),
+		Entry("overlapping ranges",
+			[][]string{{"10.0.0.0/25", "10.0.0.64/26", "10.0.0.128/25", "10.0.1.0/24"}},
+			[]string{"10.0.0.0/23"}),
+		Entry("unnormalized CIDRs",
+			[][]string{{"10.0.0.5/24"}},
+			[]string{"10.0.0.0/24"}),
+		Entry("mixed IP versions",
+			[][]string{{"fd00::1", "fd00::/127", "1.2.3.4", "1.2.3.5"}},
+			[]string{"1.2.3.4/31", "fd00::/127"}),
+		Entry("full address space",
+			[][]string{{"0.0.0.0/1", "128.0.0.0/1"}},
+			[]string{"0.0.0.0/0"}),
+	)
+
+	It("should reject invalid CIDRs", func() {
+		_, err := networkset.ConsolidateCIDRs([]string{"10.0.0.0/24", "not-a-cidr"})
+		Expect(err).To(HaveOccurred())
+	})
+})
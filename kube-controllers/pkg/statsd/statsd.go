@@ -0,0 +1,174 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package statsd pushes the metrics in a Prometheus registry to a StatsD server, for monitoring
+// systems that can't scrape the Prometheus endpoint. Labels are sent as DogStatsD tags.
+package statsd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	log "github.com/sirupsen/logrus"
+)
+
+// maxPacketSize is the maximum size of the UDP packets sent to the StatsD server, chosen to fit in
+// the MTU of most networks.
+const maxPacketSize = 1432
+
+// Pusher periodically pushes the metrics gathered from a Prometheus registry to a StatsD server.
+// Gauges are sent as gauges. Counters, and the counts and sums of histograms and summaries, are
+// sent as counters holding the increase since the previous push.
+type Pusher struct {
+	conn     net.Conn
+	prefix   string
+	gatherer prometheus.Gatherer
+
+	// Value of each cumulative metric at the previous push, keyed by its StatsD name and tags.
+	last map[string]float64
+}
+
+// NewPusher returns a Pusher that sends the metrics from the given gatherer to the StatsD server
+// at the given address, with their names prefixed by prefix.
+func NewPusher(address, prefix string, gatherer prometheus.Gatherer) (*Pusher, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to StatsD server %s: %w", address, err)
+	}
+	return &Pusher{conn: conn, prefix: prefix, gatherer: gatherer, last: map[string]float64{}}, nil
+}
+
+// Run pushes the metrics every interval until the context is done.
+func (p *Pusher) Run(ctx context.Context, interval time.Duration) {
+	defer p.conn.Close()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Push(); err != nil {
+				log.WithError(err).Warn("Failed to push metrics to StatsD")
+			}
+		}
+	}
+}
+
+// Push sends the current value of each metric to the StatsD server.
+func (p *Pusher) Push() error {
+	mfs, err := p.gatherer.Gather()
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	for _, mf := range mfs {
+		name := p.prefix + mf.GetName()
+		for _, m := range mf.GetMetric() {
+			tags := tags(m.GetLabel())
+			switch mf.GetType() {
+			case dto.MetricType_COUNTER:
+				lines = p.appendDelta(lines, name, tags, m.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				lines = append(lines, line(name, m.GetGauge().GetValue(), "g", tags))
+			case dto.MetricType_UNTYPED:
+				lines = append(lines, line(name, m.GetUntyped().GetValue(), "g", tags))
+			case dto.MetricType_HISTOGRAM:
+				lines = p.appendDelta(lines, name+".count", tags, float64(m.GetHistogram().GetSampleCount()))
+				lines = p.appendDelta(lines, name+".sum", tags, m.GetHistogram().GetSampleSum())
+			case dto.MetricType_SUMMARY:
+				lines = p.appendDelta(lines, name+".count", tags, float64(m.GetSummary().GetSampleCount()))
+				lines = p.appendDelta(lines, name+".sum", tags, m.GetSummary().GetSampleSum())
+			}
+		}
+	}
+	return p.send(lines)
+}
+
+// appendDelta appends a counter line for the increase in the given cumulative value since the
+// previous push, if it has increased.
+func (p *Pusher) appendDelta(lines []string, name, tags string, value float64) []string {
+	id := name + tags
+	delta := value - p.last[id]
+	p.last[id] = value
+	if delta < 0 {
+		// The metric has been reset.
+		delta = value
+	}
+	if delta == 0 {
+		return lines
+	}
+	return append(lines, line(name, delta, "c", tags))
+}
+
+// send sends the given lines to the StatsD server, batching as many into each packet as fit.
+func (p *Pusher) send(lines []string) error {
+	var packet strings.Builder
+	flush := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := p.conn.Write([]byte(packet.String()))
+		packet.Reset()
+		return err
+	}
+	for _, l := range lines {
+		if packet.Len() > 0 && packet.Len()+1+len(l) > maxPacketSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(l)
+	}
+	return flush()
+}
+
+// line formats a StatsD line.
+func line(name string, value float64, statType, tags string) string {
+	return fmt.Sprintf("%s:%g|%s%s", name, value, statType, tags)
+}
+
+// tags formats the given labels as DogStatsD tags.
+func tags(labels []*dto.LabelPair) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	parts := make([]string, 0, len(labels))
+	for _, l := range labels {
+		parts = append(parts, sanitize(l.GetName())+":"+sanitize(l.GetValue()))
+	}
+	sort.Strings(parts)
+	return "|#" + strings.Join(parts, ",")
+}
+
+// sanitize replaces the characters that have special meaning in the StatsD protocol.
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '|', ',', '#', ':', '\n':
+			return '_'
+		}
+		return r
+	}, s)
+}
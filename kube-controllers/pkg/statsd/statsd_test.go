@@ -0,0 +1,86 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package statsd_test
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/statsd"
+)
+
+var _ = Describe("StatsD pusher", func() {
+	var server net.PacketConn
+	var registry *prometheus.Registry
+	var pusher *statsd.Pusher
+
+	var counter *prometheus.CounterVec
+	var gauge prometheus.Gauge
+	var histogram prometheus.Histogram
+
+	BeforeEach(func() {
+		var err error
+		server, err = net.ListenPacket("udp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		registry = prometheus.NewRegistry()
+		counter = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "writes_total", Help: "writes"}, []string{"controller"})
+		gauge = prometheus.NewGauge(prometheus.GaugeOpts{Name: "depth", Help: "depth"})
+		histogram = prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency_seconds", Help: "latency"})
+		registry.MustRegister(counter, gauge, histogram)
+
+		pusher, err = statsd.NewPusher(server.LocalAddr().String(), "kc.", registry)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	receive := func() []string {
+		buf := make([]byte, 65536)
+		Expect(server.SetReadDeadline(time.Now().Add(time.Second))).To(Succeed())
+		n, _, err := server.ReadFrom(buf)
+		Expect(err).NotTo(HaveOccurred())
+		return strings.Split(string(buf[:n]), "\n")
+	}
+
+	It("should push gauges, counter increases and histogram counts and sums", func() {
+		counter.WithLabelValues("policy").Add(3)
+		gauge.Set(7)
+		histogram.Observe(0.5)
+		Expect(pusher.Push()).To(Succeed())
+		Expect(receive()).To(ConsistOf(
+			"kc.depth:7|g",
+			"kc.latency_seconds.count:1|c",
+			"kc.latency_seconds.sum:0.5|c",
+			"kc.writes_total:3|c|#controller:policy",
+		))
+
+		// Only the increase since the last push is sent for counters, and unchanged counters
+		// are skipped.
+		counter.WithLabelValues("policy").Add(2)
+		Expect(pusher.Push()).To(Succeed())
+		Expect(receive()).To(ConsistOf(
+			"kc.depth:7|g",
+			"kc.writes_total:2|c|#controller:policy",
+		))
+	})
+})
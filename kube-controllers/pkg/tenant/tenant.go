@@ -0,0 +1,76 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant
+
+import (
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Other is the tenant that namespaces are attributed to if their tenant is not in the allowlist.
+const Other = "other"
+
+// Resolver maps namespaces to the tenant used to label metrics. Only tenants in the allowlist
+// are used as label values, so that the cardinality of the metrics is bounded by the size of
+// the allowlist rather than by the number of namespaces in the cluster.
+type Resolver struct {
+	allowed map[string]bool
+	label   string
+
+	// Store of Kubernetes namespaces, used to look up the tenant label. Only required if a
+	// tenant label is configured.
+	namespaces cache.Store
+}
+
+// NewResolver returns a Resolver for the given allowlist of tenants. If label is empty, each
+// namespace is its own tenant; otherwise the tenant is the value of the given label on the
+// namespace, looked up in the namespaces store.
+func NewResolver(allowed []string, label string, namespaces cache.Store) *Resolver {
+	r := &Resolver{allowed: map[string]bool{}, label: label, namespaces: namespaces}
+	for _, t := range allowed {
+		if t != "" {
+			r.allowed[t] = true
+		}
+	}
+	return r
+}
+
+// Enabled returns true if metrics should be labelled by tenant.
+func (r *Resolver) Enabled() bool {
+	return len(r.allowed) > 0
+}
+
+// Tenant returns the tenant label value for the given namespace. It returns an empty string
+// if per-tenant labels are disabled, and Other if the tenant is not in the allowlist.
+func (r *Resolver) Tenant(namespace string) string {
+	if !r.Enabled() {
+		return ""
+	}
+	t := namespace
+	if r.label != "" {
+		t = ""
+		obj, ok, err := r.namespaces.GetByKey(namespace)
+		if err != nil {
+			log.WithError(err).WithField("namespace", namespace).Debug("Failed to look up namespace tenant")
+		} else if ok {
+			t = obj.(*v1.Namespace).Labels[r.label]
+		}
+	}
+	if r.allowed[t] {
+		return t
+	}
+	return Other
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tenant_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/tenant"
+)
+
+var _ = Describe("Tenant resolver", func() {
+	It("should be disabled without an allowlist", func() {
+		r := tenant.NewResolver(nil, "", nil)
+		Expect(r.Enabled()).To(BeFalse())
+		Expect(r.Tenant("team-a")).To(Equal(""))
+	})
+
+	It("should use the namespace as the tenant", func() {
+		r := tenant.NewResolver([]string{"team-a", ""}, "", nil)
+		Expect(r.Enabled()).To(BeTrue())
+		Expect(r.Tenant("team-a")).To(Equal("team-a"))
+		Expect(r.Tenant("team-b")).To(Equal(tenant.Other))
+		Expect(r.Tenant("")).To(Equal(tenant.Other))
+	})
+
+	It("should use the tenant label of the namespace", func() {
+		store := cache.NewStore(cache.MetaNamespaceKeyFunc)
+		for name, t := range map[string]string{"ns1": "team-a", "ns2": "team-b", "ns3": ""} {
+			ns := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			if t != "" {
+				ns.Labels = map[string]string{"tenant": t}
+			}
+			Expect(store.Add(ns)).To(Succeed())
+		}
+
+		r := tenant.NewResolver([]string{"team-a"}, "tenant", store)
+		Expect(r.Tenant("ns1")).To(Equal("team-a"))
+		Expect(r.Tenant("ns2")).To(Equal(tenant.Other))
+		Expect(r.Tenant("ns3")).To(Equal(tenant.Other))
+		Expect(r.Tenant("missing")).To(Equal(tenant.Other))
+	})
+})
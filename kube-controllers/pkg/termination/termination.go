@@ -0,0 +1,114 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package termination
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultLogPath is the default path that Kubernetes reads a container's termination message from.
+	DefaultLogPath = "/dev/termination-log"
+
+	// CodeField is the log field used to classify a fatal error, e.g.
+	//
+	//	log.WithField(termination.CodeField, termination.CodeConfig).Fatal("invalid config")
+	CodeField = "terminationCode"
+
+	// Kubernetes truncates termination messages longer than 4096 bytes, so limit the length
+	// of the free-form fields to keep the message valid JSON.
+	maxFieldLength = 1024
+)
+
+// Code classifies the reason that kube-controllers exited.
+type Code string
+
+const (
+	CodeConfig                Code = "ConfigError"
+	CodeDatastoreUnavailable  Code = "DatastoreUnavailable"
+	CodeKubernetesUnavailable Code = "KubernetesUnavailable"
+	CodeMetricsServer         Code = "MetricsServerError"
+	CodeUnknown               Code = "Unknown"
+)
+
+var remediations = map[Code]string{
+	CodeConfig:                "Check the kube-controllers environment variables and the default KubeControllersConfiguration resource.",
+	CodeDatastoreUnavailable:  "Check that the Calico datastore is reachable and that the configured credentials and certificates are valid.",
+	CodeKubernetesUnavailable: "Check that the Kubernetes API server is reachable and that the kubeconfig or service account credentials are valid.",
+	CodeMetricsServer:         "Check that the Prometheus metrics port is not already in use.",
+	CodeUnknown:               "Check the kube-controllers logs for more details.",
+}
+
+// Message is the machine-readable termination message.
+type Message struct {
+	Code        Code   `json:"code"`
+	Message     string `json:"message"`
+	Error       string `json:"error,omitempty"`
+	Remediation string `json:"remediation"`
+}
+
+// Hook is a logrus hook that writes a termination message when a fatal error is logged, so that
+// the reason for the exit is visible in the pod status.
+type Hook struct {
+	path string
+}
+
+// NewHook returns a Hook that writes termination messages to the given path.
+func NewHook(path string) *Hook {
+	return &Hook{path: path}
+}
+
+func (h *Hook) Levels() []log.Level {
+	return []log.Level{log.PanicLevel, log.FatalLevel}
+}
+
+func (h *Hook) Fire(entry *log.Entry) error {
+	b, err := json.Marshal(NewMessage(entry))
+	if err != nil {
+		return err
+	}
+	// If this fails we're most likely not running in a container, so there is nowhere to write
+	// the message. Don't let that get in the way of reporting the original error.
+	_ = os.WriteFile(h.path, b, 0644)
+	return nil
+}
+
+// NewMessage builds the termination message for the given log entry.
+func NewMessage(entry *log.Entry) Message {
+	code := CodeUnknown
+	if c, ok := entry.Data[CodeField].(Code); ok {
+		code = c
+	}
+	m := Message{
+		Code:        code,
+		Message:     truncate(entry.Message),
+		Remediation: remediations[code],
+	}
+	if err, ok := entry.Data[log.ErrorKey]; ok {
+		m.Error = truncate(fmt.Sprint(err))
+	}
+	return m
+}
+
+func truncate(s string) string {
+	if len(s) > maxFieldLength {
+		return s[:maxFieldLength]
+	}
+	return s
+}
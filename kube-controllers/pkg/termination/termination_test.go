@@ -0,0 +1,84 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package termination_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/kube-controllers/pkg/termination"
+)
+
+var _ = Describe("Termination messages", func() {
+	It("should classify an entry using the code field", func() {
+		entry := log.WithError(errors.New("connection refused")).
+			WithField(termination.CodeField, termination.CodeDatastoreUnavailable)
+		entry.Message = "Failed to initialize Calico datastore"
+
+		m := termination.NewMessage(entry)
+		Expect(m.Code).To(Equal(termination.CodeDatastoreUnavailable))
+		Expect(m.Message).To(Equal("Failed to initialize Calico datastore"))
+		Expect(m.Error).To(Equal("connection refused"))
+		Expect(m.Remediation).NotTo(BeEmpty())
+	})
+
+	It("should use the unknown code for unclassified entries", func() {
+		entry := log.WithField("foo", "bar")
+		entry.Message = "Something went wrong"
+
+		m := termination.NewMessage(entry)
+		Expect(m.Code).To(Equal(termination.CodeUnknown))
+		Expect(m.Error).To(BeEmpty())
+		Expect(m.Remediation).NotTo(BeEmpty())
+	})
+
+	It("should limit the length of the message", func() {
+		entry := log.WithError(errors.New(strings.Repeat("x", 10000)))
+		entry.Message = strings.Repeat("y", 10000)
+
+		b, err := json.Marshal(termination.NewMessage(entry))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(b)).To(BeNumerically("<", 4096))
+	})
+
+	It("should write the message as JSON from the hook", func() {
+		dir, err := os.MkdirTemp("", "termination")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "termination-log")
+
+		hook := termination.NewHook(path)
+		Expect(hook.Levels()).To(ContainElement(log.FatalLevel))
+
+		entry := log.WithField(termination.CodeField, termination.CodeConfig)
+		entry.Message = "invalid environment variable value"
+		Expect(hook.Fire(entry)).To(Succeed())
+
+		b, err := os.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		var m termination.Message
+		Expect(json.Unmarshal(b, &m)).To(Succeed())
+		Expect(m.Code).To(Equal(termination.CodeConfig))
+		Expect(m.Message).To(Equal("invalid environment variable value"))
+	})
+})
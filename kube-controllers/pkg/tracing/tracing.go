@@ -0,0 +1,90 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracing provides optional OpenTelemetry tracing of the controllers' processing of
+// updates, from the informer event through to the datastore write. Spans for the same object are
+// correlated by its key. Tracing is a no-op unless Setup has been called.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	tracerName  = "github.com/projectcalico/calico/kube-controllers"
+	serviceName = "calico-kube-controllers"
+
+	// KeyAttribute is the span attribute holding the key of the object being processed.
+	KeyAttribute = attribute.Key("calico.key")
+)
+
+// Setup starts exporting spans over OTLP/gRPC to the given endpoint. Other exporter options, such
+// as TLS, can be set using the standard OTEL_EXPORTER_OTLP_* environment variables. The returned
+// function flushes any outstanding spans and stops the exporter.
+func Setup(ctx context.Context, endpoint string) (func(context.Context) error, error) {
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span as a child of any span in the given context.
+func Start(ctx context.Context, name string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name)
+}
+
+// StartKey starts a span for processing the object with the given key.
+func StartKey(ctx context.Context, name, key string) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(KeyAttribute.String(key)))
+}
+
+// StartEvent starts the span for handling an informer event for the given Kubernetes object.
+func StartEvent(controller, event string, obj interface{}) (context.Context, trace.Span) {
+	// The key is only used to correlate spans, so carry on without it if it can't be generated.
+	key, _ := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	return StartKey(context.Background(), controller+" "+event, key)
+}
+
+// Record records a span for an operation that started at the given time and has just completed.
+func Record(ctx context.Context, name string, start time.Time, err error) {
+	_, span := otel.Tracer(tracerName).Start(ctx, name, trace.WithTimestamp(start))
+	End(span, err)
+}
+
+// End ends the span, recording the given error on it if not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -57,6 +57,11 @@ type EtcdConfig struct {
 	EtcdCertFile     string `json:"etcdCertFile" envconfig:"ETCD_CERT_FILE"`
 	EtcdCACertFile   string `json:"etcdCACertFile" envconfig:"ETCD_CA_CERT_FILE"`
 
+	// Files containing the etcd username and password, for example mounted from a Kubernetes
+	// Secret. These take precedence over EtcdUsername and EtcdPassword.
+	EtcdUsernameFile string `json:"etcdUsernameFile" envconfig:"ETCD_USERNAME_FILE"`
+	EtcdPasswordFile string `json:"etcdPasswordFile" envconfig:"ETCD_PASSWORD_FILE"`
+
 	// These config file parameters are to support inline certificates, keys and CA / Trusted certificate.
 	// There are no corresponding environment variables to avoid accidental exposure.
 	EtcdKey    string `json:"etcdKey" ignored:"true"`
@@ -82,6 +87,8 @@ type KubeConfig struct {
 	KubeconfigInline string `json:"kubeconfigInline" ignored:"true"`
 	// K8sClientQPS overrides the QPS for the Kube client.
 	K8sClientQPS float32 `json:"k8sClientQPS"`
+	// K8sClientBurst overrides the burst for the Kube client.
+	K8sClientBurst int `json:"k8sClientBurst"`
 	// K8sCurrentContext provides a context override for kubeconfig.
 	K8sCurrentContext string `json:"k8sCurrentContext" envconfig:"K8S_CURRENT_CONTEXT" default:""`
 }
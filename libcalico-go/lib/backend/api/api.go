@@ -195,6 +195,11 @@ const (
 	WatchModified WatchEventType = "MODIFIED"
 	WatchDeleted  WatchEventType = "DELETED"
 	WatchError    WatchEventType = "ERROR"
+
+	// WatchBookmark events don't represent a change to any resource, but report the latest
+	// revision of the watched resources, so that the watch can be resumed from that revision
+	// rather than an older one that may have expired.
+	WatchBookmark WatchEventType = "BOOKMARK"
 )
 
 // Event represents a single event to a watched resource.
@@ -206,6 +211,7 @@ type WatchEvent struct {
 	// * If Type is Modified or Deleted: the previous state of the object
 	// New is:
 	//  * If Type is Added or Modified: the new state of the object.
+	//  * If Type is Bookmark: a KVPair with just the Revision set.
 	//  * If Type is Deleted or Error: nil
 	Old *model.KVPair
 	New *model.KVPair
@@ -0,0 +1,166 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdv3
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// EnableCAReload configures the given etcd client config to verify servers against the CA bundle
+// in caFile, re-reading the file whenever it changes on disk. This allows the CA to be rotated
+// (for example, by updating a mounted Kubernetes Secret) without recreating the client. It must
+// be called once cfg.TLS has been set.
+//
+// Note that the etcd transport already re-reads the client certificate and key files on each
+// handshake, so this completes support for rotating all of the client TLS material.
+func EnableCAReload(cfg *clientv3.Config, caFile string) {
+	for _, ep := range cfg.Endpoints {
+		if !strings.HasPrefix(ep, "https://") && !strings.HasPrefix(ep, "unixs://") {
+			// The TLS credentials below would also be used for plain text endpoints, so leave
+			// the client with the CA that it loaded at startup.
+			log.WithField("endpoint", ep).Info("Not all etcd endpoints use TLS, CA file won't be reloaded")
+			return
+		}
+	}
+	cfg.DialOptions = append(cfg.DialOptions, grpc.WithTransportCredentials(newCAReloadCredentials(cfg.TLS, caFile)))
+}
+
+// caReloadCredentials are gRPC TLS credentials that verify the server's certificate against the
+// latest CA bundle. The host that the server is verified against is taken from the endpoint being
+// dialled, or from the configured server name if there is one, just as the standard verification
+// does. crypto/tls doesn't pass the name to VerifyConnection for IP addresses, so the credentials
+// build a TLS config for each handshake that verifies against that name.
+type caReloadCredentials struct {
+	credentials.TransportCredentials
+
+	base     *tls.Config
+	reloader *caReloader
+}
+
+func newCAReloadCredentials(base *tls.Config, caFile string) *caReloadCredentials {
+	return &caReloadCredentials{
+		TransportCredentials: credentials.NewTLS(base),
+		base:                 base,
+		reloader:             &caReloader{file: caFile},
+	}
+}
+
+func (c *caReloadCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	cfg := c.base.Clone()
+	if cfg.ServerName == "" {
+		cfg.ServerName = authority
+		if host, _, err := net.SplitHostPort(authority); err == nil {
+			cfg.ServerName = host
+		}
+	}
+	serverName := cfg.ServerName
+	cfg.RootCAs = nil
+	cfg.InsecureSkipVerify = true
+	cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+		return c.reloader.verify(cs, serverName)
+	}
+	return credentials.NewTLS(cfg).ClientHandshake(ctx, authority, rawConn)
+}
+
+func (c *caReloadCredentials) Clone() credentials.TransportCredentials {
+	return &caReloadCredentials{
+		TransportCredentials: c.TransportCredentials.Clone(),
+		base:                 c.base,
+		reloader:             c.reloader,
+	}
+}
+
+// caReloader loads a CA bundle from disk, caching it until the file's modification time changes.
+type caReloader struct {
+	file string
+
+	lock    sync.Mutex
+	modTime time.Time
+	pool    *x509.CertPool
+}
+
+func (r *caReloader) certPool() (*x509.CertPool, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	info, err := os.Stat(r.file)
+	if err != nil {
+		if r.pool != nil {
+			// The file may be briefly missing while it is being replaced, so continue to use
+			// the previous CA bundle.
+			log.WithError(err).WithField("file", r.file).Warn("Unable to stat etcd CA file, using previous CA")
+			return r.pool, nil
+		}
+		return nil, err
+	}
+	if r.pool != nil && info.ModTime().Equal(r.modTime) {
+		return r.pool, nil
+	}
+
+	pem, err := os.ReadFile(r.file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		if r.pool != nil {
+			log.WithField("file", r.file).Warn("Etcd CA file contains no valid certificates, using previous CA")
+			return r.pool, nil
+		}
+		return nil, fmt.Errorf("no valid certificates found in %s", r.file)
+	}
+	if r.pool != nil {
+		log.WithField("file", r.file).Info("Etcd CA file changed, reloaded CA")
+	}
+	r.pool = pool
+	r.modTime = info.ModTime()
+	return r.pool, nil
+}
+
+// verify checks that the server's certificate chains to the CA bundle, and is valid for the given
+// host name or IP address.
+func (r *caReloader) verify(cs tls.ConnectionState, serverName string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("etcd server did not present a certificate")
+	}
+	pool, err := r.certPool()
+	if err != nil {
+		return err
+	}
+	opts := x509.VerifyOptions{
+		Roots:         pool,
+		DNSName:       serverName,
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err = cs.PeerCertificates[0].Verify(opts)
+	return err
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdv3
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// testCA issues server certificates for the CA reload tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA() *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "etcd-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+func (ca *testCA) serverCert(dnsNames []string, ips []net.IP) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "etcd"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		DNSNames:     dnsNames,
+		IPAddresses:  ips,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	Expect(err).NotTo(HaveOccurred())
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+var _ = Describe("CA reload", func() {
+	var (
+		ca       *testCA
+		caDir    string
+		caFile   string
+		listener net.Listener
+	)
+
+	BeforeEach(func() {
+		listener = nil
+		ca = newTestCA()
+		var err error
+		caDir, err = os.MkdirTemp("", "etcd-ca")
+		Expect(err).NotTo(HaveOccurred())
+		caFile = filepath.Join(caDir, "ca.crt")
+		Expect(os.WriteFile(caFile, ca.pem, 0o600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if listener != nil {
+			listener.Close()
+		}
+		Expect(os.RemoveAll(caDir)).To(Succeed())
+	})
+
+	// serve starts a TLS server on localhost with the given certificate, which completes the
+	// handshake with each client and then closes the connection.
+	serve := func(cert tls.Certificate) string {
+		var err error
+		listener, err = tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+		Expect(err).NotTo(HaveOccurred())
+		// The accept loop uses its own copy of the listener, since the shared variable is reset
+		// for the next test while the loop may still be running.
+		l := listener
+		go func() {
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				_ = conn.(*tls.Conn).Handshake()
+				conn.Close()
+			}
+		}()
+		return l.Addr().String()
+	}
+
+	// handshake dials the server and performs a client handshake with the given credentials, as
+	// gRPC would for the given authority.
+	handshake := func(creds *caReloadCredentials, authority string) error {
+		conn, err := net.Dial("tcp", listener.Addr().String())
+		Expect(err).NotTo(HaveOccurred())
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, _, err = creds.ClientHandshake(ctx, authority, conn)
+		return err
+	}
+	newCreds := func(serverName string) *caReloadCredentials {
+		return newCAReloadCredentials(&tls.Config{ServerName: serverName}, caFile)
+	}
+
+	It("should verify the IP address of an IP endpoint", func() {
+		addr := serve(ca.serverCert(nil, []net.IP{net.ParseIP("127.0.0.1")}))
+		Expect(handshake(newCreds(""), addr)).To(Succeed())
+	})
+
+	It("should verify the host name of a DNS endpoint", func() {
+		serve(ca.serverCert([]string{"etcd.example.com"}, nil))
+		Expect(handshake(newCreds(""), "etcd.example.com:2379")).To(Succeed())
+	})
+
+	It("should verify against the configured server name", func() {
+		addr := serve(ca.serverCert([]string{"etcd.example.com"}, nil))
+		Expect(handshake(newCreds("etcd.example.com"), addr)).To(Succeed())
+	})
+
+	It("should reject a certificate for another IP address", func() {
+		addr := serve(ca.serverCert(nil, []net.IP{net.ParseIP("10.0.0.1")}))
+		Expect(handshake(newCreds(""), addr)).To(MatchError(ContainSubstring("127.0.0.1")))
+	})
+
+	It("should reject a certificate for another host name", func() {
+		addr := serve(ca.serverCert([]string{"other.example.com"}, nil))
+		Expect(handshake(newCreds(""), addr)).To(HaveOccurred())
+		Expect(handshake(newCreds(""), "etcd.example.com:2379")).To(MatchError(ContainSubstring("etcd.example.com")))
+	})
+
+	It("should reject a certificate from another CA", func() {
+		addr := serve(newTestCA().serverCert(nil, []net.IP{net.ParseIP("127.0.0.1")}))
+		Expect(handshake(newCreds(""), addr)).To(HaveOccurred())
+	})
+
+	It("should pick up a new CA", func() {
+		other := newTestCA()
+		addr := serve(other.serverCert(nil, []net.IP{net.ParseIP("127.0.0.1")}))
+		creds := newCreds("")
+		Expect(handshake(creds, addr)).To(HaveOccurred())
+
+		Expect(os.WriteFile(caFile, other.pem, 0o600)).To(Succeed())
+		later := time.Now().Add(time.Minute)
+		Expect(os.Chtimes(caFile, later, later)).To(Succeed())
+		Expect(handshake(creds, addr)).To(Succeed())
+	})
+
+	It("should configure TLS endpoints to use the credentials", func() {
+		cfg := clientv3.Config{
+			Endpoints: []string{"https://10.0.0.1:2379", "https://etcd.example.com:2379"},
+			TLS:       &tls.Config{},
+		}
+		EnableCAReload(&cfg, caFile)
+		Expect(cfg.DialOptions).To(HaveLen(1))
+	})
+
+	It("should leave plain text endpoints alone", func() {
+		cfg := clientv3.Config{
+			Endpoints: []string{"https://10.0.0.1:2379", "http://10.0.0.2:2379"},
+			TLS:       &tls.Config{},
+		}
+		EnableCAReload(&cfg, caFile)
+		Expect(cfg.DialOptions).To(BeEmpty())
+	})
+})
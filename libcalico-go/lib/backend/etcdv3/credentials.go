@@ -0,0 +1,63 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdv3
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+)
+
+// Credentials returns the username and password to use to authenticate with etcd, reading
+// them from the configured files if necessary. It returns empty strings if authentication is
+// not configured.
+func Credentials(config *apiconfig.EtcdConfig) (string, string, error) {
+	username := config.EtcdUsername
+	password := config.EtcdPassword
+
+	var err error
+	if config.EtcdUsernameFile != "" {
+		if username, err = readCredentialFile(config.EtcdUsernameFile); err != nil {
+			return "", "", err
+		}
+	}
+	if config.EtcdPasswordFile != "" {
+		if password, err = readCredentialFile(config.EtcdPasswordFile); err != nil {
+			return "", "", err
+		}
+	}
+
+	if username == "" || password == "" {
+		if username != "" || password != "" {
+			log.Warning("Ignoring etcd credentials, both a username and password must be specified")
+		}
+		return "", "", nil
+	}
+	return username, password, nil
+}
+
+func readCredentialFile(file string) (string, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read etcd credentials: %w", err)
+	}
+	// Secrets are often created from files with a trailing newline, which is never intended
+	// to be part of the credential.
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdv3_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/apiconfig"
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/etcdv3"
+)
+
+var _ = Describe("Credentials", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "etcd-credentials")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(contents), 0600)).To(Succeed())
+		return path
+	}
+
+	It("should use the configured username and password", func() {
+		u, p, err := etcdv3.Credentials(&apiconfig.EtcdConfig{EtcdUsername: "user", EtcdPassword: "pass"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u).To(Equal("user"))
+		Expect(p).To(Equal("pass"))
+	})
+
+	It("should prefer credentials read from files, without trailing newlines", func() {
+		u, p, err := etcdv3.Credentials(&apiconfig.EtcdConfig{
+			EtcdUsername:     "user",
+			EtcdPassword:     "pass",
+			EtcdUsernameFile: writeFile("username", "file-user\n"),
+			EtcdPasswordFile: writeFile("password", "file-pass"),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u).To(Equal("file-user"))
+		Expect(p).To(Equal("file-pass"))
+	})
+
+	It("should ignore a username without a password", func() {
+		u, p, err := etcdv3.Credentials(&apiconfig.EtcdConfig{EtcdUsername: "user"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(u).To(BeEmpty())
+		Expect(p).To(BeEmpty())
+	})
+
+	It("should return an error if a credentials file cannot be read", func() {
+		_, _, err := etcdv3.Credentials(&apiconfig.EtcdConfig{
+			EtcdUsername:     "user",
+			EtcdPasswordFile: filepath.Join(dir, "missing"),
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
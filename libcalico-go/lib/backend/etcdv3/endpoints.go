@@ -0,0 +1,109 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdv3
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var (
+	endpointHealthInterval = 10 * time.Second
+	endpointHealthTimeout  = 5 * time.Second
+)
+
+// ParseEndpoints splits a comma separated list of etcd endpoints, ignoring surrounding
+// whitespace, empty entries and duplicates.
+func ParseEndpoints(endpoints string) []string {
+	eps := []string{}
+	seen := map[string]bool{}
+	for _, ep := range strings.Split(endpoints, ",") {
+		ep = strings.TrimSpace(ep)
+		if ep == "" || seen[ep] {
+			continue
+		}
+		seen[ep] = true
+		eps = append(eps, ep)
+	}
+	return eps
+}
+
+// MonitorEndpoints periodically health checks each of the given endpoints and restricts the
+// client to the endpoints that are currently healthy, so that requests fail over away from an
+// unreachable etcd member rather than waiting on it. If no endpoints are healthy, the client is
+// given the full list, so that it can reconnect as soon as any member recovers.
+//
+// Monitoring stops when the client is closed. It is a no-op for a single endpoint, since there
+// is nothing to fail over to.
+func MonitorEndpoints(client *clientv3.Client, endpoints []string) {
+	if len(endpoints) < 2 {
+		return
+	}
+	go monitorEndpoints(client, endpoints)
+}
+
+func monitorEndpoints(client *clientv3.Client, endpoints []string) {
+	ctx := client.Ctx()
+	ticker := time.NewTicker(endpointHealthInterval)
+	defer ticker.Stop()
+
+	current := endpoints
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		healthy := map[string]bool{}
+		for _, ep := range endpoints {
+			sctx, cancel := context.WithTimeout(ctx, endpointHealthTimeout)
+			_, err := client.Status(sctx, ep)
+			cancel()
+			if err != nil {
+				log.WithError(err).WithField("endpoint", ep).Warning("etcd endpoint failed health check")
+				continue
+			}
+			healthy[ep] = true
+		}
+
+		selected := selectEndpoints(endpoints, healthy)
+		if !reflect.DeepEqual(selected, current) {
+			log.WithFields(log.Fields{"previous": current, "endpoints": selected}).Info("Updating active etcd endpoints")
+			client.SetEndpoints(selected...)
+			current = selected
+		}
+	}
+}
+
+// selectEndpoints returns the healthy endpoints, in their configured order, or all of the
+// endpoints if none are healthy.
+func selectEndpoints(endpoints []string, healthy map[string]bool) []string {
+	selected := []string{}
+	for _, ep := range endpoints {
+		if healthy[ep] {
+			selected = append(selected, ep)
+		}
+	}
+	if len(selected) == 0 {
+		return endpoints
+	}
+	return selected
+}
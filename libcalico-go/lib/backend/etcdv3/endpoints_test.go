@@ -0,0 +1,40 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdv3_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectcalico/calico/libcalico-go/lib/backend/etcdv3"
+)
+
+var _ = Describe("ParseEndpoints", func() {
+	It("should split a comma separated list of endpoints", func() {
+		Expect(etcdv3.ParseEndpoints("https://10.0.0.1:2379,https://10.0.0.2:2379")).To(Equal([]string{
+			"https://10.0.0.1:2379", "https://10.0.0.2:2379",
+		}))
+	})
+
+	It("should ignore whitespace, empty entries and duplicates", func() {
+		Expect(etcdv3.ParseEndpoints(" https://10.0.0.1:2379, ,https://10.0.0.2:2379 ,https://10.0.0.1:2379,")).To(Equal([]string{
+			"https://10.0.0.1:2379", "https://10.0.0.2:2379",
+		}))
+	})
+
+	It("should return no endpoints for an empty string", func() {
+		Expect(etcdv3.ParseEndpoints("")).To(BeEmpty())
+	})
+})
@@ -64,7 +64,7 @@ func NewEtcdV3Client(config *apiconfig.EtcdConfig) (api.Client, error) {
 	// Split the endpoints into a location slice.
 	etcdLocation := []string{}
 	if config.EtcdEndpoints != "" {
-		etcdLocation = strings.Split(config.EtcdEndpoints, ",")
+		etcdLocation = ParseEndpoints(config.EtcdEndpoints)
 	}
 
 	if config.EtcdDiscoverySrv != "" {
@@ -130,10 +130,15 @@ func NewEtcdV3Client(config *apiconfig.EtcdConfig) (api.Client, error) {
 		DialKeepAliveTimeout: keepaliveTimeout,
 	}
 
+	if !haveInline && config.EtcdCACertFile != "" {
+		// Pick up changes to the CA file without needing to recreate the client.
+		EnableCAReload(&cfg, config.EtcdCACertFile)
+	}
+
 	// Plumb through the username and password if both are configured.
-	if config.EtcdUsername != "" && config.EtcdPassword != "" {
-		cfg.Username = config.EtcdUsername
-		cfg.Password = config.EtcdPassword
+	cfg.Username, cfg.Password, err = Credentials(config)
+	if err != nil {
+		return nil, err
 	}
 
 	client, err := clientv3.New(cfg)
@@ -141,6 +146,9 @@ func NewEtcdV3Client(config *apiconfig.EtcdConfig) (api.Client, error) {
 		return nil, err
 	}
 
+	// When multiple endpoints are configured, fail over away from any that become unhealthy.
+	MonitorEndpoints(client, etcdLocation)
+
 	return &etcdV3Client{etcdClient: client}, nil
 }
 
@@ -484,6 +492,11 @@ func (c *etcdV3Client) EnsureInitialized() error {
 	return nil
 }
 
+// Close closes the connection to etcd, ending any watches and endpoint monitoring using it.
+func (c *etcdV3Client) Close() error {
+	return c.etcdClient.Close()
+}
+
 // Clean removes all of the Calico data from the datastore.
 func (c *etcdV3Client) Clean() error {
 	log.Debug("Cleaning etcdv3 datastore of all Calico data")
@@ -0,0 +1,187 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+)
+
+var (
+	apiEndpointHealthInterval = 10 * time.Second
+	apiEndpointHealthTimeout  = 5 * time.Second
+)
+
+// ParseAPIEndpoints splits a comma separated list of Kubernetes API server endpoints, in order of
+// priority, ignoring surrounding whitespace, empty entries and duplicates.
+func ParseAPIEndpoints(endpoints string) []string {
+	eps := []string{}
+	seen := map[string]bool{}
+	for _, ep := range strings.Split(endpoints, ",") {
+		ep = strings.TrimSpace(ep)
+		if ep == "" || seen[ep] {
+			continue
+		}
+		seen[ep] = true
+		eps = append(eps, ep)
+	}
+	return eps
+}
+
+// WithEndpointFailover configures the given client config to send requests to the first healthy
+// endpoint of the given prioritized list of Kubernetes API server endpoints. Each endpoint is
+// periodically health checked, so that requests fail over when an endpoint becomes unreachable,
+// and fail back once a higher priority endpoint recovers, until the given context is done. A
+// request that fails to reach its endpoint also moves the client on to the next endpoint, so that
+// it is retried elsewhere.
+//
+// The config's host is set to the first endpoint, and certificates are verified against its host
+// name unless a server name is already configured, so the API server certificates must be valid
+// for the first endpoint's host name. This is a no-op for fewer than two endpoints.
+func WithEndpointFailover(ctx context.Context, cfg *rest.Config, endpoints []string) error {
+	if len(endpoints) < 2 {
+		return nil
+	}
+	urls := []*url.URL{}
+	for _, ep := range endpoints {
+		u, err := url.Parse(ep)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return errors.New("API server endpoint must be a URL with a scheme and host: " + ep)
+		}
+		urls = append(urls, u)
+	}
+
+	cfg.Host = endpoints[0]
+	if cfg.TLSClientConfig.ServerName == "" && !cfg.TLSClientConfig.Insecure {
+		cfg.TLSClientConfig.ServerName = urls[0].Hostname()
+	}
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return newFailoverRoundTripper(ctx, rt, urls)
+	})
+	return nil
+}
+
+type failoverRoundTripper struct {
+	ctx       context.Context
+	rt        http.RoundTripper
+	endpoints []*url.URL
+	startOnce sync.Once
+
+	lock   sync.Mutex
+	active int
+}
+
+func newFailoverRoundTripper(ctx context.Context, rt http.RoundTripper, endpoints []*url.URL) *failoverRoundTripper {
+	return &failoverRoundTripper{ctx: ctx, rt: rt, endpoints: endpoints}
+}
+
+func (f *failoverRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Health checks need a transport, so start them with the first request.
+	f.startOnce.Do(func() { go f.monitorEndpoints() })
+
+	f.lock.Lock()
+	active := f.active
+	f.lock.Unlock()
+	ep := f.endpoints[active]
+
+	r := req.Clone(req.Context())
+	r.URL.Scheme = ep.Scheme
+	r.URL.Host = ep.Host
+	resp, err := f.rt.RoundTrip(r)
+	if err != nil && req.Context().Err() == nil {
+		// The endpoint couldn't be reached. Move on to the next endpoint, unless another request
+		// already did so.
+		f.lock.Lock()
+		if f.active == active {
+			f.active = (active + 1) % len(f.endpoints)
+			log.WithError(err).WithFields(log.Fields{
+				"endpoint": ep.Host,
+				"next":     f.endpoints[f.active].Host,
+			}).Warning("Kubernetes API server endpoint unreachable, failing over")
+		}
+		f.lock.Unlock()
+	}
+	return resp, err
+}
+
+// monitorEndpoints health checks the endpoints periodically, until the context is done.
+func (f *failoverRoundTripper) monitorEndpoints() {
+	ticker := time.NewTicker(apiEndpointHealthInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-ticker.C:
+			f.checkEndpoints()
+		}
+	}
+}
+
+// checkEndpoints health checks each of the endpoints and makes the highest priority healthy
+// endpoint active. If no endpoints are healthy, the active endpoint is left unchanged.
+func (f *failoverRoundTripper) checkEndpoints() {
+	for i, ep := range f.endpoints {
+		if !f.healthy(ep) {
+			continue
+		}
+		f.lock.Lock()
+		if f.active != i {
+			log.WithFields(log.Fields{
+				"previous": f.endpoints[f.active].Host,
+				"endpoint": ep.Host,
+			}).Info("Updating active Kubernetes API server endpoint")
+			f.active = i
+		}
+		f.lock.Unlock()
+		return
+	}
+	log.Warning("No Kubernetes API server endpoints passed health check")
+}
+
+// healthy returns whether the given endpoint is ready to serve requests. The check may not be
+// authenticated, so any response other than a server error counts as healthy.
+func (f *failoverRoundTripper) healthy(ep *url.URL) bool {
+	ctx, cancel := context.WithTimeout(f.ctx, apiEndpointHealthTimeout)
+	defer cancel()
+	u := *ep
+	u.Path = "/readyz"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := f.rt.RoundTrip(req)
+	if err != nil {
+		log.WithError(err).WithField("endpoint", ep.Host).Warning("Kubernetes API server endpoint failed health check")
+		return false
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		log.WithFields(log.Fields{"endpoint": ep.Host, "status": resp.StatusCode}).Warning("Kubernetes API server endpoint failed health check")
+		return false
+	}
+	return true
+}
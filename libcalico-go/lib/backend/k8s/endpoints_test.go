@@ -0,0 +1,128 @@
+// Copyright (c) 2026 Tigera, Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
+)
+
+var _ = Describe("ParseAPIEndpoints", func() {
+	It("should split a prioritized list of endpoints", func() {
+		Expect(ParseAPIEndpoints(" https://10.0.0.1:6443, ,https://10.0.0.2:6443,https://10.0.0.1:6443")).To(Equal([]string{
+			"https://10.0.0.1:6443", "https://10.0.0.2:6443",
+		}))
+	})
+})
+
+var _ = Describe("API server endpoint failover", func() {
+	var primary, backup *httptest.Server
+	var primaryReady atomic.Bool
+	var f *failoverRoundTripper
+	var cancel context.CancelFunc
+
+	BeforeEach(func() {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		primaryReady.Store(true)
+		primary = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !primaryReady.Load() {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("X-Endpoint", "primary")
+		}))
+		backup = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Endpoint", "backup")
+		}))
+		pu, _ := url.Parse(primary.URL)
+		bu, _ := url.Parse(backup.URL)
+		f = newFailoverRoundTripper(ctx, http.DefaultTransport, []*url.URL{pu, bu})
+	})
+
+	AfterEach(func() {
+		cancel()
+		primary.Close()
+		backup.Close()
+	})
+
+	get := func() (string, error) {
+		req, err := http.NewRequest(http.MethodGet, primary.URL+"/api", nil)
+		Expect(err).NotTo(HaveOccurred())
+		resp, err := f.RoundTrip(req)
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+		return resp.Header.Get("X-Endpoint"), nil
+	}
+
+	It("should use the first endpoint while it is reachable", func() {
+		Expect(get()).To(Equal("primary"))
+		Expect(get()).To(Equal("primary"))
+	})
+
+	It("should fail over when the active endpoint is unreachable", func() {
+		primary.Close()
+		_, err := get()
+		Expect(err).To(HaveOccurred())
+		Expect(get()).To(Equal("backup"))
+	})
+
+	It("should fail over and back based on health checks", func() {
+		primaryReady.Store(false)
+		f.checkEndpoints()
+		Expect(get()).To(Equal("backup"))
+
+		primaryReady.Store(true)
+		f.checkEndpoints()
+		Expect(get()).To(Equal("primary"))
+	})
+
+	It("should configure the client to use the first endpoint", func() {
+		cfg := &rest.Config{Host: "https://lb.example.com:6443"}
+		Expect(WithEndpointFailover(context.Background(), cfg, []string{"https://api1.example.com:6443", "https://10.0.0.2:6443"})).To(Succeed())
+		Expect(cfg.Host).To(Equal("https://api1.example.com:6443"))
+		Expect(cfg.TLSClientConfig.ServerName).To(Equal("api1.example.com"))
+		Expect(cfg.WrapTransport).NotTo(BeNil())
+	})
+
+	It("should reject endpoints that aren't URLs", func() {
+		cfg := &rest.Config{}
+		Expect(WithEndpointFailover(context.Background(), cfg, []string{"https://10.0.0.1:6443", "10.0.0.2"})).NotTo(Succeed())
+	})
+	It("should stop health checking once the context is done", func() {
+		ticks := apiEndpointHealthInterval
+		apiEndpointHealthInterval = time.Millisecond
+		defer func() { apiEndpointHealthInterval = ticks }()
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			f.monitorEndpoints()
+		}()
+		Consistently(done, 20*time.Millisecond).ShouldNot(BeClosed())
+		cancel()
+		Eventually(done, time.Second).Should(BeClosed())
+	})
+})
@@ -75,16 +75,22 @@ type KubeClient struct {
 
 	// Non v3 resource clients keyed off List Type.
 	clientsByListType map[reflect.Type]resources.K8sResourceClient
+
+	// Stops the health checks of the Kubernetes API server endpoints, if there are several.
+	cancel context.CancelFunc
 }
 
 func NewKubeClient(ca *apiconfig.CalicoAPIConfigSpec) (api.Client, error) {
-	config, cs, err := CreateKubernetesClientset(ca)
+	ctx, cancel := context.WithCancel(context.Background())
+	config, cs, err := createKubernetesClientset(ctx, ca)
 	if err != nil {
+		cancel()
 		return nil, err
 	}
 
 	crdClientV1, err := buildCRDClientV1(*config)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("Failed to build V1 CRD client: %v", err)
 	}
 
@@ -95,6 +101,7 @@ func NewKubeClient(ca *apiconfig.CalicoAPIConfigSpec) (api.Client, error) {
 		clientsByResourceKind: make(map[string]resources.K8sResourceClient),
 		clientsByKeyType:      make(map[reflect.Type]resources.K8sResourceClient),
 		clientsByListType:     make(map[reflect.Type]resources.K8sResourceClient),
+		cancel:                cancel,
 	}
 
 	// Create the Calico sub-clients and register them.
@@ -293,15 +300,30 @@ func fillLoadingRulesFromKubeConfigSpec(loadingRules *clientcmd.ClientConfigLoad
 	loadingRules.ExplicitPath = kubeConfig
 }
 
+// CreateKubernetesClientset returns a clientset for the given config. If several API server
+// endpoints are configured, they are health checked for the life of the process.
 func CreateKubernetesClientset(ca *apiconfig.CalicoAPIConfigSpec) (*rest.Config, *kubernetes.Clientset, error) {
+	return createKubernetesClientset(context.Background(), ca)
+}
+
+// createKubernetesClientset returns a clientset for the given config, health checking the API
+// server endpoints, if there are several, until the given context is done.
+func createKubernetesClientset(ctx context.Context, ca *apiconfig.CalicoAPIConfigSpec) (*rest.Config, *kubernetes.Clientset, error) {
 	// Use the kubernetes client code to load the kubeconfig file and combine it with the overrides.
 	configOverrides := &clientcmd.ConfigOverrides{}
+
+	// The API endpoint may be a prioritized list of endpoints to fail over between.
+	apiEndpoints := ParseAPIEndpoints(ca.K8sAPIEndpoint)
+	apiEndpoint := ""
+	if len(apiEndpoints) > 0 {
+		apiEndpoint = apiEndpoints[0]
+	}
 	overridesMap := []struct {
 		variable *string
 		value    string
 	}{
 		{&configOverrides.CurrentContext, ca.K8sCurrentContext},
-		{&configOverrides.ClusterInfo.Server, ca.K8sAPIEndpoint},
+		{&configOverrides.ClusterInfo.Server, apiEndpoint},
 		{&configOverrides.AuthInfo.ClientCertificate, ca.K8sCertFile},
 		{&configOverrides.AuthInfo.ClientKey, ca.K8sKeyFile},
 		{&configOverrides.ClusterInfo.CertificateAuthority, ca.K8sCAFile},
@@ -344,6 +366,10 @@ func CreateKubernetesClientset(ca *apiconfig.CalicoAPIConfigSpec) (*rest.Config,
 		return nil, nil, resources.K8sErrorToCalico(err, nil)
 	}
 
+	if err := WithEndpointFailover(ctx, config, apiEndpoints); err != nil {
+		return nil, nil, err
+	}
+
 	config.AcceptContentTypes = strings.Join([]string{runtime.ContentTypeProtobuf, runtime.ContentTypeJSON}, ",")
 	config.ContentType = runtime.ContentTypeProtobuf
 
@@ -356,6 +382,9 @@ func CreateKubernetesClientset(ca *apiconfig.CalicoAPIConfigSpec) (*rest.Config,
 	// efficiently. The IPAM code can create bursts of requests to the API, so
 	// in order to keep pod creation times sensible we allow a higher request rate.
 	config.Burst = 100
+	if ca.K8sClientBurst != 0 {
+		config.Burst = ca.K8sClientBurst
+	}
 	cs, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, nil, resources.K8sErrorToCalico(err, nil)
@@ -487,7 +516,10 @@ func (c *KubeClient) Clean() error {
 
 // Close the underlying client
 func (c *KubeClient) Close() error {
-	log.Debugf("Closing client - NOOP")
+	log.Debugf("Closing client")
+	if c.cancel != nil {
+		c.cancel()
+	}
 	return nil
 }
 
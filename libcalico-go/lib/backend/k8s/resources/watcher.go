@@ -146,6 +146,13 @@ func (crw *k8sWatcherConverter) convertEvent(kevent kwatch.Event) []*api.WatchEv
 			Type:  api.WatchError,
 			Error: apierrors.FromObject(kevent.Object),
 		}}
+	case kwatch.Bookmark:
+		// A bookmark just reports the latest resource version of the watched resources.
+		k8sRes := kevent.Object.(Resource)
+		return []*api.WatchEvent{{
+			Type: api.WatchBookmark,
+			New:  &model.KVPair{Revision: k8sRes.GetObjectMeta().GetResourceVersion()},
+		}}
 	case kwatch.Deleted:
 		fallthrough
 	case kwatch.Added:
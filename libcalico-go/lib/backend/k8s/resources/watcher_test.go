@@ -23,6 +23,8 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	log "github.com/sirupsen/logrus"
+	k8sapi "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kwatch "k8s.io/apimachinery/pkg/watch"
 )
 
@@ -49,12 +51,22 @@ var _ = Describe("Resources watcher ", func() {
 
 		It("should return error WatchEvent with unexpected kwatch event type", func() {
 			events := kwc.convertEvent(kwatch.Event{
-				Type: kwatch.Bookmark,
+				Type: kwatch.EventType("UNKNOWN"),
 			})
 			Expect(events).To(HaveLen(1))
 			Expect(events[0].Type).To(Equal(api.WatchError))
 		})
 
+		It("should return a bookmark WatchEvent with the revision of a kwatch Bookmark event", func() {
+			events := kwc.convertEvent(kwatch.Event{
+				Type:   kwatch.Bookmark,
+				Object: &k8sapi.Namespace{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1234"}},
+			})
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].Type).To(Equal(api.WatchBookmark))
+			Expect(events[0].New.Revision).To(Equal("1234"))
+		})
+
 		It("should return add events with kwatch Added event type", func() {
 			kwc.converter = func(r Resource) ([]*model.KVPair, error) {
 				return []*model.KVPair{
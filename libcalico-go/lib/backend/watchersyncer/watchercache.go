@@ -117,6 +117,11 @@ mainLoop:
 				}
 				kvp.Value = nil
 				wc.handleWatchListEvent(kvp)
+			case api.WatchBookmark:
+				// Nothing has changed, but track the revision so that we can resume the watch from
+				// it rather than having to do a full resync if our revision has expired.
+				wc.logger.WithField("revision", event.New.Revision).Debug("Watch bookmark received")
+				wc.currentWatchRevision = event.New.Revision
 			case api.WatchError:
 				// Handle a WatchError. This error triggered from upstream, all type
 				// of WatchError are treated equally,log the Error and trigger a full resync. We only log at info
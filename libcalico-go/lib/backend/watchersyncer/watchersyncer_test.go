@@ -174,6 +174,31 @@ var _ = Describe("Test the backend datastore multi-watch syncer", func() {
 		Eventually(rs.fc.getLatestWatchRevision, 5*time.Second, 100*time.Millisecond).Should(Equal(emptyList.Revision))
 	})
 
+	It("should resume watching from the revision of the latest bookmark", func() {
+		rs := newWatcherSyncerTester([]watchersyncer.ResourceType{r1})
+		rs.ExpectStatusUpdate(api.WaitForDatastore)
+		rs.clientListResponse(r1, emptyList)
+		rs.ExpectStatusUpdate(api.ResyncInProgress)
+		rs.ExpectStatusUpdate(api.InSync)
+		rs.clientWatchResponse(r1, nil)
+		Eventually(rs.fc.getLatestWatchRevision, 5*time.Second, 100*time.Millisecond).Should(Equal(emptyList.Revision))
+
+		rs.sendEvent(r1, api.WatchEvent{
+			Type: api.WatchBookmark,
+			New:  &model.KVPair{Revision: "bookmark-revision"},
+		})
+		Eventually(rs.allEventsHandled).Should(BeTrue())
+
+		// The watch is closed by the datastore, and should be recreated from the bookmark without a
+		// full resync.
+		rs.closeWatch(r1)
+		rs.clientWatchResponse(r1, nil)
+		Eventually(rs.fc.getLatestWatchRevision, 5*time.Second, 100*time.Millisecond).Should(Equal("bookmark-revision"))
+		Expect(rs.fc.getLatestListRevision()).To(Equal("0"))
+		rs.ExpectStatusUnchanged()
+		rs.expectAllEventsHandled()
+	})
+
 	It("should handle reconnection if watchers fail to be created", func() {
 		rs := newWatcherSyncerTester([]watchersyncer.ResourceType{r1, r2, r3})
 		rs.ExpectStatusUpdate(api.WaitForDatastore)
@@ -948,6 +973,16 @@ func (rst *watcherSyncerTester) sendEvent(r watchersyncer.ResourceType, event ap
 	}
 }
 
+// Call to close the current watcher, as if the watch had been closed by the datastore.
+func (rst *watcherSyncerTester) closeWatch(r watchersyncer.ResourceType) {
+	name := model.ListOptionsToDefaultPathRoot(r.ListInterface)
+	log.WithField("Name", name).Info("Closing watcher")
+	w := rst.lws[name].watcher
+	rst.lws[name].watcher = nil
+	w.terminate()
+	rst.expectStop(r)
+}
+
 // Call to verify that stop has been invoked on the watcher.
 func (rst *watcherSyncerTester) expectStop(r watchersyncer.ResourceType) {
 	name := model.ListOptionsToDefaultPathRoot(r.ListInterface)
@@ -359,6 +359,10 @@ func (w *watcher) run() {
 				log.Debug("Watcher results channel closed by remote")
 				return
 			}
+			if event.Type == bapi.WatchBookmark {
+				// Bookmarks are only used to resume backend watches, so aren't passed on.
+				continue
+			}
 			e := w.convertEvent(event)
 			select {
 			case w.results <- e:
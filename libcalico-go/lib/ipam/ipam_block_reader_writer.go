@@ -314,7 +314,17 @@ func (rw blockReaderWriter) releaseBlockAffinity(ctx context.Context, host strin
 	// so that we can pass it back to the datastore on Update.
 	obj, err := rw.queryBlock(ctx, blockCIDR, "")
 	if err != nil {
-		logCtx.WithError(err).Warnf("Error getting block")
+		if _, ok := err.(cerrors.ErrorResourceDoesNotExist); ok {
+			// The affinity has no block, for example because the host went away part way through
+			// claiming it. Nothing else will clean up the dangling affinity, so delete it.
+			logCtx.Info("Block does not exist - deleting dangling affinity")
+			if err := rw.deleteAffinity(ctx, aff); err != nil {
+				logCtx.WithError(err).Warn("Failed to delete dangling affinity")
+				return err
+			}
+		} else {
+			logCtx.WithError(err).Warnf("Error getting block")
+		}
 		return err
 	}
 	b := allocationBlock{obj.Value.(*model.AllocationBlock)}
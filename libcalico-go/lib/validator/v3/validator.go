@@ -1133,15 +1133,25 @@ func validateIPPoolSpec(structLevel validator.StructLevel) {
 	}
 
 	// Allowed use must be one of the enums.
+	loadBalancer := false
 	for _, a := range pool.AllowedUses {
 		switch a {
 		case api.IPPoolAllowedUseWorkload, api.IPPoolAllowedUseTunnel:
 			continue
+		case api.IPPoolAllowedUseLoadBalancer:
+			loadBalancer = true
 		default:
 			structLevel.ReportError(reflect.ValueOf(pool.AllowedUses),
 				"IPpool.AllowedUses", "", reason("unknown use: "+string(a)), "")
 		}
 	}
+
+	// Addresses of LoadBalancer services aren't assigned from blocks, so a pool for them can't be
+	// used for anything else.
+	if loadBalancer && len(pool.AllowedUses) > 1 {
+		structLevel.ReportError(reflect.ValueOf(pool.AllowedUses),
+			"IPpool.AllowedUses", "", reason("LoadBalancer use cannot be combined with other uses"), "")
+	}
 }
 
 func vxLanModeEnabled(mode api.VXLANMode) bool {
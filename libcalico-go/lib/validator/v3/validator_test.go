@@ -1037,6 +1037,25 @@ func init() {
 					},
 				},
 			}, true),
+		Entry("should accept IP pool for LoadBalancer services",
+			api.IPPool{
+				ObjectMeta: v1.ObjectMeta{Name: "pool.name"},
+				Spec: api.IPPoolSpec{
+					CIDR:        netv4_4,
+					AllowedUses: []api.IPPoolAllowedUse{api.IPPoolAllowedUseLoadBalancer},
+				},
+			}, true),
+		Entry("should reject IP pool for LoadBalancer services with other uses",
+			api.IPPool{
+				ObjectMeta: v1.ObjectMeta{Name: "pool.name"},
+				Spec: api.IPPoolSpec{
+					CIDR: netv4_4,
+					AllowedUses: []api.IPPoolAllowedUse{
+						api.IPPoolAllowedUseLoadBalancer,
+						api.IPPoolAllowedUseWorkload,
+					},
+				},
+			}, false),
 		Entry("should reject IP pool with invalid allowed uses",
 			api.IPPool{
 				ObjectMeta: v1.ObjectMeta{Name: "pool.name"},